@@ -18,38 +18,85 @@ type App struct {
 	AccountAggregate         domain.AccountHandler
 	CustomerAggregate        domain.CustomerHandler
 	DailyDebitLimitAggregate domain.DailyDebitLimitHandler
+	PayeeAggregate           domain.PayeeHandler
+	StandingOrderAggregate   domain.StandingOrderHandler
 	TransactionAggregate     domain.TransactionHandler
+	UsernameAggregate        domain.UsernameHandler
 
+	AccountClosureProcess            domain.AccountClosureProcessHandler
 	DepositProcess                   domain.DepositProcessHandler
+	HoldExpiryProcess                domain.HoldExpiryProcessHandler
+	InterestAccrualProcess           domain.InterestAccrualProcessHandler
 	OpenAccountForNewCustomerProcess domain.OpenAccountForNewCustomerProcessHandler
+	StandingOrderScheduleProcess     domain.StandingOrderScheduleProcessHandler
+	StatementProcess                 domain.StatementProcessHandler
+	ThirdPartyCreditRetryProcess     domain.ThirdPartyCreditRetryProcessHandler
+	ThirdPartyPaymentProcess         domain.ThirdPartyPaymentProcessHandler
 	TransferProcess                  domain.TransferProcessHandler
 	WithdrawalProcess                domain.WithdrawalProcessHandler
 
 	ThirdPartyBank integrations.ThirdPartyBankIntegrationHandler
 
-	ReadDB             *sql.DB
-	CustomerProjection projections.CustomerProjectionHandler
-	LedgerProjection   projections.LedgerProjectionHandler
+	ReadDB                      *sql.DB
+	CredentialsProjection       projections.CredentialsProjectionHandler
+	CustomerProjection          projections.CustomerProjectionHandler
+	LedgerProjection            projections.LedgerProjectionHandler
+	PayeeProjection             projections.PayeeProjectionHandler
+	ScheduledTransferProjection projections.ScheduledTransferProjectionHandler
+	StandingOrderProjection     projections.StandingOrderProjectionHandler
+	StatementProjection         projections.StatementProjectionHandler
+	TransactionProjection       projections.TransactionProjectionHandler
+
+	// Feed, if non-nil, is notified of changes made by the ledger and
+	// scheduled-transfers projections, so that the UI can push them to the
+	// browser.
+	Feed *projections.Feed
 }
 
 // Configure configures the Dogma engine for this application.
 func (a *App) Configure(c dogma.ApplicationConfigurer) {
 	c.Identity("bank", AppKey)
 
+	var (
+		ledger    dogma.ProjectionMessageHandler = sqlprojection.New(a.ReadDB, sqlprojection.SQLiteDriver, &a.LedgerProjection)
+		scheduled dogma.ProjectionMessageHandler = sqlprojection.New(a.ReadDB, sqlprojection.SQLiteDriver, &a.ScheduledTransferProjection)
+	)
+
+	if a.Feed != nil {
+		ledger = a.Feed.Watch(ledger)
+		scheduled = a.Feed.Watch(scheduled)
+	}
+
 	c.Routes(
 		dogma.ViaAggregate(a.AccountAggregate),
 		dogma.ViaAggregate(a.CustomerAggregate),
 		dogma.ViaAggregate(a.DailyDebitLimitAggregate),
+		dogma.ViaAggregate(a.PayeeAggregate),
+		dogma.ViaAggregate(a.StandingOrderAggregate),
 		dogma.ViaAggregate(a.TransactionAggregate),
+		dogma.ViaAggregate(a.UsernameAggregate),
 
+		dogma.ViaProcess(a.AccountClosureProcess),
 		dogma.ViaProcess(a.DepositProcess),
+		dogma.ViaProcess(a.HoldExpiryProcess),
+		dogma.ViaProcess(a.InterestAccrualProcess),
 		dogma.ViaProcess(a.OpenAccountForNewCustomerProcess),
+		dogma.ViaProcess(a.StandingOrderScheduleProcess),
+		dogma.ViaProcess(a.StatementProcess),
+		dogma.ViaProcess(a.ThirdPartyCreditRetryProcess),
+		dogma.ViaProcess(a.ThirdPartyPaymentProcess),
 		dogma.ViaProcess(a.TransferProcess),
 		dogma.ViaProcess(a.WithdrawalProcess),
 
 		dogma.ViaIntegration(a.ThirdPartyBank),
 
+		dogma.ViaProjection(sqlprojection.New(a.ReadDB, sqlprojection.SQLiteDriver, &a.CredentialsProjection)),
 		dogma.ViaProjection(sqlprojection.New(a.ReadDB, sqlprojection.SQLiteDriver, &a.CustomerProjection)),
-		dogma.ViaProjection(sqlprojection.New(a.ReadDB, sqlprojection.SQLiteDriver, &a.LedgerProjection)),
+		dogma.ViaProjection(ledger),
+		dogma.ViaProjection(sqlprojection.New(a.ReadDB, sqlprojection.SQLiteDriver, &a.PayeeProjection)),
+		dogma.ViaProjection(scheduled),
+		dogma.ViaProjection(sqlprojection.New(a.ReadDB, sqlprojection.SQLiteDriver, &a.StandingOrderProjection)),
+		dogma.ViaProjection(sqlprojection.New(a.ReadDB, sqlprojection.SQLiteDriver, &a.StatementProjection)),
+		dogma.ViaProjection(sqlprojection.New(a.ReadDB, sqlprojection.SQLiteDriver, &a.TransactionProjection)),
 	)
 }
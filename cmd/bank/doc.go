@@ -1,2 +1,30 @@
 // Package main is the entry-point for the banking example application.
+//
+// By default the application runs on the testkit engine and all state is lost
+// when the server stops. Pass the -data flag with the path of a SQLite database
+// to store the application's events and read models on disk instead.
+//
+// Transfers to third-party bank accounts are sent to the API at the URL given
+// by the -third-party-bank flag. By default this is the address at which
+// cmd/thirdpartybank serves its simulator of the third-party bank. Credits that
+// fail with a transient error are retried with increasing delays for the period
+// given by the -third-party-retry-window flag, after which the transfer fails.
+//
+// The third-party bank sends notifications of payments into the bank's accounts
+// to the API served under /third-party-bank. Each notification must be signed
+// with the secret in the file given by the -third-party-secret flag, which is
+// shared with the third-party bank. Without the flag, all notifications are
+// rejected. Payments that can not be credited are returned to the sender.
+//
+// Operations staff log in at /ops to freeze and unfreeze accounts, to change
+// their daily debit limits, to arrange overdrafts, to set the interest rate
+// paid on savings accounts and to complete or cancel pending card payments on
+// behalf of the card network. They are listed in the file given by the
+// -operators flag, one "<username>:<hash>" per line, where each hash is
+// produced by running "bank hash-password" with the operator's password on
+// stdin. Without the flag, no one can log in at /ops.
+//
+// Pass the -payee-cooling-off flag to limit the total of all transfers to a
+// newly added payee, to the amount given by the -payee-cooling-off-limit flag,
+// until the cooling-off period has elapsed.
 package main
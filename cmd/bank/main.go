@@ -2,13 +2,21 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"time"
 
+	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/config/runtimeconfig"
 	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/domain"
+	"github.com/dogmatiq/example/integrations"
+	"github.com/dogmatiq/example/integrations/thirdpartybank"
+	durable "github.com/dogmatiq/example/internal/engine"
 	"github.com/dogmatiq/example/ui"
 	"github.com/dogmatiq/example/ui/projections"
 	"github.com/dogmatiq/testkit/engine"
@@ -16,19 +24,187 @@ import (
 )
 
 func main() {
+	data := flag.String(
+		"data",
+		"",
+		"path to a SQLite database in which to store the bank's state; if empty, all state is lost when the server stops",
+	)
+	operatorsFile := flag.String(
+		"operators",
+		"",
+		"path to a file listing the operations staff that may log in at /ops, one \"<username>:<password hash>\" per line",
+	)
+	thirdPartyBank := flag.String(
+		"third-party-bank",
+		"http://localhost:8081",
+		"base URL of the third-party bank's API, such as the one served by cmd/thirdpartybank",
+	)
+	thirdPartySecretFile := flag.String(
+		"third-party-secret",
+		"",
+		"path to a file containing the secret shared with the third-party bank, used to authenticate its notifications of payments; if empty, all notifications are rejected",
+	)
+	retryWindow := flag.Duration(
+		"third-party-retry-window",
+		domain.DefaultThirdPartyCreditRetryWindow,
+		"length of time to retry a credit to a third-party account that fails with a transient error",
+	)
+	coolingOffPeriod := flag.Duration(
+		"payee-cooling-off",
+		0,
+		"length of time after a payee is added during which transfers to it are limited; if zero, transfers to new payees are not limited",
+	)
+	coolingOffLimit := flag.Int64(
+		"payee-cooling-off-limit",
+		100000,
+		"limit on the total of all transfers to a payee during its cooling-off period, in cents",
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage:")
+		fmt.Fprintln(flag.CommandLine.Output(), "  bank [-data <path>] [-operators <path>] [-third-party-bank <url>] [-third-party-secret <path>] [-third-party-retry-window <duration>] [-payee-cooling-off <duration>] [-payee-cooling-off-limit <cents>]")
+		fmt.Fprintln(flag.CommandLine.Output(), "  bank -data <path> projections rebuild [-shadow] <name>")
+		fmt.Fprintln(flag.CommandLine.Output(), "  bank hash-password < <password file>")
+		fmt.Fprintln(flag.CommandLine.Output())
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
-	db := projections.MustNewDB()
+	var err error
+
+	switch args := flag.Args(); {
+	case len(args) == 0:
+		var operators map[string]string
+		var thirdPartySecret []byte
+		operators, err = loadOperators(*operatorsFile)
+		if err == nil {
+			thirdPartySecret, err = thirdpartybank.LoadSecret(*thirdPartySecretFile)
+		}
+		if err == nil {
+			err = serve(
+				ctx,
+				*data,
+				*thirdPartyBank,
+				thirdPartySecret,
+				*retryWindow,
+				domain.PayeeHandler{
+					CoolingOffPeriod: *coolingOffPeriod,
+					CoolingOffLimit:  *coolingOffLimit,
+				},
+				operators,
+			)
+		}
+	case len(args) == 1 && args[0] == "hash-password":
+		err = hashPassword(os.Stdin, os.Stdout)
+	case len(args) >= 2 && args[0] == "projections" && args[1] == "rebuild":
+		err = rebuildProjection(ctx, *data, args[2:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// serve runs the application and serves the UI over HTTP until ctx is
+// canceled.
+func serve(
+	ctx context.Context,
+	data, thirdPartyBank string,
+	thirdPartySecret []byte,
+	retryWindow time.Duration,
+	payees domain.PayeeHandler,
+	operators map[string]string,
+) error {
+	db, err := projections.NewDB(data)
+	if err != nil {
+		return fmt.Errorf("unable to open database: %w", err)
+	}
 	defer db.Close()
 
+	feed := &projections.Feed{DB: db}
+
 	app := &example.App{
+		PayeeAggregate: payees,
+		ThirdPartyCreditRetryProcess: domain.ThirdPartyCreditRetryProcessHandler{
+			RetryWindow: retryWindow,
+		},
+		ThirdPartyBank: integrations.ThirdPartyBankIntegrationHandler{
+			Client: &thirdpartybank.Client{BaseURL: thirdPartyBank},
+		},
 		ReadDB: db,
+		Feed:   feed,
+	}
+
+	var executor dogma.CommandExecutor
+	if data == "" {
+		executor, err = runInMemory(ctx, app)
+	} else {
+		executor, err = runDurable(ctx, app)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to start engine: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(
+		"/third-party-bank/",
+		http.StripPrefix(
+			"/third-party-bank",
+			&integrations.ThirdPartyPaymentHandler{
+				CommandExecutor: executor,
+				Secret:          thirdPartySecret,
+			},
+		),
+	)
+	mux.Handle(
+		"/",
+		&ui.Handler{
+			DB:              db,
+			CommandExecutor: executor,
+			Feed:            feed,
+			Operators:       operators,
+		},
+	)
+
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: mux,
+		// Derive each request's context from ctx, so that long-lived event
+		// streams are closed when the server shuts down.
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
 	}
 
+	// Shut down the HTTP server when the context is canceled.
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	fmt.Println("Dogmatiq Bank is running at http://localhost:8080")
+
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return fmt.Errorf("server error: %w", err)
+	}
+
+	return nil
+}
+
+// runInMemory runs the application on the testkit engine, which keeps all of
+// its state in memory.
+func runInMemory(ctx context.Context, app *example.App) (dogma.CommandExecutor, error) {
 	e, err := engine.New(runtimeconfig.FromApplication(app))
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	logger := fact.NewLogger(func(s string) {
@@ -65,27 +241,38 @@ func main() {
 		}
 	}()
 
-	server := &http.Server{
-		Addr: ":8080",
-		Handler: &ui.Handler{
-			DB: db,
-			CommandExecutor: engine.CommandExecutor{
-				Engine:  e,
-				Options: opts,
-			},
+	return engine.CommandExecutor{
+		Engine:  e,
+		Options: opts,
+	}, nil
+}
+
+// runDurable runs the application on an engine that stores its event stream
+// in the application's read database, alongside the projections' tables.
+//
+// Aggregate and process state is rebuilt from the database, and projections
+// resume from the offsets they last stored.
+func runDurable(ctx context.Context, app *example.App) (dogma.CommandExecutor, error) {
+	e, err := durable.New(
+		ctx,
+		app,
+		app.ReadDB,
+		func(format string, args ...any) {
+			fmt.Printf(format+"\n", args...)
 		},
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	// Shut down the HTTP server when the context is canceled.
+	// Run the engine in the background. This handles any messages that were
+	// not handled before the server last stopped, then processes timeouts and
+	// scheduled events that are triggered by process managers.
 	go func() {
-		<-ctx.Done()
-		server.Shutdown(context.Background())
+		if err := e.Run(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintln(os.Stderr, "engine error:", err)
+		}
 	}()
 
-	fmt.Println("Dogmatiq Bank is running at http://localhost:8080")
-
-	if err := server.ListenAndServe(); err != http.ErrServerClosed {
-		fmt.Fprintln(os.Stderr, "server error:", err)
-		os.Exit(1)
-	}
+	return e, nil
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dogmatiq/example/ui"
+)
+
+// loadOperators returns the operations staff listed in the file at path,
+// keyed by username, as expected by [ui.Handler.Operators].
+//
+// Each non-empty line of the file is of the form "<username>:<password hash>",
+// where the hash is produced by the "hash-password" command. Lines beginning
+// with "#" are ignored. If path is empty, there are no operators.
+func loadOperators(path string) (map[string]string, error) {
+	operators := map[string]string{}
+
+	if path == "" {
+		return operators, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open operators file: %w", err)
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		username, hash, ok := strings.Cut(line, ":")
+		username = strings.ToLower(strings.TrimSpace(username))
+		hash = strings.TrimSpace(hash)
+
+		if !ok || username == "" || hash == "" {
+			return nil, fmt.Errorf("%s:%d: expected <username>:<password hash>", path, n)
+		}
+
+		if _, ok := operators[username]; ok {
+			return nil, fmt.Errorf("%s:%d: operator %q is listed more than once", path, n, username)
+		}
+
+		operators[username] = hash
+	}
+
+	return operators, s.Err()
+}
+
+// hashPassword implements the "hash-password" command, which reads a password
+// from the first line of r and writes its hash to w, for use in the operators
+// file.
+func hashPassword(r io.Reader, w io.Writer) error {
+	s := bufio.NewScanner(r)
+	if !s.Scan() {
+		if err := s.Err(); err != nil {
+			return err
+		}
+		return errors.New("expected a password on stdin")
+	}
+
+	password := strings.TrimSuffix(s.Text(), "\r")
+	if password == "" {
+		return errors.New("the password must not be empty")
+	}
+
+	hash, err := ui.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, hash)
+	return err
+}
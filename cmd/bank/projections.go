@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dogmatiq/example"
+	durable "github.com/dogmatiq/example/internal/engine"
+	"github.com/dogmatiq/example/ui/projections"
+	"github.com/dogmatiq/projectionkit/sqlprojection"
+)
+
+// progressInterval is the minimum interval between progress reports while a
+// projection is being rebuilt.
+const progressInterval = time.Second
+
+// rebuildProjection implements the "projections rebuild" command, which resets
+// a projection and replays the entire event history into it.
+//
+// With the -shadow flag the projection is rebuilt into a separate database
+// that is swapped into place once the rebuild is complete, so that the UI
+// continues to serve the existing data in the meantime.
+func rebuildProjection(ctx context.Context, data string, args []string) error {
+	flags := flag.NewFlagSet("projections rebuild", flag.ContinueOnError)
+	shadow := flags.Bool(
+		"shadow",
+		false,
+		"rebuild into a shadow database, then atomically swap it into place",
+	)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: bank -data <path> projections rebuild [-shadow] <name>")
+	}
+
+	if data == "" {
+		return errors.New("projections can only be rebuilt from a stored event history, use the -data flag")
+	}
+
+	db, err := projections.NewDB(data)
+	if err != nil {
+		return fmt.Errorf("unable to open database: %w", err)
+	}
+	defer db.Close()
+
+	app := &example.App{
+		ReadDB: db,
+	}
+
+	name := flags.Arg(0)
+	handlers := map[string]sqlprojection.MessageHandler{
+		"credentials":         &app.CredentialsProjection,
+		"customers":           &app.CustomerProjection,
+		"ledger":              &app.LedgerProjection,
+		"payees":              &app.PayeeProjection,
+		"scheduled-transfers": &app.ScheduledTransferProjection,
+		"standing-orders":     &app.StandingOrderProjection,
+		"statements":          &app.StatementProjection,
+		"transactions":        &app.TransactionProjection,
+	}
+
+	h, ok := handlers[name]
+	if !ok {
+		return fmt.Errorf("unrecognized projection: %q", name)
+	}
+
+	e, err := durable.New(ctx, app, db, nil)
+	if err != nil {
+		return fmt.Errorf("unable to start engine: %w", err)
+	}
+
+	if !*shadow {
+		if err := e.RebuildProjection(
+			ctx,
+			sqlprojection.New(db, sqlprojection.SQLiteDriver, h),
+			reportProgress(name),
+		); err != nil {
+			return err
+		}
+
+		fmt.Printf("rebuilt %s\n", name)
+		return nil
+	}
+
+	shadowPath := filepath.Join(filepath.Dir(data), fmt.Sprintf(".%s.%s.shadow", filepath.Base(data), name))
+	defer removeDB(shadowPath)
+
+	// Remove any shadow database left behind by an earlier rebuild that did
+	// not complete.
+	removeDB(shadowPath)
+
+	shadowDB, err := projections.NewDB(shadowPath)
+	if err != nil {
+		return fmt.Errorf("unable to open shadow database: %w", err)
+	}
+	defer shadowDB.Close()
+
+	if err := e.RebuildProjection(
+		ctx,
+		sqlprojection.New(shadowDB, sqlprojection.SQLiteDriver, h),
+		reportProgress(name),
+	); err != nil {
+		return err
+	}
+
+	if err := shadowDB.Close(); err != nil {
+		return err
+	}
+
+	if err := projections.SwapProjection(ctx, db, shadowPath, h); err != nil {
+		return fmt.Errorf("unable to swap shadow database into place: %w", err)
+	}
+
+	fmt.Printf("rebuilt %s and swapped it into place\n", name)
+	return nil
+}
+
+// reportProgress returns a function that prints the progress of a projection
+// rebuild, at most once per progressInterval.
+func reportProgress(name string) func(done, total int) {
+	var last time.Time
+
+	return func(done, total int) {
+		if done < total && time.Since(last) < progressInterval {
+			return
+		}
+
+		last = time.Now()
+		fmt.Printf(
+			"rebuilding %s: %d of %d events (%d%%)\n",
+			name,
+			done,
+			total,
+			done*100/total,
+		)
+	}
+}
+
+// removeDB removes the SQLite database at path, along with its write-ahead log
+// and shared memory files.
+func removeDB(path string) {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		os.Remove(path + suffix)
+	}
+}
@@ -0,0 +1,26 @@
+// Package main is the entry-point for a simulator of the third-party bank
+// that the banking example application credits when money is transferred out
+// of the bank, and that sends payments into the bank's accounts.
+//
+// It serves the API described by the thirdpartybank package. Credits to
+// accounts with numeric IDs are accepted, and credits to any other account are
+// rejected. Flags add latency, random errors and rejections of specific
+// accounts. The outcome of the next requests can also be scripted while the
+// simulator is running, for example:
+//
+//	curl -X POST localhost:8081/simulator/script -d '[{"status": 503}, {"delay": "5s"}]'
+//
+// Payments are sent to the API at the URL given by the -bank flag, which by
+// default is the address at which cmd/bank serves it. They are signed with the
+// secret in the file given by the -secret flag, which must match the one given
+// to cmd/bank by its -third-party-secret flag:
+//
+//	thirdpartybank -secret third-party-secret.txt
+//
+//	curl -X POST localhost:8081/simulator/payments -d '{"account_id": "A001", "sender_account_id": "200001", "amount": 500}'
+//
+// Account names are given by the -names flag. Payees at other accounts can not
+// be confirmed:
+//
+//	thirdpartybank -names '06200012345674=Anna Smith,08200112345671=Bob Jones'
+package main
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/dogmatiq/example/integrations/thirdpartybank"
+)
+
+func main() {
+	addr := flag.String(
+		"listen",
+		":8081",
+		"address on which to serve the simulated API",
+	)
+	latency := flag.Duration(
+		"latency",
+		0,
+		"time taken to respond to each request",
+	)
+	jitter := flag.Duration(
+		"jitter",
+		0,
+		"maximum random time added to the latency",
+	)
+	errorRate := flag.Float64(
+		"error-rate",
+		0,
+		"probability, between 0 and 1, that a request fails with 503 Service Unavailable",
+	)
+	bank := flag.String(
+		"bank",
+		"http://localhost:8080/third-party-bank",
+		"base URL of the API to which payments are sent, such as the one served by cmd/bank",
+	)
+	secretFile := flag.String(
+		"secret",
+		"",
+		"path to a file containing the secret shared with the bank, used to sign payments; if empty, payments are not signed",
+	)
+	closed := flag.String(
+		"closed",
+		"",
+		"comma-separated IDs of accounts that reject credits because they are closed",
+	)
+	names := flag.String(
+		"names",
+		"",
+		"comma-separated <id>=<name> pairs giving the names of accounts, used to confirm payees",
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage:")
+		fmt.Fprintln(flag.CommandLine.Output(), "  thirdpartybank [-listen <addr>] [-latency <duration>] [-jitter <duration>] [-error-rate <p>] [-closed <ids>] [-names <id>=<name>,...] [-bank <url>] [-secret <path>]")
+		fmt.Fprintln(flag.CommandLine.Output())
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() != 0 || *errorRate < 0 || *errorRate > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	secret, err := thirdpartybank.LoadSecret(*secretFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	closedAccounts := map[string]bool{}
+	for _, id := range strings.Split(*closed, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			closedAccounts[id] = true
+		}
+	}
+
+	accountNames := map[string]string{}
+	for _, pair := range strings.Split(*names, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		id, name, ok := strings.Cut(pair, "=")
+		if !ok {
+			flag.Usage()
+			os.Exit(2)
+		}
+
+		accountNames[strings.TrimSpace(id)] = strings.TrimSpace(name)
+	}
+
+	sim := &thirdpartybank.Simulator{
+		Latency:   *latency,
+		Jitter:    *jitter,
+		ErrorRate: *errorRate,
+		Reject: func(accountID string) thirdpartybank.RejectionReason {
+			if closedAccounts[accountID] {
+				return thirdpartybank.AccountClosed
+			}
+			return thirdpartybank.DefaultReject(accountID)
+		},
+		Names:   accountNames,
+		BankURL: *bank,
+		Secret:  secret,
+		Logf: func(format string, args ...any) {
+			fmt.Printf(format+"\n", args...)
+		},
+	}
+
+	if err := serve(ctx, *addr, sim); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// serve serves the simulated API until ctx is canceled.
+func serve(ctx context.Context, addr string, h http.Handler) error {
+	server := &http.Server{
+		Addr:    addr,
+		Handler: h,
+	}
+
+	// Shut down the HTTP server when the context is canceled.
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	fmt.Printf("Third-party bank simulator is listening on %s\n", addr)
+
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return fmt.Errorf("server error: %w", err)
+	}
+
+	return nil
+}
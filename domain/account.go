@@ -2,6 +2,8 @@ package domain
 
 import (
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/example/messages"
@@ -9,15 +11,105 @@ import (
 	"github.com/dogmatiq/example/messages/events"
 )
 
+const (
+	// defaultDailyDebitLimit is the daily debit limit applied to newly opened
+	// accounts, in cents.
+	defaultDailyDebitLimit = 900000
+
+	// maximumSavingsWithdrawalsPerMonth is the number of withdrawals that may
+	// be made from a savings account each month.
+	maximumSavingsWithdrawalsPerMonth = 4
+)
+
 // account is the aggregate root for a bank account.
 type account struct {
 	dogma.NoSnapshotBehavior
 
+	// CustomerID is the ID of the customer that owns the account.
+	CustomerID string
+
 	// Name is the account name.
 	Name string
 
-	// Balance is the current account balance, in cents.
+	// AccountType is the type of the account, which determines the rules that
+	// apply to debits.
+	AccountType messages.AccountType
+
+	// MaturityDate is the date on which a term deposit matures, in YYYY-MM-DD
+	// format. It is empty for other account types.
+	MaturityDate string
+
+	// Balance is the current ledger balance of the account, in cents. It
+	// includes only those debits and credits that have been posted.
 	Balance int64
+
+	// HeldAmount is the total of all holds that have been authorized but not
+	// yet captured or released, in cents. The available balance of the
+	// account is its ledger balance less this amount.
+	HeldAmount int64
+
+	// DailyDebitLimit is the maximum total of all debits that may be applied
+	// to the account in a single day, in cents.
+	DailyDebitLimit int64
+
+	// OverdraftLimit is the arranged overdraft limit of the account, in cents.
+	// The balance may be negative by up to this amount.
+	OverdraftLimit int64
+
+	// InterestRate is the annual interest rate paid on the account balance, in
+	// basis points.
+	InterestRate int64
+
+	// InterestAccrualID is the ID of the [InterestAccrualProcessHandler]
+	// instance that accrues interest at the current rate, or an empty string
+	// if the rate is zero.
+	InterestAccrualID string
+
+	// InterestAccruals is the number of times interest has begun accruing on
+	// the account, that is, the number of times the rate has been changed from
+	// zero. It is used to give each accrual a distinct ID.
+	InterestAccruals int
+
+	// PendingDebits maps the transaction IDs of debits that have not yet been
+	// settled, and may therefore still be refunded, to the event that applied
+	// them.
+	PendingDebits map[string]*events.AccountDebited
+
+	// Withdrawals is the number of customer withdrawals and card payments made
+	// from the account in each month, keyed by the month in YYYY-MM format.
+	// Other debits, such as transfers, do not count towards the savings
+	// withdrawal limit.
+	Withdrawals map[string]int
+
+	// IsClosed is true if the account has been closed.
+	IsClosed bool
+
+	// PendingSweepID is the transaction ID of the most recent sweep of the
+	// balance out of the closed account. The balance is credited back to the
+	// account under this ID if the sweep fails, which is the only credit a
+	// closed account accepts. It is empty once the balance has been returned.
+	PendingSweepID string
+
+	// FreezeType is the type of freeze applied to the account, or an empty
+	// string if the account is not frozen.
+	FreezeType messages.FreezeType
+
+	// QueuedCredits is the list of credits that have been queued while the
+	// account is fully frozen, in the order they were received.
+	QueuedCredits []*events.AccountCreditQueued
+
+	// Holds maps the IDs of holds that have been authorized but not yet
+	// captured or released to the event that authorized them.
+	Holds map[string]*events.DebitAuthorized
+
+	// StatementBalance is the closing balance of the account's most recent
+	// statement, which is the opening balance of its next statement.
+	StatementBalance int64
+
+	// LastStatementDate is the last day covered by the account's most recent
+	// statement, in YYYY-MM-DD format. It is empty if no statement has been
+	// issued.
+	LastStatementDate string
 }
 
 func (a *account) AggregateInstanceDescription() string {
@@ -25,6 +117,19 @@ func (a *account) AggregateInstanceDescription() string {
 		return ""
 	}
 
+	if a.IsClosed {
+		return fmt.Sprintf("%s (closed)", a.Name)
+	}
+
+	if a.FreezeType != "" {
+		return fmt.Sprintf(
+			"%s (%s, %s freeze)",
+			a.Name,
+			messages.FormatAmount(a.Balance),
+			a.FreezeType,
+		)
+	}
+
 	return fmt.Sprintf(
 		"%s (%s)",
 		a.Name,
@@ -39,13 +144,54 @@ func (a *account) OpenAccount(s dogma.AggregateCommandScope[*account], m *comman
 	}
 
 	s.RecordEvent(&events.AccountOpened{
-		CustomerID:  m.CustomerID,
-		AccountID:   m.AccountID,
-		AccountName: m.AccountName,
+		CustomerID:      m.CustomerID,
+		AccountID:       m.AccountID,
+		AccountName:     m.AccountName,
+		AccountType:     m.AccountType,
+		MaturityDate:    m.MaturityDate,
+		DailyDebitLimit: defaultDailyDebitLimit,
 	})
 }
 
 func (a *account) CreditAccount(s dogma.AggregateCommandScope[*account], m *commands.CreditAccount) {
+	// Other credits are only requested for accounts that are known to exist,
+	// but a third-party bank may send a payment to any account ID.
+	if a.Name == "" && m.TransactionType == messages.ThirdPartyPayment {
+		s.RecordEvent(&events.AccountCreditDeclined{
+			TransactionID:   m.TransactionID,
+			AccountID:       m.AccountID,
+			TransactionType: m.TransactionType,
+			Amount:          m.Amount,
+			Reason:          messages.AccountNotFound,
+		})
+		return
+	}
+
+	// A closed account only accepts the return of its own swept balance, which
+	// occurs if the sweep to the nominated account fails. The sweeps of other
+	// closed accounts are declined, so that their balances are returned to
+	// them instead.
+	if a.IsClosed && (m.TransactionType != messages.AccountClosure || m.TransactionID != a.PendingSweepID) {
+		s.RecordEvent(&events.AccountCreditDeclined{
+			TransactionID:   m.TransactionID,
+			AccountID:       m.AccountID,
+			TransactionType: m.TransactionType,
+			Amount:          m.Amount,
+			Reason:          messages.AccountClosed,
+		})
+		return
+	}
+
+	if a.FreezeType == messages.FullFreeze {
+		s.RecordEvent(&events.AccountCreditQueued{
+			TransactionID:   m.TransactionID,
+			AccountID:       m.AccountID,
+			TransactionType: m.TransactionType,
+			Amount:          m.Amount,
+		})
+		return
+	}
+
 	s.RecordEvent(&events.AccountCredited{
 		TransactionID:   m.TransactionID,
 		AccountID:       m.AccountID,
@@ -55,13 +201,38 @@ func (a *account) CreditAccount(s dogma.AggregateCommandScope[*account], m *comm
 }
 
 func (a *account) DebitAccount(s dogma.AggregateCommandScope[*account], m *commands.DebitAccount) {
-	if a.hasSufficientFunds(m.Amount) {
+	if a.IsClosed {
+		s.RecordEvent(&events.AccountDebitDeclined{
+			TransactionID:   m.TransactionID,
+			AccountID:       m.AccountID,
+			TransactionType: m.TransactionType,
+			Amount:          m.Amount,
+			Reason:          messages.AccountClosed,
+		})
+	} else if a.FreezeType != "" {
+		s.RecordEvent(&events.AccountDebitDeclined{
+			TransactionID:   m.TransactionID,
+			AccountID:       m.AccountID,
+			TransactionType: m.TransactionType,
+			Amount:          m.Amount,
+			Reason:          messages.AccountFrozen,
+		})
+	} else if reason, ok := a.violatesAccountTypeRules(m.TransactionType, m.ScheduledTime); ok {
+		s.RecordEvent(&events.AccountDebitDeclined{
+			TransactionID:   m.TransactionID,
+			AccountID:       m.AccountID,
+			TransactionType: m.TransactionType,
+			Amount:          m.Amount,
+			Reason:          reason,
+		})
+	} else if a.hasSufficientFunds(m.Amount) {
 		s.RecordEvent(&events.AccountDebited{
 			TransactionID:   m.TransactionID,
 			AccountID:       m.AccountID,
 			TransactionType: m.TransactionType,
 			Amount:          m.Amount,
 			ScheduledTime:   m.ScheduledTime,
+			DailyDebitLimit: a.DailyDebitLimit,
 		})
 	} else {
 		s.RecordEvent(&events.AccountDebitDeclined{
@@ -74,30 +245,569 @@ func (a *account) DebitAccount(s dogma.AggregateCommandScope[*account], m *comma
 	}
 }
 
+func (a *account) AuthorizeDebit(s dogma.AggregateCommandScope[*account], m *commands.AuthorizeDebit) {
+	if a.Name == "" {
+		s.Log("account has not been opened")
+		return
+	}
+
+	if _, ok := a.Holds[m.HoldID]; ok {
+		s.Log("hold has already been authorized")
+		return
+	}
+
+	if a.IsClosed {
+		s.RecordEvent(&events.DebitAuthorizationDeclined{
+			HoldID:    m.HoldID,
+			AccountID: m.AccountID,
+			Merchant:  m.Merchant,
+			Amount:    m.Amount,
+			Reason:    messages.AccountClosed,
+		})
+	} else if a.FreezeType != "" {
+		s.RecordEvent(&events.DebitAuthorizationDeclined{
+			HoldID:    m.HoldID,
+			AccountID: m.AccountID,
+			Merchant:  m.Merchant,
+			Amount:    m.Amount,
+			Reason:    messages.AccountFrozen,
+		})
+	} else if reason, ok := a.violatesAccountTypeRules(messages.CardPayment, m.ScheduledTime); ok {
+		s.RecordEvent(&events.DebitAuthorizationDeclined{
+			HoldID:    m.HoldID,
+			AccountID: m.AccountID,
+			Merchant:  m.Merchant,
+			Amount:    m.Amount,
+			Reason:    reason,
+		})
+	} else if a.hasSufficientFunds(m.Amount) {
+		s.RecordEvent(&events.DebitAuthorized{
+			HoldID:          m.HoldID,
+			AccountID:       m.AccountID,
+			Merchant:        m.Merchant,
+			Amount:          m.Amount,
+			ScheduledTime:   m.ScheduledTime,
+			DailyDebitLimit: a.DailyDebitLimit,
+		})
+	} else {
+		s.RecordEvent(&events.DebitAuthorizationDeclined{
+			HoldID:    m.HoldID,
+			AccountID: m.AccountID,
+			Merchant:  m.Merchant,
+			Amount:    m.Amount,
+			Reason:    messages.InsufficientFunds,
+		})
+	}
+}
+
+func (a *account) CaptureHold(s dogma.AggregateCommandScope[*account], m *commands.CaptureHold) {
+	h, ok := a.Holds[m.HoldID]
+	if !ok {
+		s.Log("hold is not outstanding")
+		return
+	}
+
+	if a.FreezeType != "" {
+		s.RecordEvent(&events.HoldCaptureDeclined{
+			HoldID:    m.HoldID,
+			AccountID: m.AccountID,
+			Merchant:  h.Merchant,
+			Amount:    h.Amount,
+			Reason:    messages.AccountFrozen,
+		})
+		return
+	}
+
+	// the account type rules and daily debit limit are applied when the hold
+	// is authorized, and the capture debits exactly the amount that was
+	// authorized, so they are not checked again here
+	s.RecordEvent(&events.HoldCaptured{
+		HoldID:    m.HoldID,
+		AccountID: m.AccountID,
+		Merchant:  h.Merchant,
+		Amount:    h.Amount,
+	})
+}
+
+func (a *account) ReleaseHold(s dogma.AggregateCommandScope[*account], m *commands.ReleaseHold) {
+	h, ok := a.Holds[m.HoldID]
+	if !ok {
+		s.Log("hold is not outstanding")
+		return
+	}
+
+	s.RecordEvent(&events.HoldReleased{
+		HoldID:    m.HoldID,
+		AccountID: m.AccountID,
+		Amount:    h.Amount,
+	})
+}
+
+func (a *account) ExpireHold(s dogma.AggregateCommandScope[*account], m *commands.ExpireHold) {
+	h, ok := a.Holds[m.HoldID]
+	if !ok {
+		s.Log("hold is not outstanding")
+		return
+	}
+
+	s.RecordEvent(&events.HoldExpired{
+		HoldID:    m.HoldID,
+		AccountID: m.AccountID,
+		Amount:    h.Amount,
+	})
+}
+
+func (a *account) ChangeDailyDebitLimit(s dogma.AggregateCommandScope[*account], m *commands.ChangeDailyDebitLimit) {
+	if a.Name == "" {
+		s.Log("account has not been opened")
+		return
+	}
+
+	if a.DailyDebitLimit == m.DailyDebitLimit {
+		s.Log("daily debit limit is unchanged")
+		return
+	}
+
+	s.RecordEvent(&events.DailyDebitLimitChanged{
+		AccountID:               m.AccountID,
+		PreviousDailyDebitLimit: a.DailyDebitLimit,
+		DailyDebitLimit:         m.DailyDebitLimit,
+	})
+}
+
+func (a *account) SetOverdraftLimit(s dogma.AggregateCommandScope[*account], m *commands.SetOverdraftLimit) {
+	if a.Name == "" {
+		s.Log("account has not been opened")
+		return
+	}
+
+	if a.IsClosed {
+		s.Log("account has been closed")
+		return
+	}
+
+	if a.OverdraftLimit == m.OverdraftLimit {
+		s.Log("overdraft limit is unchanged")
+		return
+	}
+
+	s.RecordEvent(&events.OverdraftLimitChanged{
+		AccountID:              m.AccountID,
+		PreviousOverdraftLimit: a.OverdraftLimit,
+		OverdraftLimit:         m.OverdraftLimit,
+	})
+}
+
+func (a *account) SetInterestRate(s dogma.AggregateCommandScope[*account], m *commands.SetInterestRate) {
+	if a.Name == "" {
+		s.Log("account has not been opened")
+		return
+	}
+
+	if a.IsClosed {
+		s.Log("account has been closed")
+		return
+	}
+
+	if a.AccountType != messages.Savings {
+		s.Log("interest is only paid on savings accounts")
+		return
+	}
+
+	if a.InterestRate == m.InterestRate {
+		s.Log("interest rate is unchanged")
+		return
+	}
+
+	accrualID := a.InterestAccrualID
+	if accrualID == "" {
+		// interest is not currently accruing, so the new rate starts a new
+		// accrual
+		accrualID = fmt.Sprintf("%s:%d", m.AccountID, a.InterestAccruals+1)
+	}
+
+	s.RecordEvent(&events.InterestRateChanged{
+		AccountID:            m.AccountID,
+		AccrualID:            accrualID,
+		PreviousInterestRate: a.InterestRate,
+		InterestRate:         m.InterestRate,
+	})
+}
+
+func (a *account) AccrueInterest(s dogma.AggregateCommandScope[*account], m *commands.AccrueInterest) {
+	if a.IsClosed {
+		s.Log("account has been closed")
+		return
+	}
+
+	if m.AccrualID != a.InterestAccrualID {
+		s.Log("interest accrual has stopped")
+		return
+	}
+
+	s.RecordEvent(&events.InterestAccrued{
+		AccountID:    m.AccountID,
+		AccrualID:    m.AccrualID,
+		Date:         m.Date,
+		Balance:      a.Balance,
+		InterestRate: a.InterestRate,
+	})
+}
+
+func (a *account) IssueStatement(s dogma.AggregateCommandScope[*account], m *commands.IssueStatement) {
+	if a.Name == "" {
+		s.Log("account has not been opened")
+		return
+	}
+
+	if m.PeriodEnd <= a.LastStatementDate {
+		s.Log("statement has already been issued for this period")
+		return
+	}
+
+	s.RecordEvent(&events.StatementIssued{
+		StatementID:    m.StatementID,
+		AccountID:      m.AccountID,
+		PeriodStart:    m.PeriodStart,
+		PeriodEnd:      m.PeriodEnd,
+		OpeningBalance: a.StatementBalance,
+		TotalCredits:   m.TotalCredits,
+		TotalDebits:    m.TotalDebits,
+		ClosingBalance: a.StatementBalance + m.TotalCredits - m.TotalDebits,
+	})
+}
+
+func (a *account) SettleDebit(s dogma.AggregateCommandScope[*account], m *commands.SettleDebit) {
+	if _, ok := a.PendingDebits[m.TransactionID]; !ok {
+		s.Log("debit is not pending")
+		return
+	}
+
+	s.RecordEvent(&events.AccountDebitSettled{
+		TransactionID: m.TransactionID,
+		AccountID:     m.AccountID,
+	})
+}
+
+func (a *account) FreezeAccount(s dogma.AggregateCommandScope[*account], m *commands.FreezeAccount) {
+	if a.Name == "" {
+		s.Log("account has not been opened")
+		return
+	}
+
+	if a.IsClosed {
+		s.Log("account has been closed")
+		return
+	}
+
+	if a.FreezeType == m.FreezeType {
+		s.Log("account is already frozen")
+		return
+	}
+
+	s.RecordEvent(&events.AccountFrozen{
+		AccountID:  m.AccountID,
+		FreezeType: m.FreezeType,
+		Reason:     m.Reason,
+		Operator:   m.Operator,
+	})
+
+	// Relaxing a full freeze to a debit freeze releases any credits that were
+	// queued while the account was fully frozen.
+	if m.FreezeType == messages.DebitFreeze {
+		a.applyQueuedCredits(s)
+	}
+}
+
+func (a *account) UnfreezeAccount(s dogma.AggregateCommandScope[*account], m *commands.UnfreezeAccount) {
+	if a.FreezeType == "" {
+		s.Log("account is not frozen")
+		return
+	}
+
+	s.RecordEvent(&events.AccountUnfrozen{
+		AccountID: m.AccountID,
+		Reason:    m.Reason,
+		Operator:  m.Operator,
+	})
+
+	a.applyQueuedCredits(s)
+}
+
+// applyQueuedCredits applies the credits that were queued while the account
+// was fully frozen.
+func (a *account) applyQueuedCredits(s dogma.AggregateCommandScope[*account]) {
+	for _, c := range slices.Clone(a.QueuedCredits) {
+		s.RecordEvent(&events.AccountCredited{
+			TransactionID:   c.TransactionID,
+			AccountID:       c.AccountID,
+			TransactionType: c.TransactionType,
+			Amount:          c.Amount,
+		})
+	}
+}
+
+func (a *account) CloseAccount(s dogma.AggregateCommandScope[*account], m *commands.CloseAccount) {
+	if a.Name == "" {
+		s.Log("account has not been opened")
+		return
+	}
+
+	if a.IsClosed {
+		s.Log("account has already been closed")
+		return
+	}
+
+	if a.FreezeType != "" {
+		s.RecordEvent(&events.AccountClosureDeclined{
+			TransactionID: m.TransactionID,
+			AccountID:     m.AccountID,
+			Reason:        messages.AccountIsFrozen,
+		})
+		return
+	}
+
+	if a.Balance < 0 {
+		s.RecordEvent(&events.AccountClosureDeclined{
+			TransactionID: m.TransactionID,
+			AccountID:     m.AccountID,
+			Reason:        messages.AccountIsOverdrawn,
+		})
+		return
+	}
+
+	if len(a.PendingDebits) > 0 || len(a.Holds) > 0 {
+		s.RecordEvent(&events.AccountClosureDeclined{
+			TransactionID: m.TransactionID,
+			AccountID:     m.AccountID,
+			Reason:        messages.AccountHasPendingFunds,
+		})
+		return
+	}
+
+	if a.Balance > 0 && m.SweepToAccountID == "" {
+		s.RecordEvent(&events.AccountClosureDeclined{
+			TransactionID: m.TransactionID,
+			AccountID:     m.AccountID,
+			Reason:        messages.NoSweepAccountNominated,
+		})
+		return
+	}
+
+	s.RecordEvent(&events.AccountClosed{
+		TransactionID:         m.TransactionID,
+		CustomerID:            a.CustomerID,
+		AccountID:             m.AccountID,
+		SweepToAccountID:      m.SweepToAccountID,
+		SweepToThirdPartyBank: m.SweepToThirdPartyBank,
+		SweepToPayee:          m.SweepToPayee,
+		SweepToPayeeID:        m.SweepToPayeeID,
+		Balance:               a.Balance,
+		InterestAccrualID:     a.InterestAccrualID,
+	})
+}
+
+func (a *account) SweepClosedAccount(s dogma.AggregateCommandScope[*account], m *commands.SweepClosedAccount) {
+	if !a.IsClosed {
+		s.Log("account has not been closed")
+		return
+	}
+
+	if a.Balance <= 0 {
+		s.Log("closed account has no balance to sweep")
+		return
+	}
+
+	s.RecordEvent(&events.ClosedAccountSwept{
+		TransactionID:         m.TransactionID,
+		CustomerID:            a.CustomerID,
+		AccountID:             m.AccountID,
+		SweepToAccountID:      m.SweepToAccountID,
+		SweepToThirdPartyBank: m.SweepToThirdPartyBank,
+		SweepToPayee:          m.SweepToPayee,
+		SweepToPayeeID:        m.SweepToPayeeID,
+		Balance:               a.Balance,
+	})
+}
+
+// violatesAccountTypeRules returns the reason that a debit of the given type,
+// scheduled at time t, must be declined if it would violate the rules that
+// apply to the account's type.
+func (a *account) violatesAccountTypeRules(
+	tt messages.TransactionType,
+	t time.Time,
+) (messages.DebitFailureReason, bool) {
+	switch a.AccountType {
+	case messages.Savings:
+		if isWithdrawal(tt) &&
+			a.Withdrawals[withdrawalMonth(t)] >= maximumSavingsWithdrawalsPerMonth {
+			return messages.WithdrawalLimitExceeded, true
+		}
+	case messages.TermDeposit:
+		if t.In(time.UTC).Format(time.DateOnly) < a.MaturityDate {
+			return messages.TermDepositNotMatured, true
+		}
+	}
+
+	return "", false
+}
+
+// isWithdrawal returns true if debits of type tt count towards the number of
+// withdrawals made from a savings account each month.
+func isWithdrawal(tt messages.TransactionType) bool {
+	return tt == messages.Withdrawal || tt == messages.CardPayment
+}
+
+// withdrawalMonth returns the month of a debit for the purposes of counting the
+// number of withdrawals made each month.
+func withdrawalMonth(t time.Time) string {
+	return t.In(time.UTC).Format("2006-01")
+}
+
+// hasSufficientFunds returns true if the available balance of the account,
+// including its arranged overdraft, covers amount.
 func (a *account) hasSufficientFunds(amount int64) bool {
-	return a.Balance >= amount
+	return a.Balance-a.HeldAmount+a.OverdraftLimit >= amount
+}
+
+// removeHold makes the funds reserved by a hold available again without
+// debiting them. A card payment that is never captured does not count towards
+// the savings withdrawal limit.
+func (a *account) removeHold(holdID string) {
+	h := a.Holds[holdID]
+	delete(a.Holds, holdID)
+	a.HeldAmount -= h.Amount
+	a.Withdrawals[withdrawalMonth(h.ScheduledTime)]--
 }
 
 func (a *account) ApplyEvent(m dogma.Event) {
 	switch x := m.(type) {
 	case *events.AccountOpened:
+		a.CustomerID = x.CustomerID
 		a.Name = x.AccountName
+		a.AccountType = x.AccountType
+		a.MaturityDate = x.MaturityDate
+		a.DailyDebitLimit = x.DailyDebitLimit
+	case *events.DailyDebitLimitChanged:
+		a.DailyDebitLimit = x.DailyDebitLimit
+	case *events.OverdraftLimitChanged:
+		a.OverdraftLimit = x.OverdraftLimit
+	case *events.InterestRateChanged:
+		a.InterestRate = x.InterestRate
+		if x.AccrualID != a.InterestAccrualID {
+			a.InterestAccruals++
+		}
+		if x.InterestRate == 0 {
+			a.InterestAccrualID = ""
+		} else {
+			a.InterestAccrualID = x.AccrualID
+		}
 	case *events.AccountCredited:
 		a.Balance += x.Amount
+		if x.TransactionID == a.PendingSweepID {
+			a.PendingSweepID = ""
+		}
+		if d, ok := a.PendingDebits[x.TransactionID]; ok {
+			// refunds settle the debit, and do not count as a withdrawal
+			delete(a.PendingDebits, x.TransactionID)
+			if isWithdrawal(d.TransactionType) {
+				a.Withdrawals[withdrawalMonth(d.ScheduledTime)]--
+			}
+		}
+		a.QueuedCredits = slices.DeleteFunc(
+			a.QueuedCredits,
+			func(c *events.AccountCreditQueued) bool {
+				return c.TransactionID == x.TransactionID
+			},
+		)
+	case *events.AccountCreditQueued:
+		a.QueuedCredits = append(a.QueuedCredits, x)
 	case *events.AccountDebited:
 		a.Balance -= x.Amount
+		a.PendingDebits[x.TransactionID] = x
+		if isWithdrawal(x.TransactionType) {
+			a.Withdrawals[withdrawalMonth(x.ScheduledTime)]++
+		}
+	case *events.AccountDebitSettled:
+		delete(a.PendingDebits, x.TransactionID)
+	case *events.DebitAuthorized:
+		if a.Holds == nil {
+			a.Holds = map[string]*events.DebitAuthorized{}
+		}
+		a.Holds[x.HoldID] = x
+		a.HeldAmount += x.Amount
+		a.Withdrawals[withdrawalMonth(x.ScheduledTime)]++
+	case *events.HoldCaptured:
+		delete(a.Holds, x.HoldID)
+		a.HeldAmount -= x.Amount
+		a.Balance -= x.Amount
+	case *events.HoldReleased:
+		a.removeHold(x.HoldID)
+	case *events.HoldExpired:
+		a.removeHold(x.HoldID)
+	case *events.AccountFrozen:
+		a.FreezeType = x.FreezeType
+	case *events.AccountUnfrozen:
+		a.FreezeType = ""
+	case *events.AccountClosed:
+		a.IsClosed = true
+		a.Balance = 0
+		if x.Balance > 0 {
+			a.PendingSweepID = x.TransactionID
+		}
+	case *events.ClosedAccountSwept:
+		a.Balance = 0
+		a.PendingSweepID = x.TransactionID
+	case *events.StatementIssued:
+		a.StatementBalance = x.ClosingBalance
+		a.LastStatementDate = x.PeriodEnd
 	}
 }
 
 // AccountHandler implements the business logic for a bank account.
 //
 // It centralizes all transactions that are applied to an account in order to
-// enforce a strict no-overdraw policy.
+// ensure that the account is never overdrawn beyond its arranged overdraft
+// limit. It also owns the account's daily debit limit, which is passed along
+// with each debit so that it can be enforced by [DailyDebitLimitHandler].
+//
+// Each debit remains pending until it is either settled or refunded. An
+// account can not be closed while it has pending debits, which guarantees that
+// a closed account is never owed a refund. The only credit a closed account
+// accepts is the return of its own balance when the sweep made on closure
+// fails, which may then be swept to another account.
+//
+// Card payments are made in two phases. Authorizing a debit places a hold on
+// the funds, reducing the account's available balance without changing its
+// ledger balance. The hold is later captured, which debits the funds, or
+// released. A hold is subject to the same account type rules and daily debit
+// limit as any other debit when it is authorized, and
+// [HoldExpiryProcessHandler] releases it if it exceeds the daily debit limit.
+// Holds that are never captured are expired by the same process. An account
+// can not be closed while it has outstanding holds.
+//
+// An account may be frozen by operations staff, for example to place a
+// compliance hold. A frozen account declines all debits. Credits are either
+// applied as normal, or queued until the account is unfrozen, depending on the
+// type of freeze.
+//
+// The account type determines any further rules that apply to debits. Savings
+// accounts permit a limited number of withdrawals each month, and term deposits
+// decline all debits until they reach their maturity date. Only savings
+// accounts may have an interest rate.
+//
+// Statements are requested at the end of each month by
+// [StatementProcessHandler], which totals the credits and debits recorded
+// within each statement period. The account carries the closing balance of
+// each statement forward as the opening balance of the next.
 type AccountHandler struct{}
 
 // New returns a new account instance.
 func (AccountHandler) New() *account {
-	return &account{}
+	return &account{
+		PendingDebits: map[string]*events.AccountDebited{},
+		Withdrawals:   map[string]int{},
+	}
 }
 
 // Configure configures the behavior of the engine as it relates to this
@@ -109,10 +819,43 @@ func (AccountHandler) Configure(c dogma.AggregateConfigurer) {
 		dogma.HandlesCommand[*commands.OpenAccount](),
 		dogma.HandlesCommand[*commands.CreditAccount](),
 		dogma.HandlesCommand[*commands.DebitAccount](),
+		dogma.HandlesCommand[*commands.ChangeDailyDebitLimit](),
+		dogma.HandlesCommand[*commands.SetOverdraftLimit](),
+		dogma.HandlesCommand[*commands.SetInterestRate](),
+		dogma.HandlesCommand[*commands.AccrueInterest](),
+		dogma.HandlesCommand[*commands.SettleDebit](),
+		dogma.HandlesCommand[*commands.CloseAccount](),
+		dogma.HandlesCommand[*commands.SweepClosedAccount](),
+		dogma.HandlesCommand[*commands.FreezeAccount](),
+		dogma.HandlesCommand[*commands.UnfreezeAccount](),
+		dogma.HandlesCommand[*commands.AuthorizeDebit](),
+		dogma.HandlesCommand[*commands.CaptureHold](),
+		dogma.HandlesCommand[*commands.ReleaseHold](),
+		dogma.HandlesCommand[*commands.ExpireHold](),
+		dogma.HandlesCommand[*commands.IssueStatement](),
 		dogma.RecordsEvent[*events.AccountOpened](),
 		dogma.RecordsEvent[*events.AccountCredited](),
+		dogma.RecordsEvent[*events.AccountCreditDeclined](),
+		dogma.RecordsEvent[*events.AccountCreditQueued](),
 		dogma.RecordsEvent[*events.AccountDebited](),
 		dogma.RecordsEvent[*events.AccountDebitDeclined](),
+		dogma.RecordsEvent[*events.AccountDebitSettled](),
+		dogma.RecordsEvent[*events.DailyDebitLimitChanged](),
+		dogma.RecordsEvent[*events.OverdraftLimitChanged](),
+		dogma.RecordsEvent[*events.InterestRateChanged](),
+		dogma.RecordsEvent[*events.InterestAccrued](),
+		dogma.RecordsEvent[*events.AccountClosed](),
+		dogma.RecordsEvent[*events.AccountClosureDeclined](),
+		dogma.RecordsEvent[*events.ClosedAccountSwept](),
+		dogma.RecordsEvent[*events.AccountFrozen](),
+		dogma.RecordsEvent[*events.AccountUnfrozen](),
+		dogma.RecordsEvent[*events.DebitAuthorized](),
+		dogma.RecordsEvent[*events.DebitAuthorizationDeclined](),
+		dogma.RecordsEvent[*events.HoldCaptured](),
+		dogma.RecordsEvent[*events.HoldCaptureDeclined](),
+		dogma.RecordsEvent[*events.HoldReleased](),
+		dogma.RecordsEvent[*events.HoldExpired](),
+		dogma.RecordsEvent[*events.StatementIssued](),
 	)
 }
 
@@ -126,6 +869,34 @@ func (AccountHandler) RouteCommandToInstance(m dogma.Command) string {
 		return x.AccountID
 	case *commands.DebitAccount:
 		return x.AccountID
+	case *commands.ChangeDailyDebitLimit:
+		return x.AccountID
+	case *commands.SetOverdraftLimit:
+		return x.AccountID
+	case *commands.SetInterestRate:
+		return x.AccountID
+	case *commands.AccrueInterest:
+		return x.AccountID
+	case *commands.SettleDebit:
+		return x.AccountID
+	case *commands.CloseAccount:
+		return x.AccountID
+	case *commands.SweepClosedAccount:
+		return x.AccountID
+	case *commands.FreezeAccount:
+		return x.AccountID
+	case *commands.UnfreezeAccount:
+		return x.AccountID
+	case *commands.AuthorizeDebit:
+		return x.AccountID
+	case *commands.CaptureHold:
+		return x.AccountID
+	case *commands.ReleaseHold:
+		return x.AccountID
+	case *commands.ExpireHold:
+		return x.AccountID
+	case *commands.IssueStatement:
+		return x.AccountID
 	default:
 		panic(dogma.UnexpectedMessage)
 	}
@@ -144,6 +915,34 @@ func (AccountHandler) HandleCommand(
 		a.CreditAccount(s, x)
 	case *commands.DebitAccount:
 		a.DebitAccount(s, x)
+	case *commands.ChangeDailyDebitLimit:
+		a.ChangeDailyDebitLimit(s, x)
+	case *commands.SetOverdraftLimit:
+		a.SetOverdraftLimit(s, x)
+	case *commands.SetInterestRate:
+		a.SetInterestRate(s, x)
+	case *commands.AccrueInterest:
+		a.AccrueInterest(s, x)
+	case *commands.SettleDebit:
+		a.SettleDebit(s, x)
+	case *commands.CloseAccount:
+		a.CloseAccount(s, x)
+	case *commands.SweepClosedAccount:
+		a.SweepClosedAccount(s, x)
+	case *commands.FreezeAccount:
+		a.FreezeAccount(s, x)
+	case *commands.UnfreezeAccount:
+		a.UnfreezeAccount(s, x)
+	case *commands.AuthorizeDebit:
+		a.AuthorizeDebit(s, x)
+	case *commands.CaptureHold:
+		a.CaptureHold(s, x)
+	case *commands.ReleaseHold:
+		a.ReleaseHold(s, x)
+	case *commands.ExpireHold:
+		a.ExpireHold(s, x)
+	case *commands.IssueStatement:
+		a.IssueStatement(s, x)
 	default:
 		panic(dogma.UnexpectedMessage)
 	}
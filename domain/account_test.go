@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
 	"github.com/dogmatiq/example/messages/commands"
 	"github.com/dogmatiq/example/messages/events"
 	. "github.com/dogmatiq/testkit"
@@ -23,13 +24,16 @@ func Test_OpenAccount(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ToRecordEvent(
 								&events.AccountOpened{
-									CustomerID:  "C001",
-									AccountID:   "A001",
-									AccountName: "Anna Smith",
+									CustomerID:      "C001",
+									AccountID:       "A001",
+									AccountName:     "Anna Smith",
+									AccountType:     messages.Everyday,
+									DailyDebitLimit: expectedDailyDebitLimit,
 								},
 							),
 						)
@@ -51,6 +55,7 @@ func Test_OpenAccount(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 						).
@@ -60,6 +65,7 @@ func Test_OpenAccount(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							NoneOf(
@@ -0,0 +1,333 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_AccountType(t *testing.T) {
+	t.Run(
+		"when the account is a savings account",
+		func(t *testing.T) {
+			t.Run(
+				"it declines withdrawals beyond the monthly limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        1000,
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W002",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 2, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W003",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W004",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 4, 0, 0, 0, 0, time.UTC),
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W005",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.WithdrawalDeclined{
+									TransactionID: "W005",
+									AccountID:     "A001",
+									Amount:        10,
+									Reason:        messages.WithdrawalLimitExceeded,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it allows withdrawals in the following month",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        1000,
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W002",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 2, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W003",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W004",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 4, 0, 0, 0, 0, time.UTC),
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W005",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.March, 1, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.WithdrawalApproved{
+									TransactionID: "W005",
+									AccountID:     "A001",
+									Amount:        10,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it does not count transfers towards the monthly limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        1000,
+								},
+							),
+							ExecuteCommand(
+								&commands.DebitAccount{
+									TransactionID:   "T001",
+									AccountID:       "A001",
+									TransactionType: messages.Transfer,
+									Amount:          10,
+									ScheduledTime:   time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.DebitAccount{
+									TransactionID:   "T002",
+									AccountID:       "A001",
+									TransactionType: messages.Transfer,
+									Amount:          10,
+									ScheduledTime:   time.Date(2001, time.February, 2, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.DebitAccount{
+									TransactionID:   "T003",
+									AccountID:       "A001",
+									TransactionType: messages.Transfer,
+									Amount:          10,
+									ScheduledTime:   time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.DebitAccount{
+									TransactionID:   "T004",
+									AccountID:       "A001",
+									TransactionType: messages.Transfer,
+									Amount:          10,
+									ScheduledTime:   time.Date(2001, time.February, 4, 0, 0, 0, 0, time.UTC),
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.WithdrawalApproved{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        10,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account is a term deposit",
+		func(t *testing.T) {
+			t.Run(
+				"it declines withdrawals before the maturity date",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:   "C001",
+									AccountID:    "A001",
+									AccountName:  "Anna Smith",
+									AccountType:  messages.TermDeposit,
+									MaturityDate: "2001-03-01",
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        1000,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 28, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.WithdrawalDeclined{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        10,
+									Reason:        messages.TermDepositNotMatured,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it allows withdrawals from the maturity date",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:   "C001",
+									AccountID:    "A001",
+									AccountName:  "Anna Smith",
+									AccountType:  messages.TermDeposit,
+									MaturityDate: "2001-03-01",
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        1000,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.March, 1, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.WithdrawalApproved{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        10,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+}
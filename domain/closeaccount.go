@@ -0,0 +1,284 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+// accountClosureProcess is the process root for sweeping the remaining balance
+// out of a closed account.
+type accountClosureProcess struct {
+	AccountID             string
+	SweepToAccountID      string
+	SweepToThirdPartyBank bool
+	SweepToPayee          *messages.ThirdPartyPayee
+	Amount                int64
+	Returned              bool
+}
+
+// sweep describes the movement of a closed account's balance to the account
+// nominated by the customer.
+type sweep struct {
+	TransactionID         string
+	CustomerID            string
+	AccountID             string
+	SweepToAccountID      string
+	SweepToThirdPartyBank bool
+	SweepToPayee          *messages.ThirdPartyPayee
+	SweepToPayeeID        string
+	Amount                int64
+}
+
+// ProcessInstanceDescription returns a human-readable description of the
+// account closure's current state.
+func (p *accountClosureProcess) ProcessInstanceDescription(ended bool) string {
+	if p.Amount == 0 {
+		return ""
+	}
+
+	if !ended {
+		return fmt.Sprintf(
+			"sweeping %s from closed account %s to %s",
+			messages.FormatAmount(p.Amount),
+			p.AccountID,
+			p.SweepToAccountID,
+		)
+	}
+
+	if p.Returned {
+		return fmt.Sprintf(
+			"returned %s to closed account %s after sweep to %s failed",
+			messages.FormatAmount(p.Amount),
+			p.AccountID,
+			p.SweepToAccountID,
+		)
+	}
+
+	return fmt.Sprintf(
+		"swept %s from closed account %s to %s",
+		messages.FormatAmount(p.Amount),
+		p.AccountID,
+		p.SweepToAccountID,
+	)
+}
+
+// MarshalBinary returns the accountClosureProcess encoded as binary data.
+func (p *accountClosureProcess) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// UnmarshalBinary decodes binary data into the accountClosureProcess.
+func (p *accountClosureProcess) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// AccountClosureProcessHandler manages the process of sweeping the remaining
+// balance of a closed account to the account nominated by the customer.
+//
+// A sweep to one of the customer's saved payees counts towards the limit on
+// transfers to the payee during its cooling-off period.
+//
+// If the nominated account can not be credited, or the sweep would exceed the
+// payee's cooling-off limit, the balance is returned to the closed account,
+// which accepts credits of this kind only. The customer may then nominate
+// another account, and the returned balance is swept again.
+type AccountClosureProcessHandler struct {
+	dogma.NoDeadlineMessagesBehavior[*accountClosureProcess]
+}
+
+// New returns a new account closure instance.
+func (AccountClosureProcessHandler) New() *accountClosureProcess {
+	return &accountClosureProcess{}
+}
+
+// Configure configures the behavior of the engine as it relates to this handler.
+func (AccountClosureProcessHandler) Configure(c dogma.ProcessConfigurer) {
+	c.Identity("account-closure", "144f2676-0270-41cd-8f0c-1341a78cb050")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.AccountClosed](),
+		dogma.HandlesEvent[*events.ClosedAccountSwept](),
+		dogma.HandlesEvent[*events.PayeeCoolingOffLimitConsumed](),
+		dogma.HandlesEvent[*events.PayeeCoolingOffLimitExceeded](),
+		dogma.HandlesEvent[*events.AccountCredited](),
+		dogma.HandlesEvent[*events.AccountCreditDeclined](),
+		dogma.HandlesEvent[*events.ThirdPartyAccountCredited](),
+		dogma.HandlesEvent[*events.ThirdPartyAccountCreditFailed](),
+		dogma.ExecutesCommand[*commands.ConsumePayeeCoolingOffLimit](),
+		dogma.ExecutesCommand[*commands.CreditAccount](),
+		dogma.ExecutesCommand[*commands.CreditThirdPartyAccount](),
+	)
+}
+
+// RouteEventToInstance returns the ID of the process instance that is targeted
+// by m.
+func (AccountClosureProcessHandler) RouteEventToInstance(
+	_ context.Context,
+	m dogma.Event,
+) (string, bool, error) {
+	switch x := m.(type) {
+	case *events.AccountClosed:
+		return x.TransactionID, x.Balance > 0, nil
+	case *events.ClosedAccountSwept:
+		return x.TransactionID, true, nil
+	case *events.PayeeCoolingOffLimitConsumed:
+		return x.TransactionID, x.TransactionType == messages.AccountClosure, nil
+	case *events.PayeeCoolingOffLimitExceeded:
+		return x.TransactionID, x.TransactionType == messages.AccountClosure, nil
+	case *events.AccountCredited:
+		return x.TransactionID, x.TransactionType == messages.AccountClosure, nil
+	case *events.AccountCreditDeclined:
+		return x.TransactionID, x.TransactionType == messages.AccountClosure, nil
+	case *events.ThirdPartyAccountCredited:
+		return x.TransactionID, x.TransactionType == messages.AccountClosure, nil
+	case *events.ThirdPartyAccountCreditFailed:
+		return x.TransactionID, x.TransactionType == messages.AccountClosure, nil
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// HandleEvent handles an event message that has been routed to this handler.
+func (AccountClosureProcessHandler) HandleEvent(
+	_ context.Context,
+	p *accountClosureProcess,
+	s dogma.ProcessEventScope[*accountClosureProcess],
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.AccountClosed:
+		sweepBalance(s, sweep{
+			TransactionID:         x.TransactionID,
+			CustomerID:            x.CustomerID,
+			AccountID:             x.AccountID,
+			SweepToAccountID:      x.SweepToAccountID,
+			SweepToThirdPartyBank: x.SweepToThirdPartyBank,
+			SweepToPayee:          x.SweepToPayee,
+			SweepToPayeeID:        x.SweepToPayeeID,
+			Amount:                x.Balance,
+		})
+
+	case *events.ClosedAccountSwept:
+		sweepBalance(s, sweep{
+			TransactionID:         x.TransactionID,
+			CustomerID:            x.CustomerID,
+			AccountID:             x.AccountID,
+			SweepToAccountID:      x.SweepToAccountID,
+			SweepToThirdPartyBank: x.SweepToThirdPartyBank,
+			SweepToPayee:          x.SweepToPayee,
+			SweepToPayeeID:        x.SweepToPayeeID,
+			Amount:                x.Balance,
+		})
+
+	case *events.PayeeCoolingOffLimitConsumed:
+		creditSweepAccount(s, sweep{
+			TransactionID:         x.TransactionID,
+			SweepToAccountID:      p.SweepToAccountID,
+			SweepToThirdPartyBank: p.SweepToThirdPartyBank,
+			SweepToPayee:          p.SweepToPayee,
+			Amount:                p.Amount,
+		})
+
+	case *events.PayeeCoolingOffLimitExceeded:
+		returnSweptBalance(s, p, x.TransactionID)
+
+	case *events.AccountCredited:
+		// either the sweep succeeded, or the balance has been returned to the
+		// closed account
+		s.End()
+
+	case *events.AccountCreditDeclined:
+		returnSweptBalance(s, p, x.TransactionID)
+
+	case *events.ThirdPartyAccountCredited:
+		s.End()
+
+	case *events.ThirdPartyAccountCreditFailed:
+		returnSweptBalance(s, p, x.TransactionID)
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// sweepBalance moves the balance removed from a closed account to the
+// nominated account. A sweep to a saved payee is first counted towards the
+// payee's cooling-off limit.
+func sweepBalance(
+	s dogma.ProcessEventScope[*accountClosureProcess],
+	x sweep,
+) {
+	s.Mutate(func(p *accountClosureProcess) {
+		p.AccountID = x.AccountID
+		p.SweepToAccountID = x.SweepToAccountID
+		p.SweepToThirdPartyBank = x.SweepToThirdPartyBank
+		p.SweepToPayee = x.SweepToPayee
+		p.Amount = x.Amount
+		p.Returned = false
+	})
+
+	if x.SweepToPayeeID != "" {
+		s.ExecuteCommand(&commands.ConsumePayeeCoolingOffLimit{
+			TransactionID:   x.TransactionID,
+			CustomerID:      x.CustomerID,
+			PayeeID:         x.SweepToPayeeID,
+			TransactionType: messages.AccountClosure,
+			Amount:          x.Amount,
+			ScheduledTime:   s.RecordedAt(),
+		})
+		return
+	}
+
+	creditSweepAccount(s, x)
+}
+
+// creditSweepAccount credits the balance removed from a closed account to the
+// nominated account.
+func creditSweepAccount(
+	s dogma.ProcessEventScope[*accountClosureProcess],
+	x sweep,
+) {
+	if x.SweepToThirdPartyBank {
+		s.ExecuteCommand(&commands.CreditThirdPartyAccount{
+			TransactionID:   x.TransactionID,
+			AccountID:       x.SweepToAccountID,
+			Payee:           x.SweepToPayee,
+			TransactionType: messages.AccountClosure,
+			Amount:          x.Amount,
+		})
+	} else {
+		s.ExecuteCommand(&commands.CreditAccount{
+			TransactionID:   x.TransactionID,
+			AccountID:       x.SweepToAccountID,
+			TransactionType: messages.AccountClosure,
+			Amount:          x.Amount,
+		})
+	}
+}
+
+// returnSweptBalance credits the swept balance back to the closed account
+// after the sweep to the nominated account fails or is refused.
+func returnSweptBalance(
+	s dogma.ProcessEventScope[*accountClosureProcess],
+	p *accountClosureProcess,
+	transactionID string,
+) {
+	s.Mutate(func(p *accountClosureProcess) {
+		p.Returned = true
+	})
+
+	s.ExecuteCommand(&commands.CreditAccount{
+		TransactionID:   transactionID,
+		AccountID:       p.AccountID,
+		TransactionType: messages.AccountClosure,
+		Amount:          p.Amount,
+	})
+}
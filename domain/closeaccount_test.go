@@ -0,0 +1,751 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/domain"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_CloseAccount(t *testing.T) {
+	addedAt := time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC)
+
+	// payeeApp and payeeAccounts are used by the tests that sweep the balance
+	// to a payee that is still within its cooling-off period.
+	payeeApp := &example.App{
+		PayeeAggregate: domain.PayeeHandler{
+			CoolingOffPeriod: 24 * time.Hour,
+			CoolingOffLimit:  1000,
+		},
+	}
+
+	payeeAccounts := []Action{
+		ExecuteCommand(
+			&commands.OpenAccount{
+				CustomerID:  "C001",
+				AccountID:   "A001",
+				AccountName: "Anna Smith",
+				AccountType: messages.Everyday,
+			},
+		),
+		ExecuteCommand(
+			&commands.OpenAccount{
+				CustomerID:  "C002",
+				AccountID:   "A002",
+				AccountName: "Bob Jones",
+				AccountType: messages.Everyday,
+			},
+		),
+		ExecuteCommand(
+			&commands.Deposit{
+				TransactionID: "D001",
+				AccountID:     "A001",
+				Amount:        500,
+			},
+		),
+		ExecuteCommand(
+			&commands.AddPayee{
+				CustomerID: "C001",
+				PayeeID:    "P001",
+				Name:       "Bob",
+				AccountID:  "A002",
+			},
+		),
+	}
+
+	t.Run(
+		"when the account has no balance",
+		func(t *testing.T) {
+			t.Run(
+				"it closes the account",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.AccountClosed{
+									TransactionID: "X001",
+									CustomerID:    "C001",
+									AccountID:     "A001",
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account has a balance",
+		func(t *testing.T) {
+			t.Run(
+				"it sweeps the balance to the nominated account",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A002",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID:    "X001",
+									AccountID:        "A001",
+									SweepToAccountID: "A002",
+								},
+							),
+							ToRecordEvent(
+								&events.AccountCredited{
+									TransactionID:   "X001",
+									AccountID:       "A002",
+									TransactionType: messages.AccountClosure,
+									Amount:          500,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it sweeps the balance to a third-party account",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID:         "X001",
+									AccountID:             "A001",
+									SweepToAccountID:      "06200012345674",
+									SweepToThirdPartyBank: true,
+									SweepToPayee:          thirdPartyPayee,
+								},
+							),
+							ToExecuteCommand(
+								&commands.CreditThirdPartyAccount{
+									TransactionID:   "X001",
+									AccountID:       "06200012345674",
+									Payee:           thirdPartyPayee,
+									TransactionType: messages.AccountClosure,
+									Amount:          500,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it counts a sweep to a payee towards the payee's cooling-off limit",
+				func(t *testing.T) {
+					Begin(t, payeeApp, StartTimeAt(addedAt)).
+						Prepare(payeeAccounts...).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID:    "X001",
+									AccountID:        "A001",
+									SweepToAccountID: "A002",
+									SweepToPayeeID:   "P001",
+								},
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.PayeeCoolingOffLimitConsumed{
+										TransactionID:         "X001",
+										CustomerID:            "C001",
+										PayeeID:               "P001",
+										TransactionType:       messages.AccountClosure,
+										Amount:                500,
+										TotalDuringCoolingOff: 500,
+										CoolingOffLimit:       1000,
+									},
+								),
+								ToRecordEvent(
+									&events.AccountCredited{
+										TransactionID:   "X001",
+										AccountID:       "A002",
+										TransactionType: messages.AccountClosure,
+										Amount:          500,
+									},
+								),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it returns the balance to the closed account if the sweep exceeds the payee's cooling-off limit",
+				func(t *testing.T) {
+					Begin(t, payeeApp, StartTimeAt(addedAt)).
+						Prepare(payeeAccounts...).
+						Prepare(
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D002",
+									AccountID:     "A001",
+									Amount:        600,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID:    "X001",
+									AccountID:        "A001",
+									SweepToAccountID: "A002",
+									SweepToPayeeID:   "P001",
+								},
+							),
+							AllOf(
+								ToRecordEventOfType(&events.PayeeCoolingOffLimitExceeded{}),
+								ToRecordEvent(
+									&events.AccountCredited{
+										TransactionID:   "X001",
+										AccountID:       "A001",
+										TransactionType: messages.AccountClosure,
+										Amount:          1100,
+									},
+								),
+								NoneOf(
+									ToExecuteCommand(
+										&commands.CreditAccount{
+											TransactionID:   "X001",
+											AccountID:       "A002",
+											TransactionType: messages.AccountClosure,
+											Amount:          1100,
+										},
+									),
+								),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it returns the balance to the closed account if the sweep fails",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID:         "X001",
+									AccountID:             "A001",
+									SweepToAccountID:      "06200012345674",
+									SweepToThirdPartyBank: true,
+									SweepToPayee:          thirdPartyPayee,
+								},
+							),
+						).
+						Expect(
+							RecordEvent(
+								&events.ThirdPartyAccountCreditFailed{
+									TransactionID:   "X001",
+									AccountID:       "06200012345674",
+									TransactionType: messages.AccountClosure,
+									Amount:          500,
+								},
+							),
+							ToRecordEvent(
+								&events.AccountCredited{
+									TransactionID:   "X001",
+									AccountID:       "A001",
+									TransactionType: messages.AccountClosure,
+									Amount:          500,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it sweeps a returned balance to another account",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A002",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID:         "X001",
+									AccountID:             "A001",
+									SweepToAccountID:      "06200012345674",
+									SweepToThirdPartyBank: true,
+									SweepToPayee:          thirdPartyPayee,
+								},
+							),
+							RecordEvent(
+								&events.ThirdPartyAccountCreditFailed{
+									TransactionID:   "X001",
+									AccountID:       "06200012345674",
+									TransactionType: messages.AccountClosure,
+									Amount:          500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.SweepClosedAccount{
+									TransactionID:    "X002",
+									AccountID:        "A001",
+									SweepToAccountID: "A002",
+								},
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.ClosedAccountSwept{
+										TransactionID:    "X002",
+										CustomerID:       "C001",
+										AccountID:        "A001",
+										SweepToAccountID: "A002",
+										Balance:          500,
+									},
+								),
+								ToRecordEvent(
+									&events.AccountCredited{
+										TransactionID:   "X002",
+										AccountID:       "A002",
+										TransactionType: messages.AccountClosure,
+										Amount:          500,
+									},
+								),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it does not sweep a closed account that has no balance",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A001",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.SweepClosedAccount{
+									TransactionID:    "X002",
+									AccountID:        "A001",
+									SweepToAccountID: "A002",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.ClosedAccountSwept{}),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it is declined if no account is nominated for the balance",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.AccountClosureDeclined{
+									TransactionID: "X001",
+									AccountID:     "A001",
+									Reason:        messages.NoSweepAccountNominated,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account has pending funds",
+		func(t *testing.T) {
+			t.Run(
+				"it declines the closure",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						// disable the withdrawal process so that the debit is
+						// never settled
+						DisableHandlers("withdrawal").
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							ExecuteCommand(
+								&commands.DebitAccount{
+									TransactionID:   "W001",
+									AccountID:       "A001",
+									TransactionType: messages.Withdrawal,
+									Amount:          100,
+									ScheduledTime:   time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID:    "X001",
+									AccountID:        "A001",
+									SweepToAccountID: "100001",
+								},
+							),
+							ToRecordEvent(
+								&events.AccountClosureDeclined{
+									TransactionID: "X001",
+									AccountID:     "A001",
+									Reason:        messages.AccountHasPendingFunds,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account has been closed",
+		func(t *testing.T) {
+			t.Run(
+				"it declines withdrawals",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A001",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        100,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.WithdrawalDeclined{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        100,
+									Reason:        messages.AccountClosed,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines deposits",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A001",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        100,
+								},
+							),
+							ToRecordEvent(
+								&events.DepositDeclined{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        100,
+									Reason:        messages.AccountClosed,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines incoming transfers and refunds the sender",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C002",
+									AccountID:   "A002",
+									AccountName: "Bob Jones",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A002",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Transfer{
+									TransactionID: "T001",
+									FromAccountID: "A001",
+									ToAccountID:   "A002",
+									Amount:        100,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.TransferDeclined{
+									TransactionID: "T001",
+									FromAccountID: "A001",
+									ToAccountID:   "A002",
+									Amount:        100,
+									Reason:        messages.AccountClosed,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines the balance swept from another closed account, which is returned to it",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A002",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A002",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID:    "X002",
+									AccountID:        "A001",
+									SweepToAccountID: "A002",
+								},
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.AccountCreditDeclined{
+										TransactionID:   "X002",
+										AccountID:       "A002",
+										TransactionType: messages.AccountClosure,
+										Amount:          500,
+										Reason:          messages.AccountClosed,
+									},
+								),
+								ToRecordEvent(
+									&events.AccountCredited{
+										TransactionID:   "X002",
+										AccountID:       "A001",
+										TransactionType: messages.AccountClosure,
+										Amount:          500,
+									},
+								),
+							),
+						)
+				},
+			)
+		},
+	)
+}
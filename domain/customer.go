@@ -10,7 +10,8 @@ import (
 type customer struct {
 	dogma.NoSnapshotBehavior
 
-	Name string
+	Name           string
+	HasCredentials bool
 }
 
 func (c *customer) AggregateInstanceDescription() string {
@@ -28,6 +29,41 @@ func (c *customer) Acquire(s dogma.AggregateCommandScope[*customer], m *commands
 		CustomerName: m.CustomerName,
 		AccountID:    m.AccountID,
 		AccountName:  m.AccountName,
+		AccountType:  m.AccountType,
+		MaturityDate: m.MaturityDate,
+	})
+}
+
+func (c *customer) SetCredentials(s dogma.AggregateCommandScope[*customer], m *commands.SetCustomerCredentials) {
+	if c.Name == "" {
+		s.Log("customer has not been acquired")
+		return
+	}
+
+	// Credentials are only set once, when the customer signs up. A repeated
+	// signup must not be able to replace them, so the password can only be
+	// changed by a ChangeCustomerPassword command.
+	if c.HasCredentials {
+		s.Log("customer's credentials have already been set")
+		return
+	}
+
+	s.RecordEvent(&events.CustomerCredentialsSet{
+		CustomerID:   m.CustomerID,
+		Username:     m.Username,
+		PasswordHash: m.PasswordHash,
+	})
+}
+
+func (c *customer) ChangePassword(s dogma.AggregateCommandScope[*customer], m *commands.ChangeCustomerPassword) {
+	if !c.HasCredentials {
+		s.Log("customer's credentials have not been set")
+		return
+	}
+
+	s.RecordEvent(&events.CustomerPasswordChanged{
+		CustomerID:   m.CustomerID,
+		PasswordHash: m.PasswordHash,
 	})
 }
 
@@ -35,6 +71,8 @@ func (c *customer) ApplyEvent(m dogma.Event) {
 	switch m := m.(type) {
 	case *events.CustomerAcquired:
 		c.Name = m.CustomerName
+	case *events.CustomerCredentialsSet:
+		c.HasCredentials = true
 	}
 }
 
@@ -48,7 +86,11 @@ func (CustomerHandler) Configure(c dogma.AggregateConfigurer) {
 
 	c.Routes(
 		dogma.HandlesCommand[*commands.OpenAccountForNewCustomer](),
+		dogma.HandlesCommand[*commands.SetCustomerCredentials](),
+		dogma.HandlesCommand[*commands.ChangeCustomerPassword](),
 		dogma.RecordsEvent[*events.CustomerAcquired](),
+		dogma.RecordsEvent[*events.CustomerCredentialsSet](),
+		dogma.RecordsEvent[*events.CustomerPasswordChanged](),
 	)
 }
 
@@ -63,6 +105,10 @@ func (CustomerHandler) RouteCommandToInstance(m dogma.Command) string {
 	switch x := m.(type) {
 	case *commands.OpenAccountForNewCustomer:
 		return x.CustomerID
+	case *commands.SetCustomerCredentials:
+		return x.CustomerID
+	case *commands.ChangeCustomerPassword:
+		return x.CustomerID
 	default:
 		panic(dogma.UnexpectedMessage)
 	}
@@ -77,6 +123,10 @@ func (CustomerHandler) HandleCommand(
 	switch x := m.(type) {
 	case *commands.OpenAccountForNewCustomer:
 		c.Acquire(s, x)
+	case *commands.SetCustomerCredentials:
+		c.SetCredentials(s, x)
+	case *commands.ChangeCustomerPassword:
+		c.ChangePassword(s, x)
 	default:
 		panic(dogma.UnexpectedMessage)
 	}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
 	"github.com/dogmatiq/example/messages/commands"
 	"github.com/dogmatiq/example/messages/events"
 	. "github.com/dogmatiq/testkit"
@@ -24,6 +25,7 @@ func Test_OpenAccountForNewCustomer(t *testing.T) {
 									CustomerName: "Bob Jones",
 									AccountID:    "A001",
 									AccountName:  "Bob Jones",
+									AccountType:  messages.Everyday,
 								},
 							),
 							ToRecordEvent(
@@ -32,6 +34,7 @@ func Test_OpenAccountForNewCustomer(t *testing.T) {
 									CustomerName: "Bob Jones",
 									AccountID:    "A001",
 									AccountName:  "Bob Jones",
+									AccountType:  messages.Everyday,
 								},
 							),
 						)
@@ -51,6 +54,7 @@ func Test_OpenAccountForNewCustomer(t *testing.T) {
 						CustomerName: "Bob Jones",
 						AccountID:    "A001",
 						AccountName:  "Bob Jones",
+						AccountType:  messages.Everyday,
 					}
 
 					Begin(t, &example.App{}).
@@ -68,3 +72,190 @@ func Test_OpenAccountForNewCustomer(t *testing.T) {
 		},
 	)
 }
+
+func Test_SetCustomerCredentials(t *testing.T) {
+	t.Run(
+		"when the customer exists",
+		func(t *testing.T) {
+			t.Run(
+				"it sets the customer's credentials",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccountForNewCustomer{
+									CustomerID:   "C001",
+									CustomerName: "Bob Jones",
+									AccountID:    "A001",
+									AccountName:  "Bob Jones",
+									AccountType:  messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.SetCustomerCredentials{
+									CustomerID:   "C001",
+									Username:     "bob",
+									PasswordHash: "<hash>",
+								},
+							),
+							ToRecordEvent(
+								&events.CustomerCredentialsSet{
+									CustomerID:   "C001",
+									Username:     "bob",
+									PasswordHash: "<hash>",
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the customer's credentials have already been set",
+		func(t *testing.T) {
+			t.Run(
+				"it does not replace them",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccountForNewCustomer{
+									CustomerID:   "C001",
+									CustomerName: "Bob Jones",
+									AccountID:    "A001",
+									AccountName:  "Bob Jones",
+									AccountType:  messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetCustomerCredentials{
+									CustomerID:   "C001",
+									Username:     "bob",
+									PasswordHash: "<hash>",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.SetCustomerCredentials{
+									CustomerID:   "C001",
+									Username:     "mallory",
+									PasswordHash: "<other hash>",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.CustomerCredentialsSet{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the customer does not exist",
+		func(t *testing.T) {
+			t.Run(
+				"it does not set the customer's credentials",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Expect(
+							ExecuteCommand(
+								&commands.SetCustomerCredentials{
+									CustomerID:   "C001",
+									Username:     "bob",
+									PasswordHash: "<hash>",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.CustomerCredentialsSet{}),
+							),
+						)
+				},
+			)
+		},
+	)
+}
+
+func Test_ChangeCustomerPassword(t *testing.T) {
+	t.Run(
+		"when the customer's credentials have been set",
+		func(t *testing.T) {
+			t.Run(
+				"it changes the customer's password",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccountForNewCustomer{
+									CustomerID:   "C001",
+									CustomerName: "Bob Jones",
+									AccountID:    "A001",
+									AccountName:  "Bob Jones",
+									AccountType:  messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetCustomerCredentials{
+									CustomerID:   "C001",
+									Username:     "bob",
+									PasswordHash: "<hash>",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.ChangeCustomerPassword{
+									CustomerID:   "C001",
+									PasswordHash: "<new hash>",
+								},
+							),
+							ToRecordEvent(
+								&events.CustomerPasswordChanged{
+									CustomerID:   "C001",
+									PasswordHash: "<new hash>",
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the customer's credentials have not been set",
+		func(t *testing.T) {
+			t.Run(
+				"it does not change the customer's password",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccountForNewCustomer{
+									CustomerID:   "C001",
+									CustomerName: "Bob Jones",
+									AccountID:    "A001",
+									AccountName:  "Bob Jones",
+									AccountType:  messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.ChangeCustomerPassword{
+									CustomerID:   "C001",
+									PasswordHash: "<new hash>",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.CustomerPasswordChanged{}),
+							),
+						)
+				},
+			)
+		},
+	)
+}
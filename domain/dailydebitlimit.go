@@ -9,9 +9,6 @@ import (
 	"github.com/dogmatiq/example/messages/events"
 )
 
-// Limit amount, in cents.
-const maximumDailyDebitLimit = 900000
-
 // dailyDebitLimit is the aggregate root for an account daily debit limit
 // policy.
 type dailyDebitLimit struct {
@@ -19,18 +16,22 @@ type dailyDebitLimit struct {
 
 	// TotalDebitsForDay is the total of all debits for the day, in cents.
 	TotalDebitsForDay int64
+
+	// DailyLimit is the account's daily debit limit as of the most recent
+	// debit, in cents.
+	DailyLimit int64
 }
 
 func (d *dailyDebitLimit) AggregateInstanceDescription() string {
 	return fmt.Sprintf(
 		"%s/%s",
 		messages.FormatAmount(d.TotalDebitsForDay),
-		messages.FormatAmount(maximumDailyDebitLimit),
+		messages.FormatAmount(d.DailyLimit),
 	)
 }
 
 func (d *dailyDebitLimit) Consume(s dogma.AggregateCommandScope[*dailyDebitLimit], m *commands.ConsumeDailyDebitLimit) {
-	if d.wouldExceedLimit(m.Amount) {
+	if d.wouldExceedLimit(m.Amount, m.DailyLimit) {
 		s.RecordEvent(&events.DailyDebitLimitExceeded{
 			TransactionID:     m.TransactionID,
 			AccountID:         m.AccountID,
@@ -38,7 +39,7 @@ func (d *dailyDebitLimit) Consume(s dogma.AggregateCommandScope[*dailyDebitLimit
 			Amount:            m.Amount,
 			Date:              m.Date,
 			TotalDebitsForDay: d.TotalDebitsForDay,
-			DailyLimit:        maximumDailyDebitLimit,
+			DailyLimit:        m.DailyLimit,
 		})
 	} else {
 		s.RecordEvent(&events.DailyDebitLimitConsumed{
@@ -48,19 +49,37 @@ func (d *dailyDebitLimit) Consume(s dogma.AggregateCommandScope[*dailyDebitLimit
 			Amount:            m.Amount,
 			Date:              m.Date,
 			TotalDebitsForDay: d.TotalDebitsForDay + m.Amount,
-			DailyLimit:        maximumDailyDebitLimit,
+			DailyLimit:        m.DailyLimit,
 		})
 	}
 }
 
-func (d *dailyDebitLimit) wouldExceedLimit(amount int64) bool {
-	return d.TotalDebitsForDay+amount > maximumDailyDebitLimit
+// Restore gives back an amount that was consumed by a debit that did not go
+// ahead, such as a card hold that was released or expired.
+func (d *dailyDebitLimit) Restore(s dogma.AggregateCommandScope[*dailyDebitLimit], m *commands.RestoreDailyDebitLimit) {
+	s.RecordEvent(&events.DailyDebitLimitRestored{
+		TransactionID:     m.TransactionID,
+		AccountID:         m.AccountID,
+		DebitType:         m.DebitType,
+		Amount:            m.Amount,
+		Date:              m.Date,
+		TotalDebitsForDay: max(d.TotalDebitsForDay-m.Amount, 0),
+	})
+}
+
+func (d *dailyDebitLimit) wouldExceedLimit(amount, limit int64) bool {
+	return d.TotalDebitsForDay+amount > limit
 }
 
 func (d *dailyDebitLimit) ApplyEvent(m dogma.Event) {
 	switch x := m.(type) {
 	case *events.DailyDebitLimitConsumed:
 		d.TotalDebitsForDay = x.TotalDebitsForDay
+		d.DailyLimit = x.DailyLimit
+	case *events.DailyDebitLimitExceeded:
+		d.DailyLimit = x.DailyLimit
+	case *events.DailyDebitLimitRestored:
+		d.TotalDebitsForDay = x.TotalDebitsForDay
 	}
 }
 
@@ -68,7 +87,9 @@ func (d *dailyDebitLimit) ApplyEvent(m dogma.Event) {
 // debit limit policy.
 //
 // It centralizes all debits that are applied to an account over a calendar day
-// in order to enforce a policy of limited daily debits.
+// in order to enforce a policy of limited daily debits. The limit itself is
+// owned by [AccountHandler] and supplied with each command, so that changes to
+// an account's limit take effect for subsequent debits on the same day.
 type DailyDebitLimitHandler struct{}
 
 // New returns a new daily debit limit instance.
@@ -83,8 +104,10 @@ func (DailyDebitLimitHandler) Configure(c dogma.AggregateConfigurer) {
 
 	c.Routes(
 		dogma.HandlesCommand[*commands.ConsumeDailyDebitLimit](),
+		dogma.HandlesCommand[*commands.RestoreDailyDebitLimit](),
 		dogma.RecordsEvent[*events.DailyDebitLimitConsumed](),
 		dogma.RecordsEvent[*events.DailyDebitLimitExceeded](),
+		dogma.RecordsEvent[*events.DailyDebitLimitRestored](),
 	)
 }
 
@@ -94,6 +117,8 @@ func (DailyDebitLimitHandler) RouteCommandToInstance(m dogma.Command) string {
 	switch x := m.(type) {
 	case *commands.ConsumeDailyDebitLimit:
 		return fmt.Sprintf("%s:%s", x.Date, x.AccountID)
+	case *commands.RestoreDailyDebitLimit:
+		return fmt.Sprintf("%s:%s", x.Date, x.AccountID)
 	default:
 		panic(dogma.UnexpectedMessage)
 	}
@@ -108,6 +133,8 @@ func (DailyDebitLimitHandler) HandleCommand(
 	switch x := m.(type) {
 	case *commands.ConsumeDailyDebitLimit:
 		d.Consume(s, x)
+	case *commands.RestoreDailyDebitLimit:
+		d.Restore(s, x)
 	default:
 		panic(dogma.UnexpectedMessage)
 	}
@@ -0,0 +1,214 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_ChangeDailyDebitLimit(t *testing.T) {
+	t.Run(
+		"when the account exists",
+		func(t *testing.T) {
+			t.Run(
+				"it changes the daily debit limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.ChangeDailyDebitLimit{
+									AccountID:       "A001",
+									DailyDebitLimit: 1000,
+								},
+							),
+							ToRecordEvent(
+								&events.DailyDebitLimitChanged{
+									AccountID:               "A001",
+									PreviousDailyDebitLimit: expectedDailyDebitLimit,
+									DailyDebitLimit:         1000,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it does nothing if the limit is unchanged",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.ChangeDailyDebitLimit{
+									AccountID:       "A001",
+									DailyDebitLimit: expectedDailyDebitLimit,
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.DailyDebitLimitChanged{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account does not exist",
+		func(t *testing.T) {
+			t.Run(
+				"it does not change the daily debit limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Expect(
+							ExecuteCommand(
+								&commands.ChangeDailyDebitLimit{
+									AccountID:       "A001",
+									DailyDebitLimit: 1000,
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.DailyDebitLimitChanged{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the limit has been lowered",
+		func(t *testing.T) {
+			t.Run(
+				"it declines debits that exceed the new limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        5000,
+								},
+							),
+							ExecuteCommand(
+								&commands.ChangeDailyDebitLimit{
+									AccountID:       "A001",
+									DailyDebitLimit: 1000,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        1001,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.DailyDebitLimitExceeded{
+									TransactionID:     "W001",
+									AccountID:         "A001",
+									DebitType:         messages.Withdrawal,
+									Amount:            1001,
+									Date:              "2001-02-03",
+									TotalDebitsForDay: 0,
+									DailyLimit:        1000,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the limit has been raised",
+		func(t *testing.T) {
+			t.Run(
+				"it approves debits that exceed the default limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        expectedDailyDebitLimit * 2,
+								},
+							),
+							ExecuteCommand(
+								&commands.ChangeDailyDebitLimit{
+									AccountID:       "A001",
+									DailyDebitLimit: expectedDailyDebitLimit * 2,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        expectedDailyDebitLimit + 1,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.DailyDebitLimitConsumed{
+									TransactionID:     "W001",
+									AccountID:         "A001",
+									DebitType:         messages.Withdrawal,
+									Amount:            expectedDailyDebitLimit + 1,
+									Date:              "2001-02-03",
+									TotalDebitsForDay: expectedDailyDebitLimit + 1,
+									DailyLimit:        expectedDailyDebitLimit * 2,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+}
@@ -24,9 +24,12 @@ func (DepositProcessHandler) Configure(c dogma.ProcessConfigurer) {
 	c.Routes(
 		dogma.HandlesEvent[*events.DepositStarted](),
 		dogma.HandlesEvent[*events.AccountCredited](),
+		dogma.HandlesEvent[*events.AccountCreditDeclined](),
 		dogma.HandlesEvent[*events.DepositApproved](),
+		dogma.HandlesEvent[*events.DepositDeclined](),
 		dogma.ExecutesCommand[*commands.CreditAccount](),
 		dogma.ExecutesCommand[*commands.ApproveDeposit](),
+		dogma.ExecutesCommand[*commands.DeclineDeposit](),
 	)
 }
 
@@ -41,8 +44,12 @@ func (DepositProcessHandler) RouteEventToInstance(
 		return x.TransactionID, true, nil
 	case *events.AccountCredited:
 		return x.TransactionID, x.TransactionType == messages.Deposit, nil
+	case *events.AccountCreditDeclined:
+		return x.TransactionID, x.TransactionType == messages.Deposit, nil
 	case *events.DepositApproved:
 		return x.TransactionID, true, nil
+	case *events.DepositDeclined:
+		return x.TransactionID, true, nil
 	default:
 		panic(dogma.UnexpectedMessage)
 	}
@@ -71,7 +78,16 @@ func (DepositProcessHandler) HandleEvent(
 			Amount:        x.Amount,
 		})
 
-	case *events.DepositApproved:
+	case *events.AccountCreditDeclined:
+		s.ExecuteCommand(&commands.DeclineDeposit{
+			TransactionID: x.TransactionID,
+			AccountID:     x.AccountID,
+			Amount:        x.Amount,
+			Reason:        x.Reason,
+		})
+
+	case *events.DepositApproved,
+		*events.DepositDeclined:
 		s.End()
 
 	default:
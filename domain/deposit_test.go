@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
 	"github.com/dogmatiq/example/messages/commands"
 	"github.com/dogmatiq/example/messages/events"
 	. "github.com/dogmatiq/testkit"
@@ -24,6 +25,7 @@ func Test_Deposit(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 						).
@@ -65,4 +67,46 @@ func Test_Deposit(t *testing.T) {
 			)
 		},
 	)
+
+	t.Run(
+		"when the deposit has already started",
+		func(t *testing.T) {
+			t.Run(
+				"it does not deposit the funds again",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "T001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "T001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.DepositStarted{}),
+								ToRecordEventOfType(&events.DepositApproved{}),
+							),
+						)
+				},
+			)
+		},
+	)
 }
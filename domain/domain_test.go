@@ -1,4 +1,14 @@
 package domain_test
 
-// The current expected daily debit limit.
+import "github.com/dogmatiq/example/messages"
+
+// The expected daily debit limit for newly opened accounts.
 const expectedDailyDebitLimit = 900000
+
+// thirdPartyPayee is the payee used by tests that transfer money to an account
+// held at a third-party bank. Its account ID is "06200012345674".
+var thirdPartyPayee = &messages.ThirdPartyPayee{
+	BSB:           "062-000",
+	AccountNumber: "12345674",
+	AccountName:   "Anna Smith",
+}
@@ -0,0 +1,373 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_FreezeAccount(t *testing.T) {
+	t.Run(
+		"when the account is not frozen",
+		func(t *testing.T) {
+			t.Run(
+				"it freezes the account",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.FreezeAccount{
+									AccountID:  "A001",
+									FreezeType: messages.DebitFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+							ToRecordEvent(
+								&events.AccountFrozen{
+									AccountID:  "A001",
+									FreezeType: messages.DebitFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account is frozen",
+		func(t *testing.T) {
+			t.Run(
+				"it declines withdrawals",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							ExecuteCommand(
+								&commands.FreezeAccount{
+									AccountID:  "A001",
+									FreezeType: messages.DebitFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        100,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.WithdrawalDeclined{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        100,
+									Reason:        messages.AccountFrozen,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines the account closure",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.FreezeAccount{
+									AccountID:  "A001",
+									FreezeType: messages.DebitFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.AccountClosureDeclined{
+									TransactionID: "X001",
+									AccountID:     "A001",
+									Reason:        messages.AccountIsFrozen,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it accepts deposits if only debits are frozen",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.FreezeAccount{
+									AccountID:  "A001",
+									FreezeType: messages.DebitFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							ToRecordEvent(
+								&events.DepositApproved{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it queues deposits if the account is fully frozen",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.FreezeAccount{
+									AccountID:  "A001",
+									FreezeType: messages.FullFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.AccountCreditQueued{
+										TransactionID:   "D001",
+										AccountID:       "A001",
+										TransactionType: messages.Deposit,
+										Amount:          500,
+									},
+								),
+								NoneOf(
+									ToRecordEventOfType(&events.DepositApproved{}),
+								),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it does nothing if the freeze type is unchanged",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.FreezeAccount{
+									AccountID:  "A001",
+									FreezeType: messages.DebitFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.FreezeAccount{
+									AccountID:  "A001",
+									FreezeType: messages.DebitFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.AccountFrozen{}),
+							),
+						)
+				},
+			)
+		},
+	)
+}
+
+func Test_UnfreezeAccount(t *testing.T) {
+	t.Run(
+		"when the account is frozen",
+		func(t *testing.T) {
+			t.Run(
+				"it unfreezes the account and applies queued credits",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.FreezeAccount{
+									AccountID:  "A001",
+									FreezeType: messages.FullFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.UnfreezeAccount{
+									AccountID: "A001",
+									Reason:    "investigation complete",
+									Operator:  "j.citizen",
+								},
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.AccountUnfrozen{
+										AccountID: "A001",
+										Reason:    "investigation complete",
+										Operator:  "j.citizen",
+									},
+								),
+								ToRecordEvent(
+									&events.DepositApproved{
+										TransactionID: "D001",
+										AccountID:     "A001",
+										Amount:        500,
+									},
+								),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account is not frozen",
+		func(t *testing.T) {
+			t.Run(
+				"it does nothing",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.UnfreezeAccount{
+									AccountID: "A001",
+									Reason:    "investigation complete",
+									Operator:  "j.citizen",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.AccountUnfrozen{}),
+							),
+						)
+				},
+			)
+		},
+	)
+}
@@ -0,0 +1,296 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+const (
+	// holdExpiryPeriod is the length of time after a debit is authorized that
+	// the hold on the funds is released if it has not been captured.
+	holdExpiryPeriod = 7 * 24 * time.Hour
+)
+
+func init() {
+	dogma.RegisterDeadline[*HoldExpiryDue]("4e8b1d27-93c5-4a6f-b2e0-d5a71c9f3e86")
+}
+
+// holdExpiryProcess is the process root for the expiry of a hold.
+type holdExpiryProcess struct {
+	HoldID    string
+	AccountID string
+	Amount    int64
+
+	// Date is the date of the daily debit limit that the hold is applied to.
+	Date string
+
+	// LimitChecked is true once the hold has been applied to the account's
+	// daily debit limit, and LimitConsumed is true if it consumed part of the
+	// limit, which must be restored if the hold is not captured.
+	LimitChecked  bool
+	LimitConsumed bool
+
+	// Released is true if the hold was released or expired before it was
+	// applied to the daily debit limit.
+	Released bool
+}
+
+// ProcessInstanceDescription returns a human-readable description of the hold
+// expiry's current state.
+func (p *holdExpiryProcess) ProcessInstanceDescription(ended bool) string {
+	if p.HoldID == "" {
+		return ""
+	}
+
+	if ended {
+		return fmt.Sprintf(
+			"hold of %s on account %s is no longer outstanding",
+			messages.FormatAmount(p.Amount),
+			p.AccountID,
+		)
+	}
+
+	return fmt.Sprintf(
+		"waiting for hold of %s on account %s to be captured",
+		messages.FormatAmount(p.Amount),
+		p.AccountID,
+	)
+}
+
+// MarshalBinary returns the holdExpiryProcess encoded as binary data.
+func (p *holdExpiryProcess) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// UnmarshalBinary decodes binary data into the holdExpiryProcess.
+func (p *holdExpiryProcess) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// HoldExpiryProcessHandler manages a hold from the time it is authorized until
+// it is captured or released.
+//
+// It applies the account's daily debit limit to each hold, releasing holds
+// that would exceed it, and expires holds that are not captured or released
+// within the hold expiry period. The amount of a hold that is released or
+// expires is restored to the daily debit limit.
+type HoldExpiryProcessHandler struct{}
+
+// New returns a new hold expiry instance.
+func (HoldExpiryProcessHandler) New() *holdExpiryProcess {
+	return &holdExpiryProcess{}
+}
+
+// Configure configures the behavior of the engine as it relates to this handler.
+func (HoldExpiryProcessHandler) Configure(c dogma.ProcessConfigurer) {
+	c.Identity("hold-expiry", "a7c3f0e2-58d9-4b16-8e4a-1f6b92d0c5e7")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.DebitAuthorized](),
+		dogma.HandlesEvent[*events.HoldCaptured](),
+		dogma.HandlesEvent[*events.HoldReleased](),
+		dogma.HandlesEvent[*events.HoldExpired](),
+		dogma.HandlesEvent[*events.DailyDebitLimitConsumed](),
+		dogma.HandlesEvent[*events.DailyDebitLimitExceeded](),
+		dogma.ExecutesCommand[*commands.ConsumeDailyDebitLimit](),
+		dogma.ExecutesCommand[*commands.RestoreDailyDebitLimit](),
+		dogma.ExecutesCommand[*commands.ReleaseHold](),
+		dogma.ExecutesCommand[*commands.ExpireHold](),
+		dogma.SchedulesDeadline[*HoldExpiryDue](),
+	)
+}
+
+// RouteEventToInstance returns the ID of the process instance that is targeted
+// by m.
+func (HoldExpiryProcessHandler) RouteEventToInstance(
+	_ context.Context,
+	m dogma.Event,
+) (string, bool, error) {
+	switch x := m.(type) {
+	case *events.DebitAuthorized:
+		return x.HoldID, true, nil
+	case *events.HoldCaptured:
+		return x.HoldID, true, nil
+	case *events.HoldReleased:
+		return x.HoldID, true, nil
+	case *events.HoldExpired:
+		return x.HoldID, true, nil
+	case *events.DailyDebitLimitConsumed:
+		return x.TransactionID, x.DebitType == messages.CardPayment, nil
+	case *events.DailyDebitLimitExceeded:
+		return x.TransactionID, x.DebitType == messages.CardPayment, nil
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// HandleEvent handles an event message that has been routed to this handler.
+func (HoldExpiryProcessHandler) HandleEvent(
+	_ context.Context,
+	p *holdExpiryProcess,
+	s dogma.ProcessEventScope[*holdExpiryProcess],
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.DebitAuthorized:
+		date := messages.DailyDebitLimitDate(x.ScheduledTime)
+
+		s.Mutate(func(p *holdExpiryProcess) {
+			p.HoldID = x.HoldID
+			p.AccountID = x.AccountID
+			p.Amount = x.Amount
+			p.Date = date
+		})
+
+		s.ScheduleDeadline(
+			&HoldExpiryDue{
+				HoldID:    x.HoldID,
+				AccountID: x.AccountID,
+			},
+			s.RecordedAt().Add(holdExpiryPeriod),
+		)
+
+		s.ExecuteCommand(&commands.ConsumeDailyDebitLimit{
+			TransactionID: x.HoldID,
+			AccountID:     x.AccountID,
+			DebitType:     messages.CardPayment,
+			Amount:        x.Amount,
+			Date:          date,
+			DailyLimit:    x.DailyDebitLimit,
+		})
+
+	case *events.DailyDebitLimitConsumed:
+		if p.Released {
+			restoreHoldDailyDebitLimit(p, s)
+			s.End()
+			break
+		}
+
+		s.Mutate(func(p *holdExpiryProcess) {
+			p.LimitChecked = true
+			p.LimitConsumed = true
+		})
+
+	case *events.DailyDebitLimitExceeded:
+		if p.Released {
+			s.End()
+			break
+		}
+
+		s.Mutate(func(p *holdExpiryProcess) {
+			p.LimitChecked = true
+		})
+
+		s.ExecuteCommand(&commands.ReleaseHold{
+			HoldID:    x.TransactionID,
+			AccountID: x.AccountID,
+		})
+
+	case *events.HoldCaptured:
+		s.End()
+
+	case *events.HoldReleased, *events.HoldExpired:
+		if !p.LimitChecked {
+			// Wait for the outcome of the daily debit limit check so that any
+			// amount it consumes can be restored.
+			s.Mutate(func(p *holdExpiryProcess) {
+				p.Released = true
+			})
+			break
+		}
+
+		if p.LimitConsumed {
+			restoreHoldDailyDebitLimit(p, s)
+		}
+
+		s.End()
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// restoreHoldDailyDebitLimit gives back the amount of a hold that was not
+// captured to the account's daily debit limit.
+func restoreHoldDailyDebitLimit(
+	p *holdExpiryProcess,
+	s dogma.ProcessEventScope[*holdExpiryProcess],
+) {
+	s.ExecuteCommand(&commands.RestoreDailyDebitLimit{
+		TransactionID: p.HoldID,
+		AccountID:     p.AccountID,
+		DebitType:     messages.CardPayment,
+		Amount:        p.Amount,
+		Date:          p.Date,
+	})
+}
+
+// HandleDeadline handles a deadline message that has been routed to this handler.
+func (HoldExpiryProcessHandler) HandleDeadline(
+	_ context.Context,
+	_ *holdExpiryProcess,
+	s dogma.ProcessDeadlineScope[*holdExpiryProcess],
+	m dogma.Deadline,
+) error {
+	switch x := m.(type) {
+	case *HoldExpiryDue:
+		s.ExecuteCommand(&commands.ExpireHold{
+			HoldID:    x.HoldID,
+			AccountID: x.AccountID,
+		})
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// HoldExpiryDue is a deadline message notifying that a hold has reached the end
+// of the hold expiry period.
+type HoldExpiryDue struct {
+	HoldID    string
+	AccountID string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *HoldExpiryDue) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s on account %s is due to expire",
+		m.HoldID,
+		m.AccountID,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *HoldExpiryDue) Validate(dogma.DeadlineValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("HoldExpiryDue must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("HoldExpiryDue must not have an empty account ID")
+	}
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *HoldExpiryDue) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *HoldExpiryDue) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
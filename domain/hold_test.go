@@ -0,0 +1,735 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_Hold(t *testing.T) {
+	// openAccount returns the actions that open the account used by each test
+	// and deposit balance into it.
+	openAccount := func(balance int64) []Action {
+		return []Action{
+			ExecuteCommand(
+				&commands.OpenAccount{
+					CustomerID:  "C001",
+					AccountID:   "A001",
+					AccountName: "Anna Smith",
+					AccountType: messages.Everyday,
+				},
+			),
+			ExecuteCommand(
+				&commands.Deposit{
+					TransactionID: "D001",
+					AccountID:     "A001",
+					Amount:        balance,
+				},
+			),
+		}
+	}
+
+	t.Run(
+		"when a debit is authorized",
+		func(t *testing.T) {
+			t.Run(
+				"it holds the funds if the available balance is sufficient",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Expect(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    500,
+								},
+							),
+							ToRecordEvent(
+								&events.DebitAuthorized{
+									HoldID:          "H001",
+									AccountID:       "A001",
+									Merchant:        "Corner Cafe",
+									Amount:          500,
+									DailyDebitLimit: 900000,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines the authorization if the available balance is insufficient",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    300,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H002",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    300,
+								},
+							),
+							ToRecordEvent(
+								&events.DebitAuthorizationDeclined{
+									HoldID:    "H002",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    300,
+									Reason:    messages.InsufficientFunds,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines withdrawals that exceed the available balance",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    300,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "T001",
+									AccountID:     "A001",
+									Amount:        300,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.AccountDebitDeclined{
+									TransactionID:   "T001",
+									AccountID:       "A001",
+									TransactionType: messages.Withdrawal,
+									Amount:          300,
+									Reason:          messages.InsufficientFunds,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines the authorization if the account is frozen",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.FreezeAccount{
+									AccountID:  "A001",
+									FreezeType: messages.DebitFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    300,
+								},
+							),
+							ToRecordEvent(
+								&events.DebitAuthorizationDeclined{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    300,
+									Reason:    messages.AccountFrozen,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines the authorization if the savings withdrawal limit has been reached",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W002",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 2, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W003",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W004",
+									AccountID:     "A001",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 4, 0, 0, 0, 0, time.UTC),
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:        "H001",
+									AccountID:     "A001",
+									Merchant:      "Corner Cafe",
+									Amount:        10,
+									ScheduledTime: time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.DebitAuthorizationDeclined{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    10,
+									Reason:    messages.WithdrawalLimitExceeded,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it releases the hold if it exceeds the daily debit limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.ChangeDailyDebitLimit{
+									AccountID:       "A001",
+									DailyDebitLimit: 200,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:        "H001",
+									AccountID:     "A001",
+									Merchant:      "Corner Cafe",
+									Amount:        300,
+									ScheduledTime: time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.DailyDebitLimitExceeded{
+										TransactionID:     "H001",
+										AccountID:         "A001",
+										DebitType:         messages.CardPayment,
+										Amount:            300,
+										Date:              "2001-02-01",
+										TotalDebitsForDay: 0,
+										DailyLimit:        200,
+									},
+								),
+								ToRecordEvent(
+									&events.HoldReleased{
+										HoldID:    "H001",
+										AccountID: "A001",
+										Amount:    300,
+									},
+								),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it does not authorize a debit from an account that has not been opened",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Expect(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    300,
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.DebitAuthorized{}),
+								ToRecordEventOfType(&events.DebitAuthorizationDeclined{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when a hold is captured",
+		func(t *testing.T) {
+			t.Run(
+				"it debits the held funds",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    300,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CaptureHold{
+									HoldID:    "H001",
+									AccountID: "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.HoldCaptured{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    300,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H002",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    200,
+								},
+							),
+							ToRecordEvent(
+								&events.DebitAuthorized{
+									HoldID:          "H002",
+									AccountID:       "A001",
+									Merchant:        "Corner Cafe",
+									Amount:          200,
+									DailyDebitLimit: 900000,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines the capture if the account is frozen",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    300,
+								},
+							),
+							ExecuteCommand(
+								&commands.FreezeAccount{
+									AccountID:  "A001",
+									FreezeType: messages.DebitFreeze,
+									Reason:     "suspected fraud",
+									Operator:   "j.citizen",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CaptureHold{
+									HoldID:    "H001",
+									AccountID: "A001",
+								},
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.HoldCaptureDeclined{
+										HoldID:    "H001",
+										AccountID: "A001",
+										Merchant:  "Corner Cafe",
+										Amount:    300,
+										Reason:    messages.AccountFrozen,
+									},
+								),
+								NoneOf(
+									ToRecordEventOfType(&events.HoldCaptured{}),
+								),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when a hold is released",
+		func(t *testing.T) {
+			t.Run(
+				"it makes the held funds available again",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.ReleaseHold{
+									HoldID:    "H001",
+									AccountID: "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.HoldReleased{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Amount:    500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H002",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    500,
+								},
+							),
+							ToRecordEventOfType(&events.DebitAuthorized{}),
+						)
+				},
+			)
+
+			t.Run(
+				"it restores the held amount to the daily debit limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.ChangeDailyDebitLimit{
+									AccountID:       "A001",
+									DailyDebitLimit: 400,
+								},
+							),
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:        "H001",
+									AccountID:     "A001",
+									Merchant:      "Corner Cafe",
+									Amount:        300,
+									ScheduledTime: time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC),
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.ReleaseHold{
+									HoldID:    "H001",
+									AccountID: "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.DailyDebitLimitRestored{
+									TransactionID:     "H001",
+									AccountID:         "A001",
+									DebitType:         messages.CardPayment,
+									Amount:            300,
+									Date:              "2001-02-01",
+									TotalDebitsForDay: 0,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:        "H002",
+									AccountID:     "A001",
+									Merchant:      "Corner Cafe",
+									Amount:        300,
+									ScheduledTime: time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.HoldReleased{}),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it can no longer be captured",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    500,
+								},
+							),
+							ExecuteCommand(
+								&commands.ReleaseHold{
+									HoldID:    "H001",
+									AccountID: "A001",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CaptureHold{
+									HoldID:    "H001",
+									AccountID: "A001",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.HoldCaptured{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when a hold is not captured in time",
+		func(t *testing.T) {
+			t.Run(
+				"it expires the hold",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    500,
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 8, 11, 22, 32, 0, time.UTC)),
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.HoldExpired{}),
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 8, 11, 22, 33, 0, time.UTC)),
+							),
+							ToRecordEvent(
+								&events.HoldExpired{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Amount:    500,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it restores the held amount to the daily debit limit",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:        "H001",
+									AccountID:     "A001",
+									Merchant:      "Corner Cafe",
+									Amount:        500,
+									ScheduledTime: time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ByDuration(7*24*time.Hour),
+							),
+							ToRecordEvent(
+								&events.DailyDebitLimitRestored{
+									TransactionID:     "H001",
+									AccountID:         "A001",
+									DebitType:         messages.CardPayment,
+									Amount:            500,
+									Date:              "2001-02-01",
+									TotalDebitsForDay: 0,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it does not expire a hold that has been captured",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    500,
+								},
+							),
+							ExecuteCommand(
+								&commands.CaptureHold{
+									HoldID:    "H001",
+									AccountID: "A001",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ByDuration(8*24*time.Hour),
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.HoldExpired{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when an account has outstanding holds",
+		func(t *testing.T) {
+			t.Run(
+				"it can not be closed",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(openAccount(500)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AuthorizeDebit{
+									HoldID:    "H001",
+									AccountID: "A001",
+									Merchant:  "Corner Cafe",
+									Amount:    500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "T001",
+									AccountID:     "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.AccountClosureDeclined{
+									TransactionID: "T001",
+									AccountID:     "A001",
+									Reason:        messages.AccountHasPendingFunds,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+}
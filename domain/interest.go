@@ -0,0 +1,311 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/google/uuid"
+)
+
+func init() {
+	dogma.RegisterDeadline[*InterestAccrualDue]("9c0b3a6e-54f1-4c1d-b0b4-7a2e5d8f3c61")
+}
+
+// interestNamespace is the namespace used to derive the transaction IDs of
+// interest payments.
+var interestNamespace = uuid.MustParse("c5d3f0a2-8e41-4b7a-9d6c-2f1e0b7a4c93")
+
+// interestDivisor converts the sum of each day's end-of-day balance (in cents)
+// multiplied by the annual interest rate (in basis points) to an amount of
+// interest in cents.
+const interestDivisor = 10000 * 365
+
+// interestAccrualProcess is the process root for accruing interest on an
+// account.
+type interestAccrualProcess struct {
+	AccountID string
+	AccrualID string
+
+	// Accrued is the sum of each day's end-of-day balance multiplied by the
+	// interest rate for that day, since interest was last paid, including any
+	// fraction of a cent that was not paid.
+	Accrued int64
+}
+
+// ProcessInstanceDescription returns a human-readable description of the
+// interest accrual's current state.
+func (p *interestAccrualProcess) ProcessInstanceDescription(ended bool) string {
+	if p.AccountID == "" {
+		return ""
+	}
+
+	if ended {
+		return fmt.Sprintf("stopped accruing interest on account %s", p.AccountID)
+	}
+
+	return fmt.Sprintf(
+		"accruing interest on account %s, %s accrued this month",
+		p.AccountID,
+		messages.FormatAmount(p.Accrued/interestDivisor),
+	)
+}
+
+// MarshalBinary returns the interestAccrualProcess encoded as binary data.
+func (p *interestAccrualProcess) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// UnmarshalBinary decodes binary data into the interestAccrualProcess.
+func (p *interestAccrualProcess) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// InterestAccrualProcessHandler manages the process of accruing interest on an
+// account's balance.
+//
+// Once an interest rate is set on a savings account, interest is accrued daily
+// on the end-of-day balance. The total is paid into the account on the last day of
+// each month. Interest is not accrued on a negative balance. Any interest
+// accrued during the month in which the account is closed is forfeited.
+//
+// Interest is paid in whole cents, rounded down. The fraction of a cent that
+// is not paid is carried over to the next month's payment.
+//
+// Each instance accrues interest from the time the rate is changed from zero
+// until it is changed back to zero, at which point the interest accrued so far
+// is paid, any remaining fraction of a cent is forfeited, and the instance
+// ends. Setting a rate again begins a new instance.
+type InterestAccrualProcessHandler struct{}
+
+// New returns a new interest accrual instance.
+func (InterestAccrualProcessHandler) New() *interestAccrualProcess {
+	return &interestAccrualProcess{}
+}
+
+// Configure configures the behavior of the engine as it relates to this handler.
+func (InterestAccrualProcessHandler) Configure(c dogma.ProcessConfigurer) {
+	c.Identity("interest-accrual", "6f2d8e1a-3b7c-4e95-a0d4-c81f5b2e9a37")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.InterestRateChanged](),
+		dogma.HandlesEvent[*events.InterestAccrued](),
+		dogma.HandlesEvent[*events.AccountClosed](),
+		dogma.ExecutesCommand[*commands.AccrueInterest](),
+		dogma.ExecutesCommand[*commands.CreditAccount](),
+		dogma.SchedulesDeadline[*InterestAccrualDue](),
+	)
+}
+
+// RouteEventToInstance returns the ID of the process instance that is targeted
+// by m.
+func (InterestAccrualProcessHandler) RouteEventToInstance(
+	_ context.Context,
+	m dogma.Event,
+) (string, bool, error) {
+	switch x := m.(type) {
+	case *events.InterestRateChanged:
+		return x.AccrualID, true, nil
+	case *events.InterestAccrued:
+		return x.AccrualID, true, nil
+	case *events.AccountClosed:
+		return x.InterestAccrualID, x.InterestAccrualID != "", nil
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// HandleEvent handles an event message that has been routed to this handler.
+func (InterestAccrualProcessHandler) HandleEvent(
+	_ context.Context,
+	p *interestAccrualProcess,
+	s dogma.ProcessEventScope[*interestAccrualProcess],
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.InterestRateChanged:
+		if x.InterestRate == 0 {
+			// the rate has been set back to zero, so interest stops accruing
+			// and the interest accrued so far is paid
+			if amount := p.Accrued / interestDivisor; amount > 0 {
+				s.ExecuteCommand(&commands.CreditAccount{
+					TransactionID:   InterestTransactionID(x.AccrualID, s.RecordedAt().UTC().Format(time.DateOnly)[:7]),
+					AccountID:       x.AccountID,
+					TransactionType: messages.Interest,
+					Amount:          amount,
+				})
+			}
+
+			s.End()
+			return nil
+		}
+
+		if p.AccountID != "" {
+			// interest is already being accrued, the new rate applies from
+			// the next accrual onwards
+			return nil
+		}
+
+		s.Mutate(func(p *interestAccrualProcess) {
+			p.AccountID = x.AccountID
+			p.AccrualID = x.AccrualID
+		})
+
+		// interest is first accrued at the end of the day on which the
+		// interest rate is set
+		endOfDay := s.RecordedAt().UTC().Truncate(24 * time.Hour)
+		s.ScheduleDeadline(
+			&InterestAccrualDue{
+				AccountID: x.AccountID,
+				AccrualID: x.AccrualID,
+				Date:      endOfDay.Format(time.DateOnly),
+			},
+			endOfDay.Add(24*time.Hour),
+		)
+
+	case *events.InterestAccrued:
+		if p.AccountID == "" {
+			// interest was not accrued by this process, so there is nothing
+			// to pay it into
+			s.End()
+			return nil
+		}
+
+		accrued := p.Accrued
+		if x.Balance > 0 {
+			accrued += x.Balance * x.InterestRate
+		}
+
+		if !isLastDayOfMonth(x.Date) {
+			s.Mutate(func(p *interestAccrualProcess) {
+				p.Accrued = accrued
+			})
+			return nil
+		}
+
+		// only whole cents are paid, the remainder is carried over to the
+		// next month
+		amount := accrued / interestDivisor
+
+		s.Mutate(func(p *interestAccrualProcess) {
+			p.Accrued = accrued - amount*interestDivisor
+		})
+
+		if amount > 0 {
+			s.ExecuteCommand(&commands.CreditAccount{
+				TransactionID:   InterestTransactionID(x.AccrualID, x.Date[:7]),
+				AccountID:       x.AccountID,
+				TransactionType: messages.Interest,
+				Amount:          amount,
+			})
+		}
+
+	case *events.AccountClosed:
+		s.End()
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// HandleDeadline handles a deadline message that has been routed to this handler.
+func (InterestAccrualProcessHandler) HandleDeadline(
+	_ context.Context,
+	_ *interestAccrualProcess,
+	s dogma.ProcessDeadlineScope[*interestAccrualProcess],
+	m dogma.Deadline,
+) error {
+	switch x := m.(type) {
+	case *InterestAccrualDue:
+		s.ExecuteCommand(&commands.AccrueInterest{
+			AccountID: x.AccountID,
+			AccrualID: x.AccrualID,
+			Date:      x.Date,
+		})
+
+		next := s.ScheduledFor().UTC().Truncate(24 * time.Hour)
+		s.ScheduleDeadline(
+			&InterestAccrualDue{
+				AccountID: x.AccountID,
+				AccrualID: x.AccrualID,
+				Date:      next.Format(time.DateOnly),
+			},
+			next.Add(24*time.Hour),
+		)
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// InterestTransactionID returns the ID of the transaction that pays the
+// interest accrued by an interest accrual during a month, in YYYY-MM format.
+// It is a UUID derived from the accrual ID and the month, so that at most one
+// payment is made for each.
+func InterestTransactionID(accrualID, month string) string {
+	return uuid.NewSHA1(
+		interestNamespace,
+		[]byte(accrualID+"-interest-"+month),
+	).String()
+}
+
+// isLastDayOfMonth returns true if date, in YYYY-MM-DD format, is the last day
+// of its month.
+func isLastDayOfMonth(date string) bool {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		panic(err)
+	}
+
+	return t.AddDate(0, 0, 1).Day() == 1
+}
+
+// InterestAccrualDue is a deadline message notifying that the end of a day has
+// been reached, and that interest is to be accrued on the account's end-of-day
+// balance.
+type InterestAccrualDue struct {
+	AccountID string
+	AccrualID string
+	Date      string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *InterestAccrualDue) MessageDescription() string {
+	return fmt.Sprintf("interest on account %s is due to accrue for %s", m.AccountID, m.Date)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *InterestAccrualDue) Validate(dogma.DeadlineValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("InterestAccrualDue must not have an empty account ID")
+	}
+	if m.AccrualID == "" {
+		return errors.New("InterestAccrualDue must not have an empty accrual ID")
+	}
+	if _, err := time.Parse(time.DateOnly, m.Date); err != nil {
+		return errors.New("InterestAccrualDue must have a valid date")
+	}
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *InterestAccrualDue) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *InterestAccrualDue) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
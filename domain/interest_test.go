@@ -0,0 +1,472 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/domain"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_InterestAccrual(t *testing.T) {
+	t.Run(
+		"when an interest rate is set on an account",
+		func(t *testing.T) {
+			t.Run(
+				"it accrues interest on the end-of-day balance",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        3650000,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 1000,
+								},
+							),
+							ToRecordEvent(
+								&events.InterestRateChanged{
+									AccountID:            "A001",
+									PreviousInterestRate: 0,
+									InterestRate:         1000,
+									AccrualID:            "A001:1",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.January, 31, 0, 0, 0, 0, time.UTC)),
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.InterestAccrued{
+										AccountID:    "A001",
+										AccrualID:    "A001:1",
+										Date:         "2001-01-30",
+										Balance:      3650000,
+										InterestRate: 1000,
+									},
+								),
+								NoneOf(
+									ToRecordEventOfType(&events.AccountCredited{}),
+								),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it pays the accrued interest at the end of the month",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        3650000,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 1000,
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC)),
+							),
+							ToRecordEvent(
+								&events.AccountCredited{
+									TransactionID:   domain.InterestTransactionID("A001:1", "2001-01"),
+									AccountID:       "A001",
+									TransactionType: messages.Interest,
+									Amount:          2000,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it does not accrue interest on a negative balance",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetOverdraftLimit{
+									AccountID:      "A001",
+									OverdraftLimit: 3650000,
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        3650000,
+									ScheduledTime: time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+								},
+							),
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 1000,
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC)),
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.AccountCredited{}),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it carries the unpaid fraction of a cent over to the next month",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        1368750,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 1,
+								},
+							),
+							// 2 days of interest in January is 0.75 cents,
+							// which is not paid
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC)),
+							),
+						).
+						Expect(
+							// 28 days of interest in February is 10.5 cents,
+							// plus the 0.75 cents carried over from January
+							AdvanceTime(
+								ToTime(time.Date(2001, time.March, 1, 0, 0, 0, 0, time.UTC)),
+							),
+							ToRecordEvent(
+								&events.AccountCredited{
+									TransactionID:   domain.InterestTransactionID("A001:1", "2001-02"),
+									AccountID:       "A001",
+									TransactionType: messages.Interest,
+									Amount:          11,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the interest rate is set back to zero",
+		func(t *testing.T) {
+			t.Run(
+				"it pays the interest accrued so far and stops accruing interest",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        3650000,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 1000,
+								},
+							),
+							AdvanceTime(
+								ToTime(time.Date(2001, time.January, 31, 0, 0, 0, 0, time.UTC)),
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 0,
+								},
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.InterestRateChanged{
+										AccountID:            "A001",
+										PreviousInterestRate: 1000,
+										InterestRate:         0,
+										AccrualID:            "A001:1",
+									},
+								),
+								ToRecordEvent(
+									&events.AccountCredited{
+										TransactionID:   domain.InterestTransactionID("A001:1", "2001-01"),
+										AccountID:       "A001",
+										TransactionType: messages.Interest,
+										Amount:          1000,
+									},
+								),
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 2, 0, 0, 0, 0, time.UTC)),
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.InterestAccrued{}),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it begins a new accrual if a rate is set again",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        3650000,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 1000,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 0,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 500,
+								},
+							),
+							ToRecordEvent(
+								&events.InterestRateChanged{
+									AccountID:            "A001",
+									PreviousInterestRate: 0,
+									InterestRate:         500,
+									AccrualID:            "A001:2",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.January, 31, 0, 0, 0, 0, time.UTC)),
+							),
+							ToRecordEvent(
+								&events.InterestAccrued{
+									AccountID:    "A001",
+									AccrualID:    "A001:2",
+									Date:         "2001-01-30",
+									Balance:      3650000,
+									InterestRate: 500,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account is not a savings account",
+		func(t *testing.T) {
+			t.Run(
+				"it does not set an interest rate",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 1000,
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.InterestRateChanged{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account is closed",
+		func(t *testing.T) {
+			t.Run(
+				"it stops accruing interest",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetInterestRate{
+									AccountID:    "A001",
+									InterestRate: 1000,
+								},
+							),
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A001",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.January, 31, 0, 0, 0, 0, time.UTC)),
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.InterestAccrued{}),
+							),
+						)
+				},
+			)
+		},
+	)
+}
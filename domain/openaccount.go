@@ -50,9 +50,11 @@ func (OpenAccountForNewCustomerProcessHandler) HandleEvent(
 	switch x := m.(type) {
 	case *events.CustomerAcquired:
 		s.ExecuteCommand(&commands.OpenAccount{
-			CustomerID:  x.CustomerID,
-			AccountID:   x.AccountID,
-			AccountName: x.AccountName,
+			CustomerID:   x.CustomerID,
+			AccountID:    x.AccountID,
+			AccountName:  x.AccountName,
+			AccountType:  x.AccountType,
+			MaturityDate: x.MaturityDate,
 		})
 		s.End()
 
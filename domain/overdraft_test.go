@@ -0,0 +1,225 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_SetOverdraftLimit(t *testing.T) {
+	t.Run(
+		"when the account exists",
+		func(t *testing.T) {
+			t.Run(
+				"it changes the overdraft limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.SetOverdraftLimit{
+									AccountID:      "A001",
+									OverdraftLimit: 1000,
+								},
+							),
+							ToRecordEvent(
+								&events.OverdraftLimitChanged{
+									AccountID:              "A001",
+									PreviousOverdraftLimit: 0,
+									OverdraftLimit:         1000,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it does nothing if the limit is unchanged",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.SetOverdraftLimit{
+									AccountID:      "A001",
+									OverdraftLimit: 0,
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.OverdraftLimitChanged{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account has an overdraft",
+		func(t *testing.T) {
+			t.Run(
+				"it allows debits within the overdraft limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetOverdraftLimit{
+									AccountID:      "A001",
+									OverdraftLimit: 1000,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        1500,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.WithdrawalApproved{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        1500,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines debits that exceed the overdraft limit",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetOverdraftLimit{
+									AccountID:      "A001",
+									OverdraftLimit: 1000,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        1501,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.WithdrawalDeclined{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        1501,
+									Reason:        messages.InsufficientFunds,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines the closure of an overdrawn account",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.SetOverdraftLimit{
+									AccountID:      "A001",
+									OverdraftLimit: 1000,
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        500,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.AccountClosureDeclined{
+									TransactionID: "X001",
+									AccountID:     "A001",
+									Reason:        messages.AccountIsOverdrawn,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+}
@@ -0,0 +1,280 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+// payees is the aggregate root for a customer's saved payees.
+type payees struct {
+	dogma.NoSnapshotBehavior
+
+	// Payees maps the ID of each payee that the customer has added to its
+	// details, including payees that have since been removed.
+	Payees map[string]*payee
+
+	// CoolingOff maps each account that the customer has ever added as a
+	// payee to the state of its cooling-off period. It is keyed by account
+	// rather than by payee so that removing a payee and adding the same
+	// account again does not start a new cooling-off period.
+	CoolingOff map[payeeAccount]*coolingOff
+}
+
+// payee is the state of a single saved payee.
+type payee struct {
+	AccountID        string
+	ToThirdPartyBank bool
+	IsRemoved        bool
+}
+
+// payeeAccount identifies the account to which a payee's transfers are made.
+type payeeAccount struct {
+	AccountID        string
+	ToThirdPartyBank bool
+}
+
+// coolingOff is the state of the cooling-off period of an account that has
+// been added as a payee.
+type coolingOff struct {
+	// Until is the time at which the cooling-off period ends, and Limit is the
+	// limit on the total of all transfers to the account until then. Both are
+	// zero if there is no cooling-off period.
+	Until time.Time
+	Limit int64
+
+	// Total is the total of all transfers to the account during its
+	// cooling-off period, in cents.
+	Total int64
+}
+
+// account returns the account to which transfers to the payee are made.
+func (x *payee) account() payeeAccount {
+	return payeeAccount{x.AccountID, x.ToThirdPartyBank}
+}
+
+func (p *payees) AggregateInstanceDescription() string {
+	n := 0
+	for _, x := range p.Payees {
+		if !x.IsRemoved {
+			n++
+		}
+	}
+
+	return fmt.Sprintf("%d payee(s)", n)
+}
+
+func (p *payees) Add(
+	s dogma.AggregateCommandScope[*payees],
+	m *commands.AddPayee,
+	coolingOffPeriod time.Duration,
+	coolingOffLimit int64,
+) {
+	if _, ok := p.Payees[m.PayeeID]; ok {
+		s.Log("payee has already been added")
+		return
+	}
+
+	account := payeeAccount{m.AccountID, m.ThirdPartyPayee != nil}
+
+	for _, x := range p.Payees {
+		if !x.IsRemoved && x.account() == account {
+			s.Log("account %s is already a payee", m.AccountID)
+			return
+		}
+	}
+
+	e := &events.PayeeAdded{
+		CustomerID:      m.CustomerID,
+		PayeeID:         m.PayeeID,
+		Name:            m.Name,
+		AccountID:       m.AccountID,
+		ThirdPartyPayee: m.ThirdPartyPayee,
+		AddedAt:         s.Now(),
+	}
+
+	if c, ok := p.CoolingOff[account]; ok {
+		// the account has been added before, so the payee remains subject to
+		// the cooling-off period that began when it was first added
+		e.CoolingOffUntil = c.Until
+		e.CoolingOffLimit = c.Limit
+		e.TotalDuringCoolingOff = c.Total
+	} else if coolingOffPeriod > 0 && coolingOffLimit > 0 {
+		e.CoolingOffUntil = e.AddedAt.Add(coolingOffPeriod)
+		e.CoolingOffLimit = coolingOffLimit
+	}
+
+	s.RecordEvent(e)
+}
+
+func (p *payees) Remove(s dogma.AggregateCommandScope[*payees], m *commands.RemovePayee) {
+	x, ok := p.Payees[m.PayeeID]
+	if !ok || x.IsRemoved {
+		s.Log("payee does not exist")
+		return
+	}
+
+	s.RecordEvent(&events.PayeeRemoved{
+		CustomerID: m.CustomerID,
+		PayeeID:    m.PayeeID,
+	})
+}
+
+func (p *payees) ConsumeCoolingOffLimit(s dogma.AggregateCommandScope[*payees], m *commands.ConsumePayeeCoolingOffLimit) {
+	// A payee that has been removed is still subject to its cooling-off
+	// limit, so that removing it can not be used to lift the limit on a
+	// transfer that is already scheduled.
+	var c coolingOff
+	if x, ok := p.Payees[m.PayeeID]; ok {
+		c = *p.CoolingOff[x.account()]
+	}
+
+	if c.Limit == 0 || !m.ScheduledTime.Before(c.Until) {
+		s.RecordEvent(&events.PayeeCoolingOffLimitConsumed{
+			TransactionID:   m.TransactionID,
+			CustomerID:      m.CustomerID,
+			PayeeID:         m.PayeeID,
+			TransactionType: m.TransactionType,
+			Amount:          m.Amount,
+		})
+		return
+	}
+
+	if c.Total+m.Amount > c.Limit {
+		s.RecordEvent(&events.PayeeCoolingOffLimitExceeded{
+			TransactionID:         m.TransactionID,
+			CustomerID:            m.CustomerID,
+			PayeeID:               m.PayeeID,
+			TransactionType:       m.TransactionType,
+			Amount:                m.Amount,
+			TotalDuringCoolingOff: c.Total,
+			CoolingOffLimit:       c.Limit,
+		})
+		return
+	}
+
+	s.RecordEvent(&events.PayeeCoolingOffLimitConsumed{
+		TransactionID:         m.TransactionID,
+		CustomerID:            m.CustomerID,
+		PayeeID:               m.PayeeID,
+		TransactionType:       m.TransactionType,
+		Amount:                m.Amount,
+		TotalDuringCoolingOff: c.Total + m.Amount,
+		CoolingOffLimit:       c.Limit,
+	})
+}
+
+func (p *payees) ApplyEvent(m dogma.Event) {
+	switch x := m.(type) {
+	case *events.PayeeAdded:
+		if p.Payees == nil {
+			p.Payees = map[string]*payee{}
+		}
+
+		y := &payee{
+			AccountID:        x.AccountID,
+			ToThirdPartyBank: x.ThirdPartyPayee != nil,
+		}
+		p.Payees[x.PayeeID] = y
+
+		if p.CoolingOff == nil {
+			p.CoolingOff = map[payeeAccount]*coolingOff{}
+		}
+
+		if _, ok := p.CoolingOff[y.account()]; !ok {
+			p.CoolingOff[y.account()] = &coolingOff{
+				Until: x.CoolingOffUntil,
+				Limit: x.CoolingOffLimit,
+			}
+		}
+
+	case *events.PayeeRemoved:
+		p.Payees[x.PayeeID].IsRemoved = true
+
+	case *events.PayeeCoolingOffLimitConsumed:
+		if x.CoolingOffLimit != 0 {
+			p.CoolingOff[p.Payees[x.PayeeID].account()].Total = x.TotalDuringCoolingOff
+		}
+	}
+}
+
+// PayeeHandler implements the business logic for a customer's saved payees,
+// which are the accounts, other than their own, to which the customer
+// transfers funds.
+//
+// If a cooling-off period is configured, the total of all transfers to a newly
+// added payee is limited until the period has elapsed. This limits the loss if
+// a customer is deceived into adding a payee, or if someone else gains access
+// to their account and adds one.
+//
+// The cooling-off period is measured from the time the account is first added
+// as a payee. Removing the payee and adding the same account again does not
+// start a new cooling-off period, nor reset the total of the transfers made
+// during it.
+type PayeeHandler struct {
+	// CoolingOffPeriod is the length of time after a payee is added during
+	// which transfers to it are limited. If it or CoolingOffLimit is zero,
+	// transfers to new payees are not limited.
+	CoolingOffPeriod time.Duration
+
+	// CoolingOffLimit is the limit on the total of all transfers to a payee
+	// during its cooling-off period, in cents.
+	CoolingOffLimit int64
+}
+
+// Configure configures the behavior of the engine as it relates to this
+// handler.
+func (PayeeHandler) Configure(c dogma.AggregateConfigurer) {
+	c.Identity("payee", "352d4f98-f496-4001-af83-d34d1d16e68e")
+
+	c.Routes(
+		dogma.HandlesCommand[*commands.AddPayee](),
+		dogma.HandlesCommand[*commands.RemovePayee](),
+		dogma.HandlesCommand[*commands.ConsumePayeeCoolingOffLimit](),
+		dogma.RecordsEvent[*events.PayeeAdded](),
+		dogma.RecordsEvent[*events.PayeeRemoved](),
+		dogma.RecordsEvent[*events.PayeeCoolingOffLimitConsumed](),
+		dogma.RecordsEvent[*events.PayeeCoolingOffLimitExceeded](),
+	)
+}
+
+// New returns a new payees instance.
+func (PayeeHandler) New() *payees {
+	return &payees{}
+}
+
+// RouteCommandToInstance returns the ID of the aggregate instance that is
+// targetted by m.
+func (PayeeHandler) RouteCommandToInstance(m dogma.Command) string {
+	switch x := m.(type) {
+	case *commands.AddPayee:
+		return x.CustomerID
+	case *commands.RemovePayee:
+		return x.CustomerID
+	case *commands.ConsumePayeeCoolingOffLimit:
+		return x.CustomerID
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// HandleCommand handles a command message that has been routed to this handler.
+func (h PayeeHandler) HandleCommand(
+	p *payees,
+	s dogma.AggregateCommandScope[*payees],
+	m dogma.Command,
+) {
+	switch x := m.(type) {
+	case *commands.AddPayee:
+		p.Add(s, x, h.CoolingOffPeriod, h.CoolingOffLimit)
+	case *commands.RemovePayee:
+		p.Remove(s, x)
+	case *commands.ConsumePayeeCoolingOffLimit:
+		p.ConsumeCoolingOffLimit(s, x)
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
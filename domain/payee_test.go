@@ -0,0 +1,507 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/domain"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_AddPayee(t *testing.T) {
+	addedAt := time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC)
+
+	t.Run(
+		"it adds an in-house payee",
+		func(t *testing.T) {
+			Begin(t, &example.App{}, StartTimeAt(addedAt)).
+				Expect(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+							Name:       "Bob",
+							AccountID:  "A002",
+						},
+					),
+					ToRecordEvent(
+						&events.PayeeAdded{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+							Name:       "Bob",
+							AccountID:  "A002",
+							AddedAt:    addedAt,
+						},
+					),
+				)
+		},
+	)
+
+	t.Run(
+		"it adds a third-party payee",
+		func(t *testing.T) {
+			payee := &messages.ThirdPartyPayee{
+				BSB:           "062-000",
+				AccountNumber: "12345674",
+				AccountName:   "Anna Smith",
+			}
+
+			Begin(t, &example.App{}, StartTimeAt(addedAt)).
+				Expect(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID:      "C001",
+							PayeeID:         "P001",
+							Name:            "Anna",
+							AccountID:       "06200012345674",
+							ThirdPartyPayee: payee,
+						},
+					),
+					ToRecordEvent(
+						&events.PayeeAdded{
+							CustomerID:      "C001",
+							PayeeID:         "P001",
+							Name:            "Anna",
+							AccountID:       "06200012345674",
+							ThirdPartyPayee: payee,
+							AddedAt:         addedAt,
+						},
+					),
+				)
+		},
+	)
+
+	t.Run(
+		"it starts a cooling-off period if one is configured",
+		func(t *testing.T) {
+			Begin(
+				t,
+				&example.App{
+					PayeeAggregate: domain.PayeeHandler{
+						CoolingOffPeriod: 24 * time.Hour,
+						CoolingOffLimit:  1000,
+					},
+				},
+				StartTimeAt(addedAt),
+			).
+				Expect(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+							Name:       "Bob",
+							AccountID:  "A002",
+						},
+					),
+					ToRecordEvent(
+						&events.PayeeAdded{
+							CustomerID:      "C001",
+							PayeeID:         "P001",
+							Name:            "Bob",
+							AccountID:       "A002",
+							AddedAt:         addedAt,
+							CoolingOffUntil: addedAt.Add(24 * time.Hour),
+							CoolingOffLimit: 1000,
+						},
+					),
+				)
+		},
+	)
+
+	t.Run(
+		"it does not start a new cooling-off period when an account is added again",
+		func(t *testing.T) {
+			Begin(
+				t,
+				&example.App{
+					PayeeAggregate: domain.PayeeHandler{
+						CoolingOffPeriod: 24 * time.Hour,
+						CoolingOffLimit:  1000,
+					},
+				},
+				StartTimeAt(addedAt),
+			).
+				Prepare(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+							Name:       "Bob",
+							AccountID:  "A002",
+						},
+					),
+					ExecuteCommand(
+						&commands.RemovePayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+						},
+					),
+					AdvanceTime(ByDuration(12*time.Hour)),
+				).
+				Expect(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P002",
+							Name:       "Bob",
+							AccountID:  "A002",
+						},
+					),
+					ToRecordEvent(
+						&events.PayeeAdded{
+							CustomerID:      "C001",
+							PayeeID:         "P002",
+							Name:            "Bob",
+							AccountID:       "A002",
+							AddedAt:         addedAt.Add(12 * time.Hour),
+							CoolingOffUntil: addedAt.Add(24 * time.Hour),
+							CoolingOffLimit: 1000,
+						},
+					),
+				)
+		},
+	)
+
+	t.Run(
+		"it does not add the same account twice",
+		func(t *testing.T) {
+			Begin(t, &example.App{}).
+				Prepare(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+							Name:       "Bob",
+							AccountID:  "A002",
+						},
+					),
+				).
+				Expect(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P002",
+							Name:       "Bobby",
+							AccountID:  "A002",
+						},
+					),
+					NoneOf(
+						ToRecordEventOfType(&events.PayeeAdded{}),
+					),
+				)
+		},
+	)
+
+	t.Run(
+		"it adds an account again after it has been removed",
+		func(t *testing.T) {
+			Begin(t, &example.App{}).
+				Prepare(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+							Name:       "Bob",
+							AccountID:  "A002",
+						},
+					),
+					ExecuteCommand(
+						&commands.RemovePayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+						},
+					),
+				).
+				Expect(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P002",
+							Name:       "Bob",
+							AccountID:  "A002",
+						},
+					),
+					ToRecordEventOfType(&events.PayeeAdded{}),
+				)
+		},
+	)
+}
+
+func Test_RemovePayee(t *testing.T) {
+	t.Run(
+		"it removes the payee",
+		func(t *testing.T) {
+			Begin(t, &example.App{}).
+				Prepare(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+							Name:       "Bob",
+							AccountID:  "A002",
+						},
+					),
+				).
+				Expect(
+					ExecuteCommand(
+						&commands.RemovePayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+						},
+					),
+					ToRecordEvent(
+						&events.PayeeRemoved{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+						},
+					),
+				)
+		},
+	)
+
+	t.Run(
+		"it does nothing if the payee does not exist",
+		func(t *testing.T) {
+			Begin(t, &example.App{}).
+				Expect(
+					ExecuteCommand(
+						&commands.RemovePayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+						},
+					),
+					NoneOf(
+						ToRecordEventOfType(&events.PayeeRemoved{}),
+					),
+				)
+		},
+	)
+}
+
+func Test_PayeeCoolingOffLimit(t *testing.T) {
+	addedAt := time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC)
+
+	app := func(t *testing.T) *Test {
+		return Begin(
+			t,
+			&example.App{
+				PayeeAggregate: domain.PayeeHandler{
+					CoolingOffPeriod: 24 * time.Hour,
+					CoolingOffLimit:  1000,
+				},
+			},
+			StartTimeAt(addedAt),
+		).
+			Prepare(
+				ExecuteCommand(
+					&commands.OpenAccount{
+						CustomerID:  "C001",
+						AccountID:   "A001",
+						AccountName: "Anna Smith",
+						AccountType: messages.Everyday,
+					},
+				),
+				ExecuteCommand(
+					&commands.OpenAccount{
+						CustomerID:  "C002",
+						AccountID:   "A002",
+						AccountName: "Bob Jones",
+						AccountType: messages.Everyday,
+					},
+				),
+				ExecuteCommand(
+					&commands.Deposit{
+						TransactionID: "D001",
+						AccountID:     "A001",
+						Amount:        5000,
+					},
+				),
+				ExecuteCommand(
+					&commands.AddPayee{
+						CustomerID: "C001",
+						PayeeID:    "P001",
+						Name:       "Bob",
+						AccountID:  "A002",
+					},
+				),
+			)
+	}
+
+	transfer := func(id string, amount int64, scheduledTime time.Time) *commands.Transfer {
+		return &commands.Transfer{
+			TransactionID: id,
+			FromAccountID: "A001",
+			ToAccountID:   "A002",
+			Amount:        amount,
+			ScheduledTime: scheduledTime,
+			CustomerID:    "C001",
+			PayeeID:       "P001",
+		}
+	}
+
+	t.Run(
+		"when the transfer is within the cooling-off period",
+		func(t *testing.T) {
+			t.Run(
+				"it approves transfers that do not exceed the limit",
+				func(t *testing.T) {
+					app(t).
+						Prepare(
+							ExecuteCommand(transfer("T001", 600, addedAt)),
+						).
+						Expect(
+							ExecuteCommand(transfer("T002", 400, addedAt)),
+							ToRecordEvent(
+								&events.TransferApproved{
+									TransactionID: "T002",
+									FromAccountID: "A001",
+									ToAccountID:   "A002",
+									Amount:        400,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it declines transfers that exceed the limit",
+				func(t *testing.T) {
+					app(t).
+						Prepare(
+							ExecuteCommand(transfer("T001", 600, addedAt)),
+						).
+						Expect(
+							ExecuteCommand(transfer("T002", 401, addedAt)),
+							AllOf(
+								ToRecordEvent(
+									&events.PayeeCoolingOffLimitExceeded{
+										TransactionID:         "T002",
+										CustomerID:            "C001",
+										PayeeID:               "P001",
+										TransactionType:       messages.Transfer,
+										Amount:                401,
+										TotalDuringCoolingOff: 600,
+										CoolingOffLimit:       1000,
+									},
+								),
+								ToRecordEvent(
+									&events.TransferDeclined{
+										TransactionID: "T002",
+										FromAccountID: "A001",
+										ToAccountID:   "A002",
+										Amount:        401,
+										Reason:        messages.PayeeCoolingOffLimitExceeded,
+									},
+								),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it continues to limit transfers after the payee is removed",
+				func(t *testing.T) {
+					app(t).
+						Prepare(
+							ExecuteCommand(
+								&commands.RemovePayee{
+									CustomerID: "C001",
+									PayeeID:    "P001",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(transfer("T001", 1001, addedAt)),
+							ToRecordEventOfType(&events.PayeeCoolingOffLimitExceeded{}),
+						)
+				},
+			)
+
+			t.Run(
+				"it continues to limit transfers after the account is added again",
+				func(t *testing.T) {
+					app(t).
+						Prepare(
+							ExecuteCommand(transfer("T001", 600, addedAt)),
+							ExecuteCommand(
+								&commands.RemovePayee{
+									CustomerID: "C001",
+									PayeeID:    "P001",
+								},
+							),
+							ExecuteCommand(
+								&commands.AddPayee{
+									CustomerID: "C001",
+									PayeeID:    "P002",
+									Name:       "Bob",
+									AccountID:  "A002",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Transfer{
+									TransactionID: "T002",
+									FromAccountID: "A001",
+									ToAccountID:   "A002",
+									Amount:        401,
+									ScheduledTime: addedAt,
+									CustomerID:    "C001",
+									PayeeID:       "P002",
+								},
+							),
+							ToRecordEvent(
+								&events.PayeeCoolingOffLimitExceeded{
+									TransactionID:         "T002",
+									CustomerID:            "C001",
+									PayeeID:               "P002",
+									TransactionType:       messages.Transfer,
+									Amount:                401,
+									TotalDuringCoolingOff: 600,
+									CoolingOffLimit:       1000,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the transfer is after the cooling-off period",
+		func(t *testing.T) {
+			t.Run(
+				"it does not limit the transfer",
+				func(t *testing.T) {
+					app(t).
+						Expect(
+							ExecuteCommand(transfer("T001", 2000, addedAt.Add(24*time.Hour))),
+							AllOf(
+								ToRecordEvent(
+									&events.PayeeCoolingOffLimitConsumed{
+										TransactionID:   "T001",
+										CustomerID:      "C001",
+										PayeeID:         "P001",
+										TransactionType: messages.Transfer,
+										Amount:          2000,
+									},
+								),
+								ToRecordEvent(
+									&events.TransferApproved{
+										TransactionID: "T001",
+										FromAccountID: "A001",
+										ToAccountID:   "A002",
+										Amount:        2000,
+									},
+								),
+							),
+						)
+				},
+			)
+		},
+	)
+}
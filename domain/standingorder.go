@@ -0,0 +1,273 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+// standingOrder is the aggregate root for a standing order.
+type standingOrder struct {
+	dogma.NoSnapshotBehavior
+
+	FromAccountID    string
+	ToAccountID      string
+	ToThirdPartyBank bool
+	ToPayee          *messages.ThirdPartyPayee
+	CustomerID       string
+	PayeeID          string
+	Amount           int64
+	Frequency        messages.Frequency
+
+	// IsPaused is true if payments that fall due are to be skipped.
+	IsPaused bool
+
+	// IsEnded is true if the standing order has been cancelled or completed.
+	IsEnded bool
+}
+
+func (o *standingOrder) AggregateInstanceDescription() string {
+	if o.FromAccountID == "" {
+		return ""
+	}
+
+	status := "active"
+	if o.IsEnded {
+		status = "ended"
+	} else if o.IsPaused {
+		status = "paused"
+	}
+
+	return fmt.Sprintf(
+		"%s payment of %s from %s to %s (%s)",
+		o.Frequency,
+		messages.FormatAmount(o.Amount),
+		o.FromAccountID,
+		o.ToAccountID,
+		status,
+	)
+}
+
+func (o *standingOrder) Create(s dogma.AggregateCommandScope[*standingOrder], m *commands.CreateStandingOrder) {
+	if o.FromAccountID != "" {
+		s.Log("standing order already created")
+		return
+	}
+
+	s.RecordEvent(&events.StandingOrderCreated{
+		StandingOrderID:  m.StandingOrderID,
+		FromAccountID:    m.FromAccountID,
+		ToAccountID:      m.ToAccountID,
+		ToThirdPartyBank: m.ToThirdPartyBank,
+		Amount:           m.Amount,
+		Frequency:        m.Frequency,
+		StartDate:        m.StartDate,
+		EndDate:          m.EndDate,
+		NumberOfPayments: m.NumberOfPayments,
+		ToPayee:          m.ToPayee,
+		CustomerID:       m.CustomerID,
+		PayeeID:          m.PayeeID,
+	})
+}
+
+func (o *standingOrder) Pause(s dogma.AggregateCommandScope[*standingOrder], m *commands.PauseStandingOrder) {
+	if !o.isActive(s) || !o.isFrom(s, m.FromAccountID) {
+		return
+	}
+
+	if o.IsPaused {
+		s.Log("standing order is already paused")
+		return
+	}
+
+	s.RecordEvent(&events.StandingOrderPaused{
+		StandingOrderID: m.StandingOrderID,
+	})
+}
+
+func (o *standingOrder) Resume(s dogma.AggregateCommandScope[*standingOrder], m *commands.ResumeStandingOrder) {
+	if !o.isActive(s) || !o.isFrom(s, m.FromAccountID) {
+		return
+	}
+
+	if !o.IsPaused {
+		s.Log("standing order is not paused")
+		return
+	}
+
+	s.RecordEvent(&events.StandingOrderResumed{
+		StandingOrderID: m.StandingOrderID,
+	})
+}
+
+func (o *standingOrder) Cancel(s dogma.AggregateCommandScope[*standingOrder], m *commands.CancelStandingOrder) {
+	if !o.isActive(s) || !o.isFrom(s, m.FromAccountID) {
+		return
+	}
+
+	s.RecordEvent(&events.StandingOrderCancelled{
+		StandingOrderID: m.StandingOrderID,
+	})
+}
+
+func (o *standingOrder) StartPayment(s dogma.AggregateCommandScope[*standingOrder], m *commands.StartStandingOrderPayment) {
+	if !o.isActive(s) {
+		return
+	}
+
+	if o.IsPaused {
+		s.RecordEvent(&events.StandingOrderPaymentSkipped{
+			StandingOrderID: m.StandingOrderID,
+			PaymentNumber:   m.PaymentNumber,
+			Date:            m.Date,
+			NextPaymentDate: m.NextPaymentDate,
+		})
+	} else {
+		s.RecordEvent(&events.StandingOrderPaymentStarted{
+			StandingOrderID:  m.StandingOrderID,
+			TransactionID:    fmt.Sprintf("%s-%d", m.StandingOrderID, m.PaymentNumber),
+			PaymentNumber:    m.PaymentNumber,
+			FromAccountID:    o.FromAccountID,
+			ToAccountID:      o.ToAccountID,
+			ToThirdPartyBank: o.ToThirdPartyBank,
+			Amount:           o.Amount,
+			Date:             m.Date,
+			NextPaymentDate:  m.NextPaymentDate,
+			ToPayee:          o.ToPayee,
+			CustomerID:       o.CustomerID,
+			PayeeID:          o.PayeeID,
+		})
+	}
+
+	if m.NextPaymentDate == "" {
+		s.RecordEvent(&events.StandingOrderCompleted{
+			StandingOrderID: m.StandingOrderID,
+		})
+	}
+}
+
+// isActive returns true if the standing order has been created and has not yet
+// ended. It logs the reason if it returns false.
+func (o *standingOrder) isActive(s dogma.AggregateCommandScope[*standingOrder]) bool {
+	if o.FromAccountID == "" {
+		s.Log("standing order does not exist")
+		return false
+	}
+
+	if o.IsEnded {
+		s.Log("standing order has ended")
+		return false
+	}
+
+	return true
+}
+
+// isFrom returns true if the standing order makes payments from the given
+// account. It logs the reason if it returns false.
+func (o *standingOrder) isFrom(s dogma.AggregateCommandScope[*standingOrder], accountID string) bool {
+	if o.FromAccountID != accountID {
+		s.Log("standing order does not make payments from account %s", accountID)
+		return false
+	}
+
+	return true
+}
+
+func (o *standingOrder) ApplyEvent(m dogma.Event) {
+	switch x := m.(type) {
+	case *events.StandingOrderCreated:
+		o.FromAccountID = x.FromAccountID
+		o.ToAccountID = x.ToAccountID
+		o.ToThirdPartyBank = x.ToThirdPartyBank
+		o.ToPayee = x.ToPayee
+		o.CustomerID = x.CustomerID
+		o.PayeeID = x.PayeeID
+		o.Amount = x.Amount
+		o.Frequency = x.Frequency
+	case *events.StandingOrderPaused:
+		o.IsPaused = true
+	case *events.StandingOrderResumed:
+		o.IsPaused = false
+	case *events.StandingOrderCancelled, *events.StandingOrderCompleted:
+		o.IsEnded = true
+	}
+}
+
+// StandingOrderHandler implements the business logic for a standing order.
+//
+// It tracks whether the standing order is paused or has ended. The payment
+// schedule itself is driven by [StandingOrderScheduleProcessHandler], which
+// asks this aggregate to start each payment as it falls due. Payments that fall
+// due while the standing order is paused are skipped; they still count towards
+// the standing order's number of payments.
+type StandingOrderHandler struct{}
+
+// New returns a new standing order instance.
+func (StandingOrderHandler) New() *standingOrder {
+	return &standingOrder{}
+}
+
+// Configure configures the behavior of the engine as it relates to this
+// handler.
+func (StandingOrderHandler) Configure(c dogma.AggregateConfigurer) {
+	c.Identity("standing-order", "0e5c3b8a-9d47-4f21-b6a3-52e8c1d07f94")
+
+	c.Routes(
+		dogma.HandlesCommand[*commands.CreateStandingOrder](),
+		dogma.HandlesCommand[*commands.PauseStandingOrder](),
+		dogma.HandlesCommand[*commands.ResumeStandingOrder](),
+		dogma.HandlesCommand[*commands.CancelStandingOrder](),
+		dogma.HandlesCommand[*commands.StartStandingOrderPayment](),
+		dogma.RecordsEvent[*events.StandingOrderCreated](),
+		dogma.RecordsEvent[*events.StandingOrderPaused](),
+		dogma.RecordsEvent[*events.StandingOrderResumed](),
+		dogma.RecordsEvent[*events.StandingOrderCancelled](),
+		dogma.RecordsEvent[*events.StandingOrderCompleted](),
+		dogma.RecordsEvent[*events.StandingOrderPaymentStarted](),
+		dogma.RecordsEvent[*events.StandingOrderPaymentSkipped](),
+	)
+}
+
+// RouteCommandToInstance returns the ID of the aggregate instance that is
+// targeted by m.
+func (StandingOrderHandler) RouteCommandToInstance(m dogma.Command) string {
+	switch x := m.(type) {
+	case *commands.CreateStandingOrder:
+		return x.StandingOrderID
+	case *commands.PauseStandingOrder:
+		return x.StandingOrderID
+	case *commands.ResumeStandingOrder:
+		return x.StandingOrderID
+	case *commands.CancelStandingOrder:
+		return x.StandingOrderID
+	case *commands.StartStandingOrderPayment:
+		return x.StandingOrderID
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// HandleCommand handles a command message that has been routed to this handler.
+func (StandingOrderHandler) HandleCommand(
+	o *standingOrder,
+	s dogma.AggregateCommandScope[*standingOrder],
+	m dogma.Command,
+) {
+	switch x := m.(type) {
+	case *commands.CreateStandingOrder:
+		o.Create(s, x)
+	case *commands.PauseStandingOrder:
+		o.Pause(s, x)
+	case *commands.ResumeStandingOrder:
+		o.Resume(s, x)
+	case *commands.CancelStandingOrder:
+		o.Cancel(s, x)
+	case *commands.StartStandingOrderPayment:
+		o.StartPayment(s, x)
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
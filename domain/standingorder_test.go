@@ -0,0 +1,593 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/domain"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_StandingOrder(t *testing.T) {
+	// openAccounts returns the actions that open the accounts used by each test,
+	// depositing balance into the "from" account.
+	openAccounts := func(balance int64) []Action {
+		actions := []Action{
+			ExecuteCommand(
+				&commands.OpenAccount{
+					CustomerID:  "C001",
+					AccountID:   "A001",
+					AccountName: "Anna Smith",
+					AccountType: messages.Everyday,
+				},
+			),
+			ExecuteCommand(
+				&commands.OpenAccount{
+					CustomerID:  "C002",
+					AccountID:   "A002",
+					AccountName: "Bob Jones",
+					AccountType: messages.Everyday,
+				},
+			),
+		}
+
+		if balance > 0 {
+			actions = append(
+				actions,
+				ExecuteCommand(
+					&commands.Deposit{
+						TransactionID: "D001",
+						AccountID:     "A001",
+						Amount:        balance,
+					},
+				),
+			)
+		}
+
+		return actions
+	}
+
+	t.Run(
+		"when a payment falls due",
+		func(t *testing.T) {
+			t.Run(
+				"it makes the payment as an ordinary transfer",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccounts(10000)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.CreateStandingOrder{
+									StandingOrderID:  "SO001",
+									FromAccountID:    "A001",
+									ToAccountID:      "A002",
+									Amount:           500,
+									Frequency:        messages.Weekly,
+									StartDate:        "2001-02-05",
+									NumberOfPayments: 2,
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC)),
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.StandingOrderPaymentStarted{
+										StandingOrderID: "SO001",
+										TransactionID:   "SO001-1",
+										PaymentNumber:   1,
+										FromAccountID:   "A001",
+										ToAccountID:     "A002",
+										Amount:          500,
+										Date:            "2001-02-05",
+										NextPaymentDate: "2001-02-12",
+									},
+								),
+								ToRecordEvent(
+									&events.TransferApproved{
+										TransactionID: "SO001-1",
+										FromAccountID: "A001",
+										ToAccountID:   "A002",
+										Amount:        500,
+									},
+								),
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 12, 0, 0, 0, 0, time.UTC)),
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.TransferApproved{
+										TransactionID: "SO001-2",
+										FromAccountID: "A001",
+										ToAccountID:   "A002",
+										Amount:        500,
+									},
+								),
+								ToRecordEvent(
+									&events.StandingOrderCompleted{
+										StandingOrderID: "SO001",
+									},
+								),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it makes the payment to the third-party payee",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccounts(10000)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.CreateStandingOrder{
+									StandingOrderID:  "SO001",
+									FromAccountID:    "A001",
+									ToAccountID:      "06200012345674",
+									ToThirdPartyBank: true,
+									ToPayee:          thirdPartyPayee,
+									Amount:           500,
+									Frequency:        messages.Weekly,
+									StartDate:        "2001-02-05",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC)),
+							),
+							ToExecuteCommand(
+								&commands.Transfer{
+									TransactionID:    "SO001-1",
+									FromAccountID:    "A001",
+									ToAccountID:      "06200012345674",
+									ToThirdPartyBank: true,
+									ToPayee:          thirdPartyPayee,
+									Amount:           500,
+									ScheduledTime:    time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC),
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it counts the payment towards the payee's cooling-off limit",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{
+							PayeeAggregate: domain.PayeeHandler{
+								CoolingOffPeriod: 7 * 24 * time.Hour,
+								CoolingOffLimit:  400,
+							},
+						},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccounts(10000)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.AddPayee{
+									CustomerID: "C001",
+									PayeeID:    "P001",
+									Name:       "Bob",
+									AccountID:  "A002",
+								},
+							),
+							ExecuteCommand(
+								&commands.CreateStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A001",
+									ToAccountID:     "A002",
+									Amount:          500,
+									Frequency:       messages.Weekly,
+									StartDate:       "2001-02-05",
+									CustomerID:      "C001",
+									PayeeID:         "P001",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC)),
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.PayeeCoolingOffLimitExceeded{
+										TransactionID:   "SO001-1",
+										CustomerID:      "C001",
+										PayeeID:         "P001",
+										TransactionType: messages.Transfer,
+										Amount:          500,
+										CoolingOffLimit: 400,
+									},
+								),
+								ToRecordEvent(
+									&events.TransferDeclined{
+										TransactionID: "SO001-1",
+										FromAccountID: "A001",
+										ToAccountID:   "A002",
+										Amount:        500,
+										Reason:        messages.PayeeCoolingOffLimitExceeded,
+									},
+								),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it continues the schedule after a payment is declined",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccounts(0)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.CreateStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A001",
+									ToAccountID:     "A002",
+									Amount:          500,
+									Frequency:       messages.Weekly,
+									StartDate:       "2001-02-05",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC)),
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.TransferDeclined{
+										TransactionID: "SO001-1",
+										FromAccountID: "A001",
+										ToAccountID:   "A002",
+										Amount:        500,
+										Reason:        messages.InsufficientFunds,
+									},
+								),
+								NoneOf(
+									ToRecordEventOfType(&events.StandingOrderCompleted{}),
+								),
+							),
+						).
+						Prepare(
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        10000,
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 12, 0, 0, 0, 0, time.UTC)),
+							),
+							ToRecordEvent(
+								&events.TransferApproved{
+									TransactionID: "SO001-2",
+									FromAccountID: "A001",
+									ToAccountID:   "A002",
+									Amount:        500,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it pays monthly orders on the last day of shorter months",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccounts(10000)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.CreateStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A001",
+									ToAccountID:     "A002",
+									Amount:          500,
+									Frequency:       messages.Monthly,
+									StartDate:       "2001-01-31",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.January, 31, 0, 0, 0, 0, time.UTC)),
+							),
+							ToRecordEvent(
+								&events.StandingOrderPaymentStarted{
+									StandingOrderID: "SO001",
+									TransactionID:   "SO001-1",
+									PaymentNumber:   1,
+									FromAccountID:   "A001",
+									ToAccountID:     "A002",
+									Amount:          500,
+									Date:            "2001-01-31",
+									NextPaymentDate: "2001-02-28",
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it completes the standing order once the end date is reached",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccounts(10000)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.CreateStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A001",
+									ToAccountID:     "A002",
+									Amount:          500,
+									Frequency:       messages.Fortnightly,
+									StartDate:       "2001-02-05",
+									EndDate:         "2001-02-18",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC)),
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.StandingOrderPaymentStarted{
+										StandingOrderID: "SO001",
+										TransactionID:   "SO001-1",
+										PaymentNumber:   1,
+										FromAccountID:   "A001",
+										ToAccountID:     "A002",
+										Amount:          500,
+										Date:            "2001-02-05",
+										NextPaymentDate: "",
+									},
+								),
+								ToRecordEvent(
+									&events.StandingOrderCompleted{
+										StandingOrderID: "SO001",
+									},
+								),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when a standing order is paused",
+		func(t *testing.T) {
+			t.Run(
+				"it skips payments until it is resumed",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccounts(10000)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.CreateStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A001",
+									ToAccountID:     "A002",
+									Amount:          500,
+									Frequency:       messages.Weekly,
+									StartDate:       "2001-02-05",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.PauseStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.StandingOrderPaused{
+									StandingOrderID: "SO001",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC)),
+							),
+							AllOf(
+								ToRecordEvent(
+									&events.StandingOrderPaymentSkipped{
+										StandingOrderID: "SO001",
+										PaymentNumber:   1,
+										Date:            "2001-02-05",
+										NextPaymentDate: "2001-02-12",
+									},
+								),
+								NoneOf(
+									ToRecordEventOfType(&events.TransferStarted{}),
+								),
+							),
+						).
+						Prepare(
+							ExecuteCommand(
+								&commands.ResumeStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A001",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 12, 0, 0, 0, 0, time.UTC)),
+							),
+							ToRecordEvent(
+								&events.TransferApproved{
+									TransactionID: "SO001-2",
+									FromAccountID: "A001",
+									ToAccountID:   "A002",
+									Amount:        500,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when a standing order is cancelled",
+		func(t *testing.T) {
+			t.Run(
+				"it makes no further payments",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccounts(10000)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.CreateStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A001",
+									ToAccountID:     "A002",
+									Amount:          500,
+									Frequency:       messages.Weekly,
+									StartDate:       "2001-02-05",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CancelStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.StandingOrderCancelled{
+									StandingOrderID: "SO001",
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.March, 1, 0, 0, 0, 0, time.UTC)),
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.StandingOrderPaymentStarted{}),
+								ToRecordEventOfType(&events.StandingOrderPaymentSkipped{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when a standing order does not make payments from the given account",
+		func(t *testing.T) {
+			t.Run(
+				"it can not be paused or cancelled",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(openAccounts(10000)...).
+						Prepare(
+							ExecuteCommand(
+								&commands.CreateStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A001",
+									ToAccountID:     "A002",
+									Amount:          500,
+									Frequency:       messages.Weekly,
+									StartDate:       "2001-02-05",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.PauseStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A002",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.StandingOrderPaused{}),
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CancelStandingOrder{
+									StandingOrderID: "SO001",
+									FromAccountID:   "A002",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.StandingOrderCancelled{}),
+							),
+						)
+				},
+			)
+		},
+	)
+}
@@ -0,0 +1,279 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+func init() {
+	dogma.RegisterDeadline[*StandingOrderPaymentDue]("3a9e47c2-6b15-4d8f-9e20-c7f4b81d5a63")
+}
+
+// standingOrderScheduleProcess is the process root for the payment schedule of
+// a standing order.
+type standingOrderScheduleProcess struct {
+	StandingOrderID  string
+	Frequency        messages.Frequency
+	StartDate        string
+	EndDate          string
+	NumberOfPayments int
+}
+
+// ProcessInstanceDescription returns a human-readable description of the
+// standing order schedule's current state.
+func (p *standingOrderScheduleProcess) ProcessInstanceDescription(ended bool) string {
+	if p.StandingOrderID == "" {
+		return ""
+	}
+
+	if ended {
+		return fmt.Sprintf("no further payments due on standing order %s", p.StandingOrderID)
+	}
+
+	return fmt.Sprintf(
+		"making %s payments on standing order %s",
+		p.Frequency,
+		p.StandingOrderID,
+	)
+}
+
+// MarshalBinary returns the standingOrderScheduleProcess encoded as binary
+// data.
+func (p *standingOrderScheduleProcess) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// UnmarshalBinary decodes binary data into the standingOrderScheduleProcess.
+func (p *standingOrderScheduleProcess) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// StandingOrderScheduleProcessHandler manages the process of making each
+// payment on a standing order as it falls due.
+//
+// Each payment is made as an ordinary transfer, via [TransferProcessHandler].
+// The outcome of the transfer has no bearing on the schedule; a declined or
+// failed payment does not prevent subsequent payments from being made.
+type StandingOrderScheduleProcessHandler struct{}
+
+// New returns a new standing order schedule instance.
+func (StandingOrderScheduleProcessHandler) New() *standingOrderScheduleProcess {
+	return &standingOrderScheduleProcess{}
+}
+
+// Configure configures the behavior of the engine as it relates to this handler.
+func (StandingOrderScheduleProcessHandler) Configure(c dogma.ProcessConfigurer) {
+	c.Identity("standing-order-schedule", "c2d81f6e-7a49-4b3c-8e15-94f0a6b2d7e8")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.StandingOrderCreated](),
+		dogma.HandlesEvent[*events.StandingOrderPaymentStarted](),
+		dogma.HandlesEvent[*events.StandingOrderCancelled](),
+		dogma.HandlesEvent[*events.StandingOrderCompleted](),
+		dogma.ExecutesCommand[*commands.StartStandingOrderPayment](),
+		dogma.ExecutesCommand[*commands.Transfer](),
+		dogma.SchedulesDeadline[*StandingOrderPaymentDue](),
+	)
+}
+
+// RouteEventToInstance returns the ID of the process instance that is targeted
+// by m.
+func (StandingOrderScheduleProcessHandler) RouteEventToInstance(
+	_ context.Context,
+	m dogma.Event,
+) (string, bool, error) {
+	switch x := m.(type) {
+	case *events.StandingOrderCreated:
+		return x.StandingOrderID, true, nil
+	case *events.StandingOrderPaymentStarted:
+		return x.StandingOrderID, true, nil
+	case *events.StandingOrderCancelled:
+		return x.StandingOrderID, true, nil
+	case *events.StandingOrderCompleted:
+		return x.StandingOrderID, true, nil
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// HandleEvent handles an event message that has been routed to this handler.
+func (StandingOrderScheduleProcessHandler) HandleEvent(
+	_ context.Context,
+	_ *standingOrderScheduleProcess,
+	s dogma.ProcessEventScope[*standingOrderScheduleProcess],
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.StandingOrderCreated:
+		s.Mutate(func(p *standingOrderScheduleProcess) {
+			p.StandingOrderID = x.StandingOrderID
+			p.Frequency = x.Frequency
+			p.StartDate = x.StartDate
+			p.EndDate = x.EndDate
+			p.NumberOfPayments = x.NumberOfPayments
+		})
+
+		s.ScheduleDeadline(
+			&StandingOrderPaymentDue{
+				StandingOrderID: x.StandingOrderID,
+				PaymentNumber:   1,
+				Date:            x.StartDate,
+			},
+			startOfDay(x.StartDate),
+		)
+
+	case *events.StandingOrderPaymentStarted:
+		s.ExecuteCommand(&commands.Transfer{
+			TransactionID:    x.TransactionID,
+			FromAccountID:    x.FromAccountID,
+			ToAccountID:      x.ToAccountID,
+			ToThirdPartyBank: x.ToThirdPartyBank,
+			ToPayee:          x.ToPayee,
+			Amount:           x.Amount,
+			ScheduledTime:    s.RecordedAt(),
+			CustomerID:       x.CustomerID,
+			PayeeID:          x.PayeeID,
+		})
+
+	case *events.StandingOrderCancelled, *events.StandingOrderCompleted:
+		s.End()
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// HandleDeadline handles a deadline message that has been routed to this handler.
+func (StandingOrderScheduleProcessHandler) HandleDeadline(
+	_ context.Context,
+	p *standingOrderScheduleProcess,
+	s dogma.ProcessDeadlineScope[*standingOrderScheduleProcess],
+	m dogma.Deadline,
+) error {
+	switch x := m.(type) {
+	case *StandingOrderPaymentDue:
+		next := p.paymentDate(x.PaymentNumber + 1)
+
+		s.ExecuteCommand(&commands.StartStandingOrderPayment{
+			StandingOrderID: x.StandingOrderID,
+			PaymentNumber:   x.PaymentNumber,
+			Date:            x.Date,
+			NextPaymentDate: next,
+		})
+
+		if next != "" {
+			s.ScheduleDeadline(
+				&StandingOrderPaymentDue{
+					StandingOrderID: x.StandingOrderID,
+					PaymentNumber:   x.PaymentNumber + 1,
+					Date:            next,
+				},
+				startOfDay(next),
+			)
+		}
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// paymentDate returns the date on which the nth payment falls due, in
+// YYYY-MM-DD format, or an empty string if the standing order ends before the
+// nth payment.
+func (p *standingOrderScheduleProcess) paymentDate(n int) string {
+	if p.NumberOfPayments != 0 && n > p.NumberOfPayments {
+		return ""
+	}
+
+	start := startOfDay(p.StartDate)
+	var date time.Time
+
+	switch p.Frequency {
+	case messages.Weekly:
+		date = start.AddDate(0, 0, 7*(n-1))
+	case messages.Fortnightly:
+		date = start.AddDate(0, 0, 14*(n-1))
+	case messages.Monthly:
+		// payments are made on the same day of each month as the first, or on
+		// the last day of the month if it has fewer days
+		month := time.Date(start.Year(), start.Month()+time.Month(n-1), 1, 0, 0, 0, 0, time.UTC)
+		lastDay := month.AddDate(0, 1, -1).Day()
+		date = month.AddDate(0, 0, min(start.Day(), lastDay)-1)
+	default:
+		panic(fmt.Sprintf("unsupported frequency: %s", p.Frequency))
+	}
+
+	d := date.Format(time.DateOnly)
+	if p.EndDate != "" && d > p.EndDate {
+		return ""
+	}
+
+	return d
+}
+
+// startOfDay returns the time at the start of date, in YYYY-MM-DD format, in
+// UTC.
+func startOfDay(date string) time.Time {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// StandingOrderPaymentDue is a deadline message notifying that a payment on a
+// standing order has fallen due.
+type StandingOrderPaymentDue struct {
+	StandingOrderID string
+	PaymentNumber   int
+	Date            string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StandingOrderPaymentDue) MessageDescription() string {
+	return fmt.Sprintf(
+		"payment #%d on standing order %s is due on %s",
+		m.PaymentNumber,
+		m.StandingOrderID,
+		m.Date,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StandingOrderPaymentDue) Validate(dogma.DeadlineValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("StandingOrderPaymentDue must not have an empty standing order ID")
+	}
+	if m.PaymentNumber < 1 {
+		return errors.New("StandingOrderPaymentDue must have a positive payment number")
+	}
+	if _, err := time.Parse(time.DateOnly, m.Date); err != nil {
+		return errors.New("StandingOrderPaymentDue must have a valid date")
+	}
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderPaymentDue) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderPaymentDue) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
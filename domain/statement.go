@@ -0,0 +1,322 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+func init() {
+	dogma.RegisterDeadline[*StatementDue]("0985e6da-ceaf-478d-9a12-0a037c2482e4")
+}
+
+// statementProcess is the process root for issuing monthly statements for an
+// account.
+type statementProcess struct {
+	AccountID string
+
+	// PeriodStart is the first day covered by the next statement, in
+	// YYYY-MM-DD format.
+	PeriodStart string
+
+	// Totals maps each month, in YYYY-MM format, to the totals of the credits
+	// and debits recorded against the account in that month that have not yet
+	// been included in a statement.
+	Totals map[string]statementTotals
+}
+
+// statementTotals is the total of the credits and debits recorded against an
+// account within a single month, in cents.
+type statementTotals struct {
+	Credits int64
+	Debits  int64
+}
+
+// ProcessInstanceDescription returns a human-readable description of the
+// statement process's current state.
+func (p *statementProcess) ProcessInstanceDescription(ended bool) string {
+	if p.AccountID == "" {
+		return ""
+	}
+
+	if ended {
+		return fmt.Sprintf("stopped issuing statements for account %s", p.AccountID)
+	}
+
+	return fmt.Sprintf(
+		"issuing monthly statements for account %s, next statement starts %s",
+		p.AccountID,
+		p.PeriodStart,
+	)
+}
+
+// MarshalBinary returns the statementProcess encoded as binary data.
+func (p *statementProcess) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// UnmarshalBinary decodes binary data into the statementProcess.
+func (p *statementProcess) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// StatementProcessHandler manages the process of issuing monthly statements
+// for an account.
+//
+// A statement is issued at the end of each calendar month (UTC) from the
+// month in which the account is opened. The first statement covers the period
+// from the day the account was opened. When the account is closed, a final
+// statement is issued immediately, covering the period up to and including
+// the day of closure.
+//
+// Each credit and debit is counted towards the month in which it is recorded,
+// so a statement that is issued late does not include the transactions of the
+// following month.
+type StatementProcessHandler struct{}
+
+// New returns a new statement process instance.
+func (StatementProcessHandler) New() *statementProcess {
+	return &statementProcess{}
+}
+
+// Configure configures the behavior of the engine as it relates to this handler.
+func (StatementProcessHandler) Configure(c dogma.ProcessConfigurer) {
+	c.Identity("statement", "6ec71a5e-1b43-4a02-a241-43c5bac452e9")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.AccountOpened](),
+		dogma.HandlesEvent[*events.AccountCredited](),
+		dogma.HandlesEvent[*events.AccountDebited](),
+		dogma.HandlesEvent[*events.HoldCaptured](),
+		dogma.HandlesEvent[*events.AccountClosed](),
+		dogma.ExecutesCommand[*commands.IssueStatement](),
+		dogma.SchedulesDeadline[*StatementDue](),
+	)
+}
+
+// RouteEventToInstance returns the ID of the process instance that is targeted
+// by m.
+func (StatementProcessHandler) RouteEventToInstance(
+	_ context.Context,
+	m dogma.Event,
+) (string, bool, error) {
+	switch x := m.(type) {
+	case *events.AccountOpened:
+		return x.AccountID, true, nil
+	case *events.AccountCredited:
+		return x.AccountID, true, nil
+	case *events.AccountDebited:
+		return x.AccountID, true, nil
+	case *events.HoldCaptured:
+		return x.AccountID, true, nil
+	case *events.AccountClosed:
+		return x.AccountID, true, nil
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// HandleEvent handles an event message that has been routed to this handler.
+func (StatementProcessHandler) HandleEvent(
+	_ context.Context,
+	p *statementProcess,
+	s dogma.ProcessEventScope[*statementProcess],
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.AccountOpened:
+		if p.AccountID != "" {
+			return nil
+		}
+
+		start := s.RecordedAt().UTC().Truncate(24 * time.Hour)
+
+		s.Mutate(func(p *statementProcess) {
+			p.AccountID = x.AccountID
+			p.PeriodStart = start.Format(time.DateOnly)
+		})
+
+		scheduleStatement(s, x.AccountID, start)
+
+	case *events.AccountCredited:
+		addToStatement(s, p, x.Amount, 0)
+
+	case *events.AccountDebited:
+		addToStatement(s, p, 0, x.Amount)
+
+	case *events.HoldCaptured:
+		addToStatement(s, p, 0, x.Amount)
+
+	case *events.AccountClosed:
+		if p.AccountID == "" {
+			return nil
+		}
+
+		// the remaining balance is swept out of the account
+		addToStatement(s, p, 0, x.Balance)
+
+		// the final statement covers every month that has not yet been
+		// included in a statement
+		var total statementTotals
+		for _, t := range p.Totals {
+			total.Credits += t.Credits
+			total.Debits += t.Debits
+		}
+
+		end := s.RecordedAt().UTC().Format(time.DateOnly)
+
+		s.ExecuteCommand(&commands.IssueStatement{
+			StatementID:  statementID(p.AccountID, end),
+			AccountID:    p.AccountID,
+			PeriodStart:  p.PeriodStart,
+			PeriodEnd:    end,
+			TotalCredits: total.Credits,
+			TotalDebits:  total.Debits,
+		})
+
+		s.End()
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// HandleDeadline handles a deadline message that has been routed to this handler.
+func (StatementProcessHandler) HandleDeadline(
+	_ context.Context,
+	p *statementProcess,
+	s dogma.ProcessDeadlineScope[*statementProcess],
+	m dogma.Deadline,
+) error {
+	switch x := m.(type) {
+	case *StatementDue:
+		month := x.PeriodEnd[:7]
+		total := p.Totals[month]
+
+		s.ExecuteCommand(&commands.IssueStatement{
+			StatementID:  statementID(x.AccountID, x.PeriodEnd),
+			AccountID:    x.AccountID,
+			PeriodStart:  x.PeriodStart,
+			PeriodEnd:    x.PeriodEnd,
+			TotalCredits: total.Credits,
+			TotalDebits:  total.Debits,
+		})
+
+		end, _ := time.Parse(time.DateOnly, x.PeriodEnd)
+		next := end.AddDate(0, 0, 1)
+
+		s.Mutate(func(p *statementProcess) {
+			p.PeriodStart = next.Format(time.DateOnly)
+			delete(p.Totals, month)
+		})
+
+		scheduleStatement(s, x.AccountID, next)
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// addToStatement counts a credit or debit towards the statement for the month
+// in which it was recorded.
+func addToStatement(
+	s dogma.ProcessEventScope[*statementProcess],
+	p *statementProcess,
+	credit, debit int64,
+) {
+	if p.AccountID == "" {
+		return
+	}
+
+	month := s.RecordedAt().UTC().Format(time.DateOnly)[:7]
+
+	s.Mutate(func(p *statementProcess) {
+		if p.Totals == nil {
+			p.Totals = map[string]statementTotals{}
+		}
+
+		t := p.Totals[month]
+		t.Credits += credit
+		t.Debits += debit
+		p.Totals[month] = t
+	})
+}
+
+// scheduleStatement schedules a deadline at the end of the month that contains
+// start, at which the statement for the period beginning at start is issued.
+func scheduleStatement(
+	s dogma.ProcessScope[*statementProcess],
+	accountID string,
+	start time.Time,
+) {
+	nextMonth := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.ScheduleDeadline(
+		&StatementDue{
+			AccountID:   accountID,
+			PeriodStart: start.Format(time.DateOnly),
+			PeriodEnd:   nextMonth.AddDate(0, 0, -1).Format(time.DateOnly),
+		},
+		nextMonth,
+	)
+}
+
+// statementID returns the ID of the statement for an account that ends on the
+// given date. An account has at most one statement ending in each month.
+func statementID(accountID, periodEnd string) string {
+	return fmt.Sprintf("%s-statement-%s", accountID, periodEnd[:7])
+}
+
+// StatementDue is a deadline message notifying that the end of a statement
+// period has been reached, and that a statement is to be issued.
+type StatementDue struct {
+	AccountID   string
+	PeriodStart string
+	PeriodEnd   string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StatementDue) MessageDescription() string {
+	return fmt.Sprintf(
+		"statement for account %s is due, from %s to %s",
+		m.AccountID,
+		m.PeriodStart,
+		m.PeriodEnd,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StatementDue) Validate(dogma.DeadlineValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("StatementDue must not have an empty account ID")
+	}
+	if _, err := time.Parse(time.DateOnly, m.PeriodStart); err != nil {
+		return errors.New("StatementDue must have a valid period start date")
+	}
+	if _, err := time.Parse(time.DateOnly, m.PeriodEnd); err != nil {
+		return errors.New("StatementDue must have a valid period end date")
+	}
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StatementDue) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StatementDue) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,215 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_Statement(t *testing.T) {
+	t.Run(
+		"when a month ends",
+		func(t *testing.T) {
+			t.Run(
+				"it issues a statement summarizing the month's transactions",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        10000,
+								},
+							),
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        3000,
+									ScheduledTime: time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC)),
+							),
+							ToRecordEvent(
+								&events.StatementIssued{
+									StatementID:    "A001-statement-2001-01",
+									AccountID:      "A001",
+									PeriodStart:    "2001-01-30",
+									PeriodEnd:      "2001-01-31",
+									OpeningBalance: 0,
+									TotalCredits:   10000,
+									TotalDebits:    3000,
+									ClosingBalance: 7000,
+								},
+							),
+						).
+						Prepare(
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D002",
+									AccountID:     "A001",
+									Amount:        5000,
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.March, 1, 0, 0, 0, 0, time.UTC)),
+							),
+							ToRecordEvent(
+								&events.StatementIssued{
+									StatementID:    "A001-statement-2001-02",
+									AccountID:      "A001",
+									PeriodStart:    "2001-02-01",
+									PeriodEnd:      "2001-02-28",
+									OpeningBalance: 7000,
+									TotalCredits:   5000,
+									TotalDebits:    0,
+									ClosingBalance: 12000,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the account is closed",
+		func(t *testing.T) {
+			t.Run(
+				"it issues a final statement and stops issuing statements",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.StatementIssued{
+									StatementID:    "A001-statement-2001-01",
+									AccountID:      "A001",
+									PeriodStart:    "2001-01-30",
+									PeriodEnd:      "2001-01-30",
+									OpeningBalance: 0,
+									TotalCredits:   0,
+									TotalDebits:    0,
+									ClosingBalance: 0,
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC)),
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.StatementIssued{}),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it includes the swept balance in the total debits",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A002",
+									AccountName: "Anna Smith Savings",
+									AccountType: messages.Savings,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        10000,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID:    "X001",
+									AccountID:        "A001",
+									SweepToAccountID: "A002",
+								},
+							),
+							ToRecordEvent(
+								&events.StatementIssued{
+									StatementID:    "A001-statement-2001-01",
+									AccountID:      "A001",
+									PeriodStart:    "2001-01-30",
+									PeriodEnd:      "2001-01-30",
+									OpeningBalance: 0,
+									TotalCredits:   10000,
+									TotalDebits:    10000,
+									ClosingBalance: 0,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+}
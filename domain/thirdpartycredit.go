@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+const (
+	// DefaultThirdPartyCreditRetryWindow is the length of time after the first
+	// failed attempt to credit a third-party account that the credit is
+	// retried, if no other window is configured.
+	DefaultThirdPartyCreditRetryWindow = 24 * time.Hour
+
+	// thirdPartyCreditInitialBackoff is the delay before the first retry of a
+	// credit to a third-party account. It doubles with each further retry.
+	thirdPartyCreditInitialBackoff = 5 * time.Second
+
+	// thirdPartyCreditMaxBackoff is the longest delay between retries of a
+	// credit to a third-party account.
+	thirdPartyCreditMaxBackoff = 30 * time.Minute
+)
+
+func init() {
+	dogma.RegisterDeadline[*ThirdPartyCreditRetryDue]("67948c78-ddb7-44d4-9110-054d3d632a93")
+}
+
+// thirdPartyCreditRetryProcess is the process root for retrying a credit to a
+// third-party account.
+type thirdPartyCreditRetryProcess struct {
+	AccountID       string
+	Payee           *messages.ThirdPartyPayee
+	TransactionType messages.TransactionType
+	Amount          int64
+	Attempts        int
+	FirstFailedAt   time.Time
+}
+
+// ProcessInstanceDescription returns a human-readable description of the
+// retry's current state.
+func (p *thirdPartyCreditRetryProcess) ProcessInstanceDescription(ended bool) string {
+	if p.Attempts == 0 {
+		return ""
+	}
+
+	if ended {
+		return fmt.Sprintf(
+			"stopped retrying credit of %s to third-party account %s after %d failed attempts",
+			messages.FormatAmount(p.Amount),
+			p.AccountID,
+			p.Attempts,
+		)
+	}
+
+	return fmt.Sprintf(
+		"retrying credit of %s to third-party account %s after %d failed attempts",
+		messages.FormatAmount(p.Amount),
+		p.AccountID,
+		p.Attempts,
+	)
+}
+
+// MarshalBinary returns the thirdPartyCreditRetryProcess encoded as binary
+// data.
+func (p *thirdPartyCreditRetryProcess) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// UnmarshalBinary decodes binary data into the thirdPartyCreditRetryProcess.
+func (p *thirdPartyCreditRetryProcess) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// ThirdPartyCreditRetryProcessHandler manages the process of retrying credits
+// to third-party accounts that fail with a transient error.
+//
+// Each retry is delayed by twice as long as the one before it, up to a
+// maximum. Once the retry window has elapsed since the first failed attempt
+// the credit is abandoned, at which point it fails as though the third-party
+// bank had rejected it.
+type ThirdPartyCreditRetryProcessHandler struct {
+	// RetryWindow is the length of time after the first failed attempt that
+	// the credit is retried. If it is zero,
+	// DefaultThirdPartyCreditRetryWindow is used.
+	RetryWindow time.Duration
+}
+
+// New returns a new third-party credit retry instance.
+func (ThirdPartyCreditRetryProcessHandler) New() *thirdPartyCreditRetryProcess {
+	return &thirdPartyCreditRetryProcess{}
+}
+
+// Configure configures the behavior of the engine as it relates to this handler.
+func (ThirdPartyCreditRetryProcessHandler) Configure(c dogma.ProcessConfigurer) {
+	c.Identity("third-party-credit-retry", "9a00510f-ae52-4997-a17e-85f0947ac1fc")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.ThirdPartyCreditAttemptFailed](),
+		dogma.HandlesEvent[*events.ThirdPartyAccountCredited](),
+		dogma.HandlesEvent[*events.ThirdPartyAccountCreditFailed](),
+		dogma.ExecutesCommand[*commands.CreditThirdPartyAccount](),
+		dogma.ExecutesCommand[*commands.AbandonThirdPartyCredit](),
+		dogma.SchedulesDeadline[*ThirdPartyCreditRetryDue](),
+	)
+}
+
+// RouteEventToInstance returns the ID of the process instance that is targeted
+// by m.
+func (ThirdPartyCreditRetryProcessHandler) RouteEventToInstance(
+	_ context.Context,
+	m dogma.Event,
+) (string, bool, error) {
+	switch x := m.(type) {
+	case *events.ThirdPartyCreditAttemptFailed:
+		return x.TransactionID, true, nil
+	case *events.ThirdPartyAccountCredited:
+		return x.TransactionID, true, nil
+	case *events.ThirdPartyAccountCreditFailed:
+		return x.TransactionID, true, nil
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// HandleEvent handles an event message that has been routed to this handler.
+func (h ThirdPartyCreditRetryProcessHandler) HandleEvent(
+	_ context.Context,
+	p *thirdPartyCreditRetryProcess,
+	s dogma.ProcessEventScope[*thirdPartyCreditRetryProcess],
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.ThirdPartyCreditAttemptFailed:
+		now := s.RecordedAt()
+
+		s.Mutate(func(p *thirdPartyCreditRetryProcess) {
+			p.AccountID = x.AccountID
+			p.Payee = x.Payee
+			p.TransactionType = x.TransactionType
+			p.Amount = x.Amount
+			p.Attempts = x.Attempt
+			if p.FirstFailedAt.IsZero() {
+				p.FirstFailedAt = now
+			}
+		})
+
+		window := h.RetryWindow
+		if window == 0 {
+			window = DefaultThirdPartyCreditRetryWindow
+		}
+
+		if now.Sub(p.FirstFailedAt) >= window {
+			s.ExecuteCommand(&commands.AbandonThirdPartyCredit{
+				TransactionID:   x.TransactionID,
+				AccountID:       x.AccountID,
+				TransactionType: x.TransactionType,
+				Amount:          x.Amount,
+				Attempts:        x.Attempt,
+			})
+			return nil
+		}
+
+		s.ScheduleDeadline(
+			&ThirdPartyCreditRetryDue{
+				TransactionID: x.TransactionID,
+				Retry:         x.Attempt,
+			},
+			now.Add(thirdPartyCreditBackoff(x.Attempt)),
+		)
+
+	case *events.ThirdPartyAccountCredited,
+		*events.ThirdPartyAccountCreditFailed:
+		// The instance is ended even if the credit succeeded or failed on the
+		// first attempt, so that a late report of a failed attempt can not
+		// start retrying a credit that has already been settled.
+		s.End()
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// HandleDeadline handles a deadline message that has been routed to this handler.
+func (ThirdPartyCreditRetryProcessHandler) HandleDeadline(
+	_ context.Context,
+	p *thirdPartyCreditRetryProcess,
+	s dogma.ProcessDeadlineScope[*thirdPartyCreditRetryProcess],
+	m dogma.Deadline,
+) error {
+	switch x := m.(type) {
+	case *ThirdPartyCreditRetryDue:
+		s.ExecuteCommand(&commands.CreditThirdPartyAccount{
+			TransactionID:   x.TransactionID,
+			AccountID:       p.AccountID,
+			Payee:           p.Payee,
+			TransactionType: p.TransactionType,
+			Amount:          p.Amount,
+			Retry:           x.Retry,
+		})
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
+
+// thirdPartyCreditBackoff returns the delay before retrying a credit to a
+// third-party account after the given number of failed attempts.
+func thirdPartyCreditBackoff(attempts int) time.Duration {
+	d := thirdPartyCreditInitialBackoff
+	for i := 1; i < attempts && d < thirdPartyCreditMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, thirdPartyCreditMaxBackoff)
+}
+
+// ThirdPartyCreditRetryDue is a deadline message notifying that a credit to a
+// third-party account is to be retried.
+type ThirdPartyCreditRetryDue struct {
+	TransactionID string
+	Retry         int
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ThirdPartyCreditRetryDue) MessageDescription() string {
+	return fmt.Sprintf("retry %d of third-party credit %s is due", m.Retry, m.TransactionID)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ThirdPartyCreditRetryDue) Validate(dogma.DeadlineValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("ThirdPartyCreditRetryDue must not have an empty transaction ID")
+	}
+	if m.Retry < 1 {
+		return errors.New("ThirdPartyCreditRetryDue must have a positive retry number")
+	}
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ThirdPartyCreditRetryDue) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ThirdPartyCreditRetryDue) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
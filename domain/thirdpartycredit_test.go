@@ -0,0 +1,262 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/domain"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_ThirdPartyCreditRetry(t *testing.T) {
+	payee := &messages.ThirdPartyPayee{
+		BSB:           "062-000",
+		AccountNumber: "12345674",
+		AccountName:   "Anna Smith",
+	}
+
+	attemptFailed := func(attempt int) *events.ThirdPartyCreditAttemptFailed {
+		return &events.ThirdPartyCreditAttemptFailed{
+			TransactionID:   "T001",
+			AccountID:       "06200012345674",
+			TransactionType: messages.Transfer,
+			Amount:          100,
+			Attempt:         attempt,
+			Error:           "third-party bank responded with 503 Service Unavailable",
+			Payee:           payee,
+		}
+	}
+
+	t.Run(
+		"when an attempt to credit a third-party account fails",
+		func(t *testing.T) {
+			t.Run(
+				"it retries the credit after a delay that doubles with each attempt",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 3, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							RecordEvent(attemptFailed(1)),
+						).
+						Expect(
+							AdvanceTime(
+								ByDuration(4*time.Second),
+							),
+							NoneOf(
+								ToExecuteCommandOfType(&commands.CreditThirdPartyAccount{}),
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ByDuration(1*time.Second),
+							),
+							ToExecuteCommand(
+								&commands.CreditThirdPartyAccount{
+									TransactionID:   "T001",
+									AccountID:       "06200012345674",
+									Payee:           payee,
+									TransactionType: messages.Transfer,
+									Amount:          100,
+									Retry:           1,
+								},
+							),
+						).
+						Prepare(
+							RecordEvent(attemptFailed(2)),
+						).
+						Expect(
+							AdvanceTime(
+								ByDuration(9*time.Second),
+							),
+							NoneOf(
+								ToExecuteCommandOfType(&commands.CreditThirdPartyAccount{}),
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ByDuration(1*time.Second),
+							),
+							ToExecuteCommand(
+								&commands.CreditThirdPartyAccount{
+									TransactionID:   "T001",
+									AccountID:       "06200012345674",
+									Payee:           payee,
+									TransactionType: messages.Transfer,
+									Amount:          100,
+									Retry:           2,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it abandons the credit once the retry window has elapsed",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{
+							ThirdPartyCreditRetryProcess: domain.ThirdPartyCreditRetryProcessHandler{
+								RetryWindow: time.Minute,
+							},
+						},
+						StartTimeAt(
+							time.Date(2001, time.February, 3, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							RecordEvent(attemptFailed(1)),
+							AdvanceTime(ByDuration(time.Minute)),
+						).
+						Expect(
+							RecordEvent(attemptFailed(2)),
+							ToExecuteCommand(
+								&commands.AbandonThirdPartyCredit{
+									TransactionID:   "T001",
+									AccountID:       "06200012345674",
+									TransactionType: messages.Transfer,
+									Amount:          100,
+									Attempts:        2,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it stops retrying once the credit succeeds",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 3, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+							ExecuteCommand(
+								&commands.Transfer{
+									TransactionID:    "T001",
+									FromAccountID:    "A001",
+									ToAccountID:      "06200012345674",
+									Amount:           100,
+									ScheduledTime:    time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+									ToThirdPartyBank: true,
+									ToPayee:          thirdPartyPayee,
+								},
+							),
+							RecordEvent(attemptFailed(1)),
+							RecordEvent(
+								&events.ThirdPartyAccountCredited{
+									TransactionID:   "T001",
+									AccountID:       "06200012345674",
+									TransactionType: messages.Transfer,
+									Amount:          100,
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ByDuration(time.Hour),
+							),
+							NoneOf(
+								ToExecuteCommandOfType(&commands.CreditThirdPartyAccount{}),
+							),
+						)
+				},
+			)
+		},
+	)
+	t.Run(
+		"when the first attempt to credit a third-party account settles the credit",
+		func(t *testing.T) {
+			t.Run(
+				"it does not retry a failed attempt that is reported afterwards",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 3, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							RecordEvent(
+								&events.ThirdPartyAccountCredited{
+									TransactionID:   "T001",
+									AccountID:       "06200012345674",
+									TransactionType: messages.Transfer,
+									Amount:          100,
+								},
+							),
+							RecordEvent(attemptFailed(1)),
+						).
+						Expect(
+							AdvanceTime(
+								ByDuration(time.Hour),
+							),
+							NoneOf(
+								ToExecuteCommandOfType(&commands.CreditThirdPartyAccount{}),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it does not retry after the credit fails outright",
+				func(t *testing.T) {
+					Begin(
+						t,
+						&example.App{},
+						StartTimeAt(
+							time.Date(2001, time.February, 3, 11, 22, 33, 0, time.UTC),
+						),
+					).
+						Prepare(
+							RecordEvent(
+								&events.ThirdPartyAccountCreditFailed{
+									TransactionID:   "T001",
+									AccountID:       "06200012345674",
+									TransactionType: messages.Transfer,
+									Amount:          100,
+								},
+							),
+							RecordEvent(attemptFailed(1)),
+						).
+						Expect(
+							AdvanceTime(
+								ByDuration(time.Hour),
+							),
+							NoneOf(
+								ToExecuteCommandOfType(&commands.CreditThirdPartyAccount{}),
+							),
+						)
+				},
+			)
+		},
+	)
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+// thirdPartyPaymentProcess is the process root for crediting an account with a
+// payment received from a third-party bank.
+type thirdPartyPaymentProcess struct {
+	ThirdPartyTransactionID string
+	AccountID               string
+	SenderAccountID         string
+	Amount                  int64
+}
+
+// ProcessInstanceDescription returns a human-readable description of the
+// payment's current state.
+func (p *thirdPartyPaymentProcess) ProcessInstanceDescription(ended bool) string {
+	if p.AccountID == "" {
+		return ""
+	}
+
+	if ended {
+		return fmt.Sprintf(
+			"processed payment of %s from third-party account %s into account %s",
+			messages.FormatAmount(p.Amount),
+			p.SenderAccountID,
+			p.AccountID,
+		)
+	}
+
+	return fmt.Sprintf(
+		"processing payment of %s from third-party account %s into account %s",
+		messages.FormatAmount(p.Amount),
+		p.SenderAccountID,
+		p.AccountID,
+	)
+}
+
+// MarshalBinary returns the thirdPartyPaymentProcess encoded as binary data.
+func (p *thirdPartyPaymentProcess) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// UnmarshalBinary decodes binary data into the thirdPartyPaymentProcess.
+func (p *thirdPartyPaymentProcess) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// ThirdPartyPaymentProcessHandler manages the process of crediting an account
+// with a payment received from a third-party bank.
+//
+// If the account can not be credited, for example because it does not exist or
+// has been closed, the payment is returned to the sender.
+type ThirdPartyPaymentProcessHandler struct {
+	dogma.NoDeadlineMessagesBehavior[*thirdPartyPaymentProcess]
+}
+
+// New returns a new third-party payment instance.
+func (ThirdPartyPaymentProcessHandler) New() *thirdPartyPaymentProcess {
+	return &thirdPartyPaymentProcess{}
+}
+
+// Configure configures the behavior of the engine as it relates to this handler.
+func (ThirdPartyPaymentProcessHandler) Configure(c dogma.ProcessConfigurer) {
+	c.Identity("third-party-payment", "a248aa30-a045-4e48-882c-adeedc449894")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.ThirdPartyPaymentReceived](),
+		dogma.HandlesEvent[*events.AccountCredited](),
+		dogma.HandlesEvent[*events.AccountCreditDeclined](),
+		dogma.HandlesEvent[*events.ThirdPartyPaymentReturned](),
+		dogma.ExecutesCommand[*commands.CreditAccount](),
+		dogma.ExecutesCommand[*commands.ReturnThirdPartyPayment](),
+	)
+}
+
+// RouteEventToInstance returns the ID of the process instance that is targeted
+// by m.
+func (ThirdPartyPaymentProcessHandler) RouteEventToInstance(
+	_ context.Context,
+	m dogma.Event,
+) (string, bool, error) {
+	switch x := m.(type) {
+	case *events.ThirdPartyPaymentReceived:
+		return x.TransactionID, true, nil
+	case *events.AccountCredited:
+		return x.TransactionID, x.TransactionType == messages.ThirdPartyPayment, nil
+	case *events.AccountCreditDeclined:
+		return x.TransactionID, x.TransactionType == messages.ThirdPartyPayment, nil
+	case *events.ThirdPartyPaymentReturned:
+		return x.TransactionID, true, nil
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// HandleEvent handles an event message that has been routed to this handler.
+func (ThirdPartyPaymentProcessHandler) HandleEvent(
+	_ context.Context,
+	p *thirdPartyPaymentProcess,
+	s dogma.ProcessEventScope[*thirdPartyPaymentProcess],
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.ThirdPartyPaymentReceived:
+		if p.AccountID != "" {
+			// the third-party bank has notified us of the same payment
+			// more than once
+			s.Log("payment has already been received")
+			return nil
+		}
+
+		s.Mutate(func(p *thirdPartyPaymentProcess) {
+			p.ThirdPartyTransactionID = x.ThirdPartyTransactionID
+			p.AccountID = x.AccountID
+			p.SenderAccountID = x.SenderAccountID
+			p.Amount = x.Amount
+		})
+
+		s.ExecuteCommand(&commands.CreditAccount{
+			TransactionID:   x.TransactionID,
+			AccountID:       x.AccountID,
+			TransactionType: messages.ThirdPartyPayment,
+			Amount:          x.Amount,
+		})
+
+	case *events.AccountCredited:
+		s.End()
+
+	case *events.AccountCreditDeclined:
+		s.ExecuteCommand(&commands.ReturnThirdPartyPayment{
+			TransactionID:           x.TransactionID,
+			ThirdPartyTransactionID: p.ThirdPartyTransactionID,
+			AccountID:               x.AccountID,
+			SenderAccountID:         p.SenderAccountID,
+			Amount:                  x.Amount,
+			Reason:                  x.Reason,
+		})
+
+	case *events.ThirdPartyPaymentReturned:
+		s.End()
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_ThirdPartyPayment(t *testing.T) {
+	received := &events.ThirdPartyPaymentReceived{
+		TransactionID:           "P001",
+		ThirdPartyTransactionID: "TPBP00000001",
+		AccountID:               "A001",
+		SenderAccountID:         "200001",
+		Amount:                  500,
+	}
+
+	t.Run(
+		"when a payment is received from a third-party bank",
+		func(t *testing.T) {
+			t.Run(
+				"it credits the account",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+						).
+						Expect(
+							RecordEvent(received),
+							ToRecordEvent(
+								&events.AccountCredited{
+									TransactionID:   "P001",
+									AccountID:       "A001",
+									TransactionType: messages.ThirdPartyPayment,
+									Amount:          500,
+								},
+							),
+						).
+						// verify that the funds are available
+						Expect(
+							ExecuteCommand(
+								&commands.Withdraw{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        500,
+									ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+								},
+							),
+							ToRecordEvent(
+								&events.WithdrawalApproved{
+									TransactionID: "W001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it credits the account only once if the payment is received more than once",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							RecordEvent(received),
+						).
+						Expect(
+							RecordEvent(received),
+							NoneOf(
+								ToExecuteCommandOfType(&commands.CreditAccount{}),
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it returns the payment if the account does not exist",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Expect(
+							RecordEvent(received),
+							ToExecuteCommand(
+								&commands.ReturnThirdPartyPayment{
+									TransactionID:           "P001",
+									ThirdPartyTransactionID: "TPBP00000001",
+									AccountID:               "A001",
+									SenderAccountID:         "200001",
+									Amount:                  500,
+									Reason:                  messages.AccountNotFound,
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it returns the payment if the account is closed",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.CloseAccount{
+									TransactionID: "X001",
+									AccountID:     "A001",
+								},
+							),
+						).
+						Expect(
+							RecordEvent(received),
+							ToExecuteCommand(
+								&commands.ReturnThirdPartyPayment{
+									TransactionID:           "P001",
+									ThirdPartyTransactionID: "TPBP00000001",
+									AccountID:               "A001",
+									SenderAccountID:         "200001",
+									Amount:                  500,
+									Reason:                  messages.AccountClosed,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+}
@@ -16,6 +16,10 @@ type transaction struct {
 	Type   string
 	Amount int64
 	Status string
+
+	// FromAccountID and ToAccountID are the accounts involved in a transfer.
+	FromAccountID string
+	ToAccountID   string
 }
 
 func (t *transaction) AggregateInstanceDescription() string {
@@ -33,7 +37,7 @@ func (t *transaction) AggregateInstanceDescription() string {
 
 func (t *transaction) StartDeposit(s dogma.AggregateCommandScope[*transaction], m *commands.Deposit) {
 	if t.Status != "" {
-		s.Log("transaction already started")
+		s.Log("transaction already started, ignoring retry (%s)", t.Status)
 		return
 	}
 
@@ -52,9 +56,18 @@ func (t *transaction) ApproveDeposit(s dogma.AggregateCommandScope[*transaction]
 	})
 }
 
+func (t *transaction) DeclineDeposit(s dogma.AggregateCommandScope[*transaction], m *commands.DeclineDeposit) {
+	s.RecordEvent(&events.DepositDeclined{
+		TransactionID: m.TransactionID,
+		AccountID:     m.AccountID,
+		Amount:        m.Amount,
+		Reason:        m.Reason,
+	})
+}
+
 func (t *transaction) StartWithdraw(s dogma.AggregateCommandScope[*transaction], m *commands.Withdraw) {
 	if t.Status != "" {
-		s.Log("transaction already started")
+		s.Log("transaction already started, ignoring retry (%s)", t.Status)
 		return
 	}
 
@@ -85,7 +98,7 @@ func (t *transaction) DeclineWithdrawal(s dogma.AggregateCommandScope[*transacti
 
 func (t *transaction) StartTransfer(s dogma.AggregateCommandScope[*transaction], m *commands.Transfer) {
 	if t.Status != "" {
-		s.Log("transaction already started")
+		s.Log("transaction already started, ignoring retry (%s)", t.Status)
 		return
 	}
 
@@ -101,6 +114,43 @@ func (t *transaction) StartTransfer(s dogma.AggregateCommandScope[*transaction],
 		ToThirdPartyBank: m.ToThirdPartyBank,
 		Amount:           m.Amount,
 		ScheduledTime:    m.ScheduledTime,
+		ToPayee:          m.ToPayee,
+		CustomerID:       m.CustomerID,
+		PayeeID:          m.PayeeID,
+	})
+}
+
+func (t *transaction) ProceedWithTransfer(s dogma.AggregateCommandScope[*transaction], m *commands.ProceedWithTransfer) {
+	if t.Type != "transfer" || t.Status != "pending" {
+		s.Log("transfer is not pending")
+		return
+	}
+
+	s.RecordEvent(&events.TransferProceeded{
+		TransactionID: m.TransactionID,
+		FromAccountID: t.FromAccountID,
+		ToAccountID:   t.ToAccountID,
+		Amount:        t.Amount,
+		ScheduledTime: m.ScheduledTime,
+	})
+}
+
+func (t *transaction) CancelTransfer(s dogma.AggregateCommandScope[*transaction], m *commands.CancelTransfer) {
+	if t.Type != "transfer" || t.Status != "pending" {
+		s.Log("transfer is not pending, it can no longer be cancelled")
+		return
+	}
+
+	if t.FromAccountID != m.FromAccountID {
+		s.Log("transfer is not from account %s", m.FromAccountID)
+		return
+	}
+
+	s.RecordEvent(&events.TransferCancelled{
+		TransactionID: m.TransactionID,
+		FromAccountID: t.FromAccountID,
+		ToAccountID:   t.ToAccountID,
+		Amount:        t.Amount,
 	})
 }
 
@@ -140,6 +190,8 @@ func (t *transaction) ApplyEvent(m dogma.Event) {
 		t.Status = "pending"
 	case *events.DepositApproved:
 		t.Status = "approved"
+	case *events.DepositDeclined:
+		t.Status = "declined: " + string(m.Reason)
 
 	case *events.WithdrawalStarted:
 		t.Type = "withdrawal"
@@ -154,6 +206,12 @@ func (t *transaction) ApplyEvent(m dogma.Event) {
 		t.Type = "transfer"
 		t.Amount = m.Amount
 		t.Status = "pending"
+		t.FromAccountID = m.FromAccountID
+		t.ToAccountID = m.ToAccountID
+	case *events.TransferProceeded:
+		t.Status = "in progress"
+	case *events.TransferCancelled:
+		t.Status = "cancelled"
 	case *events.TransferApproved:
 		t.Status = "approved"
 	case *events.TransferDeclined:
@@ -166,7 +224,11 @@ func (t *transaction) ApplyEvent(m dogma.Event) {
 // TransactionHandler implements the business logic for a transaction of any
 // kind against an account.
 //
-// It's sole purpose is to ensure the global uniqueness of transaction IDs.
+// Its main purpose is to ensure the global uniqueness of transaction IDs. A
+// command that starts a transaction with an ID that is already in use is
+// treated as a retry and ignored, so that clients may safely resend a request
+// and then look up the outcome of the original transaction. It also ensures
+// that a scheduled transfer can only be cancelled before it proceeds.
 type TransactionHandler struct{}
 
 // New returns a new transaction instance.
@@ -182,6 +244,7 @@ func (TransactionHandler) Configure(c dogma.AggregateConfigurer) {
 	c.Routes(
 		dogma.HandlesCommand[*commands.Deposit](),
 		dogma.HandlesCommand[*commands.ApproveDeposit](),
+		dogma.HandlesCommand[*commands.DeclineDeposit](),
 		dogma.HandlesCommand[*commands.Withdraw](),
 		dogma.HandlesCommand[*commands.ApproveWithdrawal](),
 		dogma.HandlesCommand[*commands.DeclineWithdrawal](),
@@ -189,8 +252,11 @@ func (TransactionHandler) Configure(c dogma.AggregateConfigurer) {
 		dogma.HandlesCommand[*commands.ApproveTransfer](),
 		dogma.HandlesCommand[*commands.DeclineTransfer](),
 		dogma.HandlesCommand[*commands.MarkTransferAsFailed](),
+		dogma.HandlesCommand[*commands.ProceedWithTransfer](),
+		dogma.HandlesCommand[*commands.CancelTransfer](),
 		dogma.RecordsEvent[*events.DepositStarted](),
 		dogma.RecordsEvent[*events.DepositApproved](),
+		dogma.RecordsEvent[*events.DepositDeclined](),
 		dogma.RecordsEvent[*events.WithdrawalStarted](),
 		dogma.RecordsEvent[*events.WithdrawalApproved](),
 		dogma.RecordsEvent[*events.WithdrawalDeclined](),
@@ -198,6 +264,8 @@ func (TransactionHandler) Configure(c dogma.AggregateConfigurer) {
 		dogma.RecordsEvent[*events.TransferApproved](),
 		dogma.RecordsEvent[*events.TransferDeclined](),
 		dogma.RecordsEvent[*events.TransferFailed](),
+		dogma.RecordsEvent[*events.TransferProceeded](),
+		dogma.RecordsEvent[*events.TransferCancelled](),
 	)
 }
 
@@ -209,6 +277,8 @@ func (TransactionHandler) RouteCommandToInstance(m dogma.Command) string {
 		return x.TransactionID
 	case *commands.ApproveDeposit:
 		return x.TransactionID
+	case *commands.DeclineDeposit:
+		return x.TransactionID
 	case *commands.Withdraw:
 		return x.TransactionID
 	case *commands.ApproveWithdrawal:
@@ -223,6 +293,10 @@ func (TransactionHandler) RouteCommandToInstance(m dogma.Command) string {
 		return x.TransactionID
 	case *commands.MarkTransferAsFailed:
 		return x.TransactionID
+	case *commands.ProceedWithTransfer:
+		return x.TransactionID
+	case *commands.CancelTransfer:
+		return x.TransactionID
 	default:
 		panic(dogma.UnexpectedMessage)
 	}
@@ -240,6 +314,8 @@ func (TransactionHandler) HandleCommand(
 		t.StartDeposit(s, x)
 	case *commands.ApproveDeposit:
 		t.ApproveDeposit(s, x)
+	case *commands.DeclineDeposit:
+		t.DeclineDeposit(s, x)
 	case *commands.Withdraw:
 		t.StartWithdraw(s, x)
 	case *commands.ApproveWithdrawal:
@@ -254,6 +330,10 @@ func (TransactionHandler) HandleCommand(
 		t.DeclineTransfer(s, x)
 	case *commands.MarkTransferAsFailed:
 		t.MarkTransferAsFailed(s, x)
+	case *commands.ProceedWithTransfer:
+		t.ProceedWithTransfer(s, x)
+	case *commands.CancelTransfer:
+		t.CancelTransfer(s, x)
 	default:
 		panic(dogma.UnexpectedMessage)
 	}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/example/messages"
@@ -21,8 +22,13 @@ type transferProcess struct {
 	FromAccountID    string
 	ToAccountID      string
 	ToThirdPartyBank bool
+	ToPayee          *messages.ThirdPartyPayee
 	Amount           int64
+	ScheduledTime    time.Time
+	CustomerID       string
+	PayeeID          string
 	DeclineReason    messages.DebitFailureReason
+	Cancelled        bool
 }
 
 // ProcessInstanceDescription returns a human-readable description of the
@@ -41,6 +47,15 @@ func (p *transferProcess) ProcessInstanceDescription(ended bool) string {
 		)
 	}
 
+	if p.Cancelled {
+		return fmt.Sprintf(
+			"transfer of %s from %s to %s cancelled",
+			messages.FormatAmount(p.Amount),
+			p.FromAccountID,
+			p.ToAccountID,
+		)
+	}
+
 	if p.DeclineReason != "" {
 		return fmt.Sprintf(
 			"transfer of %s from %s to %s declined: %s",
@@ -71,6 +86,11 @@ func (p *transferProcess) UnmarshalBinary(data []byte) error {
 
 // TransferProcessHandler manages the process of transferring funds between
 // accounts.
+//
+// A transfer that is scheduled for a later time may be cancelled up until that
+// time is reached, at which point the transfer proceeds and the "from" account
+// is debited. A transfer to a saved payee is then counted towards the limit on
+// transfers to the payee during its cooling-off period, if any.
 type TransferProcessHandler struct{}
 
 // New returns a new transfer instance.
@@ -84,23 +104,31 @@ func (TransferProcessHandler) Configure(c dogma.ProcessConfigurer) {
 
 	c.Routes(
 		dogma.HandlesEvent[*events.TransferStarted](),
+		dogma.HandlesEvent[*events.TransferProceeded](),
+		dogma.HandlesEvent[*events.TransferCancelled](),
 		dogma.HandlesEvent[*events.AccountDebited](),
 		dogma.HandlesEvent[*events.AccountDebitDeclined](),
 		dogma.HandlesEvent[*events.DailyDebitLimitConsumed](),
 		dogma.HandlesEvent[*events.DailyDebitLimitExceeded](),
+		dogma.HandlesEvent[*events.PayeeCoolingOffLimitConsumed](),
+		dogma.HandlesEvent[*events.PayeeCoolingOffLimitExceeded](),
 		dogma.HandlesEvent[*events.AccountCredited](),
+		dogma.HandlesEvent[*events.AccountCreditDeclined](),
 		dogma.HandlesEvent[*events.ThirdPartyAccountCredited](),
 		dogma.HandlesEvent[*events.ThirdPartyAccountCreditFailed](),
 		dogma.HandlesEvent[*events.TransferApproved](),
 		dogma.HandlesEvent[*events.TransferDeclined](),
 		dogma.HandlesEvent[*events.TransferFailed](),
+		dogma.ExecutesCommand[*commands.ProceedWithTransfer](),
 		dogma.ExecutesCommand[*commands.DebitAccount](),
 		dogma.ExecutesCommand[*commands.ConsumeDailyDebitLimit](),
+		dogma.ExecutesCommand[*commands.ConsumePayeeCoolingOffLimit](),
 		dogma.ExecutesCommand[*commands.CreditAccount](),
 		dogma.ExecutesCommand[*commands.CreditThirdPartyAccount](),
 		dogma.ExecutesCommand[*commands.ApproveTransfer](),
 		dogma.ExecutesCommand[*commands.DeclineTransfer](),
 		dogma.ExecutesCommand[*commands.MarkTransferAsFailed](),
+		dogma.ExecutesCommand[*commands.SettleDebit](),
 		dogma.SchedulesDeadline[*TransferReadyToProceed](),
 	)
 }
@@ -114,6 +142,10 @@ func (TransferProcessHandler) RouteEventToInstance(
 	switch x := m.(type) {
 	case *events.TransferStarted:
 		return x.TransactionID, true, nil
+	case *events.TransferProceeded:
+		return x.TransactionID, true, nil
+	case *events.TransferCancelled:
+		return x.TransactionID, true, nil
 	case *events.AccountDebited:
 		return x.TransactionID, x.TransactionType == messages.Transfer, nil
 	case *events.AccountDebitDeclined:
@@ -122,12 +154,18 @@ func (TransferProcessHandler) RouteEventToInstance(
 		return x.TransactionID, x.DebitType == messages.Transfer, nil
 	case *events.DailyDebitLimitExceeded:
 		return x.TransactionID, x.DebitType == messages.Transfer, nil
+	case *events.PayeeCoolingOffLimitConsumed:
+		return x.TransactionID, x.TransactionType == messages.Transfer, nil
+	case *events.PayeeCoolingOffLimitExceeded:
+		return x.TransactionID, x.TransactionType == messages.Transfer, nil
 	case *events.AccountCredited:
 		return x.TransactionID, x.TransactionType == messages.Transfer, nil
+	case *events.AccountCreditDeclined:
+		return x.TransactionID, x.TransactionType == messages.Transfer, nil
 	case *events.ThirdPartyAccountCredited:
-		return x.TransactionID, true, nil
+		return x.TransactionID, x.TransactionType == messages.Transfer, nil
 	case *events.ThirdPartyAccountCreditFailed:
-		return x.TransactionID, true, nil
+		return x.TransactionID, x.TransactionType == messages.Transfer, nil
 	case *events.TransferApproved:
 		return x.TransactionID, true, nil
 	case *events.TransferDeclined:
@@ -152,7 +190,11 @@ func (TransferProcessHandler) HandleEvent(
 			t.FromAccountID = x.FromAccountID
 			t.ToAccountID = x.ToAccountID
 			t.ToThirdPartyBank = x.ToThirdPartyBank
+			t.ToPayee = x.ToPayee
 			t.Amount = x.Amount
+			t.ScheduledTime = x.ScheduledTime
+			t.CustomerID = x.CustomerID
+			t.PayeeID = x.PayeeID
 		})
 
 		s.ScheduleDeadline(
@@ -162,6 +204,15 @@ func (TransferProcessHandler) HandleEvent(
 			x.ScheduledTime,
 		)
 
+	case *events.TransferProceeded:
+		s.ExecuteCommand(&commands.DebitAccount{
+			TransactionID:   x.TransactionID,
+			AccountID:       x.FromAccountID,
+			TransactionType: messages.Transfer,
+			Amount:          x.Amount,
+			ScheduledTime:   x.ScheduledTime,
+		})
+
 	case *events.AccountDebited:
 		s.ExecuteCommand(&commands.ConsumeDailyDebitLimit{
 			TransactionID: x.TransactionID,
@@ -169,6 +220,7 @@ func (TransferProcessHandler) HandleEvent(
 			DebitType:     messages.Transfer,
 			Amount:        x.Amount,
 			Date:          messages.DailyDebitLimitDate(x.ScheduledTime),
+			DailyLimit:    x.DailyDebitLimit,
 		})
 
 	case *events.AccountDebitDeclined:
@@ -181,21 +233,22 @@ func (TransferProcessHandler) HandleEvent(
 		})
 
 	case *events.DailyDebitLimitConsumed:
-		if t.ToThirdPartyBank {
-			s.ExecuteCommand(&commands.CreditThirdPartyAccount{
-				TransactionID: x.TransactionID,
-				AccountID:     t.ToAccountID,
-				Amount:        x.Amount,
-			})
-		} else {
-			s.ExecuteCommand(&commands.CreditAccount{
+		if t.PayeeID != "" {
+			s.ExecuteCommand(&commands.ConsumePayeeCoolingOffLimit{
 				TransactionID:   x.TransactionID,
-				AccountID:       t.ToAccountID,
+				CustomerID:      t.CustomerID,
+				PayeeID:         t.PayeeID,
 				TransactionType: messages.Transfer,
 				Amount:          x.Amount,
+				ScheduledTime:   t.ScheduledTime,
 			})
+		} else {
+			creditRecipient(s, t, x.TransactionID, x.Amount)
 		}
 
+	case *events.PayeeCoolingOffLimitConsumed:
+		creditRecipient(s, t, x.TransactionID, x.Amount)
+
 	case *events.DailyDebitLimitExceeded:
 		s.Mutate(func(t *transferProcess) {
 			t.DeclineReason = messages.DailyDebitLimitExceeded
@@ -209,6 +262,19 @@ func (TransferProcessHandler) HandleEvent(
 			Amount:          x.Amount,
 		})
 
+	case *events.PayeeCoolingOffLimitExceeded:
+		s.Mutate(func(t *transferProcess) {
+			t.DeclineReason = messages.PayeeCoolingOffLimitExceeded
+		})
+
+		// compensate the initial debit
+		s.ExecuteCommand(&commands.CreditAccount{
+			TransactionID:   x.TransactionID,
+			AccountID:       t.FromAccountID,
+			TransactionType: messages.Transfer,
+			Amount:          x.Amount,
+		})
+
 	case *events.AccountCredited:
 		if t.ToAccountID == x.AccountID {
 			// it was a credit to complete the transfer (success)
@@ -229,6 +295,19 @@ func (TransferProcessHandler) HandleEvent(
 			})
 		}
 
+	case *events.AccountCreditDeclined:
+		s.Mutate(func(t *transferProcess) {
+			t.DeclineReason = x.Reason
+		})
+
+		// compensate the initial debit
+		s.ExecuteCommand(&commands.CreditAccount{
+			TransactionID:   x.TransactionID,
+			AccountID:       t.FromAccountID,
+			TransactionType: messages.Transfer,
+			Amount:          x.Amount,
+		})
+
 	case *events.ThirdPartyAccountCredited:
 		s.ExecuteCommand(&commands.ApproveTransfer{
 			TransactionID: x.TransactionID,
@@ -252,7 +331,20 @@ func (TransferProcessHandler) HandleEvent(
 			Amount:          t.Amount,
 		})
 
-	case *events.TransferApproved, *events.TransferDeclined, *events.TransferFailed:
+	case *events.TransferApproved:
+		s.ExecuteCommand(&commands.SettleDebit{
+			TransactionID: x.TransactionID,
+			AccountID:     x.FromAccountID,
+		})
+		s.End()
+
+	case *events.TransferCancelled:
+		s.Mutate(func(t *transferProcess) {
+			t.Cancelled = true
+		})
+		s.End()
+
+	case *events.TransferDeclined, *events.TransferFailed:
 		s.End()
 
 	default:
@@ -262,21 +354,46 @@ func (TransferProcessHandler) HandleEvent(
 	return nil
 }
 
+// creditRecipient executes the command that credits the "to" account of a
+// transfer, once the funds have been debited and all limits checked.
+func creditRecipient(
+	s dogma.ProcessEventScope[*transferProcess],
+	t *transferProcess,
+	transactionID string,
+	amount int64,
+) {
+	if t.ToThirdPartyBank {
+		s.ExecuteCommand(&commands.CreditThirdPartyAccount{
+			TransactionID:   transactionID,
+			AccountID:       t.ToAccountID,
+			Payee:           t.ToPayee,
+			TransactionType: messages.Transfer,
+			Amount:          amount,
+		})
+	} else {
+		s.ExecuteCommand(&commands.CreditAccount{
+			TransactionID:   transactionID,
+			AccountID:       t.ToAccountID,
+			TransactionType: messages.Transfer,
+			Amount:          amount,
+		})
+	}
+}
+
 // HandleDeadline handles a deadline message that has been routed to this handler.
 func (TransferProcessHandler) HandleDeadline(
 	_ context.Context,
-	t *transferProcess,
+	_ *transferProcess,
 	s dogma.ProcessDeadlineScope[*transferProcess],
 	m dogma.Deadline,
 ) error {
 	switch x := m.(type) {
 	case *TransferReadyToProceed:
-		s.ExecuteCommand(&commands.DebitAccount{
-			TransactionID:   x.TransactionID,
-			AccountID:       t.FromAccountID,
-			TransactionType: messages.Transfer,
-			Amount:          t.Amount,
-			ScheduledTime:   s.ScheduledFor(),
+		// the transaction aggregate has the final say on whether the transfer
+		// proceeds, as it may have been cancelled in the meantime
+		s.ExecuteCommand(&commands.ProceedWithTransfer{
+			TransactionID: x.TransactionID,
+			ScheduledTime: s.ScheduledFor(),
 		})
 
 	default:
@@ -19,9 +19,10 @@ func Test_Transfer(t *testing.T) {
 		{"it transfers to an in-house account", &commands.Transfer{
 			ToAccountID: "A002",
 		}},
-		{"it transfers to a third-party account", &commands.Transfer{
-			ToAccountID:      "100001",
+		{"it transfers to a third-party payee", &commands.Transfer{
+			ToAccountID:      "06200012345674",
 			ToThirdPartyBank: true,
+			ToPayee:          thirdPartyPayee,
 		}},
 	}
 
@@ -53,6 +54,7 @@ func Test_Transfer(t *testing.T) {
 										CustomerID:  "C001",
 										AccountID:   "A001",
 										AccountName: "Anna Smith",
+										AccountType: messages.Everyday,
 									},
 								),
 								ExecuteCommand(
@@ -60,6 +62,7 @@ func Test_Transfer(t *testing.T) {
 										CustomerID:  "C002",
 										AccountID:   "A002",
 										AccountName: "Bob Jones",
+										AccountType: messages.Everyday,
 									},
 								),
 								ExecuteCommand(
@@ -100,6 +103,7 @@ func Test_Transfer(t *testing.T) {
 										CustomerID:  "C001",
 										AccountID:   "A001",
 										AccountName: "Anna Smith",
+										AccountType: messages.Everyday,
 									},
 								),
 								ExecuteCommand(
@@ -107,6 +111,7 @@ func Test_Transfer(t *testing.T) {
 										CustomerID:  "C002",
 										AccountID:   "A002",
 										AccountName: "Bob Jones",
+										AccountType: messages.Everyday,
 									},
 								),
 								ExecuteCommand(
@@ -151,6 +156,7 @@ func Test_Transfer(t *testing.T) {
 										CustomerID:  "C001",
 										AccountID:   "A001",
 										AccountName: "Anna Smith",
+										AccountType: messages.Everyday,
 									},
 								),
 								ExecuteCommand(
@@ -158,6 +164,7 @@ func Test_Transfer(t *testing.T) {
 										CustomerID:  "C002",
 										AccountID:   "A002",
 										AccountName: "Bob Jones",
+										AccountType: messages.Everyday,
 									},
 								),
 								ExecuteCommand(
@@ -206,6 +213,7 @@ func Test_Transfer(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -213,6 +221,7 @@ func Test_Transfer(t *testing.T) {
 									CustomerID:  "C002",
 									AccountID:   "A002",
 									AccountName: "Bob Jones",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -280,6 +289,7 @@ func Test_Transfer(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -287,6 +297,7 @@ func Test_Transfer(t *testing.T) {
 									CustomerID:  "C002",
 									AccountID:   "A002",
 									AccountName: "Bob Jones",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -357,6 +368,7 @@ func Test_Transfer(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -370,26 +382,28 @@ func Test_Transfer(t *testing.T) {
 								&commands.Transfer{
 									TransactionID:    "T001",
 									FromAccountID:    "A001",
-									ToAccountID:      "100001",
+									ToAccountID:      "06200012345674",
 									Amount:           100,
 									ScheduledTime:    time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
 									ToThirdPartyBank: true,
+									ToPayee:          thirdPartyPayee,
 								},
 							),
 						).
 						Expect(
 							RecordEvent(
 								&events.ThirdPartyAccountCreditFailed{
-									TransactionID: "T001",
-									AccountID:     "100001",
-									Amount:        100,
+									TransactionID:   "T001",
+									AccountID:       "06200012345674",
+									TransactionType: messages.Transfer,
+									Amount:          100,
 								},
 							),
 							ToRecordEvent(
 								&events.TransferFailed{
 									TransactionID: "T001",
 									FromAccountID: "A001",
-									ToAccountID:   "100001",
+									ToAccountID:   "06200012345674",
 									Amount:        100,
 								},
 							),
@@ -417,3 +431,137 @@ func Test_Transfer(t *testing.T) {
 		},
 	)
 }
+
+func Test_CancelTransfer(t *testing.T) {
+	prepare := func(t *testing.T) *Test {
+		return Begin(
+			t,
+			&example.App{},
+			StartTimeAt(
+				time.Date(2001, time.February, 3, 11, 22, 33, 0, time.UTC),
+			),
+		).
+			Prepare(
+				ExecuteCommand(
+					&commands.OpenAccount{
+						CustomerID:  "C001",
+						AccountID:   "A001",
+						AccountName: "Anna Smith",
+						AccountType: messages.Everyday,
+					},
+				),
+				ExecuteCommand(
+					&commands.OpenAccount{
+						CustomerID:  "C002",
+						AccountID:   "A002",
+						AccountName: "Bob Jones",
+						AccountType: messages.Everyday,
+					},
+				),
+				ExecuteCommand(
+					&commands.Deposit{
+						TransactionID: "D001",
+						AccountID:     "A001",
+						Amount:        500,
+					},
+				),
+				ExecuteCommand(
+					&commands.Transfer{
+						TransactionID: "T001",
+						FromAccountID: "A001",
+						ToAccountID:   "A002",
+						Amount:        100,
+						ScheduledTime: time.Date(2001, time.February, 4, 0, 0, 0, 0, time.UTC),
+					},
+				),
+			)
+	}
+
+	t.Run(
+		"when the transfer has not reached its scheduled time",
+		func(t *testing.T) {
+			t.Run(
+				"it cancels the transfer without debiting the account",
+				func(t *testing.T) {
+					prepare(t).
+						Expect(
+							ExecuteCommand(
+								&commands.CancelTransfer{
+									TransactionID: "T001",
+									FromAccountID: "A001",
+								},
+							),
+							ToRecordEvent(
+								&events.TransferCancelled{
+									TransactionID: "T001",
+									FromAccountID: "A001",
+									ToAccountID:   "A002",
+									Amount:        100,
+								},
+							),
+						).
+						Expect(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 4, 0, 0, 0, 0, time.UTC)),
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.TransferProceeded{}),
+								ToRecordEventOfType(&events.AccountDebited{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the transfer has already proceeded",
+		func(t *testing.T) {
+			t.Run(
+				"it does not cancel the transfer",
+				func(t *testing.T) {
+					prepare(t).
+						Prepare(
+							AdvanceTime(
+								ToTime(time.Date(2001, time.February, 4, 0, 0, 0, 0, time.UTC)),
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.CancelTransfer{
+									TransactionID: "T001",
+									FromAccountID: "A001",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.TransferCancelled{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the transfer is not from the given account",
+		func(t *testing.T) {
+			t.Run(
+				"it does not cancel the transfer",
+				func(t *testing.T) {
+					prepare(t).
+						Expect(
+							ExecuteCommand(
+								&commands.CancelTransfer{
+									TransactionID: "T001",
+									FromAccountID: "A002",
+								},
+							),
+							NoneOf(
+								ToRecordEventOfType(&events.TransferCancelled{}),
+							),
+						)
+				},
+			)
+		},
+	)
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+// username is the aggregate root for a username that customers log in with.
+//
+// Each instance is identified by the normalized username itself, so that two
+// customers can never reserve the same username, no matter how close together
+// they sign up.
+type username struct {
+	dogma.NoSnapshotBehavior
+
+	// CustomerID is the ID of the customer that the username is reserved for,
+	// or empty if it has not been reserved.
+	CustomerID string
+}
+
+func (u *username) AggregateInstanceDescription() string {
+	if u.CustomerID == "" {
+		return "available"
+	}
+	return "reserved for customer " + u.CustomerID
+}
+
+func (u *username) Reserve(s dogma.AggregateCommandScope[*username], m *commands.ReserveUsername) {
+	if u.CustomerID == m.CustomerID {
+		s.Log("username is already reserved for this customer")
+		return
+	}
+
+	if u.CustomerID != "" {
+		s.RecordEvent(&events.UsernameReservationDeclined{
+			Username:   m.Username,
+			CustomerID: m.CustomerID,
+		})
+		return
+	}
+
+	s.RecordEvent(&events.UsernameReserved{
+		Username:   m.Username,
+		CustomerID: m.CustomerID,
+	})
+}
+
+func (u *username) ApplyEvent(m dogma.Event) {
+	switch m := m.(type) {
+	case *events.UsernameReserved:
+		u.CustomerID = m.CustomerID
+	}
+}
+
+// UsernameHandler implements the business logic for the usernames that
+// customers log in with.
+type UsernameHandler struct{}
+
+// Configure configures the behavior of the engine as it relates to this
+// handler.
+func (UsernameHandler) Configure(c dogma.AggregateConfigurer) {
+	c.Identity("username", "aef0d85d-1689-4a0f-9405-476b5ebe7331")
+
+	c.Routes(
+		dogma.HandlesCommand[*commands.ReserveUsername](),
+		dogma.RecordsEvent[*events.UsernameReserved](),
+		dogma.RecordsEvent[*events.UsernameReservationDeclined](),
+	)
+}
+
+// New returns a new username instance.
+func (UsernameHandler) New() *username {
+	return &username{}
+}
+
+// RouteCommandToInstance returns the ID of the aggregate instance that is
+// targetted by m.
+func (UsernameHandler) RouteCommandToInstance(m dogma.Command) string {
+	switch x := m.(type) {
+	case *commands.ReserveUsername:
+		return x.Username
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// HandleCommand handles a command message that has been routed to this handler.
+func (UsernameHandler) HandleCommand(
+	u *username,
+	s dogma.AggregateCommandScope[*username],
+	m dogma.Command,
+) {
+	switch x := m.(type) {
+	case *commands.ReserveUsername:
+		u.Reserve(s, x)
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
@@ -0,0 +1,99 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_ReserveUsername(t *testing.T) {
+	t.Run(
+		"when the username has not been reserved",
+		func(t *testing.T) {
+			t.Run(
+				"it reserves the username for the customer",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Expect(
+							ExecuteCommand(
+								&commands.ReserveUsername{
+									Username:   "bob",
+									CustomerID: "C001",
+								},
+							),
+							ToRecordEvent(
+								&events.UsernameReserved{
+									Username:   "bob",
+									CustomerID: "C001",
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the username has already been reserved for the same customer",
+		func(t *testing.T) {
+			t.Run(
+				"it does not reserve the username again",
+				func(t *testing.T) {
+					cmd := &commands.ReserveUsername{
+						Username:   "bob",
+						CustomerID: "C001",
+					}
+
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(cmd),
+						).
+						Expect(
+							ExecuteCommand(cmd),
+							NoneOf(
+								ToRecordEventOfType(&events.UsernameReserved{}),
+								ToRecordEventOfType(&events.UsernameReservationDeclined{}),
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the username has already been reserved for another customer",
+		func(t *testing.T) {
+			t.Run(
+				"it declines the reservation",
+				func(t *testing.T) {
+					Begin(t, &example.App{}).
+						Prepare(
+							ExecuteCommand(
+								&commands.ReserveUsername{
+									Username:   "bob",
+									CustomerID: "C001",
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.ReserveUsername{
+									Username:   "bob",
+									CustomerID: "C002",
+								},
+							),
+							ToRecordEvent(
+								&events.UsernameReservationDeclined{
+									Username:   "bob",
+									CustomerID: "C002",
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+}
@@ -35,6 +35,7 @@ func (WithdrawalProcessHandler) Configure(c dogma.ProcessConfigurer) {
 		dogma.ExecutesCommand[*commands.CreditAccount](),
 		dogma.ExecutesCommand[*commands.ApproveWithdrawal](),
 		dogma.ExecutesCommand[*commands.DeclineWithdrawal](),
+		dogma.ExecutesCommand[*commands.SettleDebit](),
 	)
 }
 
@@ -90,6 +91,7 @@ func (WithdrawalProcessHandler) HandleEvent(
 			DebitType:     messages.Withdrawal,
 			Amount:        x.Amount,
 			Date:          messages.DailyDebitLimitDate(x.ScheduledTime),
+			DailyLimit:    x.DailyDebitLimit,
 		})
 
 	case *events.AccountDebitDeclined:
@@ -123,8 +125,14 @@ func (WithdrawalProcessHandler) HandleEvent(
 			Reason:        messages.DailyDebitLimitExceeded,
 		})
 
-	case *events.WithdrawalApproved,
-		*events.WithdrawalDeclined:
+	case *events.WithdrawalApproved:
+		s.ExecuteCommand(&commands.SettleDebit{
+			TransactionID: x.TransactionID,
+			AccountID:     x.AccountID,
+		})
+		s.End()
+
+	case *events.WithdrawalDeclined:
 		s.End()
 
 	default:
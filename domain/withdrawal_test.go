@@ -25,6 +25,7 @@ func Test_Withdraw(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -70,6 +71,7 @@ func Test_Withdraw(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 						).
@@ -109,6 +111,7 @@ func Test_Withdraw(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -149,6 +152,7 @@ func Test_Withdraw(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -156,6 +160,7 @@ func Test_Withdraw(t *testing.T) {
 									CustomerID:  "C002",
 									AccountID:   "A002",
 									AccountName: "Bob Jones",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -220,6 +225,7 @@ func Test_Withdraw(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -268,6 +274,7 @@ func Test_Withdraw(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -330,6 +337,7 @@ func Test_Withdraw(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -2,9 +2,11 @@ package integrations
 
 import (
 	"context"
-	"strconv"
+	"errors"
+	"fmt"
 
 	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/integrations/thirdpartybank"
 	"github.com/dogmatiq/example/messages"
 	"github.com/dogmatiq/example/messages/commands"
 	"github.com/dogmatiq/example/messages/events"
@@ -12,7 +14,10 @@ import (
 
 // ThirdPartyBankIntegrationHandler handles commands that interact with
 // a hypothetical third-party bank's API on behalf of the application.
-type ThirdPartyBankIntegrationHandler struct{}
+type ThirdPartyBankIntegrationHandler struct {
+	// Client is the client used to make requests to the third-party bank.
+	Client *thirdpartybank.Client
+}
 
 // Configure configures the behavior of the engine as it relates to this handler.
 func (ThirdPartyBankIntegrationHandler) Configure(c dogma.IntegrationConfigurer) {
@@ -20,40 +25,61 @@ func (ThirdPartyBankIntegrationHandler) Configure(c dogma.IntegrationConfigurer)
 
 	c.Routes(
 		dogma.HandlesCommand[*commands.CreditThirdPartyAccount](),
+		dogma.HandlesCommand[*commands.AbandonThirdPartyCredit](),
+		dogma.HandlesCommand[*commands.CheckPayee](),
+		dogma.HandlesCommand[*commands.ReceiveThirdPartyPayment](),
+		dogma.HandlesCommand[*commands.ReturnThirdPartyPayment](),
 		dogma.RecordsEvent[*events.ThirdPartyAccountCredited](),
 		dogma.RecordsEvent[*events.ThirdPartyAccountCreditFailed](),
+		dogma.RecordsEvent[*events.ThirdPartyCreditAttemptFailed](),
+		dogma.RecordsEvent[*events.PayeeChecked](),
+		dogma.RecordsEvent[*events.ThirdPartyPaymentReceived](),
+		dogma.RecordsEvent[*events.ThirdPartyPaymentReturned](),
 	)
 }
 
 // HandleCommand handles a command message that has been routed to this handler.
-func (ThirdPartyBankIntegrationHandler) HandleCommand(
-	_ context.Context,
+//
+// A credit that fails with a transient error, such as a timeout, is recorded
+// as a failed attempt, which the ThirdPartyCreditRetryProcessHandler retries.
+// The bank recognizes the retried request by its transaction ID, so the
+// account is never credited twice. A credit that the bank rejects, or that
+// fails with any other error, fails immediately.
+//
+// It returns an error if the bank does not accept the return of a payment, so
+// that the command is retried.
+func (h ThirdPartyBankIntegrationHandler) HandleCommand(
+	ctx context.Context,
 	s dogma.IntegrationCommandScope,
 	c dogma.Command,
 ) error {
 	switch x := c.(type) {
 	case *commands.CreditThirdPartyAccount:
-		s.Log(
-			"crediting third-party account %s with %s (transaction %s)",
-			x.AccountID,
-			messages.FormatAmount(x.Amount),
-			x.TransactionID,
-		)
-
-		if _, err := strconv.ParseUint(x.AccountID, 10, 64); err != nil {
-			s.Log("third-party bank rejected the credit: account %s not found", x.AccountID)
-			s.RecordEvent(&events.ThirdPartyAccountCreditFailed{
-				TransactionID: x.TransactionID,
-				AccountID:     x.AccountID,
-				Amount:        x.Amount,
-			})
-		} else {
-			s.RecordEvent(&events.ThirdPartyAccountCredited{
-				TransactionID: x.TransactionID,
-				AccountID:     x.AccountID,
-				Amount:        x.Amount,
-			})
-		}
+		return h.credit(ctx, s, x)
+
+	case *commands.AbandonThirdPartyCredit:
+		s.Log("giving up on the credit after %d attempts", x.Attempts)
+		s.RecordEvent(&events.ThirdPartyAccountCreditFailed{
+			TransactionID:   x.TransactionID,
+			AccountID:       x.AccountID,
+			TransactionType: x.TransactionType,
+			Amount:          x.Amount,
+		})
+
+	case *commands.CheckPayee:
+		h.checkPayee(ctx, s, x)
+
+	case *commands.ReceiveThirdPartyPayment:
+		s.RecordEvent(&events.ThirdPartyPaymentReceived{
+			TransactionID:           x.TransactionID,
+			ThirdPartyTransactionID: x.ThirdPartyTransactionID,
+			AccountID:               x.AccountID,
+			SenderAccountID:         x.SenderAccountID,
+			Amount:                  x.Amount,
+		})
+
+	case *commands.ReturnThirdPartyPayment:
+		return h.returnPayment(ctx, s, x)
 
 	default:
 		panic(dogma.UnexpectedMessage)
@@ -61,3 +87,165 @@ func (ThirdPartyBankIntegrationHandler) HandleCommand(
 
 	return nil
 }
+
+// credit requests that the third-party bank credits an account.
+func (h ThirdPartyBankIntegrationHandler) credit(
+	ctx context.Context,
+	s dogma.IntegrationCommandScope,
+	x *commands.CreditThirdPartyAccount,
+) error {
+	if h.Client == nil {
+		return errors.New("no third-party bank client is configured")
+	}
+
+	s.Log(
+		"crediting third-party account %s with %s (transaction %s, attempt %d)",
+		x.AccountID,
+		messages.FormatAmount(x.Amount),
+		x.TransactionID,
+		x.Retry+1,
+	)
+
+	res, err := h.Client.Credit(
+		ctx,
+		thirdpartybank.CreditRequest{
+			TransactionID: x.TransactionID,
+			AccountID:     x.AccountID,
+			BSB:           x.Payee.BSB,
+			AccountNumber: x.Payee.AccountNumber,
+			AccountName:   x.Payee.AccountName,
+			Amount:        x.Amount,
+		},
+	)
+
+	switch {
+	case ctx.Err() != nil:
+		// The engine is stopping, so leave the command to be handled again
+		// rather than recording an attempt that the bank may not have seen.
+		return ctx.Err()
+
+	case thirdpartybank.IsTransient(err):
+		s.Log("third-party bank did not respond with an outcome: %s", err)
+		s.RecordEvent(&events.ThirdPartyCreditAttemptFailed{
+			TransactionID:   x.TransactionID,
+			AccountID:       x.AccountID,
+			TransactionType: x.TransactionType,
+			Amount:          x.Amount,
+			Attempt:         x.Retry + 1,
+			Error:           err.Error(),
+			Payee:           x.Payee,
+		})
+
+	case err != nil:
+		s.Log("third-party bank refused the request: %s", err)
+		s.RecordEvent(&events.ThirdPartyAccountCreditFailed{
+			TransactionID:   x.TransactionID,
+			AccountID:       x.AccountID,
+			TransactionType: x.TransactionType,
+			Amount:          x.Amount,
+		})
+
+	case res.Status == thirdpartybank.Rejected:
+		s.Log("third-party bank rejected the credit: %s", res.Reason)
+		s.RecordEvent(&events.ThirdPartyAccountCreditFailed{
+			TransactionID:   x.TransactionID,
+			AccountID:       x.AccountID,
+			TransactionType: x.TransactionType,
+			Amount:          x.Amount,
+		})
+
+	default:
+		s.Log("third-party bank accepted the credit, reference %s", res.Reference)
+		s.RecordEvent(&events.ThirdPartyAccountCredited{
+			TransactionID:   x.TransactionID,
+			AccountID:       x.AccountID,
+			TransactionType: x.TransactionType,
+			Amount:          x.Amount,
+		})
+	}
+
+	return nil
+}
+
+// checkPayee requests that the third-party bank confirms the account name of
+// a payee.
+//
+// The customer is waiting for the result, so a check that fails for any reason
+// is recorded as unavailable rather than being retried.
+func (h ThirdPartyBankIntegrationHandler) checkPayee(
+	ctx context.Context,
+	s dogma.IntegrationCommandScope,
+	x *commands.CheckPayee,
+) {
+	e := &events.PayeeChecked{
+		CheckID: x.CheckID,
+		Payee:   x.Payee,
+		Result:  messages.PayeeCheckUnavailable,
+	}
+
+	if h.Client == nil {
+		s.Log("no third-party bank client is configured")
+		s.RecordEvent(e)
+		return
+	}
+
+	res, err := h.Client.CheckPayee(
+		ctx,
+		thirdpartybank.PayeeCheckRequest{
+			AccountID:   x.Payee.AccountID(),
+			AccountName: x.Payee.AccountName,
+		},
+	)
+	if err != nil {
+		s.Log("unable to check the payee: %s", err)
+		s.RecordEvent(e)
+		return
+	}
+
+	switch res.Result {
+	case thirdpartybank.Match:
+		e.Result = messages.PayeeMatched
+	case thirdpartybank.CloseMatch:
+		e.Result = messages.PayeeCloselyMatched
+		e.MatchedAccountName = res.AccountName
+	case thirdpartybank.NoMatch:
+		e.Result = messages.PayeeNotMatched
+	}
+
+	s.RecordEvent(e)
+}
+
+// returnPayment requests that the third-party bank returns a payment to its
+// sender.
+func (h ThirdPartyBankIntegrationHandler) returnPayment(
+	ctx context.Context,
+	s dogma.IntegrationCommandScope,
+	x *commands.ReturnThirdPartyPayment,
+) error {
+	if h.Client == nil {
+		return errors.New("no third-party bank client is configured")
+	}
+
+	res, err := h.Client.Return(
+		ctx,
+		thirdpartybank.ReturnRequest{
+			TransactionID: x.ThirdPartyTransactionID,
+			Amount:        x.Amount,
+			Reason:        string(x.Reason),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to return payment %s: %w", x.TransactionID, err)
+	}
+
+	s.Log("third-party bank accepted the return, reference %s", res.Reference)
+	s.RecordEvent(&events.ThirdPartyPaymentReturned{
+		TransactionID:   x.TransactionID,
+		AccountID:       x.AccountID,
+		SenderAccountID: x.SenderAccountID,
+		Amount:          x.Amount,
+		Reason:          x.Reason,
+	})
+
+	return nil
+}
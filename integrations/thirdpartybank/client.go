@@ -0,0 +1,227 @@
+package thirdpartybank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBodySize is the largest body that the client or simulator will read.
+const maxBodySize = 64 << 10
+
+// defaultHTTPClient is the HTTP client used by a [Client] that does not
+// specify one. Its timeout bounds the time spent waiting for a bank that has
+// stopped responding.
+var defaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// Client is a client for the third-party bank's HTTP API.
+type Client struct {
+	// BaseURL is the URL of the bank's API, such as "http://localhost:8081".
+	BaseURL string
+
+	// HTTPClient is the client used to make requests. If it is nil, a client
+	// with a 30 second timeout is used.
+	HTTPClient *http.Client
+
+	// Secret is the secret shared with the bank, which is used to sign each
+	// request as described by [Sign]. If it is empty, requests are not signed.
+	Secret []byte
+}
+
+// StatusError is an error returned when the bank responds with a status code
+// that does not indicate a known outcome.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf(
+			"third-party bank responded with %d %s",
+			e.StatusCode,
+			http.StatusText(e.StatusCode),
+		)
+	}
+
+	return fmt.Sprintf(
+		"third-party bank responded with %d %s: %s",
+		e.StatusCode,
+		http.StatusText(e.StatusCode),
+		e.Message,
+	)
+}
+
+// IsTransient returns true if err, as returned by a [Client] method, may not
+// recur if the request is retried.
+//
+// Errors that occur before a response is received, such as timeouts, are
+// transient, as are responses with a 5xx, 408 Request Timeout or 429 Too Many
+// Requests status code. Other 4xx responses indicate that the request itself
+// is at fault.
+func IsTransient(err error) bool {
+	var e *StatusError
+	if errors.As(err, &e) {
+		return e.StatusCode >= 500 ||
+			e.StatusCode == http.StatusRequestTimeout ||
+			e.StatusCode == http.StatusTooManyRequests
+	}
+
+	return err != nil
+}
+
+// Credit requests that the bank credits an account.
+//
+// It returns an error if the bank does not respond with the outcome of the
+// credit. If [IsTransient] returns true for the error it is safe to retry the
+// request with the same transaction ID. A credit that is rejected by the bank
+// is not an error.
+func (c *Client) Credit(ctx context.Context, req CreditRequest) (CreditResponse, error) {
+	code, body, err := c.post(ctx, CreditPath, req)
+	if err != nil {
+		return CreditResponse{}, err
+	}
+
+	var want CreditStatus
+	switch code {
+	case http.StatusOK:
+		want = Accepted
+	case http.StatusUnprocessableEntity:
+		want = Rejected
+	default:
+		return CreditResponse{}, newStatusError(code, body)
+	}
+
+	var resp CreditResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return CreditResponse{}, fmt.Errorf("unable to decode response from third-party bank: %w", err)
+	}
+
+	if resp.TransactionID != req.TransactionID {
+		return CreditResponse{}, fmt.Errorf(
+			"third-party bank responded to transaction %s with the outcome of transaction %s",
+			req.TransactionID,
+			resp.TransactionID,
+		)
+	}
+
+	if resp.Status != want {
+		return CreditResponse{}, fmt.Errorf(
+			"third-party bank responded with %d %s but a status of %q",
+			code,
+			http.StatusText(code),
+			resp.Status,
+		)
+	}
+
+	return resp, nil
+}
+
+// CheckPayee requests that the bank confirms the name of an account.
+func (c *Client) CheckPayee(ctx context.Context, req PayeeCheckRequest) (PayeeCheckResponse, error) {
+	code, body, err := c.post(ctx, PayeeCheckPath, req)
+	if err != nil {
+		return PayeeCheckResponse{}, err
+	}
+
+	if code != http.StatusOK {
+		return PayeeCheckResponse{}, newStatusError(code, body)
+	}
+
+	var resp PayeeCheckResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return PayeeCheckResponse{}, fmt.Errorf("unable to decode response from third-party bank: %w", err)
+	}
+
+	switch resp.Result {
+	case Match, CloseMatch, NoMatch, Unavailable:
+		return resp, nil
+	default:
+		return PayeeCheckResponse{}, fmt.Errorf("third-party bank responded with an unrecognized payee check result %q", resp.Result)
+	}
+}
+
+// Return requests that the bank returns a payment to its sender.
+//
+// It returns an error if the bank does not accept the return. If [IsTransient]
+// returns true for the error it is safe to retry the request.
+func (c *Client) Return(ctx context.Context, req ReturnRequest) (ReturnResponse, error) {
+	code, body, err := c.post(ctx, ReturnPath, req)
+	if err != nil {
+		return ReturnResponse{}, err
+	}
+
+	if code != http.StatusOK {
+		return ReturnResponse{}, newStatusError(code, body)
+	}
+
+	var resp ReturnResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return ReturnResponse{}, fmt.Errorf("unable to decode response from third-party bank: %w", err)
+	}
+
+	if resp.TransactionID != req.TransactionID {
+		return ReturnResponse{}, fmt.Errorf(
+			"third-party bank responded to the return of transaction %s with the return of transaction %s",
+			req.TransactionID,
+			resp.TransactionID,
+		)
+	}
+
+	return resp, nil
+}
+
+// post sends v as the JSON body of a POST request to the given path, and
+// returns the status code and body of the response.
+func (c *Client) post(ctx context.Context, path string, v any) (int, []byte, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	r, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		strings.TrimSuffix(c.BaseURL, "/")+path,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	if len(c.Secret) != 0 {
+		r.Header.Set(SignatureHeader, Sign(c.Secret, body))
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = defaultHTTPClient
+	}
+
+	res, err := hc.Do(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err = io.ReadAll(io.LimitReader(res.Body, maxBodySize))
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read response from third-party bank: %w", err)
+	}
+
+	return res.StatusCode, body, nil
+}
+
+// newStatusError returns a [StatusError] for a response with the given status
+// code and body.
+func newStatusError(code int, body []byte) *StatusError {
+	var e ErrorResponse
+	json.Unmarshal(body, &e) // nolint:errcheck // the body is optional
+	return &StatusError{code, e.Error}
+}
@@ -0,0 +1,393 @@
+package thirdpartybank_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/dogmatiq/example/integrations/thirdpartybank"
+)
+
+func Test_Client(t *testing.T) {
+	setup := func(t *testing.T) (*Simulator, *Client) {
+		sim := &Simulator{}
+		srv := httptest.NewServer(sim)
+		t.Cleanup(srv.Close)
+		return sim, &Client{BaseURL: srv.URL}
+	}
+
+	req := CreditRequest{
+		TransactionID: "T001",
+		AccountID:     "06200012345674",
+		BSB:           "062-000",
+		AccountNumber: "12345674",
+		AccountName:   "Anna Smith",
+		Amount:        500,
+	}
+
+	t.Run(
+		"when the credit is accepted",
+		func(t *testing.T) {
+			t.Run(
+				"it returns the bank's reference",
+				func(t *testing.T) {
+					sim, client := setup(t)
+
+					res, err := client.Credit(context.Background(), req)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if res.Status != Accepted {
+						t.Fatalf("unexpected status: got %q, want %q", res.Status, Accepted)
+					}
+
+					if res.Reference == "" {
+						t.Fatal("expected a reference")
+					}
+
+					if got := sim.Credits(); len(got) != 1 || got[0] != req {
+						t.Fatalf("unexpected credits: %v", got)
+					}
+				},
+			)
+
+			t.Run(
+				"it returns the same response if the request is retried",
+				func(t *testing.T) {
+					sim, client := setup(t)
+
+					first, err := client.Credit(context.Background(), req)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					second, err := client.Credit(context.Background(), req)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if first != second {
+						t.Fatalf("unexpected response to retry: got %v, want %v", second, first)
+					}
+
+					if got := sim.Credits(); len(got) != 1 {
+						t.Fatalf("expected the account to be credited once, got %d credits", len(got))
+					}
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the credit is rejected",
+		func(t *testing.T) {
+			t.Run(
+				"it returns the reason",
+				func(t *testing.T) {
+					_, client := setup(t)
+
+					r := req
+					r.AccountID = "EXT001"
+
+					res, err := client.Credit(context.Background(), r)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if res.Status != Rejected || res.Reason != AccountNotFound {
+						t.Fatalf("unexpected response: %v", res)
+					}
+				},
+			)
+
+			t.Run(
+				"it returns a scripted reason",
+				func(t *testing.T) {
+					sim, client := setup(t)
+					sim.Script(Outcome{Reject: AccountClosed})
+
+					res, err := client.Credit(context.Background(), req)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if res.Status != Rejected || res.Reason != AccountClosed {
+						t.Fatalf("unexpected response: %v", res)
+					}
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when the outcome is unknown",
+		func(t *testing.T) {
+			t.Run(
+				"it returns a status error",
+				func(t *testing.T) {
+					sim, client := setup(t)
+					sim.Script(Outcome{StatusCode: http.StatusServiceUnavailable})
+
+					_, err := client.Credit(context.Background(), req)
+
+					var statusErr *StatusError
+					if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+						t.Fatalf("unexpected error: %v", err)
+					}
+
+					if got := sim.Credits(); len(got) != 0 {
+						t.Fatalf("expected no credits, got %v", got)
+					}
+
+					if _, err := client.Credit(context.Background(), req); err != nil {
+						t.Fatalf("expected the retry to succeed: %v", err)
+					}
+				},
+			)
+
+			t.Run(
+				"it returns an error if the request times out",
+				func(t *testing.T) {
+					sim, client := setup(t)
+					sim.Script(Outcome{Delay: time.Second})
+
+					ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+					defer cancel()
+
+					if _, err := client.Credit(ctx, req); !errors.Is(err, context.DeadlineExceeded) {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				},
+			)
+
+			t.Run(
+				"it returns an error if the transaction ID is reused for a different credit",
+				func(t *testing.T) {
+					_, client := setup(t)
+
+					if _, err := client.Credit(context.Background(), req); err != nil {
+						t.Fatal(err)
+					}
+
+					r := req
+					r.Amount++
+
+					var statusErr *StatusError
+					if _, err := client.Credit(context.Background(), r); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusConflict {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				},
+			)
+		},
+	)
+}
+
+func Test_Simulator(t *testing.T) {
+	t.Run(
+		"it accepts scripted outcomes over HTTP",
+		func(t *testing.T) {
+			sim := &Simulator{}
+			srv := httptest.NewServer(sim)
+			defer srv.Close()
+
+			res, err := http.Post(
+				srv.URL+"/simulator/script",
+				"application/json",
+				strings.NewReader(`[{"status": 502}, {"reject": "account_closed"}]`),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+
+			if res.StatusCode != http.StatusNoContent {
+				t.Fatalf("unexpected status: %d", res.StatusCode)
+			}
+
+			client := &Client{BaseURL: srv.URL}
+			req := CreditRequest{TransactionID: "T001", AccountID: "100001", Amount: 500}
+
+			var statusErr *StatusError
+			if _, err := client.Credit(context.Background(), req); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			r, err := client.Credit(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r.Reason != AccountClosed {
+				t.Fatalf("unexpected response: %v", r)
+			}
+		},
+	)
+	t.Run(
+		"it sends payments to the receiving bank and accepts their return",
+		func(t *testing.T) {
+			var received []Payment
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != PaymentPath {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				if !Verify([]byte("<secret>"), body, r.Header.Get(SignatureHeader)) {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				var p Payment
+				if err := json.Unmarshal(body, &p); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				received = append(received, p)
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer receiver.Close()
+
+			sim := &Simulator{
+				BankURL: receiver.URL,
+				Secret:  []byte("<secret>"),
+			}
+			srv := httptest.NewServer(sim)
+			defer srv.Close()
+
+			res, err := http.Post(
+				srv.URL+"/simulator/payments",
+				"application/json",
+				strings.NewReader(`{"account_id": "A001", "sender_account_id": "200001", "amount": 500}`),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+
+			if res.StatusCode != http.StatusCreated {
+				t.Fatalf("unexpected status: %d", res.StatusCode)
+			}
+
+			if len(received) != 1 {
+				t.Fatalf("expected one payment, got %v", received)
+			}
+
+			p := received[0]
+			if p.TransactionID == "" || p.AccountID != "A001" || p.SenderAccountID != "200001" || p.Amount != 500 {
+				t.Fatalf("unexpected payment: %v", p)
+			}
+
+			client := &Client{BaseURL: srv.URL}
+			req := ReturnRequest{TransactionID: p.TransactionID, Amount: 500, Reason: "account not found"}
+
+			first, err := client.Return(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			second, err := client.Return(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if first != second {
+				t.Fatalf("unexpected response to retry: got %v, want %v", second, first)
+			}
+
+			if got := sim.Returns(); len(got) != 1 || got[0] != req {
+				t.Fatalf("unexpected returns: %v", got)
+			}
+
+			var statusErr *StatusError
+			if _, err := client.Return(context.Background(), ReturnRequest{TransactionID: "X001", Amount: 500}); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"it confirms the names of payees",
+		func(t *testing.T) {
+			sim := &Simulator{
+				Names: map[string]string{
+					"06200012345674": "Anna Smith",
+				},
+			}
+			srv := httptest.NewServer(sim)
+			defer srv.Close()
+
+			client := &Client{BaseURL: srv.URL}
+
+			cases := []struct {
+				AccountID   string
+				AccountName string
+				Want        PayeeCheckResponse
+			}{
+				{"06200012345674", "Anna Smith", PayeeCheckResponse{Result: Match}},
+				{"06200012345674", "  anna   SMITH. ", PayeeCheckResponse{Result: Match}},
+				{"06200012345674", "Ana Smith", PayeeCheckResponse{Result: CloseMatch, AccountName: "Anna Smith"}},
+				{"06200012345674", "A Smith", PayeeCheckResponse{Result: CloseMatch, AccountName: "Anna Smith"}},
+				{"06200012345674", "Bob Jones", PayeeCheckResponse{Result: NoMatch}},
+				{"08200112345671", "Bob Jones", PayeeCheckResponse{Result: Unavailable}},
+				{"A001", "Bob Jones", PayeeCheckResponse{Result: NoMatch}},
+			}
+
+			for _, c := range cases {
+				res, err := client.CheckPayee(
+					context.Background(),
+					PayeeCheckRequest{AccountID: c.AccountID, AccountName: c.AccountName},
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if res != c.Want {
+					t.Errorf("unexpected response for %q at %s: got %v, want %v", c.AccountName, c.AccountID, res, c.Want)
+				}
+			}
+		},
+	)
+}
+
+func Test_IsTransient(t *testing.T) {
+	cases := []struct {
+		Name string
+		Err  error
+		Want bool
+	}{
+		{"no error", nil, false},
+		{"network error", errors.New("connection refused"), true},
+		{"timeout", context.DeadlineExceeded, true},
+		{"500 Internal Server Error", &StatusError{StatusCode: http.StatusInternalServerError}, true},
+		{"503 Service Unavailable", &StatusError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"408 Request Timeout", &StatusError{StatusCode: http.StatusRequestTimeout}, true},
+		{"429 Too Many Requests", &StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"400 Bad Request", &StatusError{StatusCode: http.StatusBadRequest}, false},
+		{"409 Conflict", &StatusError{StatusCode: http.StatusConflict}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(
+			c.Name,
+			func(t *testing.T) {
+				if got := IsTransient(c.Err); got != c.Want {
+					t.Fatalf("unexpected result: got %t, want %t", got, c.Want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,35 @@
+// Package thirdpartybank implements the HTTP protocol used to exchange payments
+// with a hypothetical third-party bank.
+//
+// A credit is requested by POSTing a JSON [CreditRequest] to [CreditPath]. The
+// bank responds with a JSON [CreditResponse] and one of the following status
+// codes:
+//
+//   - 200 OK: the credit was accepted
+//   - 422 Unprocessable Entity: the credit was rejected, for example because
+//     the account does not exist
+//
+// Any other status code indicates that the outcome of the request is unknown,
+// and the body, if any, is a JSON [ErrorResponse]. The request may be retried;
+// the bank uses the transaction ID to recognize a repeated request and
+// responds with the original outcome instead of crediting the account again.
+//
+// Before crediting an account the name of the account can be confirmed by
+// POSTing a JSON [PayeeCheckRequest] to [PayeeCheckPath]. The bank responds
+// with 200 OK and a JSON [PayeeCheckResponse] that reports how closely the
+// name matches, without revealing the name unless it is a [CloseMatch].
+//
+// Payments in the other direction are sent by the bank, which POSTs a JSON
+// [Payment] to [PaymentPath] relative to the receiving bank's base URL. The
+// body is signed with a secret shared by the two banks, and the signature is
+// sent in the [SignatureHeader] header. The receiving bank responds with 401
+// Unauthorized if the signature is missing or invalid, and with 202 Accepted
+// once it has recorded the payment. The bank repeats the notification until
+// it is accepted. A payment that can not
+// be credited is returned by POSTing a JSON [ReturnRequest] to [ReturnPath].
+// The bank responds with 200 OK and a JSON [ReturnResponse], or with any other
+// status code and an [ErrorResponse] if the return was not accepted.
+//
+// [Client] implements the client side of the protocol. [Simulator] implements
+// the bank itself, for use in tests and local development.
+package thirdpartybank
@@ -0,0 +1,164 @@
+package thirdpartybank
+
+const (
+	// CreditPath is the path, relative to the bank's base URL, of the
+	// endpoint that credits an account.
+	CreditPath = "/v1/credits"
+
+	// PayeeCheckPath is the path, relative to the bank's base URL, of the
+	// endpoint that confirms the name of an account.
+	PayeeCheckPath = "/v1/payee-checks"
+
+	// ReturnPath is the path, relative to the bank's base URL, of the endpoint
+	// that returns a payment to its sender.
+	ReturnPath = "/v1/returns"
+
+	// PaymentPath is the path, relative to the receiving bank's base URL, of
+	// the endpoint that the bank notifies of payments into the receiving
+	// bank's accounts.
+	PaymentPath = "/v1/payments"
+)
+
+// CreditRequest is the body of a request to credit an account.
+type CreditRequest struct {
+	// TransactionID uniquely identifies the credit. It is used by the bank to
+	// recognize a request that is retried.
+	TransactionID string `json:"transaction_id"`
+
+	// AccountID is the ID of the account to credit.
+	AccountID string `json:"account_id"`
+
+	// BSB and AccountNumber identify the account to credit, and AccountName
+	// is the name of the account as given by the sender. AccountID is the
+	// digits of the BSB followed by the account number.
+	BSB           string `json:"bsb"`
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name"`
+
+	// Amount is the amount to credit, in cents. It must be positive.
+	Amount int64 `json:"amount"`
+}
+
+// CreditResponse is the body of a response to a [CreditRequest] that has a
+// known outcome.
+type CreditResponse struct {
+	TransactionID string       `json:"transaction_id"`
+	Status        CreditStatus `json:"status"`
+
+	// Reference is the bank's own identifier for the credit. It is only set
+	// if the credit was accepted.
+	Reference string `json:"reference,omitempty"`
+
+	// Reason is the reason the credit was rejected. It is only set if the
+	// credit was rejected.
+	Reason RejectionReason `json:"reason,omitempty"`
+}
+
+// CreditStatus is the outcome of a credit request.
+type CreditStatus string
+
+const (
+	// Accepted indicates that the account was credited.
+	Accepted CreditStatus = "accepted"
+
+	// Rejected indicates that the account was not credited, and will not be
+	// if the request is retried.
+	Rejected CreditStatus = "rejected"
+)
+
+// RejectionReason is the reason that a credit was rejected.
+type RejectionReason string
+
+const (
+	// AccountNotFound indicates that the bank has no account with the
+	// requested ID.
+	AccountNotFound RejectionReason = "account_not_found"
+
+	// AccountClosed indicates that the account exists but no longer accepts
+	// credits.
+	AccountClosed RejectionReason = "account_closed"
+)
+
+// PayeeCheckRequest is the body of a request to confirm that an account has
+// the expected name.
+type PayeeCheckRequest struct {
+	// AccountID is the ID of the account to check.
+	AccountID string `json:"account_id"`
+
+	// AccountName is the name that the account is expected to have.
+	AccountName string `json:"account_name"`
+}
+
+// PayeeCheckResponse is the body of a response to a [PayeeCheckRequest].
+type PayeeCheckResponse struct {
+	Result NameMatch `json:"result"`
+
+	// AccountName is the actual name of the account. It is only set if the
+	// result is [CloseMatch].
+	AccountName string `json:"account_name,omitempty"`
+}
+
+// NameMatch is the outcome of a payee check.
+type NameMatch string
+
+const (
+	// Match indicates that the account has the expected name.
+	Match NameMatch = "match"
+
+	// CloseMatch indicates that the account's name is similar to the expected
+	// name, for example because it is spelled differently.
+	CloseMatch NameMatch = "close_match"
+
+	// NoMatch indicates that the account has a different name, or that there
+	// is no such account.
+	NoMatch NameMatch = "no_match"
+
+	// Unavailable indicates that the bank is unable to check the account's
+	// name.
+	Unavailable NameMatch = "unavailable"
+)
+
+// Payment is the body of a notification of a payment from an account held at
+// the bank into an account held at the receiving bank.
+type Payment struct {
+	// TransactionID uniquely identifies the payment. The bank may send the
+	// same notification more than once.
+	TransactionID string `json:"transaction_id"`
+
+	// AccountID is the ID of the account at the receiving bank to credit.
+	AccountID string `json:"account_id"`
+
+	// SenderAccountID is the ID of the account at the bank that sent the
+	// payment.
+	SenderAccountID string `json:"sender_account_id"`
+
+	// Amount is the amount of the payment, in cents. It is always positive.
+	Amount int64 `json:"amount"`
+}
+
+// ReturnRequest is the body of a request to return a [Payment] to its sender.
+type ReturnRequest struct {
+	// TransactionID is the transaction ID of the payment to return.
+	TransactionID string `json:"transaction_id"`
+
+	// Amount is the amount of the payment, in cents.
+	Amount int64 `json:"amount"`
+
+	// Reason is a human-readable explanation of why the payment is returned.
+	Reason string `json:"reason"`
+}
+
+// ReturnResponse is the body of a response to a [ReturnRequest] that was
+// accepted.
+type ReturnResponse struct {
+	TransactionID string `json:"transaction_id"`
+
+	// Reference is the bank's own identifier for the return.
+	Reference string `json:"reference"`
+}
+
+// ErrorResponse is the body of a response to a request that failed without a
+// known outcome.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
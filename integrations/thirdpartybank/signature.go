@@ -0,0 +1,62 @@
+package thirdpartybank
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SignatureHeader is the HTTP header that carries the signature of a request
+// body, as produced by [Sign].
+const SignatureHeader = "X-Signature"
+
+// Sign returns the signature of a request body, which is the hex-encoded
+// HMAC-SHA256 of the body keyed by the secret shared by the two banks.
+func Sign(secret, body []byte) string {
+	m := hmac.New(sha256.New, secret)
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// Verify returns true if signature is the signature of body produced by
+// [Sign] with the given secret. It always returns false if the secret is
+// empty.
+func Verify(secret, body []byte, signature string) bool {
+	if len(secret) == 0 {
+		return false
+	}
+
+	s, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	m := hmac.New(sha256.New, secret)
+	m.Write(body)
+	return hmac.Equal(s, m.Sum(nil))
+}
+
+// LoadSecret returns the secret shared by the two banks from the file at
+// path, ignoring any leading or trailing whitespace. If path is empty, the
+// secret is empty.
+func LoadSecret(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read secret file: %w", err)
+	}
+
+	secret := bytes.TrimSpace(data)
+	if len(secret) == 0 {
+		return nil, errors.New("the secret file is empty")
+	}
+
+	return secret, nil
+}
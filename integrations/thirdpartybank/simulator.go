@@ -0,0 +1,565 @@
+package thirdpartybank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Simulator is an [http.Handler] that simulates the third-party bank's API.
+//
+// By default it accepts every credit to an account with a numeric ID, and
+// rejects credits to any other account as [AccountNotFound]. Its behavior can
+// be changed by setting its fields, or by scripting the outcome of the
+// requests it receives next, either by calling [Simulator.Script] or over HTTP:
+//
+//   - POST /simulator/script appends a JSON array of outcomes to the script,
+//     each of the form {"delay": "2s", "status": 503, "reject": "account_closed"}
+//     with every property optional
+//   - DELETE /simulator/script discards any outcomes that have not been used
+//   - GET /simulator/credits returns the credits that have been accepted
+//
+// The simulator also sends payments to the receiving bank at BankURL, either by
+// calling [Simulator.Pay] or over HTTP:
+//
+//   - POST /simulator/payments sends a payment described by a JSON object of
+//     the form {"account_id": "A001", "sender_account_id": "200001", "amount": 500}
+//   - GET /simulator/returns returns the payments that have been returned
+type Simulator struct {
+	// Latency is the time taken to respond to each request.
+	Latency time.Duration
+
+	// Jitter is the maximum random amount of time added to Latency.
+	Jitter time.Duration
+
+	// ErrorRate is the probability, between 0 and 1, that a request fails with
+	// a 503 Service Unavailable response.
+	ErrorRate float64
+
+	// Reject returns the reason that a credit to the given account is to be
+	// rejected, or an empty string if it is to be accepted. If it is nil,
+	// [DefaultReject] is used.
+	Reject func(accountID string) RejectionReason
+
+	// Names maps account IDs to the names of the accounts, which are used to
+	// answer payee checks. Checks of accounts that are not in the map are
+	// answered as [Unavailable], unless the account does not exist.
+	Names map[string]string
+
+	// BankURL is the base URL of the receiving bank's API, to which payments
+	// are sent.
+	BankURL string
+
+	// HTTPClient is the client used to send payments. If it is nil, a client
+	// with a 30 second timeout is used.
+	HTTPClient *http.Client
+
+	// Secret is the secret shared with the receiving bank, which is used to
+	// sign the payments that are sent to it. If it is empty, payments are not
+	// signed.
+	Secret []byte
+
+	// Logf, if non-nil, is used to log each request.
+	Logf func(format string, args ...any)
+
+	once sync.Once
+	mux  http.ServeMux
+
+	m        sync.Mutex
+	script   []Outcome
+	outcomes map[string]creditOutcome
+	accepted []CreditRequest
+	payments map[string]Payment
+	returns  map[string]ReturnResponse
+	returned []ReturnRequest
+}
+
+// DefaultReject rejects credits to accounts with non-numeric IDs as
+// [AccountNotFound], and accepts all others.
+func DefaultReject(accountID string) RejectionReason {
+	if _, err := strconv.ParseUint(accountID, 10, 64); err != nil {
+		return AccountNotFound
+	}
+	return ""
+}
+
+// Outcome is a scripted outcome of a credit request.
+type Outcome struct {
+	// Delay is the time taken to respond, used instead of the simulator's
+	// latency.
+	Delay time.Duration
+
+	// StatusCode, if non-zero, is the status code of an error response to
+	// send instead of handling the request.
+	StatusCode int
+
+	// Reject, if non-empty, is the reason to reject the credit.
+	Reject RejectionReason
+}
+
+// creditOutcome is the response to a credit request with a known outcome,
+// retained so that a retried request receives the same response.
+type creditOutcome struct {
+	Request  CreditRequest
+	Response CreditResponse
+}
+
+// Script appends outcomes to be used, in order, for the next requests that
+// the simulator receives.
+func (s *Simulator) Script(outcomes ...Outcome) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.script = append(s.script, outcomes...)
+}
+
+// Credits returns the credits that the simulator has accepted, in the order
+// they were received.
+func (s *Simulator) Credits() []CreditRequest {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return append([]CreditRequest(nil), s.accepted...)
+}
+
+// Returns returns the requests to return payments that the simulator has
+// accepted, in the order they were received.
+func (s *Simulator) Returns() []ReturnRequest {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return append([]ReturnRequest(nil), s.returned...)
+}
+
+// Pay sends a payment of the given amount from senderAccountID to accountID at
+// the receiving bank.
+//
+// The payment is recorded, and may be returned, even if the receiving bank
+// does not accept the notification, in which case an error is returned and
+// the notification can be repeated by calling [Simulator.Notify].
+func (s *Simulator) Pay(
+	ctx context.Context,
+	accountID, senderAccountID string,
+	amount int64,
+) (Payment, error) {
+	s.m.Lock()
+	if s.payments == nil {
+		s.payments = map[string]Payment{}
+	}
+	p := Payment{
+		TransactionID:   fmt.Sprintf("TPBP%08d", len(s.payments)+1),
+		AccountID:       accountID,
+		SenderAccountID: senderAccountID,
+		Amount:          amount,
+	}
+	s.payments[p.TransactionID] = p
+	s.m.Unlock()
+
+	return p, s.Notify(ctx, p)
+}
+
+// Notify notifies the receiving bank of a payment.
+func (s *Simulator) Notify(ctx context.Context, p Payment) error {
+	client := &Client{
+		BaseURL:    s.BankURL,
+		HTTPClient: s.HTTPClient,
+		Secret:     s.Secret,
+	}
+
+	code, body, err := client.post(ctx, PaymentPath, p)
+	if err != nil {
+		return err
+	}
+
+	if code != http.StatusAccepted {
+		return newStatusError(code, body)
+	}
+
+	s.logf("transaction %s: sent payment of %d from account %s to account %s", p.TransactionID, p.Amount, p.SenderAccountID, p.AccountID)
+
+	return nil
+}
+
+func (s *Simulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.once.Do(func() {
+		s.mux.HandleFunc("POST "+CreditPath, s.credit)
+		s.mux.HandleFunc("POST /simulator/script", s.appendScript)
+		s.mux.HandleFunc("DELETE /simulator/script", s.clearScript)
+		s.mux.HandleFunc("POST "+PayeeCheckPath, s.checkPayee)
+		s.mux.HandleFunc("POST "+ReturnPath, s.returnPayment)
+		s.mux.HandleFunc("GET /simulator/credits", s.listCredits)
+		s.mux.HandleFunc("POST /simulator/payments", s.sendPayment)
+		s.mux.HandleFunc("GET /simulator/returns", s.listReturns)
+	})
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	s.mux.ServeHTTP(w, r)
+}
+
+// credit handles a request to credit an account.
+func (s *Simulator) credit(w http.ResponseWriter, r *http.Request) {
+	var req CreditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "the request body is not a valid credit request")
+		return
+	}
+
+	if req.TransactionID == "" || req.AccountID == "" || req.Amount < 1 {
+		writeError(w, http.StatusBadRequest, "the transaction ID, account ID and a positive amount are required")
+		return
+	}
+
+	o, scripted := s.next()
+
+	delay := o.Delay
+	if !scripted {
+		delay = s.Latency
+		if s.Jitter > 0 {
+			delay += rand.N(s.Jitter)
+		}
+	}
+
+	select {
+	case <-time.After(delay):
+	case <-r.Context().Done():
+		return
+	}
+
+	if o.StatusCode == 0 && !scripted && rand.Float64() < s.ErrorRate {
+		o.StatusCode = http.StatusServiceUnavailable
+	}
+
+	if o.StatusCode != 0 {
+		s.logf("transaction %s: responding with %d %s", req.TransactionID, o.StatusCode, http.StatusText(o.StatusCode))
+		writeError(w, o.StatusCode, "simulated failure")
+		return
+	}
+
+	resp, ok := s.decide(req, o.Reject)
+	if !ok {
+		s.logf("transaction %s: conflicts with an earlier request", req.TransactionID)
+		writeError(w, http.StatusConflict, "the transaction ID has already been used for a different credit")
+		return
+	}
+
+	code := http.StatusOK
+	if resp.Status == Rejected {
+		code = http.StatusUnprocessableEntity
+		s.logf("transaction %s: rejected credit of %d to account %s: %s", req.TransactionID, req.Amount, req.AccountID, resp.Reason)
+	} else {
+		s.logf("transaction %s: accepted credit of %d to account %s as %s", req.TransactionID, req.Amount, req.AccountID, resp.Reference)
+	}
+
+	writeJSON(w, code, resp)
+}
+
+// next returns the next scripted outcome, if any.
+func (s *Simulator) next() (Outcome, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if len(s.script) == 0 {
+		return Outcome{}, false
+	}
+
+	o := s.script[0]
+	s.script = s.script[1:]
+
+	return o, true
+}
+
+// decide returns the response to a credit request, or false if the
+// transaction ID has already been used for a different request.
+//
+// If reason is non-empty the credit is rejected for that reason, unless the
+// request has already been handled, in which case the original response is
+// returned.
+func (s *Simulator) decide(req CreditRequest, reason RejectionReason) (CreditResponse, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if prev, ok := s.outcomes[req.TransactionID]; ok {
+		return prev.Response, prev.Request == req
+	}
+
+	if reason == "" {
+		reject := s.Reject
+		if reject == nil {
+			reject = DefaultReject
+		}
+		reason = reject(req.AccountID)
+	}
+
+	resp := CreditResponse{
+		TransactionID: req.TransactionID,
+		Status:        Rejected,
+		Reason:        reason,
+	}
+
+	if reason == "" {
+		s.accepted = append(s.accepted, req)
+		resp.Status = Accepted
+		resp.Reference = fmt.Sprintf("TPB%08d", len(s.accepted))
+	}
+
+	if s.outcomes == nil {
+		s.outcomes = map[string]creditOutcome{}
+	}
+	s.outcomes[req.TransactionID] = creditOutcome{req, resp}
+
+	return resp, true
+}
+
+// checkPayee handles a request to confirm the name of an account.
+func (s *Simulator) checkPayee(w http.ResponseWriter, r *http.Request) {
+	var req PayeeCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "the request body is not a valid payee check request")
+		return
+	}
+
+	reject := s.Reject
+	if reject == nil {
+		reject = DefaultReject
+	}
+
+	var resp PayeeCheckResponse
+	if reject(req.AccountID) == AccountNotFound {
+		resp.Result = NoMatch
+	} else if name, ok := s.Names[req.AccountID]; !ok {
+		resp.Result = Unavailable
+	} else {
+		resp.Result = matchName(req.AccountName, name)
+		if resp.Result == CloseMatch {
+			resp.AccountName = name
+		}
+	}
+
+	s.logf("account %s: payee check of %q: %s", req.AccountID, req.AccountName, resp.Result)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// returnPayment handles a request to return a payment to its sender.
+func (s *Simulator) returnPayment(w http.ResponseWriter, r *http.Request) {
+	var req ReturnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "the request body is not a valid return request")
+		return
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	p, ok := s.payments[req.TransactionID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "there is no payment with this transaction ID")
+		return
+	}
+
+	if req.Amount != p.Amount {
+		writeError(w, http.StatusConflict, "the amount does not match the payment")
+		return
+	}
+
+	resp, ok := s.returns[req.TransactionID]
+	if !ok {
+		s.returned = append(s.returned, req)
+		resp = ReturnResponse{
+			TransactionID: req.TransactionID,
+			Reference:     fmt.Sprintf("TPBR%08d", len(s.returned)),
+		}
+
+		if s.returns == nil {
+			s.returns = map[string]ReturnResponse{}
+		}
+		s.returns[req.TransactionID] = resp
+
+		s.logf("transaction %s: returned payment of %d to account %s: %s", req.TransactionID, p.Amount, p.SenderAccountID, req.Reason)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// sendPayment handles a request to send a payment to the receiving bank.
+func (s *Simulator) sendPayment(w http.ResponseWriter, r *http.Request) {
+	var req Payment
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "the request body is not a valid payment")
+		return
+	}
+
+	if req.AccountID == "" || req.SenderAccountID == "" || req.Amount < 1 {
+		writeError(w, http.StatusBadRequest, "the account ID, sender account ID and a positive amount are required")
+		return
+	}
+
+	p, err := s.Pay(r.Context(), req.AccountID, req.SenderAccountID, req.Amount)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("unable to notify the receiving bank: %s", err))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, p)
+}
+
+// appendScript handles a request to append outcomes to the script.
+func (s *Simulator) appendScript(w http.ResponseWriter, r *http.Request) {
+	var steps []struct {
+		Delay  string          `json:"delay"`
+		Status int             `json:"status"`
+		Reject RejectionReason `json:"reject"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&steps); err != nil {
+		writeError(w, http.StatusBadRequest, "the request body must be a JSON array of outcomes")
+		return
+	}
+
+	outcomes := make([]Outcome, len(steps))
+	for i, step := range steps {
+		if step.Delay != "" {
+			d, err := time.ParseDuration(step.Delay)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid delay %q", step.Delay))
+				return
+			}
+			outcomes[i].Delay = d
+		}
+
+		if step.Status != 0 && (step.Status < 400 || step.Status > 599) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("status %d is not an error status code", step.Status))
+			return
+		}
+
+		outcomes[i].StatusCode = step.Status
+		outcomes[i].Reject = step.Reject
+	}
+
+	s.Script(outcomes...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// clearScript handles a request to discard any unused scripted outcomes.
+func (s *Simulator) clearScript(w http.ResponseWriter, _ *http.Request) {
+	s.m.Lock()
+	s.script = nil
+	s.m.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listCredits handles a request for the credits that have been accepted.
+func (s *Simulator) listCredits(w http.ResponseWriter, _ *http.Request) {
+	credits := s.Credits()
+	if credits == nil {
+		credits = []CreditRequest{}
+	}
+
+	writeJSON(w, http.StatusOK, credits)
+}
+
+// listReturns handles a request for the returns that have been accepted.
+func (s *Simulator) listReturns(w http.ResponseWriter, _ *http.Request) {
+	returns := s.Returns()
+	if returns == nil {
+		returns = []ReturnRequest{}
+	}
+
+	writeJSON(w, http.StatusOK, returns)
+}
+
+func (s *Simulator) logf(format string, args ...any) {
+	if s.Logf != nil {
+		s.Logf(format, args...)
+	}
+}
+
+// writeJSON writes v as the JSON body of a response.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v) // nolint:errcheck
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, ErrorResponse{message})
+}
+
+// matchName returns how closely the expected name of an account matches its
+// actual name.
+//
+// Names that differ only in case, punctuation or spacing match. Names that
+// differ by a typo or two, or that have the same surname and first initial,
+// match closely.
+func matchName(expected, actual string) NameMatch {
+	e := normalizeName(expected)
+	a := normalizeName(actual)
+
+	if strings.Join(e, " ") == strings.Join(a, " ") {
+		return Match
+	}
+
+	if editDistance(strings.Join(e, " "), strings.Join(a, " ")) <= 2 {
+		return CloseMatch
+	}
+
+	if len(e) > 1 && len(a) > 1 &&
+		e[len(e)-1] == a[len(a)-1] &&
+		e[0][0] == a[0][0] {
+		return CloseMatch
+	}
+
+	return NoMatch
+}
+
+// normalizeName returns the words of a name in lowercase, without punctuation.
+func normalizeName(name string) []string {
+	return strings.Fields(
+		strings.Map(
+			func(r rune) rune {
+				switch {
+				case unicode.IsLetter(r), unicode.IsDigit(r):
+					return unicode.ToLower(r)
+				case unicode.IsSpace(r):
+					return ' '
+				default:
+					return -1
+				}
+			},
+			name,
+		),
+	)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	x, y := []rune(a), []rune(b)
+	prev := make([]int, len(y)+1)
+	curr := make([]int, len(y)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		curr[0] = i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(y)]
+}
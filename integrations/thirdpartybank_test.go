@@ -1,23 +1,50 @@
 package integrations_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/integrations"
+	"github.com/dogmatiq/example/integrations/thirdpartybank"
+	"github.com/dogmatiq/example/messages"
 	"github.com/dogmatiq/example/messages/commands"
 	"github.com/dogmatiq/example/messages/events"
 	. "github.com/dogmatiq/testkit"
 )
 
 func Test_ThirdPartyBankIntegrationHandler(t *testing.T) {
+	sim := &thirdpartybank.Simulator{
+		Names: map[string]string{
+			"06200012345674": "Anna Smith",
+		},
+	}
+	bank := httptest.NewServer(sim)
+	defer bank.Close()
+
+	payee := &messages.ThirdPartyPayee{
+		BSB:           "062-000",
+		AccountNumber: "12345674",
+		AccountName:   "Anna Smith",
+	}
+
+	app := &example.App{
+		ThirdPartyBank: integrations.ThirdPartyBankIntegrationHandler{
+			Client: &thirdpartybank.Client{BaseURL: bank.URL},
+		},
+	}
+
 	t.Run(
 		"when a credit is requested",
 		func(t *testing.T) {
 			t.Run(
-				"it credits the account if the account ID is numeric",
+				"it credits the account if the third-party bank accepts the credit",
 				func(t *testing.T) {
-					Begin(t, &example.App{}).
+					Begin(t, app).
 						EnableHandlers("third-party-bank").
 						Prepare(
 							ExecuteCommand(
@@ -25,6 +52,7 @@ func Test_ThirdPartyBankIntegrationHandler(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -40,27 +68,44 @@ func Test_ThirdPartyBankIntegrationHandler(t *testing.T) {
 								&commands.Transfer{
 									TransactionID:    "T001",
 									FromAccountID:    "A001",
-									ToAccountID:      "100001",
+									ToAccountID:      "06200012345674",
 									Amount:           100,
 									ScheduledTime:    time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
 									ToThirdPartyBank: true,
+									ToPayee:          payee,
 								},
 							),
 							ToRecordEvent(
 								&events.ThirdPartyAccountCredited{
-									TransactionID: "T001",
-									AccountID:     "100001",
-									Amount:        100,
+									TransactionID:   "T001",
+									AccountID:       "06200012345674",
+									TransactionType: messages.Transfer,
+									Amount:          100,
 								},
 							),
 						)
+
+					want := thirdpartybank.CreditRequest{
+						TransactionID: "T001",
+						AccountID:     "06200012345674",
+						BSB:           "062-000",
+						AccountNumber: "12345674",
+						AccountName:   "Anna Smith",
+						Amount:        100,
+					}
+
+					if !slices.Contains(sim.Credits(), want) {
+						t.Fatalf("expected the credit to identify the payee, got %v", sim.Credits())
+					}
 				},
 			)
 
 			t.Run(
-				"it fails the credit if the account ID is not numeric",
+				"it fails the credit if the third-party bank rejects the credit",
 				func(t *testing.T) {
-					Begin(t, &example.App{}).
+					sim.Script(thirdpartybank.Outcome{Reject: thirdpartybank.AccountNotFound})
+
+					Begin(t, app).
 						EnableHandlers("third-party-bank").
 						Prepare(
 							ExecuteCommand(
@@ -68,6 +113,7 @@ func Test_ThirdPartyBankIntegrationHandler(t *testing.T) {
 									CustomerID:  "C001",
 									AccountID:   "A001",
 									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
 								},
 							),
 							ExecuteCommand(
@@ -81,24 +127,255 @@ func Test_ThirdPartyBankIntegrationHandler(t *testing.T) {
 						Expect(
 							ExecuteCommand(
 								&commands.Transfer{
-									TransactionID:    "T001",
+									TransactionID:    "T002",
 									FromAccountID:    "A001",
-									ToAccountID:      "EXT001",
+									ToAccountID:      "06200012345674",
 									Amount:           100,
 									ScheduledTime:    time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
 									ToThirdPartyBank: true,
+									ToPayee:          payee,
 								},
 							),
 							ToRecordEvent(
 								&events.ThirdPartyAccountCreditFailed{
-									TransactionID: "T001",
-									AccountID:     "EXT001",
-									Amount:        100,
+									TransactionID:   "T002",
+									AccountID:       "06200012345674",
+									TransactionType: messages.Transfer,
+									Amount:          100,
 								},
 							),
 						)
 				},
 			)
+
+			t.Run(
+				"it records a failed attempt if the third-party bank fails with a transient error",
+				func(t *testing.T) {
+					sim.Script(thirdpartybank.Outcome{StatusCode: http.StatusServiceUnavailable})
+
+					Begin(t, app).
+						EnableHandlers("third-party-bank").
+						Prepare(
+							ExecuteCommand(
+								&commands.OpenAccount{
+									CustomerID:  "C001",
+									AccountID:   "A001",
+									AccountName: "Anna Smith",
+									AccountType: messages.Everyday,
+								},
+							),
+							ExecuteCommand(
+								&commands.Deposit{
+									TransactionID: "D001",
+									AccountID:     "A001",
+									Amount:        500,
+								},
+							),
+						).
+						Expect(
+							ExecuteCommand(
+								&commands.Transfer{
+									TransactionID:    "T003",
+									FromAccountID:    "A001",
+									ToAccountID:      "06200012345674",
+									Amount:           100,
+									ScheduledTime:    time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+									ToThirdPartyBank: true,
+									ToPayee:          payee,
+								},
+							),
+							ToRecordEvent(
+								&events.ThirdPartyCreditAttemptFailed{
+									TransactionID:   "T003",
+									AccountID:       "06200012345674",
+									TransactionType: messages.Transfer,
+									Amount:          100,
+									Attempt:         1,
+									Error:           "third-party bank responded with 503 Service Unavailable: simulated failure",
+									Payee:           payee,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when a credit is abandoned",
+		func(t *testing.T) {
+			t.Run(
+				"it fails the credit",
+				func(t *testing.T) {
+					Begin(t, app).
+						EnableHandlers("third-party-bank").
+						Expect(
+							ExecuteCommand(
+								&commands.AbandonThirdPartyCredit{
+									TransactionID:   "T001",
+									AccountID:       "06200012345674",
+									TransactionType: messages.Transfer,
+									Amount:          100,
+									Attempts:        3,
+								},
+							),
+							ToRecordEvent(
+								&events.ThirdPartyAccountCreditFailed{
+									TransactionID:   "T001",
+									AccountID:       "06200012345674",
+									TransactionType: messages.Transfer,
+									Amount:          100,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when a payee is checked",
+		func(t *testing.T) {
+			t.Run(
+				"it records the name of the account if it closely matches",
+				func(t *testing.T) {
+					payee := messages.ThirdPartyPayee{
+						BSB:           "062-000",
+						AccountNumber: "12345674",
+						AccountName:   "Ana Smith",
+					}
+
+					Begin(t, app).
+						EnableHandlers("third-party-bank").
+						Expect(
+							ExecuteCommand(
+								&commands.CheckPayee{
+									CheckID: "K001",
+									Payee:   payee,
+								},
+							),
+							ToRecordEvent(
+								&events.PayeeChecked{
+									CheckID:            "K001",
+									Payee:              payee,
+									Result:             messages.PayeeCloselyMatched,
+									MatchedAccountName: "Anna Smith",
+								},
+							),
+						)
+				},
+			)
+
+			t.Run(
+				"it records that the check is unavailable if the third-party bank does not know the name",
+				func(t *testing.T) {
+					payee := messages.ThirdPartyPayee{
+						BSB:           "082-001",
+						AccountNumber: "12345671",
+						AccountName:   "Bob Jones",
+					}
+
+					Begin(t, app).
+						EnableHandlers("third-party-bank").
+						Expect(
+							ExecuteCommand(
+								&commands.CheckPayee{
+									CheckID: "K001",
+									Payee:   payee,
+								},
+							),
+							ToRecordEvent(
+								&events.PayeeChecked{
+									CheckID: "K001",
+									Payee:   payee,
+									Result:  messages.PayeeCheckUnavailable,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when a payment is received",
+		func(t *testing.T) {
+			t.Run(
+				"it records the payment",
+				func(t *testing.T) {
+					Begin(t, app).
+						EnableHandlers("third-party-bank").
+						Expect(
+							ExecuteCommand(
+								&commands.ReceiveThirdPartyPayment{
+									TransactionID:           "P001",
+									ThirdPartyTransactionID: "TPBP00000001",
+									AccountID:               "A001",
+									SenderAccountID:         "200001",
+									Amount:                  500,
+								},
+							),
+							ToRecordEvent(
+								&events.ThirdPartyPaymentReceived{
+									TransactionID:           "P001",
+									ThirdPartyTransactionID: "TPBP00000001",
+									AccountID:               "A001",
+									SenderAccountID:         "200001",
+									Amount:                  500,
+								},
+							),
+						)
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"when a payment is returned",
+		func(t *testing.T) {
+			t.Run(
+				"it records the return once the third-party bank accepts it",
+				func(t *testing.T) {
+					receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusAccepted)
+					}))
+					defer receiver.Close()
+
+					sim.BankURL = receiver.URL
+					p, err := sim.Pay(context.Background(), "A999", "200001", 500)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					Begin(t, app).
+						EnableHandlers("third-party-bank").
+						Expect(
+							ExecuteCommand(
+								&commands.ReturnThirdPartyPayment{
+									TransactionID:           "P001",
+									ThirdPartyTransactionID: p.TransactionID,
+									AccountID:               "A999",
+									SenderAccountID:         "200001",
+									Amount:                  500,
+									Reason:                  messages.AccountNotFound,
+								},
+							),
+							ToRecordEvent(
+								&events.ThirdPartyPaymentReturned{
+									TransactionID:   "P001",
+									AccountID:       "A999",
+									SenderAccountID: "200001",
+									Amount:          500,
+									Reason:          messages.AccountNotFound,
+								},
+							),
+						)
+
+					if got := sim.Returns(); len(got) != 1 || got[0].TransactionID != p.TransactionID {
+						t.Fatalf("unexpected returns: %v", got)
+					}
+				},
+			)
 		},
 	)
 }
@@ -0,0 +1,112 @@
+package integrations
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/integrations/thirdpartybank"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/google/uuid"
+)
+
+// maxPaymentSize is the largest notification of a payment that is read from
+// the third-party bank.
+const maxPaymentSize = 64 << 10
+
+// thirdPartyPaymentNamespace is the namespace of the transaction IDs derived
+// from the third-party bank's own IDs for its payments.
+var thirdPartyPaymentNamespace = uuid.MustParse("8d4f2b71-3e6a-4c09-b5d2-7a1e9f0c6b38")
+
+// ThirdPartyPaymentHandler is an [http.Handler] that receives notifications of
+// payments from the third-party bank into this bank's accounts.
+//
+// It serves [thirdpartybank.PaymentPath], and executes a
+// [commands.ReceiveThirdPartyPayment] command for each notification that is
+// signed with the secret shared with the third-party bank. The third-party
+// bank repeats a notification until it is accepted, so the same payment may be
+// received more than once.
+type ThirdPartyPaymentHandler struct {
+	// CommandExecutor is used to execute the command for each payment.
+	CommandExecutor dogma.CommandExecutor
+
+	// Secret is the secret shared with the third-party bank. Notifications
+	// that are not signed with it are rejected. If it is empty, every
+	// notification is rejected.
+	Secret []byte
+
+	once sync.Once
+	mux  http.ServeMux
+}
+
+func (h *ThirdPartyPaymentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.once.Do(func() {
+		h.mux.HandleFunc("POST "+thirdpartybank.PaymentPath, h.receivePayment)
+	})
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPaymentSize)
+	h.mux.ServeHTTP(w, r)
+}
+
+// receivePayment handles a notification of a payment.
+func (h *ThirdPartyPaymentHandler) receivePayment(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "the request body could not be read")
+		return
+	}
+
+	if !thirdpartybank.Verify(h.Secret, body, r.Header.Get(thirdpartybank.SignatureHeader)) {
+		writeError(w, http.StatusUnauthorized, "the request is not signed with the shared secret")
+		return
+	}
+
+	var p thirdpartybank.Payment
+	if err := json.Unmarshal(body, &p); err != nil {
+		writeError(w, http.StatusBadRequest, "the request body is not a valid payment")
+		return
+	}
+
+	if p.TransactionID == "" || p.AccountID == "" || p.SenderAccountID == "" || p.Amount < 1 {
+		writeError(w, http.StatusBadRequest, "the transaction ID, account ID, sender account ID and a positive amount are required")
+		return
+	}
+
+	if err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.ReceiveThirdPartyPayment{
+			TransactionID:           ThirdPartyPaymentTransactionID(p.TransactionID),
+			ThirdPartyTransactionID: p.TransactionID,
+			AccountID:               p.AccountID,
+			SenderAccountID:         p.SenderAccountID,
+			Amount:                  p.Amount,
+		},
+	); err != nil {
+		writeError(w, http.StatusServiceUnavailable, "the payment could not be recorded")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ThirdPartyPaymentTransactionID returns the ID of the transaction that
+// records a payment from the third-party bank, given the third-party bank's
+// own ID for the payment.
+//
+// The ID is derived rather than used as-is so that the third-party bank can
+// not choose the IDs of this bank's transactions.
+func ThirdPartyPaymentTransactionID(id string) string {
+	return uuid.NewSHA1(
+		thirdPartyPaymentNamespace,
+		[]byte("third-party:"+id),
+	).String()
+}
+
+// writeError writes an error response to the third-party bank.
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(thirdpartybank.ErrorResponse{Error: message}) // nolint:errcheck
+}
@@ -0,0 +1,180 @@
+package integrations_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/example/integrations"
+	"github.com/dogmatiq/example/integrations/thirdpartybank"
+	"github.com/dogmatiq/example/messages/commands"
+)
+
+// commandExecutorFunc is a [dogma.CommandExecutor] implemented by a function.
+type commandExecutorFunc func(context.Context, dogma.Command, ...dogma.ExecuteCommandOption) error
+
+func (fn commandExecutorFunc) ExecuteCommand(
+	ctx context.Context,
+	c dogma.Command,
+	options ...dogma.ExecuteCommandOption,
+) error {
+	return fn(ctx, c, options...)
+}
+
+func Test_ThirdPartyPaymentHandler(t *testing.T) {
+	secret := []byte("<secret>")
+
+	post := func(t *testing.T, h http.Handler, signature, body string) *http.Response {
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/v1/payments", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if signature != "" {
+			req.Header.Set(thirdpartybank.SignatureHeader, signature)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		return res
+	}
+
+	postSigned := func(t *testing.T, h http.Handler, body string) *http.Response {
+		return post(t, h, thirdpartybank.Sign(secret, []byte(body)), body)
+	}
+
+	t.Run(
+		"it executes a command for each payment",
+		func(t *testing.T) {
+			var executed []dogma.Command
+			h := &ThirdPartyPaymentHandler{
+				CommandExecutor: commandExecutorFunc(func(_ context.Context, c dogma.Command, _ ...dogma.ExecuteCommandOption) error {
+					executed = append(executed, c)
+					return nil
+				}),
+				Secret: secret,
+			}
+
+			res := postSigned(t, h, `{"transaction_id": "P001", "account_id": "A001", "sender_account_id": "200001", "amount": 500}`)
+
+			if res.StatusCode != http.StatusAccepted {
+				t.Fatalf("unexpected status: %d", res.StatusCode)
+			}
+
+			want := &commands.ReceiveThirdPartyPayment{
+				TransactionID:           ThirdPartyPaymentTransactionID("P001"),
+				ThirdPartyTransactionID: "P001",
+				AccountID:               "A001",
+				SenderAccountID:         "200001",
+				Amount:                  500,
+			}
+
+			if len(executed) != 1 || *executed[0].(*commands.ReceiveThirdPartyPayment) != *want {
+				t.Fatalf("unexpected commands: %v", executed)
+			}
+		},
+	)
+
+	t.Run(
+		"it does not use the third-party bank's ID as the transaction ID",
+		func(t *testing.T) {
+			id := ThirdPartyPaymentTransactionID("P001")
+
+			if id == "P001" {
+				t.Fatal("expected the transaction ID to be derived from the third-party bank's ID")
+			}
+
+			if id != ThirdPartyPaymentTransactionID("P001") {
+				t.Fatal("expected the same transaction ID for a repeated notification")
+			}
+
+			if id == ThirdPartyPaymentTransactionID("P002") {
+				t.Fatal("expected a different transaction ID for a different payment")
+			}
+		},
+	)
+
+	t.Run(
+		"it rejects a payment that is not signed with the shared secret",
+		func(t *testing.T) {
+			body := `{"transaction_id": "P001", "account_id": "A001", "sender_account_id": "200001", "amount": 500}`
+
+			cases := []struct {
+				Name      string
+				Secret    []byte
+				Signature string
+			}{
+				{"unsigned", secret, ""},
+				{"signed with another secret", secret, thirdpartybank.Sign([]byte("<other>"), []byte(body))},
+				{"signature is not hex", secret, "<signature>"},
+				{"no secret is configured", nil, thirdpartybank.Sign(nil, []byte(body))},
+			}
+
+			for _, c := range cases {
+				t.Run(c.Name, func(t *testing.T) {
+					h := &ThirdPartyPaymentHandler{
+						CommandExecutor: commandExecutorFunc(func(context.Context, dogma.Command, ...dogma.ExecuteCommandOption) error {
+							t.Fatal("unexpected command")
+							return nil
+						}),
+						Secret: c.Secret,
+					}
+
+					res := post(t, h, c.Signature, body)
+
+					if res.StatusCode != http.StatusUnauthorized {
+						t.Fatalf("unexpected status: %d", res.StatusCode)
+					}
+				})
+			}
+		},
+	)
+
+	t.Run(
+		"it rejects an invalid payment",
+		func(t *testing.T) {
+			h := &ThirdPartyPaymentHandler{
+				CommandExecutor: commandExecutorFunc(func(context.Context, dogma.Command, ...dogma.ExecuteCommandOption) error {
+					t.Fatal("unexpected command")
+					return nil
+				}),
+				Secret: secret,
+			}
+
+			res := postSigned(t, h, `{"transaction_id": "P001", "account_id": "A001", "amount": 500}`)
+
+			if res.StatusCode != http.StatusBadRequest {
+				t.Fatalf("unexpected status: %d", res.StatusCode)
+			}
+		},
+	)
+
+	t.Run(
+		"it asks the bank to try again if the payment can not be recorded",
+		func(t *testing.T) {
+			h := &ThirdPartyPaymentHandler{
+				CommandExecutor: commandExecutorFunc(func(context.Context, dogma.Command, ...dogma.ExecuteCommandOption) error {
+					return errors.New("<error>")
+				}),
+				Secret: secret,
+			}
+
+			res := postSigned(t, h, `{"transaction_id": "P001", "account_id": "A001", "sender_account_id": "200001", "amount": 500}`)
+
+			if res.StatusCode != http.StatusServiceUnavailable {
+				t.Fatalf("unexpected status: %d", res.StatusCode)
+			}
+		},
+	)
+}
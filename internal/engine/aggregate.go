@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+)
+
+// aggregate is an aggregate message handler, along with the roots of the
+// instances that have been loaded from the event stream.
+type aggregate struct {
+	Key     string
+	Name    string
+	Handler dogma.AggregateMessageHandler[dogma.AggregateRoot]
+	Roots   map[string]dogma.AggregateRoot
+}
+
+// handleAggregateCommand handles a command using an aggregate message handler
+// and appends the resulting events to the event stream.
+func (e *Engine) handleAggregateCommand(
+	ctx context.Context,
+	a *aggregate,
+	c storedCommand,
+) error {
+	id := a.Handler.RouteCommandToInstance(c.Command)
+
+	root, err := e.loadAggregateRoot(ctx, a, id)
+	if err != nil {
+		return err
+	}
+
+	// The handler applies events to the root as it records them. The root is
+	// removed from the cache until those events are committed, so that a root
+	// with events that never reach the event stream, because the commit failed
+	// or the handler panicked, is never reused.
+	delete(a.Roots, id)
+
+	s := &aggregateScope{
+		engine:     e,
+		handler:    a,
+		instanceID: id,
+		root:       root,
+		now:        time.Now(),
+	}
+
+	a.Handler.HandleCommand(root, s, c.Command)
+
+	if err := e.commitCommand(ctx, a.Key, id, s.now, c, s.events); err != nil {
+		return err
+	}
+
+	a.Roots[id] = root
+
+	return nil
+}
+
+// loadAggregateRoot returns the root of an aggregate instance, rebuilding it
+// from the event stream if it has not already been loaded.
+func (e *Engine) loadAggregateRoot(
+	ctx context.Context,
+	a *aggregate,
+	id string,
+) (dogma.AggregateRoot, error) {
+	if root, ok := a.Roots[id]; ok {
+		return root, nil
+	}
+
+	events, err := loadInstanceEvents(ctx, e.db, a.Key, id)
+	if err != nil {
+		return nil, err
+	}
+
+	root := a.Handler.New()
+	for _, ev := range events {
+		root.ApplyEvent(ev)
+	}
+
+	a.Roots[id] = root
+
+	return root, nil
+}
+
+// commitCommand atomically appends the events recorded while handling a
+// command to the event stream and removes the command from the queue.
+func (e *Engine) commitCommand(
+	ctx context.Context,
+	handlerKey, instanceID string,
+	recordedAt time.Time,
+	c storedCommand,
+	events []dogma.Event,
+) error {
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	if err := appendEvents(ctx, tx, handlerKey, instanceID, recordedAt, c.CorrelationID, events); err != nil {
+		return err
+	}
+
+	if err := dequeueCommand(ctx, tx, c.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// aggregateScope is an implementation of [dogma.AggregateCommandScope].
+type aggregateScope struct {
+	engine     *Engine
+	handler    *aggregate
+	instanceID string
+	root       dogma.AggregateRoot
+	now        time.Time
+	events     []dogma.Event
+}
+
+func (s *aggregateScope) InstanceID() string {
+	return s.instanceID
+}
+
+func (s *aggregateScope) RecordEvent(ev dogma.Event) {
+	if err := ev.Validate(eventValidationScope{s.now}); err != nil {
+		panic(fmt.Sprintf("%s recorded an invalid %T event: %s", s.handler.Name, ev, err))
+	}
+
+	s.root.ApplyEvent(ev)
+	s.events = append(s.events, ev)
+
+	s.engine.log("%s[%s]: %s", s.handler.Name, s.instanceID, ev.MessageDescription())
+}
+
+func (s *aggregateScope) Now() time.Time {
+	return s.now
+}
+
+func (s *aggregateScope) Log(format string, args ...any) {
+	s.engine.log("%s[%s]: "+format, append([]any{s.handler.Name, s.instanceID}, args...)...)
+}
@@ -0,0 +1,14 @@
+// Package engine is a minimal Dogma engine that persists the state of the
+// application to a SQLite database, such that it survives a restart.
+//
+// Every event recorded by an aggregate or integration is appended to a single
+// event stream. Aggregate state is rebuilt from that stream as each instance is
+// first used, process state is stored alongside it, and each process and
+// projection consumes the stream from its own checkpoint offset.
+//
+// Unlike the testkit engine it is intended for running the example as a
+// long-lived server. It makes no attempt at scalability; all messages are
+// handled one at a time, within a single operating system process. The only
+// exception is integration handlers, which run without blocking the rest of
+// the engine while they wait on external systems.
+package engine
@@ -0,0 +1,410 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	"github.com/google/uuid"
+)
+
+const (
+	// tickInterval is the interval at which the engine checks for deadlines
+	// that have fallen due, and for deferred commands that may be retried.
+	tickInterval = time.Second
+
+	// retryDelay is the length of time that the engine waits before retrying
+	// a command that an integration handler failed to handle.
+	retryDelay = 5 * time.Second
+
+	// integrationLease is the length of time that a command is withheld from
+	// the queue while an integration handler is handling it.
+	integrationLease = time.Minute
+)
+
+// Engine runs a Dogma application, persisting its state to a SQLite database.
+//
+// It implements [dogma.CommandExecutor]. Unlike most engines, ExecuteCommand
+// does not return until the command and all of the messages that it causes
+// have been handled, and all projections are up-to-date.
+type Engine struct {
+	db       *sql.DB
+	streamID string
+	log      func(format string, args ...any)
+
+	m sync.Mutex
+
+	// idle is signaled each time an integration handler finishes handling a
+	// command. inFlight is the number of commands being handled by
+	// integration handlers, keyed by correlation ID.
+	idle     *sync.Cond
+	inFlight map[string]int
+
+	aggregates   map[string]*aggregate
+	integrations map[string]*integration
+	processes    []*process
+	projections  []*projection
+}
+
+// New returns a new engine that runs app, persisting its state to db.
+//
+// log is called with a human-readable description of each message that is
+// handled. It may be nil.
+func New(
+	ctx context.Context,
+	app dogma.Application,
+	db *sql.DB,
+	log func(format string, args ...any),
+) (*Engine, error) {
+	if err := CreateSchema(ctx, db); err != nil {
+		return nil, fmt.Errorf("unable to create engine schema: %w", err)
+	}
+
+	if log == nil {
+		log = func(string, ...any) {}
+	}
+
+	cfg := runtimeconfig.FromApplication(app)
+
+	e := &Engine{
+		db:           db,
+		streamID:     cfg.Identity().GetKey().AsString(),
+		log:          log,
+		inFlight:     map[string]int{},
+		aggregates:   map[string]*aggregate{},
+		integrations: map[string]*integration{},
+	}
+
+	e.idle = sync.NewCond(&e.m)
+
+	for _, h := range cfg.Handlers() {
+		if h.IsDisabled() {
+			continue
+		}
+
+		id := h.Identity()
+		key := id.GetKey().AsString()
+		name := id.GetName()
+
+		switch h := h.(type) {
+		case *config.Aggregate:
+			a := &aggregate{
+				Key:     key,
+				Name:    name,
+				Handler: h.Interface(),
+				Roots:   map[string]dogma.AggregateRoot{},
+			}
+			for _, t := range routedTypeIDs(h.RouteSet(), config.HandlesCommandRouteType) {
+				e.aggregates[t] = a
+			}
+
+		case *config.Integration:
+			i := &integration{
+				Key:     key,
+				Name:    name,
+				Handler: h.Interface(),
+			}
+			for _, t := range routedTypeIDs(h.RouteSet(), config.HandlesCommandRouteType) {
+				e.integrations[t] = i
+			}
+
+		case *config.Process:
+			e.processes = append(e.processes, &process{
+				Key:          key,
+				Name:         name,
+				Handler:      h.Interface(),
+				EventTypeIDs: routedTypeIDs(h.RouteSet(), config.HandlesEventRouteType),
+			})
+
+		case *config.Projection:
+			e.projections = append(e.projections, &projection{
+				Name:         name,
+				Handler:      h.Interface(),
+				EventTypeIDs: routedTypeIDs(h.RouteSet(), config.HandlesEventRouteType),
+			})
+		}
+	}
+
+	return e, nil
+}
+
+// routedTypeIDs returns the registered type IDs of the messages in the route
+// set with the given route type.
+func routedTypeIDs(routes config.RouteSet, rt config.RouteType) []string {
+	var ids []string
+
+	for r := range routes.Routes() {
+		if r.RouteType.Get() == rt {
+			ids = append(ids, r.MessageTypeID.Get())
+		}
+	}
+
+	return ids
+}
+
+// ExecuteCommand executes a command, blocking until it and all of the messages
+// that it causes have been handled.
+//
+// The observers in options only see the events caused by c, even if other
+// commands are handled while an integration handler is running.
+func (e *Engine) ExecuteCommand(
+	ctx context.Context,
+	c dogma.Command,
+	options ...dogma.ExecuteCommandOption,
+) error {
+	var observers []dogma.EventObserverOption
+
+	for _, opt := range options {
+		if o, ok := opt.(dogma.EventObserverOption); ok {
+			observers = append(observers, o)
+		}
+	}
+
+	now := time.Now()
+
+	if err := c.Validate(commandValidationScope{now}); err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+
+	if !e.handlesCommand(c) {
+		return fmt.Errorf("no handler for %T", c)
+	}
+
+	correlationID := uuid.NewString()
+
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	offset, err := nextOffset(ctx, e.db)
+	if err != nil {
+		return err
+	}
+
+	if err := enqueueCommands(ctx, e.db, now, correlationID, []dogma.Command{c}); err != nil {
+		return err
+	}
+
+	for {
+		if err := e.pump(ctx); err != nil {
+			return err
+		}
+
+		// If a command caused by c is being handled by an integration on
+		// behalf of another call, wait for it to finish then handle any
+		// messages that it causes.
+		if e.inFlight[correlationID] == 0 {
+			break
+		}
+
+		e.idle.Wait()
+	}
+
+	if len(observers) == 0 {
+		return nil
+	}
+
+	return e.observe(ctx, offset, correlationID, observers)
+}
+
+// observe invokes the observers for each event with the given correlation ID
+// that was recorded at or after the given offset.
+func (e *Engine) observe(
+	ctx context.Context,
+	offset uint64,
+	correlationID string,
+	observers []dogma.EventObserverOption,
+) error {
+	events, err := loadEvents(ctx, e.db, offset, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, ev := range events {
+		if ev.CorrelationID != correlationID {
+			continue
+		}
+
+		t, _ := dogma.RegisteredMessageTypeOf(ev.Event)
+
+		for _, o := range observers {
+			if o.EventType().ID() != t.ID() {
+				continue
+			}
+
+			satisfied, err := o.Observer()(ctx, ev.Event)
+			if err != nil {
+				return err
+			}
+			if satisfied {
+				return nil
+			}
+		}
+	}
+
+	return dogma.ErrEventObserverNotSatisfied
+}
+
+// Run handles deadlines as they fall due, and retries deferred commands, until
+// ctx is canceled.
+//
+// Before it begins waiting, it handles any commands and events that were not
+// handled before the engine was last stopped.
+func (e *Engine) Run(ctx context.Context) error {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.tick(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			e.log("engine error: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick handles any deadlines that have fallen due, then any pending commands
+// and events.
+func (e *Engine) tick(ctx context.Context) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	deadlines, err := loadDueDeadlines(ctx, e.db, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deadlines {
+		if err := e.handleDeadline(ctx, d); err != nil {
+			return err
+		}
+	}
+
+	return e.pump(ctx)
+}
+
+// pump handles queued commands and the events they cause until there is no
+// more work to do, then brings all projections up-to-date.
+func (e *Engine) pump(ctx context.Context) error {
+	for {
+		for _, p := range e.processes {
+			if err := e.runProcess(ctx, p); err != nil {
+				return err
+			}
+		}
+
+		ok, err := e.handleNextCommand(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+	}
+
+	for _, p := range e.projections {
+		if err := e.runProjection(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleNextCommand handles the command at the front of the command queue. It
+// returns false if the queue is empty.
+func (e *Engine) handleNextCommand(ctx context.Context) (bool, error) {
+	now := time.Now()
+
+	c, ok, err := nextCommand(ctx, e.db, now)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	t, _ := dogma.RegisteredMessageTypeOf(c.Command)
+
+	if a, ok := e.aggregates[t.ID()]; ok {
+		return true, e.handleAggregateCommand(ctx, a, c)
+	}
+
+	if i, ok := e.integrations[t.ID()]; ok {
+		if err := e.handleIntegrationCommand(ctx, i, c); err != nil {
+			e.log("%s: unable to handle command, retrying in %s: %s", i.Name, retryDelay, err)
+			return true, deferCommand(ctx, e.db, c.ID, now.Add(retryDelay))
+		}
+		return true, nil
+	}
+
+	return false, fmt.Errorf("no handler for %T", c.Command)
+}
+
+// handlesCommand returns true if c is handled by one of the application's
+// handlers.
+func (e *Engine) handlesCommand(c dogma.Command) bool {
+	t, ok := dogma.RegisteredMessageTypeOf(c)
+	if !ok {
+		return false
+	}
+
+	_, isAggregate := e.aggregates[t.ID()]
+	_, isIntegration := e.integrations[t.ID()]
+
+	return isAggregate || isIntegration
+}
+
+// commandValidationScope is an implementation of
+// [dogma.CommandValidationScope].
+type commandValidationScope struct {
+	executedAt time.Time
+}
+
+func (commandValidationScope) IsNew() bool {
+	return true
+}
+
+func (s commandValidationScope) ExecutedAt() time.Time {
+	return s.executedAt
+}
+
+// eventValidationScope is an implementation of [dogma.EventValidationScope].
+type eventValidationScope struct {
+	recordedAt time.Time
+}
+
+func (eventValidationScope) IsNew() bool {
+	return true
+}
+
+func (s eventValidationScope) RecordedAt() time.Time {
+	return s.recordedAt
+}
+
+// deadlineValidationScope is an implementation of
+// [dogma.DeadlineValidationScope].
+type deadlineValidationScope struct {
+	scheduledAt  time.Time
+	scheduledFor time.Time
+}
+
+func (deadlineValidationScope) IsNew() bool {
+	return true
+}
+
+func (s deadlineValidationScope) ScheduledAt() time.Time {
+	return s.scheduledAt
+}
+
+func (s deadlineValidationScope) ScheduledFor() time.Time {
+	return s.scheduledFor
+}
@@ -0,0 +1,281 @@
+package engine_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/integrations/thirdpartybank"
+	. "github.com/dogmatiq/example/internal/engine"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/dogmatiq/example/ui/projections"
+	"github.com/dogmatiq/projectionkit/sqlprojection"
+)
+
+func Test_Engine(t *testing.T) {
+	t.Run("when the engine is restarted", func(t *testing.T) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "bank.db")
+
+		db, e := start(t, path)
+
+		execute(t, e, &commands.OpenAccountForNewCustomer{
+			CustomerID:   "C001",
+			CustomerName: "Anna Smith",
+			AccountID:    "A001",
+			AccountName:  "Anna Smith",
+			AccountType:  messages.Everyday,
+		})
+		execute(t, e, &commands.Deposit{
+			TransactionID: "D001",
+			AccountID:     "A001",
+			Amount:        100_00,
+		})
+
+		db.Close()
+		db, e = start(t, path)
+
+		t.Run("it rebuilds aggregate state from the event store", func(t *testing.T) {
+			execute(
+				t,
+				e,
+				&commands.Withdraw{
+					TransactionID: "W001",
+					AccountID:     "A001",
+					Amount:        40_00,
+					ScheduledTime: time.Now(),
+				},
+				dogma.WithEventObserver(
+					func(context.Context, *events.WithdrawalApproved) (bool, error) {
+						return true, nil
+					},
+				),
+			)
+		})
+
+		t.Run("it resumes projections from their stored offsets", func(t *testing.T) {
+			var balance int64
+
+			if err := db.QueryRowContext(
+				ctx,
+				`SELECT balance FROM accounts WHERE id = ?`,
+				"A001",
+			).Scan(&balance); err != nil {
+				t.Fatal(err)
+			}
+
+			if balance != 60_00 {
+				t.Fatalf("unexpected balance: got %d, want %d", balance, 60_00)
+			}
+		})
+	})
+
+	t.Run("when a projection is rebuilt into a shadow database", func(t *testing.T) {
+		ctx := context.Background()
+		dir := t.TempDir()
+
+		db, e := start(t, filepath.Join(dir, "bank.db"))
+
+		execute(t, e, &commands.OpenAccountForNewCustomer{
+			CustomerID:   "C001",
+			CustomerName: "Anna Smith",
+			AccountID:    "A001",
+			AccountName:  "Anna Smith",
+			AccountType:  messages.Everyday,
+		})
+
+		if _, err := db.ExecContext(
+			ctx,
+			`UPDATE customers SET name = 'Corrupted'`,
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		shadowPath := filepath.Join(dir, "shadow.db")
+		shadow, err := projections.NewDB(shadowPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { shadow.Close() })
+
+		h := &projections.CustomerProjectionHandler{}
+		var done, total int
+
+		if err := e.RebuildProjection(
+			ctx,
+			sqlprojection.New(shadow, sqlprojection.SQLiteDriver, h),
+			func(d, n int) { done, total = d, n },
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := projections.SwapProjection(ctx, db, shadowPath, h); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("it reports progress", func(t *testing.T) {
+			if done != 1 || total != 1 {
+				t.Fatalf("unexpected progress: got %d of %d, want 1 of 1", done, total)
+			}
+		})
+
+		t.Run("it replaces the projection's data", func(t *testing.T) {
+			var name string
+
+			if err := db.QueryRowContext(
+				ctx,
+				`SELECT name FROM customers WHERE id = ?`,
+				"C001",
+			).Scan(&name); err != nil {
+				t.Fatal(err)
+			}
+
+			if name != "Anna Smith" {
+				t.Fatalf("unexpected name: got %q, want %q", name, "Anna Smith")
+			}
+		})
+
+		t.Run("it does not handle the events again after the swap", func(t *testing.T) {
+			execute(t, e, &commands.Deposit{
+				TransactionID: "D001",
+				AccountID:     "A001",
+				Amount:        100_00,
+			})
+
+			var count int
+
+			if err := db.QueryRowContext(
+				ctx,
+				`SELECT COUNT(*) FROM customers`,
+			).Scan(&count); err != nil {
+				t.Fatal(err)
+			}
+
+			if count != 1 {
+				t.Fatalf("unexpected number of customers: got %d, want 1", count)
+			}
+		})
+	})
+
+	t.Run("when an integration handler is waiting on the third-party bank", func(t *testing.T) {
+		respond := make(chan struct{})
+		requested := make(chan struct{})
+
+		bank := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			close(requested)
+			<-respond
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"result":"match"}`)) // nolint:errcheck
+		}))
+		t.Cleanup(bank.Close)
+
+		_, e := start(
+			t,
+			filepath.Join(t.TempDir(), "bank.db"),
+			&thirdpartybank.Client{BaseURL: bank.URL},
+		)
+
+		checked := make(chan error, 1)
+
+		go func() {
+			checked <- e.ExecuteCommand(
+				context.Background(),
+				&commands.CheckPayee{
+					CheckID: "K001",
+					Payee: messages.ThirdPartyPayee{
+						BSB:           "062-000",
+						AccountNumber: "12345674",
+						AccountName:   "Anna Smith",
+					},
+				},
+				dogma.WithEventObserver(
+					func(context.Context, *events.PayeeChecked) (bool, error) {
+						return true, nil
+					},
+				),
+			)
+		}()
+
+		<-requested
+
+		t.Run("it handles other commands in the meantime", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := e.ExecuteCommand(
+				ctx,
+				&commands.OpenAccountForNewCustomer{
+					CustomerID:   "C001",
+					CustomerName: "Anna Smith",
+					AccountID:    "A001",
+					AccountName:  "Anna Smith",
+					AccountType:  messages.Everyday,
+				},
+				dogma.WithEventObserver(
+					func(context.Context, *events.AccountOpened) (bool, error) {
+						return true, nil
+					},
+				),
+			); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		close(respond)
+
+		t.Run("it returns the integration's events to the original caller", func(t *testing.T) {
+			if err := <-checked; err != nil {
+				t.Fatal(err)
+			}
+		})
+	})
+}
+
+// start opens the database at path and returns an engine that runs the
+// example application against it, using client to contact the third-party
+// bank, if given.
+func start(t *testing.T, path string, client ...*thirdpartybank.Client) (*sql.DB, *Engine) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	db, err := projections.NewDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	app := &example.App{ReadDB: db}
+	if len(client) > 0 {
+		app.ThirdPartyBank.Client = client[0]
+	}
+
+	e, err := New(ctx, app, db, t.Logf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db, e
+}
+
+// execute executes a command via e, failing the test if it returns an error.
+func execute(
+	t *testing.T,
+	e *Engine,
+	c dogma.Command,
+	options ...dogma.ExecuteCommandOption,
+) {
+	t.Helper()
+
+	if err := e.ExecuteCommand(context.Background(), c, options...); err != nil {
+		t.Fatal(err)
+	}
+}
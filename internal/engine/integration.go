@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+)
+
+// integration is an integration message handler.
+type integration struct {
+	Key     string
+	Name    string
+	Handler dogma.IntegrationMessageHandler
+}
+
+// handleIntegrationCommand handles a command using an integration message
+// handler and appends the resulting events to the event stream.
+//
+// Integration handlers typically call out to external systems, so the engine's
+// lock is released while the handler runs, allowing other commands to be
+// handled in the meantime. The command is leased for [integrationLease] so that
+// it is not handled again concurrently. If the engine stops before the handler
+// returns, the command is retried once the lease expires.
+//
+// If the handler returns an error the command remains in the queue.
+func (e *Engine) handleIntegrationCommand(
+	ctx context.Context,
+	i *integration,
+	c storedCommand,
+) error {
+	s := &integrationScope{
+		engine:  e,
+		handler: i,
+		now:     time.Now(),
+	}
+
+	if err := deferCommand(ctx, e.db, c.ID, s.now.Add(integrationLease)); err != nil {
+		return err
+	}
+
+	e.inFlight[c.CorrelationID]++
+	defer func() {
+		if e.inFlight[c.CorrelationID]--; e.inFlight[c.CorrelationID] == 0 {
+			delete(e.inFlight, c.CorrelationID)
+		}
+		e.idle.Broadcast()
+	}()
+
+	if err := e.unlocked(func() error {
+		return i.Handler.HandleCommand(ctx, s, c.Command)
+	}); err != nil {
+		return err
+	}
+
+	return e.commitCommand(ctx, i.Key, "", s.now, c, s.events)
+}
+
+// unlocked calls fn with the engine's lock released. The lock is re-acquired
+// before unlocked returns, even if fn panics.
+func (e *Engine) unlocked(fn func() error) error {
+	e.m.Unlock()
+	defer e.m.Lock()
+
+	return fn()
+}
+
+// integrationScope is an implementation of [dogma.IntegrationCommandScope].
+type integrationScope struct {
+	engine  *Engine
+	handler *integration
+	now     time.Time
+	events  []dogma.Event
+}
+
+func (s *integrationScope) RecordEvent(ev dogma.Event) {
+	if err := ev.Validate(eventValidationScope{s.now}); err != nil {
+		panic(fmt.Sprintf("%s recorded an invalid %T event: %s", s.handler.Name, ev, err))
+	}
+
+	s.events = append(s.events, ev)
+
+	s.engine.log("%s: %s", s.handler.Name, ev.MessageDescription())
+}
+
+func (s *integrationScope) Now() time.Time {
+	return s.now
+}
+
+func (s *integrationScope) Log(format string, args ...any) {
+	s.engine.log("%s: "+format, append([]any{s.handler.Name}, args...)...)
+}
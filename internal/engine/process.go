@@ -0,0 +1,430 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+)
+
+// process is a process message handler.
+type process struct {
+	Key          string
+	Name         string
+	Handler      dogma.ProcessMessageHandler[dogma.ProcessRoot]
+	EventTypeIDs []string
+}
+
+// storedDeadline is a deadline that has been scheduled by a process instance.
+type storedDeadline struct {
+	ID           int64
+	HandlerKey   string
+	InstanceID   string
+	Deadline     dogma.Deadline
+	ScheduledFor time.Time
+}
+
+// scheduledDeadline is a deadline that has been scheduled within a process
+// scope, but not yet persisted.
+type scheduledDeadline struct {
+	Deadline     dogma.Deadline
+	ScheduledFor time.Time
+}
+
+// runProcess handles the events in the stream that a process handler has not
+// yet handled.
+func (e *Engine) runProcess(ctx context.Context, p *process) error {
+	offset, err := loadCheckpoint(ctx, e.db, p.Key)
+	if err != nil {
+		return err
+	}
+
+	events, err := loadEvents(ctx, e.db, offset, p.EventTypeIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, ev := range events {
+		if err := e.handleProcessEvent(ctx, p, ev); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleProcessEvent handles a single event using a process handler, and
+// advances the handler's checkpoint past it.
+func (e *Engine) handleProcessEvent(ctx context.Context, p *process, ev storedEvent) error {
+	id, ok, err := p.Handler.RouteEventToInstance(ctx, ev.Event)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	if ok {
+		s, err := e.loadProcessInstance(ctx, tx, p, id)
+		if err != nil {
+			return err
+		}
+
+		s.correlationID = ev.CorrelationID
+
+		if !s.ended {
+			if err := p.Handler.HandleEvent(
+				ctx,
+				s.root,
+				&processEventScope{s, ev.RecordedAt},
+				ev.Event,
+			); err != nil {
+				return err
+			}
+
+			if err := s.save(ctx, tx); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := saveCheckpoint(ctx, tx, p.Key, ev.Offset+1); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// handleDeadline handles a deadline that has fallen due.
+func (e *Engine) handleDeadline(ctx context.Context, d storedDeadline) error {
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	for _, p := range e.processes {
+		if p.Key != d.HandlerKey {
+			continue
+		}
+
+		s, err := e.loadProcessInstance(ctx, tx, p, d.InstanceID)
+		if err != nil {
+			return err
+		}
+
+		if !s.ended {
+			if err := p.Handler.HandleDeadline(
+				ctx,
+				s.root,
+				&processDeadlineScope{s, d.ScheduledFor},
+				d.Deadline,
+			); err != nil {
+				return err
+			}
+
+			if err := s.save(ctx, tx); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM deadlines WHERE id = ?`,
+		d.ID,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// loadProcessInstance returns a scope for handling a message within a process
+// instance, loading the instance's root from the database if it exists.
+func (e *Engine) loadProcessInstance(
+	ctx context.Context,
+	tx *sql.Tx,
+	p *process,
+	id string,
+) (*processScope, error) {
+	s := &processScope{
+		engine:     e,
+		handler:    p,
+		instanceID: id,
+		root:       p.Handler.New(),
+		now:        time.Now(),
+	}
+
+	var data []byte
+
+	err := tx.QueryRowContext(
+		ctx,
+		`SELECT
+			data,
+			has_ended
+		FROM process_instances
+		WHERE handler_key = ?
+		AND instance_id = ?`,
+		p.Key,
+		id,
+	).Scan(
+		&data,
+		&s.ended,
+	)
+	if err == sql.ErrNoRows {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !s.ended {
+		if err := s.root.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal %s[%s]: %w", p.Name, id, err)
+		}
+	}
+
+	return s, nil
+}
+
+// loadDueDeadlines returns the deadlines that are due at or before now, in the
+// order they fall due.
+func loadDueDeadlines(ctx context.Context, db *sql.DB, now time.Time) ([]storedDeadline, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT
+			id,
+			handler_key,
+			instance_id,
+			type_id,
+			data,
+			scheduled_for
+		FROM deadlines
+		WHERE scheduled_for <= ?
+		ORDER BY scheduled_for, id`,
+		now.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deadlines []storedDeadline
+
+	for rows.Next() {
+		var (
+			d      storedDeadline
+			typeID string
+			data   []byte
+		)
+
+		if err := rows.Scan(
+			&d.ID,
+			&d.HandlerKey,
+			&d.InstanceID,
+			&typeID,
+			&data,
+			&d.ScheduledFor,
+		); err != nil {
+			return nil, err
+		}
+
+		m, err := unmarshalMessage(typeID, data)
+		if err != nil {
+			return nil, err
+		}
+
+		d.Deadline = m.(dogma.Deadline)
+		deadlines = append(deadlines, d)
+	}
+
+	return deadlines, rows.Err()
+}
+
+// processScope is the common implementation of [dogma.ProcessEventScope] and
+// [dogma.ProcessDeadlineScope].
+type processScope struct {
+	engine     *Engine
+	handler    *process
+	instanceID string
+	root       dogma.ProcessRoot
+	now        time.Time
+	ended      bool
+	commands   []dogma.Command
+	deadlines  []scheduledDeadline
+
+	// correlationID is the correlation ID of the event being handled, which is
+	// carried by the commands that the instance executes. It is empty when
+	// handling a deadline.
+	correlationID string
+}
+
+func (s *processScope) InstanceID() string {
+	return s.instanceID
+}
+
+func (s *processScope) Mutate(fn func(dogma.ProcessRoot)) {
+	if s.ended {
+		panic("cannot mutate an ended process instance")
+	}
+
+	fn(s.root)
+}
+
+func (s *processScope) End() {
+	s.ended = true
+	s.engine.log("%s[%s]: ended", s.handler.Name, s.instanceID)
+}
+
+func (s *processScope) ExecuteCommand(c dogma.Command) {
+	if s.ended {
+		panic("cannot execute a command from an ended process instance")
+	}
+
+	if err := c.Validate(commandValidationScope{s.now}); err != nil {
+		panic(fmt.Sprintf("%s executed an invalid %T command: %s", s.handler.Name, c, err))
+	}
+
+	s.commands = append(s.commands, c)
+
+	s.engine.log("%s[%s]: %s", s.handler.Name, s.instanceID, c.MessageDescription())
+}
+
+func (s *processScope) ScheduleDeadline(d dogma.Deadline, t time.Time) {
+	if s.ended {
+		panic("cannot schedule a deadline from an ended process instance")
+	}
+
+	if err := d.Validate(deadlineValidationScope{s.now, t}); err != nil {
+		panic(fmt.Sprintf("%s scheduled an invalid %T deadline: %s", s.handler.Name, d, err))
+	}
+
+	s.deadlines = append(s.deadlines, scheduledDeadline{d, t})
+}
+
+func (s *processScope) Now() time.Time {
+	return s.now
+}
+
+func (s *processScope) Log(format string, args ...any) {
+	s.engine.log("%s[%s]: "+format, append([]any{s.handler.Name, s.instanceID}, args...)...)
+}
+
+// save persists the process instance's state, along with the commands and
+// deadlines produced within the scope.
+//
+// If the instance has ended, its state is discarded and all of its pending
+// deadlines are cancelled instead.
+func (s *processScope) save(ctx context.Context, tx *sql.Tx) error {
+	if err := enqueueCommands(ctx, tx, s.now, s.correlationID, s.commands); err != nil {
+		return err
+	}
+
+	if s.ended {
+		if _, err := tx.ExecContext(
+			ctx,
+			`DELETE FROM deadlines
+			WHERE handler_key = ?
+			AND instance_id = ?`,
+			s.handler.Key,
+			s.instanceID,
+		); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO process_instances (
+				handler_key,
+				instance_id,
+				data,
+				has_ended
+			) VALUES (
+				?, ?, NULL, TRUE
+			) ON CONFLICT (handler_key, instance_id) DO UPDATE SET
+				data = NULL,
+				has_ended = TRUE`,
+			s.handler.Key,
+			s.instanceID,
+		)
+		return err
+	}
+
+	data, err := s.root.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("unable to marshal %s[%s]: %w", s.handler.Name, s.instanceID, err)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO process_instances (
+			handler_key,
+			instance_id,
+			data
+		) VALUES (
+			?, ?, ?
+		) ON CONFLICT (handler_key, instance_id) DO UPDATE SET
+			data = excluded.data`,
+		s.handler.Key,
+		s.instanceID,
+		data,
+	); err != nil {
+		return err
+	}
+
+	for _, d := range s.deadlines {
+		typeID, data, err := marshalMessage(d.Deadline)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO deadlines (
+				handler_key,
+				instance_id,
+				type_id,
+				data,
+				scheduled_at,
+				scheduled_for
+			) VALUES (
+				?, ?, ?, ?, ?, ?
+			)`,
+			s.handler.Key,
+			s.instanceID,
+			typeID,
+			data,
+			s.now.UTC(),
+			d.ScheduledFor.UTC(),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processEventScope is an implementation of [dogma.ProcessEventScope].
+type processEventScope struct {
+	*processScope
+	recordedAt time.Time
+}
+
+func (s *processEventScope) RecordedAt() time.Time {
+	return s.recordedAt
+}
+
+// processDeadlineScope is an implementation of [dogma.ProcessDeadlineScope].
+type processDeadlineScope struct {
+	*processScope
+	scheduledFor time.Time
+}
+
+func (s *processDeadlineScope) ScheduledFor() time.Time {
+	return s.scheduledFor
+}
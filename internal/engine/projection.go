@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+)
+
+// projection is a projection message handler.
+type projection struct {
+	Name         string
+	Handler      dogma.ProjectionMessageHandler
+	EventTypeIDs []string
+}
+
+// runProjection handles the events in the stream that a projection has not yet
+// handled, starting from the checkpoint offset stored by the projection itself.
+func (e *Engine) runProjection(ctx context.Context, p *projection) error {
+	return e.replay(ctx, p, nil)
+}
+
+// replay handles the events in the stream that a projection has not yet
+// handled. If progress is non-nil, it is called after each event.
+func (e *Engine) replay(
+	ctx context.Context,
+	p *projection,
+	progress func(done, total int),
+) error {
+	cp, err := p.Handler.CheckpointOffset(ctx, e.streamID)
+	if err != nil {
+		return err
+	}
+
+	events, err := loadEvents(ctx, e.db, cp, p.EventTypeIDs)
+	if err != nil {
+		return err
+	}
+
+	for i, ev := range events {
+		cp, err = p.Handler.HandleEvent(
+			ctx,
+			&projectionScope{
+				engine:     e,
+				handler:    p,
+				recordedAt: ev.RecordedAt,
+				offset:     ev.Offset,
+				checkpoint: cp,
+			},
+			ev.Event,
+		)
+		if err != nil {
+			return err
+		}
+
+		if progress != nil {
+			progress(i+1, len(events))
+		}
+	}
+
+	return nil
+}
+
+// projectionScope is an implementation of [dogma.ProjectionEventScope].
+type projectionScope struct {
+	engine     *Engine
+	handler    *projection
+	recordedAt time.Time
+	offset     uint64
+	checkpoint uint64
+}
+
+func (s *projectionScope) RecordedAt() time.Time {
+	return s.recordedAt
+}
+
+func (s *projectionScope) StreamID() string {
+	return s.engine.streamID
+}
+
+func (s *projectionScope) Offset() uint64 {
+	return s.offset
+}
+
+func (s *projectionScope) CheckpointOffset() uint64 {
+	return s.checkpoint
+}
+
+func (s *projectionScope) Now() time.Time {
+	return time.Now()
+}
+
+func (s *projectionScope) Log(format string, args ...any) {
+	s.engine.log("%s: "+format, append([]any{s.handler.Name}, args...)...)
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+)
+
+// RebuildProjection resets a projection, then replays the entire event stream
+// into it.
+//
+// h need not be one of the application's own handlers; it may be a handler that
+// writes to a different ("shadow") database, which is swapped into place once
+// the rebuild is complete.
+//
+// progress, if non-nil, is called after each event is handled with the number
+// of events handled so far and the total number of events to handle.
+func (e *Engine) RebuildProjection(
+	ctx context.Context,
+	h dogma.ProjectionMessageHandler,
+	progress func(done, total int),
+) error {
+	cfg := runtimeconfig.FromProjection(h)
+
+	p := &projection{
+		Name:         cfg.Identity().GetName(),
+		Handler:      h,
+		EventTypeIDs: routedTypeIDs(cfg.RouteSet(), config.HandlesEventRouteType),
+	}
+
+	if err := h.Reset(ctx, &projectionResetScope{e, p}); err != nil {
+		return fmt.Errorf("unable to reset %s: %w", p.Name, err)
+	}
+
+	return e.replay(ctx, p, progress)
+}
+
+// projectionResetScope is an implementation of [dogma.ProjectionResetScope].
+type projectionResetScope struct {
+	engine  *Engine
+	handler *projection
+}
+
+func (s *projectionResetScope) Now() time.Time {
+	return time.Now()
+}
+
+func (s *projectionResetScope) Log(format string, args ...any) {
+	s.engine.log("%s: "+format, append([]any{s.handler.Name}, args...)...)
+}
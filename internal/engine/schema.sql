@@ -0,0 +1,61 @@
+-- All times are stored in UTC, so that they compare correctly as strings.
+
+-- events is the application's event stream. It contains every event recorded
+-- by an aggregate or integration, in the order that it was recorded.
+CREATE TABLE IF NOT EXISTS events (
+    stream_offset  INTEGER   NOT NULL, -- position of the event within the stream, starting at 0
+    handler_key    TEXT      NOT NULL, -- identity key of the handler that recorded the event
+    instance_id    TEXT      NOT NULL, -- aggregate instance that recorded the event, empty for integrations
+    type_id        TEXT      NOT NULL, -- registered message type ID of the event
+    data           BLOB      NOT NULL, -- binary representation of the event
+    recorded_at    TIMESTAMP NOT NULL, -- time at which the event was recorded
+    correlation_id TEXT      NOT NULL, -- ExecuteCommand call that ultimately caused the event, empty if none
+
+    PRIMARY KEY (stream_offset)
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_instance ON events (handler_key, instance_id);
+
+-- commands is the queue of commands that have been executed but not yet
+-- handled.
+CREATE TABLE IF NOT EXISTS commands (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT, -- position of the command within the queue
+    type_id        TEXT      NOT NULL,                -- registered message type ID of the command
+    data           BLOB      NOT NULL,                -- binary representation of the command
+    executed_at    TIMESTAMP NOT NULL,                -- time at which the command was executed
+    not_before     TIMESTAMP NOT NULL,                -- earliest time at which the command may be handled
+    correlation_id TEXT      NOT NULL                 -- ExecuteCommand call that ultimately caused the command, empty if none
+);
+
+-- process_instances contains the state of each process instance.
+CREATE TABLE IF NOT EXISTS process_instances (
+    handler_key TEXT    NOT NULL,           -- identity key of the process handler
+    instance_id TEXT    NOT NULL,           -- process instance ID
+    data        BLOB,                       -- binary representation of the process root, NULL once ended
+    has_ended   BOOLEAN NOT NULL DEFAULT 0, -- true if the instance has ended
+
+    PRIMARY KEY (handler_key, instance_id)
+);
+
+-- deadlines contains the deadlines that have been scheduled by process
+-- instances but not yet handled.
+CREATE TABLE IF NOT EXISTS deadlines (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT, -- unique deadline identifier
+    handler_key   TEXT      NOT NULL,                -- identity key of the process handler
+    instance_id   TEXT      NOT NULL,                -- process instance that scheduled the deadline
+    type_id       TEXT      NOT NULL,                -- registered message type ID of the deadline
+    data          BLOB      NOT NULL,                -- binary representation of the deadline
+    scheduled_at  TIMESTAMP NOT NULL,                -- time at which the deadline was scheduled
+    scheduled_for TIMESTAMP NOT NULL                 -- time at which the deadline is due
+);
+
+CREATE INDEX IF NOT EXISTS idx_deadlines_scheduled_for ON deadlines (scheduled_for);
+
+-- checkpoints records the offset of the next event in the stream that each
+-- process handler has yet to handle. Projections store their own checkpoints.
+CREATE TABLE IF NOT EXISTS checkpoints (
+    handler_key   TEXT    NOT NULL, -- identity key of the process handler
+    stream_offset INTEGER NOT NULL, -- offset of the next event to handle
+
+    PRIMARY KEY (handler_key)
+);
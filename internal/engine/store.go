@@ -0,0 +1,425 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+)
+
+// schema is the SQL schema of the engine's database.
+//
+//go:embed schema.sql
+var schema string
+
+// CreateSchema creates the database tables required by the engine, if they do
+// not already exist.
+func CreateSchema(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, schema)
+	return err
+}
+
+// execer is an interface for executing SQL statements, satisfied by both
+// [sql.DB] and [sql.Tx].
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// storedEvent is an event that has been appended to the event stream.
+type storedEvent struct {
+	Offset        uint64
+	Event         dogma.Event
+	RecordedAt    time.Time
+	CorrelationID string
+}
+
+// storedCommand is a command in the command queue.
+type storedCommand struct {
+	ID            int64
+	Command       dogma.Command
+	ExecutedAt    time.Time
+	CorrelationID string
+}
+
+// marshalMessage returns the registered type ID and binary representation of
+// m.
+func marshalMessage(m dogma.Message) (string, []byte, error) {
+	t, ok := dogma.RegisteredMessageTypeOf(m)
+	if !ok {
+		return "", nil, fmt.Errorf("%T is not in the message registry", m)
+	}
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to marshal %T: %w", m, err)
+	}
+
+	return t.ID(), data, nil
+}
+
+// unmarshalMessage returns the message with the given registered type ID and
+// binary representation.
+func unmarshalMessage(typeID string, data []byte) (dogma.Message, error) {
+	t, ok := dogma.RegisteredMessageTypeByID(typeID)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a registered message type ID", typeID)
+	}
+
+	m := t.New()
+	if err := m.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal %s: %w", t.GoType(), err)
+	}
+
+	return m, nil
+}
+
+// appendEvents appends events recorded by a handler to the end of the event
+// stream. correlationID is that of the command that the handler was handling.
+func appendEvents(
+	ctx context.Context,
+	tx *sql.Tx,
+	handlerKey, instanceID string,
+	recordedAt time.Time,
+	correlationID string,
+	events []dogma.Event,
+) error {
+	var next uint64
+
+	if err := tx.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(MAX(stream_offset) + 1, 0) FROM events`,
+	).Scan(&next); err != nil {
+		return err
+	}
+
+	for _, ev := range events {
+		typeID, data, err := marshalMessage(ev)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO events (
+				stream_offset,
+				handler_key,
+				instance_id,
+				type_id,
+				data,
+				recorded_at,
+				correlation_id
+			) VALUES (
+				?, ?, ?, ?, ?, ?, ?
+			)`,
+			next,
+			handlerKey,
+			instanceID,
+			typeID,
+			data,
+			recordedAt.UTC(),
+			correlationID,
+		); err != nil {
+			return err
+		}
+
+		next++
+	}
+
+	return nil
+}
+
+// nextOffset returns the offset that will be assigned to the next event that
+// is appended to the event stream.
+func nextOffset(ctx context.Context, db *sql.DB) (uint64, error) {
+	var next uint64
+
+	err := db.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(MAX(stream_offset) + 1, 0) FROM events`,
+	).Scan(&next)
+
+	return next, err
+}
+
+// loadEvents returns the events in the stream at or after the given offset.
+//
+// If typeIDs is non-empty, only events of those types are returned.
+func loadEvents(
+	ctx context.Context,
+	db *sql.DB,
+	offset uint64,
+	typeIDs []string,
+) ([]storedEvent, error) {
+	query := `SELECT
+			stream_offset,
+			type_id,
+			data,
+			recorded_at,
+			correlation_id
+		FROM events
+		WHERE stream_offset >= ?`
+	args := []any{offset}
+
+	if len(typeIDs) > 0 {
+		query += ` AND type_id IN (?` + strings.Repeat(`, ?`, len(typeIDs)-1) + `)`
+		for _, id := range typeIDs {
+			args = append(args, id)
+		}
+	}
+
+	query += ` ORDER BY stream_offset`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []storedEvent
+
+	for rows.Next() {
+		var (
+			ev     storedEvent
+			typeID string
+			data   []byte
+		)
+
+		if err := rows.Scan(
+			&ev.Offset,
+			&typeID,
+			&data,
+			&ev.RecordedAt,
+			&ev.CorrelationID,
+		); err != nil {
+			return nil, err
+		}
+
+		m, err := unmarshalMessage(typeID, data)
+		if err != nil {
+			return nil, err
+		}
+
+		ev.Event = m.(dogma.Event)
+		events = append(events, ev)
+	}
+
+	return events, rows.Err()
+}
+
+// loadInstanceEvents returns the events recorded by a specific aggregate
+// instance, in the order they were recorded.
+func loadInstanceEvents(
+	ctx context.Context,
+	db *sql.DB,
+	handlerKey, instanceID string,
+) ([]dogma.Event, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT
+			type_id,
+			data
+		FROM events
+		WHERE handler_key = ?
+		AND instance_id = ?
+		ORDER BY stream_offset`,
+		handlerKey,
+		instanceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []dogma.Event
+
+	for rows.Next() {
+		var (
+			typeID string
+			data   []byte
+		)
+
+		if err := rows.Scan(&typeID, &data); err != nil {
+			return nil, err
+		}
+
+		m, err := unmarshalMessage(typeID, data)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, m.(dogma.Event))
+	}
+
+	return events, rows.Err()
+}
+
+// enqueueCommands adds commands to the end of the command queue.
+//
+// correlationID identifies the [Engine.ExecuteCommand] call that ultimately
+// caused the commands, if any.
+func enqueueCommands(
+	ctx context.Context,
+	db execer,
+	executedAt time.Time,
+	correlationID string,
+	commands []dogma.Command,
+) error {
+	for _, c := range commands {
+		typeID, data, err := marshalMessage(c)
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.ExecContext(
+			ctx,
+			`INSERT INTO commands (
+				type_id,
+				data,
+				executed_at,
+				not_before,
+				correlation_id
+			) VALUES (
+				?, ?, ?, ?, ?
+			)`,
+			typeID,
+			data,
+			executedAt.UTC(),
+			executedAt.UTC(),
+			correlationID,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextCommand returns the command at the front of the command queue, ignoring
+// any that may not be handled until after now.
+func nextCommand(
+	ctx context.Context,
+	db *sql.DB,
+	now time.Time,
+) (storedCommand, bool, error) {
+	var (
+		c      storedCommand
+		typeID string
+		data   []byte
+	)
+
+	err := db.QueryRowContext(
+		ctx,
+		`SELECT
+			id,
+			type_id,
+			data,
+			executed_at,
+			correlation_id
+		FROM commands
+		WHERE not_before <= ?
+		ORDER BY id
+		LIMIT 1`,
+		now.UTC(),
+	).Scan(
+		&c.ID,
+		&typeID,
+		&data,
+		&c.ExecutedAt,
+		&c.CorrelationID,
+	)
+	if err == sql.ErrNoRows {
+		return storedCommand{}, false, nil
+	} else if err != nil {
+		return storedCommand{}, false, err
+	}
+
+	m, err := unmarshalMessage(typeID, data)
+	if err != nil {
+		return storedCommand{}, false, err
+	}
+
+	c.Command = m.(dogma.Command)
+
+	return c, true, nil
+}
+
+// dequeueCommand removes a command from the command queue.
+//
+// It returns an error if the command is no longer in the queue, which occurs
+// if an integration handler takes longer than [integrationLease] to handle it
+// and the command is handled again in the meantime.
+func dequeueCommand(ctx context.Context, tx *sql.Tx, id int64) error {
+	res, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM commands WHERE id = ?`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return fmt.Errorf("command %d has already been handled", id)
+	}
+
+	return nil
+}
+
+// deferCommand prevents a command from being handled again until notBefore.
+func deferCommand(ctx context.Context, db *sql.DB, id int64, notBefore time.Time) error {
+	_, err := db.ExecContext(
+		ctx,
+		`UPDATE commands SET
+			not_before = ?
+		WHERE id = ?`,
+		notBefore.UTC(),
+		id,
+	)
+	return err
+}
+
+// loadCheckpoint returns the offset of the next event to be handled by a
+// process handler.
+func loadCheckpoint(ctx context.Context, db *sql.DB, handlerKey string) (uint64, error) {
+	var offset uint64
+
+	err := db.QueryRowContext(
+		ctx,
+		`SELECT stream_offset
+		FROM checkpoints
+		WHERE handler_key = ?`,
+		handlerKey,
+	).Scan(&offset)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+
+	return offset, err
+}
+
+// saveCheckpoint records the offset of the next event to be handled by a
+// process handler.
+func saveCheckpoint(ctx context.Context, tx *sql.Tx, handlerKey string, offset uint64) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO checkpoints (
+			handler_key,
+			stream_offset
+		) VALUES (
+			?, ?
+		) ON CONFLICT (handler_key) DO UPDATE SET
+			stream_offset = excluded.stream_offset`,
+		handlerKey,
+		offset,
+	)
+	return err
+}
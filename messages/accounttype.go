@@ -0,0 +1,31 @@
+package messages
+
+import "fmt"
+
+// AccountType defines the types of bank account.
+type AccountType string
+
+const (
+	// Everyday is a transaction account with no restrictions on debits.
+	Everyday AccountType = "everyday"
+
+	// Savings is an account that permits a limited number of withdrawals each
+	// month.
+	Savings AccountType = "savings"
+
+	// TermDeposit is an account from which funds can not be debited until it
+	// reaches its maturity date.
+	TermDeposit AccountType = "term deposit"
+)
+
+// Validate return an error if t is not a valid account type.
+func (t AccountType) Validate() error {
+	switch t {
+	case Everyday,
+		Savings,
+		TermDeposit:
+		return nil
+	default:
+		return fmt.Errorf("invalid account type: %s", string(t))
+	}
+}
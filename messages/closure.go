@@ -0,0 +1,39 @@
+package messages
+
+import "fmt"
+
+// ClosureDeclineReason defines reasons why a request to close an account may
+// be declined.
+type ClosureDeclineReason string
+
+const (
+	// AccountIsFrozen means that the account can not be closed because it has
+	// been frozen.
+	AccountIsFrozen ClosureDeclineReason = "account is frozen"
+
+	// AccountIsOverdrawn means that the account can not be closed because its
+	// balance is negative.
+	AccountIsOverdrawn ClosureDeclineReason = "account is overdrawn"
+
+	// AccountHasPendingFunds means that the account can not be closed because
+	// it has debits that may still be refunded, or holds that have not been
+	// captured or released.
+	AccountHasPendingFunds ClosureDeclineReason = "account has pending funds"
+
+	// NoSweepAccountNominated means that the account can not be closed because
+	// it has a remaining balance and no account was nominated to receive it.
+	NoSweepAccountNominated ClosureDeclineReason = "no account nominated for the remaining balance"
+)
+
+// Validate return an error if r is not a valid reason.
+func (r ClosureDeclineReason) Validate() error {
+	switch r {
+	case AccountIsFrozen,
+		AccountIsOverdrawn,
+		AccountHasPendingFunds,
+		NoSweepAccountNominated:
+		return nil
+	default:
+		return fmt.Errorf("invalid closure decline reason: %s", string(r))
+	}
+}
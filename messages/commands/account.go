@@ -8,6 +8,7 @@ import (
 
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/internal/validation"
 )
 
 func init() {
@@ -15,6 +16,9 @@ func init() {
 	dogma.RegisterCommand[*OpenAccount]("3d20299c-ba57-4756-8692-f4e3a95fe00d")
 	dogma.RegisterCommand[*CreditAccount]("0ffb0a54-eaf5-459c-a58b-76f22b95de2d")
 	dogma.RegisterCommand[*DebitAccount]("4209ab99-5f15-45b9-a88f-48bce219b911")
+	dogma.RegisterCommand[*SettleDebit]("5832dc9e-4efe-4917-abb1-c5be2ec49dc3")
+	dogma.RegisterCommand[*CloseAccount]("e75c0c94-281f-4ab9-83e2-dada6509b7ae")
+	dogma.RegisterCommand[*SweepClosedAccount]("f8fe0426-2c4d-4f5f-864e-f40a5cd50e63")
 }
 
 // OpenAccountForNewCustomer is a command requesting that a new bank account be
@@ -24,14 +28,18 @@ type OpenAccountForNewCustomer struct {
 	CustomerName string
 	AccountID    string
 	AccountName  string
+	AccountType  messages.AccountType
+	MaturityDate string
 }
 
 // OpenAccount is a command requesting that a new bank account be opened for an
 // existing customer.
 type OpenAccount struct {
-	CustomerID  string
-	AccountID   string
-	AccountName string
+	CustomerID   string
+	AccountID    string
+	AccountName  string
+	AccountType  messages.AccountType
+	MaturityDate string
 }
 
 // CreditAccount is a command that requests a bank account be credited.
@@ -51,6 +59,44 @@ type DebitAccount struct {
 	ScheduledTime   time.Time
 }
 
+// SettleDebit is a command that marks a prior debit of a bank account as
+// settled, meaning that it will not be refunded.
+type SettleDebit struct {
+	TransactionID string
+	AccountID     string
+}
+
+// CloseAccount is a command requesting that a bank account be closed, with any
+// remaining balance swept to another account.
+type CloseAccount struct {
+	TransactionID         string
+	AccountID             string
+	SweepToAccountID      string
+	SweepToThirdPartyBank bool
+
+	// SweepToPayee identifies the third-party account by its BSB, account
+	// number and name, and SweepToAccountID is the payee's account ID. It is
+	// required if SweepToThirdPartyBank is true.
+	SweepToPayee *messages.ThirdPartyPayee
+
+	// SweepToPayeeID, if non-empty, is the ID of the account owner's saved
+	// payee to which the balance is swept. The sweep counts towards the limit
+	// on transfers to the payee during its cooling-off period.
+	SweepToPayeeID string
+}
+
+// SweepClosedAccount is a command requesting that the balance of a closed
+// account be swept to another account. A closed account only has a balance if
+// the sweep made when it was closed failed and the balance was returned.
+type SweepClosedAccount struct {
+	TransactionID         string
+	AccountID             string
+	SweepToAccountID      string
+	SweepToThirdPartyBank bool
+	SweepToPayee          *messages.ThirdPartyPayee
+	SweepToPayeeID        string
+}
+
 // MessageDescription returns a human-readable description of the message.
 func (m *OpenAccountForNewCustomer) MessageDescription() string {
 	return fmt.Sprintf(
@@ -65,7 +111,8 @@ func (m *OpenAccountForNewCustomer) MessageDescription() string {
 // MessageDescription returns a human-readable description of the message.
 func (m *OpenAccount) MessageDescription() string {
 	return fmt.Sprintf(
-		"opening account %s %s for customer %s",
+		"opening %s account %s %s for customer %s",
+		m.AccountType,
 		m.AccountID,
 		m.AccountName,
 		m.CustomerID,
@@ -94,6 +141,34 @@ func (m *DebitAccount) MessageDescription() string {
 	)
 }
 
+// MessageDescription returns a human-readable description of the message.
+func (m *SettleDebit) MessageDescription() string {
+	return fmt.Sprintf(
+		"transaction %s: settling debit of account %s",
+		m.TransactionID,
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *CloseAccount) MessageDescription() string {
+	return fmt.Sprintf(
+		"account closure %s: closing account %s",
+		m.TransactionID,
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *SweepClosedAccount) MessageDescription() string {
+	return fmt.Sprintf(
+		"account closure %s: sweeping returned balance of closed account %s to %s",
+		m.TransactionID,
+		m.AccountID,
+		m.SweepToAccountID,
+	)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *OpenAccountForNewCustomer) Validate(dogma.CommandValidationScope) error {
 	if m.CustomerID == "" {
@@ -108,6 +183,16 @@ func (m *OpenAccountForNewCustomer) Validate(dogma.CommandValidationScope) error
 	if m.AccountName == "" {
 		return errors.New("OpenAccountForNewCustomer must not have an empty account name")
 	}
+	if err := m.AccountType.Validate(); err != nil {
+		return fmt.Errorf("OpenAccountForNewCustomer must have a valid account type: %w", err)
+	}
+	if m.AccountType == messages.TermDeposit {
+		if !validation.IsValidDate(m.MaturityDate) {
+			return errors.New("OpenAccountForNewCustomer must have a valid maturity date")
+		}
+	} else if m.MaturityDate != "" {
+		return errors.New("OpenAccountForNewCustomer must not have a maturity date unless it is a term deposit")
+	}
 
 	return nil
 }
@@ -123,6 +208,16 @@ func (m *OpenAccount) Validate(dogma.CommandValidationScope) error {
 	if m.AccountName == "" {
 		return errors.New("OpenAccount must not have an empty account name")
 	}
+	if err := m.AccountType.Validate(); err != nil {
+		return fmt.Errorf("OpenAccount must have a valid account type: %w", err)
+	}
+	if m.AccountType == messages.TermDeposit {
+		if !validation.IsValidDate(m.MaturityDate) {
+			return errors.New("OpenAccount must have a valid maturity date")
+		}
+	} else if m.MaturityDate != "" {
+		return errors.New("OpenAccount must not have a maturity date unless it is a term deposit")
+	}
 
 	return nil
 }
@@ -163,6 +258,77 @@ func (m *DebitAccount) Validate(dogma.CommandValidationScope) error {
 	return nil
 }
 
+// Validate returns a non-nil error if the message is invalid.
+func (m *SettleDebit) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("SettleDebit must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("SettleDebit must not have an empty account ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *CloseAccount) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("CloseAccount must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("CloseAccount must not have an empty account ID")
+	}
+	if m.SweepToAccountID == m.AccountID {
+		return errors.New("CloseAccount must not sweep the balance to the account being closed")
+	}
+	if m.SweepToThirdPartyBank {
+		if m.SweepToPayee == nil {
+			return errors.New("CloseAccount must have a payee when it is to a third-party bank")
+		}
+		if err := m.SweepToPayee.Validate(); err != nil {
+			return fmt.Errorf("CloseAccount must have a valid payee: %w", err)
+		}
+		if m.SweepToAccountID != m.SweepToPayee.AccountID() {
+			return errors.New("CloseAccount sweep account ID must be the payee's account ID")
+		}
+	} else if m.SweepToPayee != nil {
+		return errors.New("CloseAccount must not have a payee unless it is to a third-party bank")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *SweepClosedAccount) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("SweepClosedAccount must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("SweepClosedAccount must not have an empty account ID")
+	}
+	if m.SweepToAccountID == "" {
+		return errors.New("SweepClosedAccount must not have an empty sweep account ID")
+	}
+	if m.SweepToAccountID == m.AccountID {
+		return errors.New("SweepClosedAccount must not sweep the balance to the closed account")
+	}
+	if m.SweepToThirdPartyBank {
+		if m.SweepToPayee == nil {
+			return errors.New("SweepClosedAccount must have a payee when it is to a third-party bank")
+		}
+		if err := m.SweepToPayee.Validate(); err != nil {
+			return fmt.Errorf("SweepClosedAccount must have a valid payee: %w", err)
+		}
+		if m.SweepToAccountID != m.SweepToPayee.AccountID() {
+			return errors.New("SweepClosedAccount sweep account ID must be the payee's account ID")
+		}
+	} else if m.SweepToPayee != nil {
+		return errors.New("SweepClosedAccount must not have a payee unless it is to a third-party bank")
+	}
+
+	return nil
+}
+
 // MarshalBinary returns a binary representation of the message.
 // For simplicity in this example we use JSON.
 func (m *OpenAccountForNewCustomer) MarshalBinary() ([]byte, error) {
@@ -210,3 +376,39 @@ func (m *DebitAccount) MarshalBinary() ([]byte, error) {
 func (m *DebitAccount) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *SettleDebit) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *SettleDebit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *CloseAccount) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *CloseAccount) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *SweepClosedAccount) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *SweepClosedAccount) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+)
+
+func init() {
+	dogma.RegisterCommand[*SetCustomerCredentials]("033599f1-b312-4ca5-aaa8-ea37547f0955")
+	dogma.RegisterCommand[*ChangeCustomerPassword]("ceddede1-36b8-4416-b2f8-747aa65937ee")
+}
+
+// SetCustomerCredentials is a command requesting that the credentials a
+// customer uses to log in be set. It is ignored if the customer's credentials
+// have already been set.
+type SetCustomerCredentials struct {
+	CustomerID   string
+	Username     string
+	PasswordHash string
+}
+
+// ChangeCustomerPassword is a command requesting that the password a customer
+// uses to log in be replaced.
+type ChangeCustomerPassword struct {
+	CustomerID   string
+	PasswordHash string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *SetCustomerCredentials) MessageDescription() string {
+	return fmt.Sprintf(
+		"setting credentials for customer %s with username %s",
+		m.CustomerID,
+		m.Username,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ChangeCustomerPassword) MessageDescription() string {
+	return fmt.Sprintf(
+		"changing password for customer %s",
+		m.CustomerID,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *SetCustomerCredentials) Validate(dogma.CommandValidationScope) error {
+	if m.CustomerID == "" {
+		return errors.New("SetCustomerCredentials must not have an empty customer ID")
+	}
+	if m.Username == "" {
+		return errors.New("SetCustomerCredentials must not have an empty username")
+	}
+	if m.PasswordHash == "" {
+		return errors.New("SetCustomerCredentials must not have an empty password hash")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ChangeCustomerPassword) Validate(dogma.CommandValidationScope) error {
+	if m.CustomerID == "" {
+		return errors.New("ChangeCustomerPassword must not have an empty customer ID")
+	}
+	if m.PasswordHash == "" {
+		return errors.New("ChangeCustomerPassword must not have an empty password hash")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *SetCustomerCredentials) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *SetCustomerCredentials) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ChangeCustomerPassword) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ChangeCustomerPassword) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
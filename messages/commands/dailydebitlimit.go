@@ -12,6 +12,8 @@ import (
 
 func init() {
 	dogma.RegisterCommand[*ConsumeDailyDebitLimit]("d86ca816-6333-4b78-a1d3-9368b3adcf65")
+	dogma.RegisterCommand[*ChangeDailyDebitLimit]("4f0d5a3e-7b1c-4c8e-9a26-5e3f8d1b7c40")
+	dogma.RegisterCommand[*RestoreDailyDebitLimit]("7a2e9c14-d06b-4f83-b5e1-3c8f6a0d2947")
 }
 
 // ConsumeDailyDebitLimit is a command requesting that an amount of an account
@@ -22,6 +24,25 @@ type ConsumeDailyDebitLimit struct {
 	DebitType     messages.TransactionType
 	Amount        int64
 	Date          string
+	DailyLimit    int64
+}
+
+// ChangeDailyDebitLimit is a command requesting that the daily debit limit of
+// an account be changed.
+type ChangeDailyDebitLimit struct {
+	AccountID       string
+	DailyDebitLimit int64
+}
+
+// RestoreDailyDebitLimit is a command requesting that an amount previously
+// consumed from an account daily debit limit be given back, because the debit
+// that consumed it did not go ahead.
+type RestoreDailyDebitLimit struct {
+	TransactionID string
+	AccountID     string
+	DebitType     messages.TransactionType
+	Amount        int64
+	Date          string
 }
 
 // MessageDescription returns a human-readable description of the message.
@@ -36,6 +57,27 @@ func (m *ConsumeDailyDebitLimit) MessageDescription() string {
 	)
 }
 
+// MessageDescription returns a human-readable description of the message.
+func (m *ChangeDailyDebitLimit) MessageDescription() string {
+	return fmt.Sprintf(
+		"changing daily debit limit of account %s to %s",
+		m.AccountID,
+		messages.FormatAmount(m.DailyDebitLimit),
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *RestoreDailyDebitLimit) MessageDescription() string {
+	return fmt.Sprintf(
+		"%s %s: restoring %s to %s daily debit limit of account %s",
+		m.DebitType,
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.Date,
+		m.AccountID,
+	)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *ConsumeDailyDebitLimit) Validate(dogma.CommandValidationScope) error {
 	if m.TransactionID == "" {
@@ -56,6 +98,45 @@ func (m *ConsumeDailyDebitLimit) Validate(dogma.CommandValidationScope) error {
 	if !validation.IsValidDate(m.Date) {
 		return errors.New("ConsumeDailyDebitLimit must have a valid date")
 	}
+	if m.DailyLimit < 1 {
+		return errors.New("ConsumeDailyDebitLimit must have a positive daily limit")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ChangeDailyDebitLimit) Validate(dogma.CommandValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("ChangeDailyDebitLimit must not have an empty account ID")
+	}
+	if m.DailyDebitLimit < 1 {
+		return errors.New("ChangeDailyDebitLimit must have a positive daily debit limit")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *RestoreDailyDebitLimit) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("RestoreDailyDebitLimit must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("RestoreDailyDebitLimit must not have an empty account ID")
+	}
+	if err := m.DebitType.Validate(); err != nil {
+		return fmt.Errorf("RestoreDailyDebitLimit must have a valid transaction type: %w", err)
+	}
+	if !m.DebitType.IsDebit() {
+		return errors.New("RestoreDailyDebitLimit must have a debit transaction type")
+	}
+	if m.Amount < 1 {
+		return errors.New("RestoreDailyDebitLimit must have a positive amount")
+	}
+	if !validation.IsValidDate(m.Date) {
+		return errors.New("RestoreDailyDebitLimit must have a valid date")
+	}
 
 	return nil
 }
@@ -71,3 +152,27 @@ func (m *ConsumeDailyDebitLimit) MarshalBinary() ([]byte, error) {
 func (m *ConsumeDailyDebitLimit) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ChangeDailyDebitLimit) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ChangeDailyDebitLimit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *RestoreDailyDebitLimit) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *RestoreDailyDebitLimit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
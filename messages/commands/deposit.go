@@ -12,6 +12,7 @@ import (
 func init() {
 	dogma.RegisterCommand[*Deposit]("0cfac865-a1d9-4fd2-b085-f8fce0053795")
 	dogma.RegisterCommand[*ApproveDeposit]("6cd5ee09-b59d-45ef-a8ed-91cb1bb6940a")
+	dogma.RegisterCommand[*DeclineDeposit]("663cfe63-1018-41e8-b1a3-477954f841bd")
 }
 
 // Deposit is a command requesting that funds be deposited into a bank account.
@@ -28,6 +29,14 @@ type ApproveDeposit struct {
 	Amount        int64
 }
 
+// DeclineDeposit is a command that rejects an account deposit.
+type DeclineDeposit struct {
+	TransactionID string
+	AccountID     string
+	Amount        int64
+	Reason        messages.DebitFailureReason
+}
+
 // MessageDescription returns a human-readable description of the message.
 func (m *Deposit) MessageDescription() string {
 	return fmt.Sprintf(
@@ -48,6 +57,17 @@ func (m *ApproveDeposit) MessageDescription() string {
 	)
 }
 
+// MessageDescription returns a human-readable description of the message.
+func (m *DeclineDeposit) MessageDescription() string {
+	return fmt.Sprintf(
+		"deposit %s: declining deposit of %s into account %s: %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+		m.Reason,
+	)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *Deposit) Validate(dogma.CommandValidationScope) error {
 	if m.TransactionID == "" {
@@ -78,6 +98,24 @@ func (m *ApproveDeposit) Validate(dogma.CommandValidationScope) error {
 	return nil
 }
 
+// Validate returns a non-nil error if the message is invalid.
+func (m *DeclineDeposit) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("DeclineDeposit must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("DeclineDeposit must not have an empty account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("DeclineDeposit must have a positive amount")
+	}
+	if err := m.Reason.Validate(); err != nil {
+		return fmt.Errorf("DeclineDeposit must have a valid reason: %w", err)
+	}
+
+	return nil
+}
+
 // MarshalBinary returns a binary representation of the message.
 // For simplicity in this example we use JSON.
 func (m *Deposit) MarshalBinary() ([]byte, error) {
@@ -101,3 +139,15 @@ func (m *ApproveDeposit) MarshalBinary() ([]byte, error) {
 func (m *ApproveDeposit) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *DeclineDeposit) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *DeclineDeposit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
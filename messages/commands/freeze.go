@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterCommand[*FreezeAccount]("8f80c08e-c242-4bfe-a7fe-9c63bd75b22f")
+	dogma.RegisterCommand[*UnfreezeAccount]("1efddf5f-35ba-4213-8213-6ccc9d4a8fab")
+}
+
+// FreezeAccount is a command requesting that an account be frozen, such that no
+// further debits may be applied to it.
+type FreezeAccount struct {
+	AccountID  string
+	FreezeType messages.FreezeType
+	Reason     string
+	Operator   string
+}
+
+// UnfreezeAccount is a command requesting that a frozen account be unfrozen.
+type UnfreezeAccount struct {
+	AccountID string
+	Reason    string
+	Operator  string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *FreezeAccount) MessageDescription() string {
+	return fmt.Sprintf(
+		"freezing account %s (%s freeze by %s): %s",
+		m.AccountID,
+		m.FreezeType,
+		m.Operator,
+		m.Reason,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *UnfreezeAccount) MessageDescription() string {
+	return fmt.Sprintf(
+		"unfreezing account %s (by %s): %s",
+		m.AccountID,
+		m.Operator,
+		m.Reason,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *FreezeAccount) Validate(dogma.CommandValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("FreezeAccount must not have an empty account ID")
+	}
+	if err := m.FreezeType.Validate(); err != nil {
+		return fmt.Errorf("FreezeAccount must have a valid freeze type: %w", err)
+	}
+	if m.Reason == "" {
+		return errors.New("FreezeAccount must not have an empty reason")
+	}
+	if m.Operator == "" {
+		return errors.New("FreezeAccount must not have an empty operator")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *UnfreezeAccount) Validate(dogma.CommandValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("UnfreezeAccount must not have an empty account ID")
+	}
+	if m.Reason == "" {
+		return errors.New("UnfreezeAccount must not have an empty reason")
+	}
+	if m.Operator == "" {
+		return errors.New("UnfreezeAccount must not have an empty operator")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *FreezeAccount) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *FreezeAccount) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *UnfreezeAccount) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *UnfreezeAccount) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
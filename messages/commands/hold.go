@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterCommand[*AuthorizeDebit]("aac5178f-146c-4d46-b8a0-a67be02ab862")
+	dogma.RegisterCommand[*CaptureHold]("9f983916-97b3-40ed-96cf-605088fc0d10")
+	dogma.RegisterCommand[*ReleaseHold]("7fa9545d-fd9a-48f9-99d9-04e41121c685")
+	dogma.RegisterCommand[*ExpireHold]("bd12428b-3023-4fa1-95b7-426bb89da01f")
+}
+
+// AuthorizeDebit is a command requesting that funds be reserved in an account
+// for a card payment that will be captured later.
+//
+// The reserved funds are not available for other debits until the hold is
+// captured or released. A hold that is neither captured nor released expires
+// after a fixed period.
+type AuthorizeDebit struct {
+	HoldID    string
+	AccountID string
+	Merchant  string
+	Amount    int64
+
+	// ScheduledTime is the time at which the card payment was made. It
+	// determines the month and day against which the payment counts towards
+	// the account's withdrawal and daily debit limits.
+	ScheduledTime time.Time
+}
+
+// CaptureHold is a command requesting that the funds reserved by a hold be
+// debited from the account.
+type CaptureHold struct {
+	HoldID    string
+	AccountID string
+}
+
+// ReleaseHold is a command requesting that the funds reserved by a hold be made
+// available again without being debited.
+type ReleaseHold struct {
+	HoldID    string
+	AccountID string
+}
+
+// ExpireHold is a command requesting that a hold that has not been captured in
+// time be released.
+type ExpireHold struct {
+	HoldID    string
+	AccountID string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *AuthorizeDebit) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s: authorizing debit of %s from account %s for %s",
+		m.HoldID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+		m.Merchant,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *CaptureHold) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s: capturing hold on account %s",
+		m.HoldID,
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ReleaseHold) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s: releasing hold on account %s",
+		m.HoldID,
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ExpireHold) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s: expiring hold on account %s",
+		m.HoldID,
+		m.AccountID,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *AuthorizeDebit) Validate(dogma.CommandValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("AuthorizeDebit must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("AuthorizeDebit must not have an empty account ID")
+	}
+	if m.Merchant == "" {
+		return errors.New("AuthorizeDebit must not have an empty merchant")
+	}
+	if m.Amount < 1 {
+		return errors.New("AuthorizeDebit must have a positive amount")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *CaptureHold) Validate(dogma.CommandValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("CaptureHold must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("CaptureHold must not have an empty account ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ReleaseHold) Validate(dogma.CommandValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("ReleaseHold must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("ReleaseHold must not have an empty account ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ExpireHold) Validate(dogma.CommandValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("ExpireHold must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("ExpireHold must not have an empty account ID")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AuthorizeDebit) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AuthorizeDebit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *CaptureHold) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *CaptureHold) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ReleaseHold) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ReleaseHold) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ExpireHold) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ExpireHold) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/internal/validation"
+)
+
+func init() {
+	dogma.RegisterCommand[*SetInterestRate]("419fea15-1614-4d85-9ff0-96d1bf18e488")
+	dogma.RegisterCommand[*AccrueInterest]("607944d0-fa20-4fcd-9a89-cfb2addb7748")
+}
+
+// SetInterestRate is a command requesting that the annual interest rate paid on
+// an account be changed. The rate is expressed in basis points.
+type SetInterestRate struct {
+	AccountID    string
+	InterestRate int64
+}
+
+// AccrueInterest is a command requesting that a day's interest be accrued on
+// the end-of-day balance of an account.
+type AccrueInterest struct {
+	AccountID string
+	AccrualID string
+	Date      string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *SetInterestRate) MessageDescription() string {
+	return fmt.Sprintf(
+		"setting interest rate of account %s to %s",
+		m.AccountID,
+		messages.FormatInterestRate(m.InterestRate),
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *AccrueInterest) MessageDescription() string {
+	return fmt.Sprintf(
+		"accruing interest on account %s for %s",
+		m.AccountID,
+		m.Date,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *SetInterestRate) Validate(dogma.CommandValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("SetInterestRate must not have an empty account ID")
+	}
+	if m.InterestRate < 0 {
+		return errors.New("SetInterestRate must not have a negative interest rate")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *AccrueInterest) Validate(dogma.CommandValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("AccrueInterest must not have an empty account ID")
+	}
+	if m.AccrualID == "" {
+		return errors.New("AccrueInterest must not have an empty accrual ID")
+	}
+	if !validation.IsValidDate(m.Date) {
+		return errors.New("AccrueInterest must have a valid date")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *SetInterestRate) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *SetInterestRate) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AccrueInterest) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AccrueInterest) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterCommand[*SetOverdraftLimit]("c68589b3-6eba-420f-8adc-d25569177eb8")
+}
+
+// SetOverdraftLimit is a command requesting that the arranged overdraft limit
+// of an account be changed.
+type SetOverdraftLimit struct {
+	AccountID      string
+	OverdraftLimit int64
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *SetOverdraftLimit) MessageDescription() string {
+	return fmt.Sprintf(
+		"setting overdraft limit of account %s to %s",
+		m.AccountID,
+		messages.FormatAmount(m.OverdraftLimit),
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *SetOverdraftLimit) Validate(dogma.CommandValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("SetOverdraftLimit must not have an empty account ID")
+	}
+	if m.OverdraftLimit < 0 {
+		return errors.New("SetOverdraftLimit must not have a negative overdraft limit")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *SetOverdraftLimit) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *SetOverdraftLimit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterCommand[*AddPayee]("e479fe44-a008-4d63-9a46-8bfb714172b6")
+	dogma.RegisterCommand[*RemovePayee]("6f37c270-ba01-4848-a2f2-e4ac7bdb1707")
+	dogma.RegisterCommand[*ConsumePayeeCoolingOffLimit]("b66af169-0f98-402f-9abe-f0f1acaf5287")
+}
+
+// AddPayee is a command requesting that an account be saved as one of a
+// customer's payees, so that the customer may transfer funds to it.
+type AddPayee struct {
+	CustomerID string
+	PayeeID    string
+
+	// Name is the name by which the customer refers to the payee.
+	Name string
+
+	// AccountID is the ID of the payee's account. For a third-party payee it
+	// is the ID returned by ThirdPartyPayee.AccountID().
+	AccountID string
+
+	// ThirdPartyPayee, if non-nil, identifies the payee's account at a
+	// third-party bank. It is nil for accounts within the bank.
+	ThirdPartyPayee *messages.ThirdPartyPayee
+}
+
+// RemovePayee is a command requesting that a payee be removed from a
+// customer's payees.
+type RemovePayee struct {
+	CustomerID string
+	PayeeID    string
+}
+
+// ConsumePayeeCoolingOffLimit is a command requesting that a transfer to a
+// payee be counted towards the limit on transfers to the payee during its
+// cooling-off period.
+//
+// TransactionType is the type of the transaction that moves the money to the
+// payee, either a transfer or the sweep of a closed account's balance.
+type ConsumePayeeCoolingOffLimit struct {
+	TransactionID   string
+	CustomerID      string
+	PayeeID         string
+	TransactionType messages.TransactionType
+	Amount          int64
+	ScheduledTime   time.Time
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *AddPayee) MessageDescription() string {
+	if m.ThirdPartyPayee != nil {
+		return fmt.Sprintf(
+			"customer %s: adding payee %s, %s",
+			m.CustomerID,
+			m.PayeeID,
+			m.ThirdPartyPayee,
+		)
+	}
+
+	return fmt.Sprintf(
+		"customer %s: adding payee %s, %s (account %s)",
+		m.CustomerID,
+		m.PayeeID,
+		m.Name,
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *RemovePayee) MessageDescription() string {
+	return fmt.Sprintf(
+		"customer %s: removing payee %s",
+		m.CustomerID,
+		m.PayeeID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ConsumePayeeCoolingOffLimit) MessageDescription() string {
+	return fmt.Sprintf(
+		"transfer %s: consuming %s from cooling-off limit of payee %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.PayeeID,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *AddPayee) Validate(dogma.CommandValidationScope) error {
+	if m.CustomerID == "" {
+		return errors.New("AddPayee must not have an empty customer ID")
+	}
+	if m.PayeeID == "" {
+		return errors.New("AddPayee must not have an empty payee ID")
+	}
+	if m.Name == "" {
+		return errors.New("AddPayee must not have an empty name")
+	}
+	if m.AccountID == "" {
+		return errors.New("AddPayee must not have an empty account ID")
+	}
+	if m.ThirdPartyPayee != nil {
+		if err := m.ThirdPartyPayee.Validate(); err != nil {
+			return fmt.Errorf("AddPayee must have a valid third-party payee: %w", err)
+		}
+		if m.AccountID != m.ThirdPartyPayee.AccountID() {
+			return errors.New("AddPayee account ID must be the third-party payee's account ID")
+		}
+	}
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *RemovePayee) Validate(dogma.CommandValidationScope) error {
+	if m.CustomerID == "" {
+		return errors.New("RemovePayee must not have an empty customer ID")
+	}
+	if m.PayeeID == "" {
+		return errors.New("RemovePayee must not have an empty payee ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ConsumePayeeCoolingOffLimit) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("ConsumePayeeCoolingOffLimit must not have an empty transaction ID")
+	}
+	if m.CustomerID == "" {
+		return errors.New("ConsumePayeeCoolingOffLimit must not have an empty customer ID")
+	}
+	if err := m.TransactionType.Validate(); err != nil {
+		return fmt.Errorf("ConsumePayeeCoolingOffLimit must have a valid transaction type: %w", err)
+	}
+	if m.PayeeID == "" {
+		return errors.New("ConsumePayeeCoolingOffLimit must not have an empty payee ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("ConsumePayeeCoolingOffLimit must have a positive amount")
+	}
+	if m.ScheduledTime.IsZero() {
+		return errors.New("ConsumePayeeCoolingOffLimit must have a non-zero scheduled time")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AddPayee) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AddPayee) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *RemovePayee) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *RemovePayee) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ConsumePayeeCoolingOffLimit) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ConsumePayeeCoolingOffLimit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
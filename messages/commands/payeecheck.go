@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterCommand[*CheckPayee]("dba52327-c13a-45e6-8d75-6bf049669ac8")
+}
+
+// CheckPayee is a command requesting that the account name of a third-party
+// payee be confirmed with the third-party bank before money is sent to it.
+type CheckPayee struct {
+	CheckID string
+	Payee   messages.ThirdPartyPayee
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *CheckPayee) MessageDescription() string {
+	return fmt.Sprintf(
+		"payee check %s: checking the account name of %s",
+		m.CheckID,
+		m.Payee,
+	)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *CheckPayee) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *CheckPayee) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *CheckPayee) Validate(dogma.CommandValidationScope) error {
+	if m.CheckID == "" {
+		return errors.New("CheckPayee must not have an empty check ID")
+	}
+	if err := m.Payee.Validate(); err != nil {
+		return fmt.Errorf("CheckPayee must have a valid payee: %w", err)
+	}
+
+	return nil
+}
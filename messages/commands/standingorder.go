@@ -0,0 +1,297 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/internal/validation"
+)
+
+func init() {
+	dogma.RegisterCommand[*CreateStandingOrder]("0d1f2696-fc9d-49aa-aa2e-b78cb44e545f")
+	dogma.RegisterCommand[*PauseStandingOrder]("a8db2e58-0f00-459d-8cad-740f4615dc6c")
+	dogma.RegisterCommand[*ResumeStandingOrder]("6de03bc7-f4cd-4231-97b1-26d5aaab68a6")
+	dogma.RegisterCommand[*CancelStandingOrder]("af220c26-2618-4d13-a5dd-925fc118e31f")
+	dogma.RegisterCommand[*StartStandingOrderPayment]("e659b822-df61-4e43-a519-b954604fcabe")
+}
+
+// CreateStandingOrder is a command requesting that a recurring transfer be made
+// from one bank account to another.
+//
+// The end date and number of payments are both optional. If neither is given
+// the standing order continues until it is cancelled. If both are given it ends
+// when either is reached.
+type CreateStandingOrder struct {
+	StandingOrderID  string
+	FromAccountID    string
+	ToAccountID      string
+	ToThirdPartyBank bool
+	Amount           int64
+	Frequency        messages.Frequency
+	StartDate        string
+	EndDate          string
+	NumberOfPayments int
+
+	// ToPayee identifies the third-party account by its BSB, account number
+	// and name, and ToAccountID is the payee's account ID. It is required if
+	// ToThirdPartyBank is true, and nil for standing orders within the bank.
+	ToPayee *messages.ThirdPartyPayee
+
+	// PayeeID, if non-empty, is the ID of the saved payee to which the
+	// payments are made, and CustomerID is the ID of the customer whose payee
+	// it is. Each payment counts towards the limit on transfers to the payee
+	// during its cooling-off period.
+	CustomerID string
+	PayeeID    string
+}
+
+// PauseStandingOrder is a command requesting that payments falling due on a
+// standing order be skipped until it is resumed. It is ignored unless the
+// standing order makes payments from FromAccountID.
+type PauseStandingOrder struct {
+	StandingOrderID string
+	FromAccountID   string
+}
+
+// ResumeStandingOrder is a command requesting that a paused standing order
+// resume making payments. It is ignored unless the standing order makes
+// payments from FromAccountID.
+type ResumeStandingOrder struct {
+	StandingOrderID string
+	FromAccountID   string
+}
+
+// CancelStandingOrder is a command requesting that a standing order make no
+// further payments. It is ignored unless the standing order makes payments
+// from FromAccountID.
+type CancelStandingOrder struct {
+	StandingOrderID string
+	FromAccountID   string
+}
+
+// StartStandingOrderPayment is a command requesting that a payment that has
+// fallen due on a standing order be made.
+type StartStandingOrderPayment struct {
+	StandingOrderID string
+	PaymentNumber   int
+	Date            string
+	NextPaymentDate string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *CreateStandingOrder) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: creating %s standing order of %s from account %s to account %s, starting %s",
+		m.StandingOrderID,
+		m.Frequency,
+		messages.FormatAmount(m.Amount),
+		m.FromAccountID,
+		m.ToAccountID,
+		m.StartDate,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *PauseStandingOrder) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: pausing",
+		m.StandingOrderID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ResumeStandingOrder) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: resuming",
+		m.StandingOrderID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *CancelStandingOrder) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: cancelling",
+		m.StandingOrderID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StartStandingOrderPayment) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: starting payment #%d due on %s",
+		m.StandingOrderID,
+		m.PaymentNumber,
+		m.Date,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *CreateStandingOrder) Validate(dogma.CommandValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("CreateStandingOrder must not have an empty standing order ID")
+	}
+	if m.FromAccountID == "" {
+		return errors.New("CreateStandingOrder must not have an empty 'from' account ID")
+	}
+	if m.ToAccountID == "" {
+		return errors.New("CreateStandingOrder must not have an empty 'to' account ID")
+	}
+	if m.FromAccountID == m.ToAccountID {
+		return errors.New("CreateStandingOrder from account ID and to account ID must be different")
+	}
+	if m.Amount < 1 {
+		return errors.New("CreateStandingOrder must have a positive amount")
+	}
+	if m.ToThirdPartyBank {
+		if m.ToPayee == nil {
+			return errors.New("CreateStandingOrder must have a payee when it is to a third-party bank")
+		}
+		if err := m.ToPayee.Validate(); err != nil {
+			return fmt.Errorf("CreateStandingOrder must have a valid payee: %w", err)
+		}
+		if m.ToAccountID != m.ToPayee.AccountID() {
+			return errors.New("CreateStandingOrder 'to' account ID must be the payee's account ID")
+		}
+	} else if m.ToPayee != nil {
+		return errors.New("CreateStandingOrder must not have a payee unless it is to a third-party bank")
+	}
+	if (m.CustomerID == "") != (m.PayeeID == "") {
+		return errors.New("CreateStandingOrder must have both a customer ID and payee ID, or neither")
+	}
+	if err := m.Frequency.Validate(); err != nil {
+		return fmt.Errorf("CreateStandingOrder must have a valid frequency: %w", err)
+	}
+	if !validation.IsValidDate(m.StartDate) {
+		return errors.New("CreateStandingOrder must have a valid start date")
+	}
+	if m.EndDate != "" {
+		if !validation.IsValidDate(m.EndDate) {
+			return errors.New("CreateStandingOrder must have a valid end date")
+		}
+		if m.EndDate < m.StartDate {
+			return errors.New("CreateStandingOrder must not have an end date before its start date")
+		}
+	}
+	if m.NumberOfPayments < 0 {
+		return errors.New("CreateStandingOrder must not have a negative number of payments")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *PauseStandingOrder) Validate(dogma.CommandValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("PauseStandingOrder must not have an empty standing order ID")
+	}
+	if m.FromAccountID == "" {
+		return errors.New("PauseStandingOrder must not have an empty 'from' account ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ResumeStandingOrder) Validate(dogma.CommandValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("ResumeStandingOrder must not have an empty standing order ID")
+	}
+	if m.FromAccountID == "" {
+		return errors.New("ResumeStandingOrder must not have an empty 'from' account ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *CancelStandingOrder) Validate(dogma.CommandValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("CancelStandingOrder must not have an empty standing order ID")
+	}
+	if m.FromAccountID == "" {
+		return errors.New("CancelStandingOrder must not have an empty 'from' account ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StartStandingOrderPayment) Validate(dogma.CommandValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("StartStandingOrderPayment must not have an empty standing order ID")
+	}
+	if m.PaymentNumber < 1 {
+		return errors.New("StartStandingOrderPayment must have a positive payment number")
+	}
+	if !validation.IsValidDate(m.Date) {
+		return errors.New("StartStandingOrderPayment must have a valid date")
+	}
+	if m.NextPaymentDate != "" && !validation.IsValidDate(m.NextPaymentDate) {
+		return errors.New("StartStandingOrderPayment must have a valid next payment date")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *CreateStandingOrder) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *CreateStandingOrder) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *PauseStandingOrder) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *PauseStandingOrder) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ResumeStandingOrder) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ResumeStandingOrder) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *CancelStandingOrder) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *CancelStandingOrder) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StartStandingOrderPayment) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StartStandingOrderPayment) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
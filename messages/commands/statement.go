@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/internal/validation"
+)
+
+func init() {
+	dogma.RegisterCommand[*IssueStatement]("c56f3087-a779-4835-96bf-28c5d6601a5e")
+}
+
+// IssueStatement is a command requesting that a statement be issued for an
+// account, summarizing the transactions applied to it since its previous
+// statement.
+//
+// TotalCredits and TotalDebits are the totals of the credits and debits
+// recorded against the account within the period, in cents.
+type IssueStatement struct {
+	StatementID  string
+	AccountID    string
+	PeriodStart  string
+	PeriodEnd    string
+	TotalCredits int64
+	TotalDebits  int64
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *IssueStatement) MessageDescription() string {
+	return fmt.Sprintf(
+		"issuing statement %s for account %s, from %s to %s",
+		m.StatementID,
+		m.AccountID,
+		m.PeriodStart,
+		m.PeriodEnd,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *IssueStatement) Validate(dogma.CommandValidationScope) error {
+	if m.StatementID == "" {
+		return errors.New("IssueStatement must not have an empty statement ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("IssueStatement must not have an empty account ID")
+	}
+	if !validation.IsValidDate(m.PeriodStart) {
+		return errors.New("IssueStatement must have a valid period start date")
+	}
+	if !validation.IsValidDate(m.PeriodEnd) {
+		return errors.New("IssueStatement must have a valid period end date")
+	}
+	if m.PeriodEnd < m.PeriodStart {
+		return errors.New("IssueStatement must not end before it starts")
+	}
+	if m.TotalCredits < 0 {
+		return errors.New("IssueStatement must not have negative total credits")
+	}
+	if m.TotalDebits < 0 {
+		return errors.New("IssueStatement must not have negative total debits")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *IssueStatement) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *IssueStatement) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -11,26 +11,70 @@ import (
 
 func init() {
 	dogma.RegisterCommand[*CreditThirdPartyAccount]("c4a2e6f1-8b3d-4e7a-9f5c-2d1b0e8a3c6f")
+	dogma.RegisterCommand[*AbandonThirdPartyCredit]("8f642e93-4145-498f-b6e9-b68c90b008a4")
 }
 
 // CreditThirdPartyAccount is a command to credit an account held at a
 // third-party bank.
 type CreditThirdPartyAccount struct {
-	TransactionID string
-	AccountID     string
-	Amount        int64
+	TransactionID   string
+	AccountID       string
+	TransactionType messages.TransactionType
+	Amount          int64
+
+	// Payee identifies the account to credit by its BSB, account number and
+	// name. AccountID is the payee's account ID.
+	Payee *messages.ThirdPartyPayee
+
+	// Retry is the number of earlier attempts to credit the account that
+	// failed with a transient error. It is zero for the first attempt.
+	Retry int
+}
+
+// AbandonThirdPartyCredit is a command to stop retrying a credit to an account
+// held at a third-party bank, and treat it as having failed.
+type AbandonThirdPartyCredit struct {
+	TransactionID   string
+	AccountID       string
+	TransactionType messages.TransactionType
+	Amount          int64
+	Attempts        int
 }
 
 // MessageDescription returns a human-readable description of the message.
 func (m *CreditThirdPartyAccount) MessageDescription() string {
+	if m.Retry > 0 {
+		return fmt.Sprintf(
+			"%s %s: crediting %s to third-party account %s (retry %d)",
+			m.TransactionType,
+			m.TransactionID,
+			messages.FormatAmount(m.Amount),
+			m.AccountID,
+			m.Retry,
+		)
+	}
+
 	return fmt.Sprintf(
-		"transfer %s: crediting %s to third-party account %s",
+		"%s %s: crediting %s to third-party account %s",
+		m.TransactionType,
 		m.TransactionID,
 		messages.FormatAmount(m.Amount),
 		m.AccountID,
 	)
 }
 
+// MessageDescription returns a human-readable description of the message.
+func (m *AbandonThirdPartyCredit) MessageDescription() string {
+	return fmt.Sprintf(
+		"%s %s: abandoning credit of %s to third-party account %s after %d attempts",
+		m.TransactionType,
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+		m.Attempts,
+	)
+}
+
 // MarshalBinary returns a binary representation of the message.
 // For simplicity in this example we use JSON.
 func (m *CreditThirdPartyAccount) MarshalBinary() ([]byte, error) {
@@ -43,6 +87,18 @@ func (m *CreditThirdPartyAccount) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
 
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AbandonThirdPartyCredit) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AbandonThirdPartyCredit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *CreditThirdPartyAccount) Validate(dogma.CommandValidationScope) error {
 	if m.TransactionID == "" {
@@ -51,9 +107,45 @@ func (m *CreditThirdPartyAccount) Validate(dogma.CommandValidationScope) error {
 	if m.AccountID == "" {
 		return errors.New("CreditThirdPartyAccount must not have an empty account ID")
 	}
+	if m.Payee == nil {
+		return errors.New("CreditThirdPartyAccount must have a payee")
+	}
+	if err := m.Payee.Validate(); err != nil {
+		return fmt.Errorf("CreditThirdPartyAccount must have a valid payee: %w", err)
+	}
+	if m.AccountID != m.Payee.AccountID() {
+		return errors.New("CreditThirdPartyAccount account ID must be the payee's account ID")
+	}
+	if err := m.TransactionType.Validate(); err != nil {
+		return fmt.Errorf("CreditThirdPartyAccount must have a valid transaction type: %w", err)
+	}
 	if m.Amount < 1 {
 		return errors.New("CreditThirdPartyAccount must have a positive amount")
 	}
+	if m.Retry < 0 {
+		return errors.New("CreditThirdPartyAccount must not have a negative retry number")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *AbandonThirdPartyCredit) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("AbandonThirdPartyCredit must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("AbandonThirdPartyCredit must not have an empty account ID")
+	}
+	if err := m.TransactionType.Validate(); err != nil {
+		return fmt.Errorf("AbandonThirdPartyCredit must have a valid transaction type: %w", err)
+	}
+	if m.Amount < 1 {
+		return errors.New("AbandonThirdPartyCredit must have a positive amount")
+	}
+	if m.Attempts < 1 {
+		return errors.New("AbandonThirdPartyCredit must have a positive number of attempts")
+	}
 
 	return nil
 }
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterCommand[*ReceiveThirdPartyPayment]("b6ae5353-9d51-4728-96e8-8bd3b0313486")
+	dogma.RegisterCommand[*ReturnThirdPartyPayment]("f3ac255d-54b2-40b4-8219-831cd04abfe7")
+}
+
+// ReceiveThirdPartyPayment is a command to accept notification from a
+// third-party bank of a payment into an account held at this bank.
+type ReceiveThirdPartyPayment struct {
+	TransactionID           string
+	ThirdPartyTransactionID string // the third-party bank's own ID for the payment
+	AccountID               string
+	SenderAccountID         string // account at the third-party bank that sent the payment
+	Amount                  int64
+}
+
+// ReturnThirdPartyPayment is a command to return a payment received from a
+// third-party bank to its sender.
+type ReturnThirdPartyPayment struct {
+	TransactionID           string
+	ThirdPartyTransactionID string
+	AccountID               string
+	SenderAccountID         string
+	Amount                  int64
+	Reason                  messages.DebitFailureReason
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ReceiveThirdPartyPayment) MessageDescription() string {
+	return fmt.Sprintf(
+		"third-party payment %s: receiving %s from third-party account %s into account %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.SenderAccountID,
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ReturnThirdPartyPayment) MessageDescription() string {
+	return fmt.Sprintf(
+		"third-party payment %s: returning %s to third-party account %s: %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.SenderAccountID,
+		m.Reason,
+	)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ReceiveThirdPartyPayment) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ReceiveThirdPartyPayment) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ReturnThirdPartyPayment) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ReturnThirdPartyPayment) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ReceiveThirdPartyPayment) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("ReceiveThirdPartyPayment must not have an empty transaction ID")
+	}
+	if m.ThirdPartyTransactionID == "" {
+		return errors.New("ReceiveThirdPartyPayment must not have an empty third-party transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("ReceiveThirdPartyPayment must not have an empty account ID")
+	}
+	if m.SenderAccountID == "" {
+		return errors.New("ReceiveThirdPartyPayment must not have an empty sender account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("ReceiveThirdPartyPayment must have a positive amount")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ReturnThirdPartyPayment) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("ReturnThirdPartyPayment must not have an empty transaction ID")
+	}
+	if m.ThirdPartyTransactionID == "" {
+		return errors.New("ReturnThirdPartyPayment must not have an empty third-party transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("ReturnThirdPartyPayment must not have an empty account ID")
+	}
+	if m.SenderAccountID == "" {
+		return errors.New("ReturnThirdPartyPayment must not have an empty sender account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("ReturnThirdPartyPayment must have a positive amount")
+	}
+	if err := m.Reason.Validate(); err != nil {
+		return fmt.Errorf("ReturnThirdPartyPayment must have a valid reason: %w", err)
+	}
+
+	return nil
+}
@@ -15,6 +15,8 @@ func init() {
 	dogma.RegisterCommand[*ApproveTransfer]("0d22aaa5-4449-459a-b9b1-c5fb0ce4a990")
 	dogma.RegisterCommand[*DeclineTransfer]("d7d069a2-41fc-415e-91dd-7db3affa9f6d")
 	dogma.RegisterCommand[*MarkTransferAsFailed]("b3e5f6a2-7c1d-4e8b-a9f0-3d2c1e4b5f6a")
+	dogma.RegisterCommand[*ProceedWithTransfer]("9a7251df-49be-4528-99c5-c4d5b75c62de")
+	dogma.RegisterCommand[*CancelTransfer]("d32c5b0a-53cf-4988-a816-30ec67fd26a2")
 }
 
 // Transfer is a command requesting that funds be transferred from one bank
@@ -26,6 +28,18 @@ type Transfer struct {
 	ToThirdPartyBank bool
 	Amount           int64
 	ScheduledTime    time.Time
+
+	// ToPayee identifies the third-party account by its BSB, account number
+	// and name, and ToAccountID is the payee's account ID. It is required if
+	// ToThirdPartyBank is true, and nil for transfers within the bank.
+	ToPayee *messages.ThirdPartyPayee
+
+	// PayeeID, if non-empty, is the ID of the saved payee to which the
+	// transfer is made, and CustomerID is the ID of the customer whose payee
+	// it is. The transfer counts towards the limit on transfers to the payee
+	// during its cooling-off period.
+	CustomerID string
+	PayeeID    string
 }
 
 // ApproveTransfer is a command that approves an account transfer.
@@ -54,6 +68,20 @@ type MarkTransferAsFailed struct {
 	Amount        int64
 }
 
+// ProceedWithTransfer is a command requesting that a transfer proceed now that
+// its scheduled time has been reached.
+type ProceedWithTransfer struct {
+	TransactionID string
+	ScheduledTime time.Time
+}
+
+// CancelTransfer is a command requesting that a scheduled transfer be cancelled
+// before it proceeds. It is ignored unless the transfer is from FromAccountID.
+type CancelTransfer struct {
+	TransactionID string
+	FromAccountID string
+}
+
 // MessageDescription returns a human-readable description of the message.
 func (m *Transfer) MessageDescription() string {
 	return fmt.Sprintf(
@@ -105,6 +133,22 @@ func (m *Transfer) Validate(dogma.CommandValidationScope) error {
 	if m.Amount < 1 {
 		return errors.New("Transfer must have a positive amount")
 	}
+	if m.ToThirdPartyBank {
+		if m.ToPayee == nil {
+			return errors.New("Transfer must have a payee when it is to a third-party bank")
+		}
+		if err := m.ToPayee.Validate(); err != nil {
+			return fmt.Errorf("Transfer must have a valid payee: %w", err)
+		}
+		if m.ToAccountID != m.ToPayee.AccountID() {
+			return errors.New("Transfer 'to' account ID must be the payee's account ID")
+		}
+	} else if m.ToPayee != nil {
+		return errors.New("Transfer must not have a payee unless it is to a third-party bank")
+	}
+	if (m.CustomerID == "") != (m.PayeeID == "") {
+		return errors.New("Transfer must have both a customer ID and payee ID, or neither")
+	}
 
 	return nil
 }
@@ -195,6 +239,22 @@ func (m *MarkTransferAsFailed) MessageDescription() string {
 	)
 }
 
+// MessageDescription returns a human-readable description of the message.
+func (m *ProceedWithTransfer) MessageDescription() string {
+	return fmt.Sprintf(
+		"transfer %s: proceeding with transfer",
+		m.TransactionID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *CancelTransfer) MessageDescription() string {
+	return fmt.Sprintf(
+		"transfer %s: cancelling transfer",
+		m.TransactionID,
+	)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *MarkTransferAsFailed) Validate(dogma.CommandValidationScope) error {
 	if m.TransactionID == "" {
@@ -213,6 +273,27 @@ func (m *MarkTransferAsFailed) Validate(dogma.CommandValidationScope) error {
 	return nil
 }
 
+// Validate returns a non-nil error if the message is invalid.
+func (m *ProceedWithTransfer) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("ProceedWithTransfer must not have an empty transaction ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *CancelTransfer) Validate(dogma.CommandValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("CancelTransfer must not have an empty transaction ID")
+	}
+	if m.FromAccountID == "" {
+		return errors.New("CancelTransfer must not have an empty 'from' account ID")
+	}
+
+	return nil
+}
+
 // MarshalBinary returns a binary representation of the message.
 // For simplicity in this example we use JSON.
 func (m *MarkTransferAsFailed) MarshalBinary() ([]byte, error) {
@@ -224,3 +305,27 @@ func (m *MarkTransferAsFailed) MarshalBinary() ([]byte, error) {
 func (m *MarkTransferAsFailed) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ProceedWithTransfer) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ProceedWithTransfer) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *CancelTransfer) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *CancelTransfer) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
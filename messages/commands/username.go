@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+)
+
+func init() {
+	dogma.RegisterCommand[*ReserveUsername]("19388abf-c3df-4950-a85a-66d9ec1a791d")
+}
+
+// ReserveUsername is a command requesting that a username be reserved for a
+// customer, so that no other customer can log in with it.
+type ReserveUsername struct {
+	Username   string
+	CustomerID string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ReserveUsername) MessageDescription() string {
+	return fmt.Sprintf(
+		"reserving username %s for customer %s",
+		m.Username,
+		m.CustomerID,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ReserveUsername) Validate(dogma.CommandValidationScope) error {
+	if m.Username == "" {
+		return errors.New("ReserveUsername must not have an empty username")
+	}
+	if m.CustomerID == "" {
+		return errors.New("ReserveUsername must not have an empty customer ID")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ReserveUsername) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ReserveUsername) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
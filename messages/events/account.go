@@ -8,6 +8,7 @@ import (
 
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/internal/validation"
 )
 
 func init() {
@@ -15,13 +16,22 @@ func init() {
 	dogma.RegisterEvent[*AccountCredited]("f3091f46-7d36-4e5f-b0ab-fb96029e5d7a")
 	dogma.RegisterEvent[*AccountDebited]("76552351-0095-442e-85dd-68f8f7fae286")
 	dogma.RegisterEvent[*AccountDebitDeclined]("34b0c426-5467-44d8-a5db-7d09c789c930")
+	dogma.RegisterEvent[*AccountCreditDeclined]("dd0bf927-695a-402b-870d-5dd354ef9f98")
+	dogma.RegisterEvent[*AccountDebitSettled]("b04471dd-27e5-48c5-a80a-801f7067bbcc")
+	dogma.RegisterEvent[*AccountClosed]("c19379a3-6d4b-4f38-b4af-7883a1c96d48")
+	dogma.RegisterEvent[*AccountClosureDeclined]("3311677d-2687-4a6f-91bd-55e94bd3bafb")
+	dogma.RegisterEvent[*ClosedAccountSwept]("1a7c3797-eeb7-4d54-8e93-767ea63da4b6")
+	dogma.RegisterEvent[*AccountCreditQueued]("8f2e2da6-e83e-495a-9a51-5adf1fb976b0")
 }
 
 // AccountOpened is an event indicating that a new bank account has been opened.
 type AccountOpened struct {
-	CustomerID  string
-	AccountID   string
-	AccountName string
+	CustomerID      string
+	AccountID       string
+	AccountName     string
+	AccountType     messages.AccountType
+	MaturityDate    string
+	DailyDebitLimit int64
 }
 
 // AccountCredited is an event indicating that a bank account was credited.
@@ -39,6 +49,7 @@ type AccountDebited struct {
 	TransactionType messages.TransactionType
 	Amount          int64
 	ScheduledTime   time.Time
+	DailyDebitLimit int64
 }
 
 // AccountDebitDeclined is an event indicating that a bank account debit was
@@ -51,10 +62,79 @@ type AccountDebitDeclined struct {
 	Reason          messages.DebitFailureReason
 }
 
+// AccountCreditDeclined is an event indicating that a bank account credit was
+// declined.
+type AccountCreditDeclined struct {
+	TransactionID   string
+	AccountID       string
+	TransactionType messages.TransactionType
+	Amount          int64
+	Reason          messages.DebitFailureReason
+}
+
+// AccountDebitSettled is an event indicating that a prior debit of a bank
+// account has been settled and will not be refunded.
+type AccountDebitSettled struct {
+	TransactionID string
+	AccountID     string
+}
+
+// AccountClosed is an event indicating that a bank account has been closed. The
+// account's remaining balance is removed from the account and must be swept to
+// the nominated account.
+type AccountClosed struct {
+	TransactionID         string
+	CustomerID            string
+	AccountID             string
+	SweepToAccountID      string
+	SweepToThirdPartyBank bool
+	SweepToPayee          *messages.ThirdPartyPayee
+	SweepToPayeeID        string
+	Balance               int64
+
+	// InterestAccrualID is the ID of the interest accrual that is stopped by
+	// the closure, or an empty string if interest was not accruing.
+	InterestAccrualID string
+}
+
+// AccountClosureDeclined is an event indicating that a request to close a bank
+// account was declined.
+type AccountClosureDeclined struct {
+	TransactionID string
+	AccountID     string
+	Reason        messages.ClosureDeclineReason
+}
+
+// ClosedAccountSwept is an event indicating that the balance of a closed
+// account, which was returned to it after the sweep made when it was closed
+// failed, has been removed from the account and must be swept to the nominated
+// account.
+type ClosedAccountSwept struct {
+	TransactionID         string
+	CustomerID            string
+	AccountID             string
+	SweepToAccountID      string
+	SweepToThirdPartyBank bool
+	SweepToPayee          *messages.ThirdPartyPayee
+	SweepToPayeeID        string
+	Balance               int64
+}
+
+// AccountCreditQueued is an event that indicates a credit has been queued
+// because the account is frozen. The credit is applied when the account is
+// unfrozen.
+type AccountCreditQueued struct {
+	TransactionID   string
+	AccountID       string
+	TransactionType messages.TransactionType
+	Amount          int64
+}
+
 // MessageDescription returns a human-readable description of the message.
 func (m *AccountOpened) MessageDescription() string {
 	return fmt.Sprintf(
-		"opened account %s %s for customer %s",
+		"opened %s account %s %s for customer %s",
+		m.AccountType,
 		m.AccountID,
 		m.AccountName,
 		m.CustomerID,
@@ -95,6 +175,68 @@ func (m *AccountDebitDeclined) MessageDescription() string {
 	)
 }
 
+// MessageDescription returns a human-readable description of the message.
+func (m *AccountCreditDeclined) MessageDescription() string {
+	return fmt.Sprintf(
+		"%s %s: declined credit of %s to account %s: %s",
+		m.TransactionType,
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+		m.Reason,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *AccountDebitSettled) MessageDescription() string {
+	return fmt.Sprintf(
+		"transaction %s: settled debit of account %s",
+		m.TransactionID,
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *AccountClosed) MessageDescription() string {
+	return fmt.Sprintf(
+		"account closure %s: closed account %s, sweeping %s",
+		m.TransactionID,
+		m.AccountID,
+		messages.FormatAmount(m.Balance),
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *AccountClosureDeclined) MessageDescription() string {
+	return fmt.Sprintf(
+		"account closure %s: declined closure of account %s: %s",
+		m.TransactionID,
+		m.AccountID,
+		m.Reason,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ClosedAccountSwept) MessageDescription() string {
+	return fmt.Sprintf(
+		"account closure %s: sweeping %s returned to closed account %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Balance),
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *AccountCreditQueued) MessageDescription() string {
+	return fmt.Sprintf(
+		"%s %s: queued credit of %s to frozen account %s",
+		m.TransactionType,
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+	)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *AccountOpened) Validate(dogma.EventValidationScope) error {
 	if m.CustomerID == "" {
@@ -106,6 +248,19 @@ func (m *AccountOpened) Validate(dogma.EventValidationScope) error {
 	if m.AccountName == "" {
 		return errors.New("AccountOpened must not have an empty account name")
 	}
+	if err := m.AccountType.Validate(); err != nil {
+		return fmt.Errorf("AccountOpened must have a valid account type: %w", err)
+	}
+	if m.AccountType == messages.TermDeposit {
+		if !validation.IsValidDate(m.MaturityDate) {
+			return errors.New("AccountOpened must have a valid maturity date")
+		}
+	} else if m.MaturityDate != "" {
+		return errors.New("AccountOpened must not have a maturity date unless it is a term deposit")
+	}
+	if m.DailyDebitLimit < 1 {
+		return errors.New("AccountOpened must have a positive daily debit limit")
+	}
 
 	return nil
 }
@@ -142,6 +297,9 @@ func (m *AccountDebited) Validate(dogma.EventValidationScope) error {
 	if m.Amount < 1 {
 		return errors.New("AccountDebited must have a positive amount")
 	}
+	if m.DailyDebitLimit < 1 {
+		return errors.New("AccountDebited must have a positive daily debit limit")
+	}
 
 	return nil
 }
@@ -167,6 +325,124 @@ func (m *AccountDebitDeclined) Validate(dogma.EventValidationScope) error {
 	return nil
 }
 
+// Validate returns a non-nil error if the message is invalid.
+func (m *AccountCreditDeclined) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("AccountCreditDeclined must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("AccountCreditDeclined must not have an empty account ID")
+	}
+	if err := m.TransactionType.Validate(); err != nil {
+		return fmt.Errorf("AccountCreditDeclined must have a valid transaction type: %w", err)
+	}
+	if m.Amount < 1 {
+		return errors.New("AccountCreditDeclined must have a positive amount")
+	}
+	if err := m.Reason.Validate(); err != nil {
+		return fmt.Errorf("AccountCreditDeclined must have a valid reason: %w", err)
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *AccountDebitSettled) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("AccountDebitSettled must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("AccountDebitSettled must not have an empty account ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *AccountClosed) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("AccountClosed must not have an empty transaction ID")
+	}
+	if m.CustomerID == "" {
+		return errors.New("AccountClosed must not have an empty customer ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("AccountClosed must not have an empty account ID")
+	}
+	if m.Balance < 0 {
+		return errors.New("AccountClosed must not have a negative balance")
+	}
+	if m.Balance > 0 && m.SweepToAccountID == "" {
+		return errors.New("AccountClosed must not have an empty sweep account ID when the balance is positive")
+	}
+	if m.SweepToPayee != nil {
+		if err := m.SweepToPayee.Validate(); err != nil {
+			return fmt.Errorf("AccountClosed must have a valid payee: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *AccountClosureDeclined) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("AccountClosureDeclined must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("AccountClosureDeclined must not have an empty account ID")
+	}
+	if err := m.Reason.Validate(); err != nil {
+		return fmt.Errorf("AccountClosureDeclined must have a valid reason: %w", err)
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ClosedAccountSwept) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("ClosedAccountSwept must not have an empty transaction ID")
+	}
+	if m.CustomerID == "" {
+		return errors.New("ClosedAccountSwept must not have an empty customer ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("ClosedAccountSwept must not have an empty account ID")
+	}
+	if m.SweepToAccountID == "" {
+		return errors.New("ClosedAccountSwept must not have an empty sweep account ID")
+	}
+	if m.Balance < 1 {
+		return errors.New("ClosedAccountSwept must have a positive balance")
+	}
+	if m.SweepToPayee != nil {
+		if err := m.SweepToPayee.Validate(); err != nil {
+			return fmt.Errorf("ClosedAccountSwept must have a valid payee: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *AccountCreditQueued) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("AccountCreditQueued must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("AccountCreditQueued must not have an empty account ID")
+	}
+	if err := m.TransactionType.Validate(); err != nil {
+		return fmt.Errorf("AccountCreditQueued must have a valid transaction type: %w", err)
+	}
+	if m.Amount < 1 {
+		return errors.New("AccountCreditQueued must have a positive amount")
+	}
+
+	return nil
+}
+
 // MarshalBinary returns a binary representation of the message.
 // For simplicity in this example we use JSON.
 func (m *AccountOpened) MarshalBinary() ([]byte, error) {
@@ -214,3 +490,75 @@ func (m *AccountDebitDeclined) MarshalBinary() ([]byte, error) {
 func (m *AccountDebitDeclined) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AccountCreditDeclined) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AccountCreditDeclined) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AccountDebitSettled) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AccountDebitSettled) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AccountClosed) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AccountClosed) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AccountClosureDeclined) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AccountClosureDeclined) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ClosedAccountSwept) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ClosedAccountSwept) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AccountCreditQueued) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AccountCreditQueued) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
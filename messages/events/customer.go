@@ -6,10 +6,14 @@ import (
 	"fmt"
 
 	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/internal/validation"
 )
 
 func init() {
 	dogma.RegisterEvent[*CustomerAcquired]("ddf33f6c-d120-440e-b611-b86a6c3b80a6")
+	dogma.RegisterEvent[*CustomerCredentialsSet]("379806b3-b218-4705-b45f-0f79150e05f8")
+	dogma.RegisterEvent[*CustomerPasswordChanged]("cd8d64af-8cde-4b47-9175-4c6db63cb580")
 }
 
 // CustomerAcquired is an event indicating that a new customer has been
@@ -19,6 +23,23 @@ type CustomerAcquired struct {
 	CustomerName string
 	AccountID    string
 	AccountName  string
+	AccountType  messages.AccountType
+	MaturityDate string
+}
+
+// CustomerCredentialsSet is an event indicating that the credentials a customer
+// uses to log in have been set.
+type CustomerCredentialsSet struct {
+	CustomerID   string
+	Username     string
+	PasswordHash string
+}
+
+// CustomerPasswordChanged is an event indicating that the password a customer
+// uses to log in has been replaced.
+type CustomerPasswordChanged struct {
+	CustomerID   string
+	PasswordHash string
 }
 
 // MessageDescription returns a human-readable description of the message.
@@ -30,6 +51,23 @@ func (m *CustomerAcquired) MessageDescription() string {
 	)
 }
 
+// MessageDescription returns a human-readable description of the message.
+func (m *CustomerCredentialsSet) MessageDescription() string {
+	return fmt.Sprintf(
+		"set credentials for customer %s with username %s",
+		m.CustomerID,
+		m.Username,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *CustomerPasswordChanged) MessageDescription() string {
+	return fmt.Sprintf(
+		"changed password for customer %s",
+		m.CustomerID,
+	)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *CustomerAcquired) Validate(dogma.EventValidationScope) error {
 	if m.CustomerID == "" {
@@ -44,6 +82,43 @@ func (m *CustomerAcquired) Validate(dogma.EventValidationScope) error {
 	if m.AccountName == "" {
 		return errors.New("CustomerAcquired must not have an empty account name")
 	}
+	if err := m.AccountType.Validate(); err != nil {
+		return fmt.Errorf("CustomerAcquired must have a valid account type: %w", err)
+	}
+	if m.AccountType == messages.TermDeposit {
+		if !validation.IsValidDate(m.MaturityDate) {
+			return errors.New("CustomerAcquired must have a valid maturity date")
+		}
+	} else if m.MaturityDate != "" {
+		return errors.New("CustomerAcquired must not have a maturity date unless it is a term deposit")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *CustomerCredentialsSet) Validate(dogma.EventValidationScope) error {
+	if m.CustomerID == "" {
+		return errors.New("CustomerCredentialsSet must not have an empty customer ID")
+	}
+	if m.Username == "" {
+		return errors.New("CustomerCredentialsSet must not have an empty username")
+	}
+	if m.PasswordHash == "" {
+		return errors.New("CustomerCredentialsSet must not have an empty password hash")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *CustomerPasswordChanged) Validate(dogma.EventValidationScope) error {
+	if m.CustomerID == "" {
+		return errors.New("CustomerPasswordChanged must not have an empty customer ID")
+	}
+	if m.PasswordHash == "" {
+		return errors.New("CustomerPasswordChanged must not have an empty password hash")
+	}
 
 	return nil
 }
@@ -59,3 +134,27 @@ func (m *CustomerAcquired) MarshalBinary() ([]byte, error) {
 func (m *CustomerAcquired) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *CustomerCredentialsSet) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *CustomerCredentialsSet) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *CustomerPasswordChanged) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *CustomerPasswordChanged) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
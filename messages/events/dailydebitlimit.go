@@ -13,6 +13,8 @@ import (
 func init() {
 	dogma.RegisterEvent[*DailyDebitLimitConsumed]("9b4a1114-817e-42d1-963d-ba6324dd07b2")
 	dogma.RegisterEvent[*DailyDebitLimitExceeded]("83c5315e-440d-4d70-a6c8-41f97edc226f")
+	dogma.RegisterEvent[*DailyDebitLimitChanged]("b6e2c1d8-3f4a-4a57-8e0b-92d7c6a5f318")
+	dogma.RegisterEvent[*DailyDebitLimitRestored]("e1f58b3a-2c97-4d06-8a4e-b7d30c6f9152")
 }
 
 // DailyDebitLimitConsumed is an event that indicates an amount of an account
@@ -39,6 +41,25 @@ type DailyDebitLimitExceeded struct {
 	DailyLimit        int64
 }
 
+// DailyDebitLimitChanged is an event that indicates the daily debit limit of
+// an account has been changed.
+type DailyDebitLimitChanged struct {
+	AccountID               string
+	PreviousDailyDebitLimit int64
+	DailyDebitLimit         int64
+}
+
+// DailyDebitLimitRestored is an event that indicates an amount previously
+// consumed from an account daily debit limit has been given back.
+type DailyDebitLimitRestored struct {
+	TransactionID     string
+	AccountID         string
+	DebitType         messages.TransactionType
+	Amount            int64
+	Date              string
+	TotalDebitsForDay int64
+}
+
 // MessageDescription returns a human-readable description of the message.
 func (m *DailyDebitLimitConsumed) MessageDescription() string {
 	return fmt.Sprintf(
@@ -63,6 +84,28 @@ func (m *DailyDebitLimitExceeded) MessageDescription() string {
 	)
 }
 
+// MessageDescription returns a human-readable description of the message.
+func (m *DailyDebitLimitChanged) MessageDescription() string {
+	return fmt.Sprintf(
+		"changed daily debit limit of account %s from %s to %s",
+		m.AccountID,
+		messages.FormatAmount(m.PreviousDailyDebitLimit),
+		messages.FormatAmount(m.DailyDebitLimit),
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *DailyDebitLimitRestored) MessageDescription() string {
+	return fmt.Sprintf(
+		"%s %s: restored %s to %s daily debit limit of account %s",
+		m.DebitType,
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.Date,
+		m.AccountID,
+	)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *DailyDebitLimitConsumed) Validate(dogma.EventValidationScope) error {
 	if m.TransactionID == "" {
@@ -123,6 +166,48 @@ func (m *DailyDebitLimitExceeded) Validate(dogma.EventValidationScope) error {
 	return nil
 }
 
+// Validate returns a non-nil error if the message is invalid.
+func (m *DailyDebitLimitChanged) Validate(dogma.EventValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("DailyDebitLimitChanged must not have an empty account ID")
+	}
+	if m.PreviousDailyDebitLimit < 1 {
+		return errors.New("DailyDebitLimitChanged must have a positive previous daily debit limit")
+	}
+	if m.DailyDebitLimit < 1 {
+		return errors.New("DailyDebitLimitChanged must have a positive daily debit limit")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *DailyDebitLimitRestored) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("DailyDebitLimitRestored must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("DailyDebitLimitRestored must not have an empty account ID")
+	}
+	if err := m.DebitType.Validate(); err != nil {
+		return fmt.Errorf("DailyDebitLimitRestored must have a valid transaction type: %w", err)
+	}
+	if !m.DebitType.IsDebit() {
+		return errors.New("DailyDebitLimitRestored must have a debit transaction type")
+	}
+	if m.Amount < 1 {
+		return errors.New("DailyDebitLimitRestored must have a positive amount")
+	}
+	if !validation.IsValidDate(m.Date) {
+		return errors.New("DailyDebitLimitRestored must have a valid date")
+	}
+	if m.TotalDebitsForDay < 0 {
+		return errors.New("DailyDebitLimitRestored must not have a negative total debits for day")
+	}
+
+	return nil
+}
+
 // MarshalBinary returns a binary representation of the message.
 // For simplicity in this example we use JSON.
 func (m *DailyDebitLimitConsumed) MarshalBinary() ([]byte, error) {
@@ -146,3 +231,27 @@ func (m *DailyDebitLimitExceeded) MarshalBinary() ([]byte, error) {
 func (m *DailyDebitLimitExceeded) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *DailyDebitLimitChanged) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *DailyDebitLimitChanged) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *DailyDebitLimitRestored) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *DailyDebitLimitRestored) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
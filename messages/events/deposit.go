@@ -12,6 +12,7 @@ import (
 func init() {
 	dogma.RegisterEvent[*DepositStarted]("699ae4e6-ea0a-4450-8a04-8ec5248b8042")
 	dogma.RegisterEvent[*DepositApproved]("8520d995-5c0e-4905-9bbf-99b5f8028505")
+	dogma.RegisterEvent[*DepositDeclined]("af74fe2f-98f6-449a-ab85-04f6bb7369bb")
 }
 
 // DepositStarted is an event indicating that the process of depositing funds
@@ -30,6 +31,15 @@ type DepositApproved struct {
 	Amount        int64
 }
 
+// DepositDeclined is an event that indicates a requested deposit has been
+// declined.
+type DepositDeclined struct {
+	TransactionID string
+	AccountID     string
+	Amount        int64
+	Reason        messages.DebitFailureReason
+}
+
 // MessageDescription returns a human-readable description of the message.
 func (m *DepositStarted) MessageDescription() string {
 	return fmt.Sprintf(
@@ -50,6 +60,17 @@ func (m *DepositApproved) MessageDescription() string {
 	)
 }
 
+// MessageDescription returns a human-readable description of the message.
+func (m *DepositDeclined) MessageDescription() string {
+	return fmt.Sprintf(
+		"deposit %s: declined deposit of %s into account %s: %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+		m.Reason,
+	)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *DepositStarted) Validate(dogma.EventValidationScope) error {
 	if m.TransactionID == "" {
@@ -80,6 +101,24 @@ func (m *DepositApproved) Validate(dogma.EventValidationScope) error {
 	return nil
 }
 
+// Validate returns a non-nil error if the message is invalid.
+func (m *DepositDeclined) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("DepositDeclined must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("DepositDeclined must not have an empty account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("DepositDeclined must have a positive amount")
+	}
+	if err := m.Reason.Validate(); err != nil {
+		return fmt.Errorf("DepositDeclined must have a valid reason: %w", err)
+	}
+
+	return nil
+}
+
 // MarshalBinary returns a binary representation of the message.
 // For simplicity in this example we use JSON.
 func (m *DepositStarted) MarshalBinary() ([]byte, error) {
@@ -103,3 +142,15 @@ func (m *DepositApproved) MarshalBinary() ([]byte, error) {
 func (m *DepositApproved) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *DepositDeclined) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *DepositDeclined) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterEvent[*AccountFrozen]("2db8e453-af0a-4bc0-94a2-8aa50b381f1e")
+	dogma.RegisterEvent[*AccountUnfrozen]("1b679ed9-cca8-4b55-b7be-0070bdd4d210")
+}
+
+// AccountFrozen is an event indicating that an account has been frozen.
+type AccountFrozen struct {
+	AccountID  string
+	FreezeType messages.FreezeType
+	Reason     string
+	Operator   string
+}
+
+// AccountUnfrozen is an event indicating that a frozen account has been
+// unfrozen.
+type AccountUnfrozen struct {
+	AccountID string
+	Reason    string
+	Operator  string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *AccountFrozen) MessageDescription() string {
+	return fmt.Sprintf(
+		"froze account %s (%s freeze by %s): %s",
+		m.AccountID,
+		m.FreezeType,
+		m.Operator,
+		m.Reason,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *AccountUnfrozen) MessageDescription() string {
+	return fmt.Sprintf(
+		"unfroze account %s (by %s): %s",
+		m.AccountID,
+		m.Operator,
+		m.Reason,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *AccountFrozen) Validate(dogma.EventValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("AccountFrozen must not have an empty account ID")
+	}
+	if err := m.FreezeType.Validate(); err != nil {
+		return fmt.Errorf("AccountFrozen must have a valid freeze type: %w", err)
+	}
+	if m.Reason == "" {
+		return errors.New("AccountFrozen must not have an empty reason")
+	}
+	if m.Operator == "" {
+		return errors.New("AccountFrozen must not have an empty operator")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *AccountUnfrozen) Validate(dogma.EventValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("AccountUnfrozen must not have an empty account ID")
+	}
+	if m.Reason == "" {
+		return errors.New("AccountUnfrozen must not have an empty reason")
+	}
+	if m.Operator == "" {
+		return errors.New("AccountUnfrozen must not have an empty operator")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AccountFrozen) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AccountFrozen) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *AccountUnfrozen) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *AccountUnfrozen) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
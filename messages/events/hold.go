@@ -0,0 +1,322 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterEvent[*DebitAuthorized]("cee12671-8dbe-4f89-ae09-237c4c6ab8f2")
+	dogma.RegisterEvent[*DebitAuthorizationDeclined]("7c5dce05-7188-47b2-aa21-9d118c215aab")
+	dogma.RegisterEvent[*HoldCaptured]("9d14ecb9-97cb-4816-a1b4-baedf08e890c")
+	dogma.RegisterEvent[*HoldCaptureDeclined]("3b7f0e61-c94a-4d28-8f15-a6e2d9c047b3")
+	dogma.RegisterEvent[*HoldReleased]("98d2f129-356a-4adf-934e-934e048ff43d")
+	dogma.RegisterEvent[*HoldExpired]("a9d963dc-1061-46c3-bed1-bc01922d8892")
+}
+
+// DebitAuthorized is an event indicating that funds have been reserved in an
+// account for a card payment.
+type DebitAuthorized struct {
+	HoldID          string
+	AccountID       string
+	Merchant        string
+	Amount          int64
+	ScheduledTime   time.Time
+	DailyDebitLimit int64
+}
+
+// DebitAuthorizationDeclined is an event indicating that funds could not be
+// reserved in an account for a card payment.
+type DebitAuthorizationDeclined struct {
+	HoldID    string
+	AccountID string
+	Merchant  string
+	Amount    int64
+	Reason    messages.DebitFailureReason
+}
+
+// HoldCaptured is an event indicating that the funds reserved by a hold have
+// been debited from the account.
+type HoldCaptured struct {
+	HoldID    string
+	AccountID string
+	Merchant  string
+	Amount    int64
+}
+
+// HoldCaptureDeclined is an event indicating that the funds reserved by a hold
+// could not be debited from the account. The hold remains outstanding.
+type HoldCaptureDeclined struct {
+	HoldID    string
+	AccountID string
+	Merchant  string
+	Amount    int64
+	Reason    messages.DebitFailureReason
+}
+
+// HoldReleased is an event indicating that the funds reserved by a hold have
+// been made available again without being debited.
+type HoldReleased struct {
+	HoldID    string
+	AccountID string
+	Amount    int64
+}
+
+// HoldExpired is an event indicating that the funds reserved by a hold have
+// been made available again because the hold was not captured in time.
+type HoldExpired struct {
+	HoldID    string
+	AccountID string
+	Amount    int64
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *DebitAuthorized) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s: authorized debit of %s from account %s for %s",
+		m.HoldID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+		m.Merchant,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *DebitAuthorizationDeclined) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s: declined authorization of %s from account %s for %s: %s",
+		m.HoldID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+		m.Merchant,
+		m.Reason,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *HoldCaptured) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s: captured %s from account %s for %s",
+		m.HoldID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+		m.Merchant,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *HoldCaptureDeclined) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s: declined capture of %s from account %s for %s: %s",
+		m.HoldID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+		m.Merchant,
+		m.Reason,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *HoldReleased) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s: released %s on account %s",
+		m.HoldID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *HoldExpired) MessageDescription() string {
+	return fmt.Sprintf(
+		"hold %s: expired hold of %s on account %s",
+		m.HoldID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *DebitAuthorized) Validate(dogma.EventValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("DebitAuthorized must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("DebitAuthorized must not have an empty account ID")
+	}
+	if m.Merchant == "" {
+		return errors.New("DebitAuthorized must not have an empty merchant")
+	}
+	if m.Amount < 1 {
+		return errors.New("DebitAuthorized must have a positive amount")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *DebitAuthorizationDeclined) Validate(dogma.EventValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("DebitAuthorizationDeclined must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("DebitAuthorizationDeclined must not have an empty account ID")
+	}
+	if m.Merchant == "" {
+		return errors.New("DebitAuthorizationDeclined must not have an empty merchant")
+	}
+	if m.Amount < 1 {
+		return errors.New("DebitAuthorizationDeclined must have a positive amount")
+	}
+	if err := m.Reason.Validate(); err != nil {
+		return fmt.Errorf("DebitAuthorizationDeclined must have a valid reason: %w", err)
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *HoldCaptured) Validate(dogma.EventValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("HoldCaptured must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("HoldCaptured must not have an empty account ID")
+	}
+	if m.Merchant == "" {
+		return errors.New("HoldCaptured must not have an empty merchant")
+	}
+	if m.Amount < 1 {
+		return errors.New("HoldCaptured must have a positive amount")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *HoldCaptureDeclined) Validate(dogma.EventValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("HoldCaptureDeclined must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("HoldCaptureDeclined must not have an empty account ID")
+	}
+	if m.Merchant == "" {
+		return errors.New("HoldCaptureDeclined must not have an empty merchant")
+	}
+	if m.Amount < 1 {
+		return errors.New("HoldCaptureDeclined must have a positive amount")
+	}
+	if err := m.Reason.Validate(); err != nil {
+		return fmt.Errorf("HoldCaptureDeclined must have a valid reason: %w", err)
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *HoldReleased) Validate(dogma.EventValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("HoldReleased must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("HoldReleased must not have an empty account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("HoldReleased must have a positive amount")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *HoldExpired) Validate(dogma.EventValidationScope) error {
+	if m.HoldID == "" {
+		return errors.New("HoldExpired must not have an empty hold ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("HoldExpired must not have an empty account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("HoldExpired must have a positive amount")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *DebitAuthorized) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *DebitAuthorized) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *DebitAuthorizationDeclined) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *DebitAuthorizationDeclined) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *HoldCaptured) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *HoldCaptured) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *HoldCaptureDeclined) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *HoldCaptureDeclined) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *HoldReleased) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *HoldReleased) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *HoldExpired) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *HoldExpired) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,120 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/internal/validation"
+)
+
+func init() {
+	dogma.RegisterEvent[*InterestRateChanged]("3b8631b7-dcf7-4a24-b7b0-308673b5848a")
+	dogma.RegisterEvent[*InterestAccrued]("d786c170-65e2-41a3-9f93-201c47eb60e4")
+}
+
+// InterestRateChanged is an event that indicates the annual interest rate paid
+// on an account has been changed.
+type InterestRateChanged struct {
+	AccountID            string
+	PreviousInterestRate int64
+	InterestRate         int64
+
+	// AccrualID identifies the period during which interest accrues at a
+	// non-zero rate. Changing the rate from zero begins a new accrual, which
+	// ends when the rate is changed back to zero.
+	AccrualID string
+}
+
+// InterestAccrued is an event that indicates a day's interest has been accrued
+// on the end-of-day balance of an account.
+type InterestAccrued struct {
+	AccountID    string
+	AccrualID    string
+	Date         string
+	Balance      int64
+	InterestRate int64
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *InterestRateChanged) MessageDescription() string {
+	return fmt.Sprintf(
+		"changed interest rate of account %s from %s to %s",
+		m.AccountID,
+		messages.FormatInterestRate(m.PreviousInterestRate),
+		messages.FormatInterestRate(m.InterestRate),
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *InterestAccrued) MessageDescription() string {
+	return fmt.Sprintf(
+		"accrued interest on account %s for %s: %s balance at %s",
+		m.AccountID,
+		m.Date,
+		messages.FormatAmount(m.Balance),
+		messages.FormatInterestRate(m.InterestRate),
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *InterestRateChanged) Validate(dogma.EventValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("InterestRateChanged must not have an empty account ID")
+	}
+	if m.AccrualID == "" {
+		return errors.New("InterestRateChanged must not have an empty accrual ID")
+	}
+	if m.PreviousInterestRate < 0 {
+		return errors.New("InterestRateChanged must not have a negative previous interest rate")
+	}
+	if m.InterestRate < 0 {
+		return errors.New("InterestRateChanged must not have a negative interest rate")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *InterestAccrued) Validate(dogma.EventValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("InterestAccrued must not have an empty account ID")
+	}
+	if m.AccrualID == "" {
+		return errors.New("InterestAccrued must not have an empty accrual ID")
+	}
+	if !validation.IsValidDate(m.Date) {
+		return errors.New("InterestAccrued must have a valid date")
+	}
+	if m.InterestRate < 0 {
+		return errors.New("InterestAccrued must not have a negative interest rate")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *InterestRateChanged) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *InterestRateChanged) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *InterestAccrued) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *InterestAccrued) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
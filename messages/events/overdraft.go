@@ -0,0 +1,59 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterEvent[*OverdraftLimitChanged]("676e20c1-7e5f-4be1-9d26-5b188c291851")
+}
+
+// OverdraftLimitChanged is an event that indicates the arranged overdraft limit
+// of an account has been changed.
+type OverdraftLimitChanged struct {
+	AccountID              string
+	PreviousOverdraftLimit int64
+	OverdraftLimit         int64
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *OverdraftLimitChanged) MessageDescription() string {
+	return fmt.Sprintf(
+		"changed overdraft limit of account %s from %s to %s",
+		m.AccountID,
+		messages.FormatAmount(m.PreviousOverdraftLimit),
+		messages.FormatAmount(m.OverdraftLimit),
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *OverdraftLimitChanged) Validate(dogma.EventValidationScope) error {
+	if m.AccountID == "" {
+		return errors.New("OverdraftLimitChanged must not have an empty account ID")
+	}
+	if m.PreviousOverdraftLimit < 0 {
+		return errors.New("OverdraftLimitChanged must not have a negative previous overdraft limit")
+	}
+	if m.OverdraftLimit < 0 {
+		return errors.New("OverdraftLimitChanged must not have a negative overdraft limit")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *OverdraftLimitChanged) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *OverdraftLimitChanged) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
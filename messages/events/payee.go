@@ -0,0 +1,285 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterEvent[*PayeeAdded]("d6ace90d-a949-4551-ac91-0140ab1b2acb")
+	dogma.RegisterEvent[*PayeeRemoved]("23aec44b-f94d-4bb5-a461-a7fbaf955e67")
+	dogma.RegisterEvent[*PayeeCoolingOffLimitConsumed]("80d62815-6917-4c77-be82-a46c2840c087")
+	dogma.RegisterEvent[*PayeeCoolingOffLimitExceeded]("e221b846-5339-42ab-a054-567153c9957c")
+}
+
+// PayeeAdded is an event that indicates an account has been saved as one of a
+// customer's payees.
+type PayeeAdded struct {
+	CustomerID      string
+	PayeeID         string
+	Name            string
+	AccountID       string
+	ThirdPartyPayee *messages.ThirdPartyPayee
+	AddedAt         time.Time
+
+	// CoolingOffUntil is the time at which the payee's cooling-off period
+	// ends. Until then, the total of all transfers to the payee may not exceed
+	// CoolingOffLimit. It is the zero time if there is no cooling-off period.
+	CoolingOffUntil time.Time
+	CoolingOffLimit int64
+
+	// TotalDuringCoolingOff is the total of all transfers to the payee's
+	// account during its cooling-off period. It is non-zero only if the
+	// account was previously added as a payee and then removed.
+	TotalDuringCoolingOff int64
+}
+
+// PayeeRemoved is an event that indicates a payee has been removed from a
+// customer's payees.
+type PayeeRemoved struct {
+	CustomerID string
+	PayeeID    string
+}
+
+// PayeeCoolingOffLimitConsumed is an event that indicates a transfer to a payee
+// has been allowed.
+//
+// TotalDuringCoolingOff is the total of all transfers to the payee during its
+// cooling-off period, including this one. It and CoolingOffLimit are zero if
+// the transfer is not made during the cooling-off period.
+type PayeeCoolingOffLimitConsumed struct {
+	TransactionID         string
+	CustomerID            string
+	PayeeID               string
+	TransactionType       messages.TransactionType
+	Amount                int64
+	TotalDuringCoolingOff int64
+	CoolingOffLimit       int64
+}
+
+// PayeeCoolingOffLimitExceeded is an event that indicates a transfer to a payee
+// has been rejected because it would exceed the limit on transfers to the
+// payee during its cooling-off period.
+//
+// TotalDuringCoolingOff is the total of all earlier transfers to the payee
+// during its cooling-off period.
+type PayeeCoolingOffLimitExceeded struct {
+	TransactionID         string
+	CustomerID            string
+	PayeeID               string
+	TransactionType       messages.TransactionType
+	Amount                int64
+	TotalDuringCoolingOff int64
+	CoolingOffLimit       int64
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *PayeeAdded) MessageDescription() string {
+	if m.ThirdPartyPayee != nil {
+		return fmt.Sprintf(
+			"customer %s: added payee %s, %s",
+			m.CustomerID,
+			m.PayeeID,
+			m.ThirdPartyPayee,
+		)
+	}
+
+	return fmt.Sprintf(
+		"customer %s: added payee %s, %s (account %s)",
+		m.CustomerID,
+		m.PayeeID,
+		m.Name,
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *PayeeRemoved) MessageDescription() string {
+	return fmt.Sprintf(
+		"customer %s: removed payee %s",
+		m.CustomerID,
+		m.PayeeID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *PayeeCoolingOffLimitConsumed) MessageDescription() string {
+	if m.CoolingOffLimit == 0 {
+		return fmt.Sprintf(
+			"transfer %s: payee %s is not in its cooling-off period",
+			m.TransactionID,
+			m.PayeeID,
+		)
+	}
+
+	return fmt.Sprintf(
+		"transfer %s: consumed %s from cooling-off limit of payee %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.PayeeID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *PayeeCoolingOffLimitExceeded) MessageDescription() string {
+	return fmt.Sprintf(
+		"transfer %s: exceeded cooling-off limit of payee %s by %s",
+		m.TransactionID,
+		m.PayeeID,
+		messages.FormatAmount((m.TotalDuringCoolingOff+m.Amount)-m.CoolingOffLimit),
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *PayeeAdded) Validate(dogma.EventValidationScope) error {
+	if m.CustomerID == "" {
+		return errors.New("PayeeAdded must not have an empty customer ID")
+	}
+	if m.PayeeID == "" {
+		return errors.New("PayeeAdded must not have an empty payee ID")
+	}
+	if m.Name == "" {
+		return errors.New("PayeeAdded must not have an empty name")
+	}
+	if m.AccountID == "" {
+		return errors.New("PayeeAdded must not have an empty account ID")
+	}
+	if m.ThirdPartyPayee != nil {
+		if err := m.ThirdPartyPayee.Validate(); err != nil {
+			return fmt.Errorf("PayeeAdded must have a valid third-party payee: %w", err)
+		}
+	}
+	if m.AddedAt.IsZero() {
+		return errors.New("PayeeAdded must have a non-zero added time")
+	}
+	if m.CoolingOffLimit < 0 {
+		return errors.New("PayeeAdded must not have a negative cooling-off limit")
+	}
+	if m.CoolingOffUntil.IsZero() != (m.CoolingOffLimit == 0) {
+		return errors.New("PayeeAdded must have both a cooling-off time and limit, or neither")
+	}
+	if m.TotalDuringCoolingOff < 0 {
+		return errors.New("PayeeAdded must not have a negative total during cooling-off")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *PayeeRemoved) Validate(dogma.EventValidationScope) error {
+	if m.CustomerID == "" {
+		return errors.New("PayeeRemoved must not have an empty customer ID")
+	}
+	if m.PayeeID == "" {
+		return errors.New("PayeeRemoved must not have an empty payee ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *PayeeCoolingOffLimitConsumed) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("PayeeCoolingOffLimitConsumed must not have an empty transaction ID")
+	}
+	if m.CustomerID == "" {
+		return errors.New("PayeeCoolingOffLimitConsumed must not have an empty customer ID")
+	}
+	if m.PayeeID == "" {
+		return errors.New("PayeeCoolingOffLimitConsumed must not have an empty payee ID")
+	}
+	if err := m.TransactionType.Validate(); err != nil {
+		return fmt.Errorf("PayeeCoolingOffLimitConsumed must have a valid transaction type: %w", err)
+	}
+	if m.Amount < 1 {
+		return errors.New("PayeeCoolingOffLimitConsumed must have a positive amount")
+	}
+	if m.TotalDuringCoolingOff < 0 {
+		return errors.New("PayeeCoolingOffLimitConsumed must not have a negative total during cooling-off")
+	}
+	if m.CoolingOffLimit < 0 {
+		return errors.New("PayeeCoolingOffLimitConsumed must not have a negative cooling-off limit")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *PayeeCoolingOffLimitExceeded) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("PayeeCoolingOffLimitExceeded must not have an empty transaction ID")
+	}
+	if m.CustomerID == "" {
+		return errors.New("PayeeCoolingOffLimitExceeded must not have an empty customer ID")
+	}
+	if m.PayeeID == "" {
+		return errors.New("PayeeCoolingOffLimitExceeded must not have an empty payee ID")
+	}
+	if err := m.TransactionType.Validate(); err != nil {
+		return fmt.Errorf("PayeeCoolingOffLimitExceeded must have a valid transaction type: %w", err)
+	}
+	if m.Amount < 1 {
+		return errors.New("PayeeCoolingOffLimitExceeded must have a positive amount")
+	}
+	if m.TotalDuringCoolingOff < 0 {
+		return errors.New("PayeeCoolingOffLimitExceeded must not have a negative total during cooling-off")
+	}
+	if m.CoolingOffLimit < 1 {
+		return errors.New("PayeeCoolingOffLimitExceeded must have a positive cooling-off limit")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *PayeeAdded) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *PayeeAdded) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *PayeeRemoved) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *PayeeRemoved) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *PayeeCoolingOffLimitConsumed) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *PayeeCoolingOffLimitConsumed) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *PayeeCoolingOffLimitExceeded) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *PayeeCoolingOffLimitExceeded) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterEvent[*PayeeChecked]("d4705ba3-e147-4997-bafa-2171a6dfecf7")
+}
+
+// PayeeChecked is an event indicating that the account name of a third-party
+// payee has been checked with the third-party bank.
+type PayeeChecked struct {
+	CheckID string
+	Payee   messages.ThirdPartyPayee
+	Result  messages.PayeeCheckResult
+
+	// MatchedAccountName is the name of the account held at the third-party
+	// bank. It is only set if the result is [messages.PayeeCloselyMatched].
+	MatchedAccountName string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *PayeeChecked) MessageDescription() string {
+	if m.MatchedAccountName != "" {
+		return fmt.Sprintf(
+			"payee check %s: account name of %s %s %q",
+			m.CheckID,
+			m.Payee,
+			m.Result,
+			m.MatchedAccountName,
+		)
+	}
+
+	return fmt.Sprintf(
+		"payee check %s: account name of %s %s",
+		m.CheckID,
+		m.Payee,
+		m.Result,
+	)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *PayeeChecked) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *PayeeChecked) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *PayeeChecked) Validate(dogma.EventValidationScope) error {
+	if m.CheckID == "" {
+		return errors.New("PayeeChecked must not have an empty check ID")
+	}
+	if err := m.Payee.Validate(); err != nil {
+		return fmt.Errorf("PayeeChecked must have a valid payee: %w", err)
+	}
+	if err := m.Result.Validate(); err != nil {
+		return fmt.Errorf("PayeeChecked must have a valid result: %w", err)
+	}
+	if m.MatchedAccountName != "" && m.Result != messages.PayeeCloselyMatched {
+		return errors.New("PayeeChecked must not have a matched account name unless the result is a close match")
+	}
+
+	return nil
+}
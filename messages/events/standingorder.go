@@ -0,0 +1,370 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/internal/validation"
+)
+
+func init() {
+	dogma.RegisterEvent[*StandingOrderCreated]("abe46438-b620-4235-ac5c-74bcd3d00989")
+	dogma.RegisterEvent[*StandingOrderPaused]("10f7766b-a838-43b8-ab55-d9523454e846")
+	dogma.RegisterEvent[*StandingOrderResumed]("9c5b2aa0-f711-4325-8f45-fb32dcf83e3e")
+	dogma.RegisterEvent[*StandingOrderCancelled]("d8125aa2-43a1-484c-95e2-2729afb94fa1")
+	dogma.RegisterEvent[*StandingOrderCompleted]("b49ed5fb-e902-4e82-9652-2cd913d0c38b")
+	dogma.RegisterEvent[*StandingOrderPaymentStarted]("9b29786a-ee4e-4f12-bde8-8ba6c0402540")
+	dogma.RegisterEvent[*StandingOrderPaymentSkipped]("7530efa6-1997-4a74-ab43-7f943a02aaf4")
+}
+
+// StandingOrderCreated is an event indicating that a recurring transfer from
+// one bank account to another has been set up.
+type StandingOrderCreated struct {
+	StandingOrderID  string
+	FromAccountID    string
+	ToAccountID      string
+	ToThirdPartyBank bool
+	Amount           int64
+	Frequency        messages.Frequency
+	StartDate        string
+	EndDate          string
+	NumberOfPayments int
+	ToPayee          *messages.ThirdPartyPayee
+	CustomerID       string
+	PayeeID          string
+}
+
+// StandingOrderPaused is an event indicating that payments falling due on a
+// standing order will be skipped until it is resumed.
+type StandingOrderPaused struct {
+	StandingOrderID string
+}
+
+// StandingOrderResumed is an event indicating that a paused standing order has
+// resumed making payments.
+type StandingOrderResumed struct {
+	StandingOrderID string
+}
+
+// StandingOrderCancelled is an event indicating that a standing order will make
+// no further payments.
+type StandingOrderCancelled struct {
+	StandingOrderID string
+}
+
+// StandingOrderCompleted is an event indicating that a standing order has
+// reached its end date or number of payments.
+type StandingOrderCompleted struct {
+	StandingOrderID string
+}
+
+// StandingOrderPaymentStarted is an event indicating that a transfer has been
+// started to make a payment that has fallen due on a standing order.
+type StandingOrderPaymentStarted struct {
+	StandingOrderID  string
+	TransactionID    string
+	PaymentNumber    int
+	FromAccountID    string
+	ToAccountID      string
+	ToThirdPartyBank bool
+	Amount           int64
+	Date             string
+	NextPaymentDate  string
+	ToPayee          *messages.ThirdPartyPayee
+	CustomerID       string
+	PayeeID          string
+}
+
+// StandingOrderPaymentSkipped is an event indicating that a payment that fell
+// due on a standing order was not made because the standing order is paused.
+type StandingOrderPaymentSkipped struct {
+	StandingOrderID string
+	PaymentNumber   int
+	Date            string
+	NextPaymentDate string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StandingOrderCreated) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: created %s standing order of %s from account %s to account %s, starting %s",
+		m.StandingOrderID,
+		m.Frequency,
+		messages.FormatAmount(m.Amount),
+		m.FromAccountID,
+		m.ToAccountID,
+		m.StartDate,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StandingOrderPaused) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: paused",
+		m.StandingOrderID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StandingOrderResumed) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: resumed",
+		m.StandingOrderID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StandingOrderCancelled) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: cancelled",
+		m.StandingOrderID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StandingOrderCompleted) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: completed",
+		m.StandingOrderID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StandingOrderPaymentStarted) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: started payment #%d of %s from account %s to account %s as transfer %s",
+		m.StandingOrderID,
+		m.PaymentNumber,
+		messages.FormatAmount(m.Amount),
+		m.FromAccountID,
+		m.ToAccountID,
+		m.TransactionID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StandingOrderPaymentSkipped) MessageDescription() string {
+	return fmt.Sprintf(
+		"standing order %s: skipped payment #%d due on %s",
+		m.StandingOrderID,
+		m.PaymentNumber,
+		m.Date,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StandingOrderCreated) Validate(dogma.EventValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("StandingOrderCreated must not have an empty standing order ID")
+	}
+	if m.FromAccountID == "" {
+		return errors.New("StandingOrderCreated must not have an empty 'from' account ID")
+	}
+	if m.ToAccountID == "" {
+		return errors.New("StandingOrderCreated must not have an empty 'to' account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("StandingOrderCreated must have a positive amount")
+	}
+	if m.ToPayee != nil {
+		if err := m.ToPayee.Validate(); err != nil {
+			return fmt.Errorf("StandingOrderCreated must have a valid payee: %w", err)
+		}
+	}
+	if (m.CustomerID == "") != (m.PayeeID == "") {
+		return errors.New("StandingOrderCreated must have both a customer ID and payee ID, or neither")
+	}
+	if err := m.Frequency.Validate(); err != nil {
+		return fmt.Errorf("StandingOrderCreated must have a valid frequency: %w", err)
+	}
+	if !validation.IsValidDate(m.StartDate) {
+		return errors.New("StandingOrderCreated must have a valid start date")
+	}
+	if m.EndDate != "" && !validation.IsValidDate(m.EndDate) {
+		return errors.New("StandingOrderCreated must have a valid end date")
+	}
+	if m.NumberOfPayments < 0 {
+		return errors.New("StandingOrderCreated must not have a negative number of payments")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StandingOrderPaused) Validate(dogma.EventValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("StandingOrderPaused must not have an empty standing order ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StandingOrderResumed) Validate(dogma.EventValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("StandingOrderResumed must not have an empty standing order ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StandingOrderCancelled) Validate(dogma.EventValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("StandingOrderCancelled must not have an empty standing order ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StandingOrderCompleted) Validate(dogma.EventValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("StandingOrderCompleted must not have an empty standing order ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StandingOrderPaymentStarted) Validate(dogma.EventValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("StandingOrderPaymentStarted must not have an empty standing order ID")
+	}
+	if m.TransactionID == "" {
+		return errors.New("StandingOrderPaymentStarted must not have an empty transaction ID")
+	}
+	if m.PaymentNumber < 1 {
+		return errors.New("StandingOrderPaymentStarted must have a positive payment number")
+	}
+	if m.FromAccountID == "" {
+		return errors.New("StandingOrderPaymentStarted must not have an empty 'from' account ID")
+	}
+	if m.ToAccountID == "" {
+		return errors.New("StandingOrderPaymentStarted must not have an empty 'to' account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("StandingOrderPaymentStarted must have a positive amount")
+	}
+	if m.ToPayee != nil {
+		if err := m.ToPayee.Validate(); err != nil {
+			return fmt.Errorf("StandingOrderPaymentStarted must have a valid payee: %w", err)
+		}
+	}
+	if (m.CustomerID == "") != (m.PayeeID == "") {
+		return errors.New("StandingOrderPaymentStarted must have both a customer ID and payee ID, or neither")
+	}
+	if !validation.IsValidDate(m.Date) {
+		return errors.New("StandingOrderPaymentStarted must have a valid date")
+	}
+	if m.NextPaymentDate != "" && !validation.IsValidDate(m.NextPaymentDate) {
+		return errors.New("StandingOrderPaymentStarted must have a valid next payment date")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StandingOrderPaymentSkipped) Validate(dogma.EventValidationScope) error {
+	if m.StandingOrderID == "" {
+		return errors.New("StandingOrderPaymentSkipped must not have an empty standing order ID")
+	}
+	if m.PaymentNumber < 1 {
+		return errors.New("StandingOrderPaymentSkipped must have a positive payment number")
+	}
+	if !validation.IsValidDate(m.Date) {
+		return errors.New("StandingOrderPaymentSkipped must have a valid date")
+	}
+	if m.NextPaymentDate != "" && !validation.IsValidDate(m.NextPaymentDate) {
+		return errors.New("StandingOrderPaymentSkipped must have a valid next payment date")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderCreated) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderCreated) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderPaused) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderPaused) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderResumed) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderResumed) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderCancelled) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderCancelled) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderCompleted) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderCompleted) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderPaymentStarted) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderPaymentStarted) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderPaymentSkipped) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StandingOrderPaymentSkipped) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/internal/validation"
+)
+
+func init() {
+	dogma.RegisterEvent[*StatementIssued]("5d469dde-17cf-44d6-b992-977e074abefd")
+}
+
+// StatementIssued is an event that indicates a statement has been issued for
+// an account, summarizing the transactions applied to it during a period.
+//
+// The closing balance is always equal to the opening balance, plus the total
+// of all credits, less the total of all debits.
+type StatementIssued struct {
+	StatementID    string
+	AccountID      string
+	PeriodStart    string
+	PeriodEnd      string
+	OpeningBalance int64
+	TotalCredits   int64
+	TotalDebits    int64
+	ClosingBalance int64
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *StatementIssued) MessageDescription() string {
+	return fmt.Sprintf(
+		"issued statement %s for account %s, from %s to %s: opening balance %s, credits %s, debits %s, closing balance %s",
+		m.StatementID,
+		m.AccountID,
+		m.PeriodStart,
+		m.PeriodEnd,
+		messages.FormatAmount(m.OpeningBalance),
+		messages.FormatAmount(m.TotalCredits),
+		messages.FormatAmount(m.TotalDebits),
+		messages.FormatAmount(m.ClosingBalance),
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *StatementIssued) Validate(dogma.EventValidationScope) error {
+	if m.StatementID == "" {
+		return errors.New("StatementIssued must not have an empty statement ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("StatementIssued must not have an empty account ID")
+	}
+	if !validation.IsValidDate(m.PeriodStart) {
+		return errors.New("StatementIssued must have a valid period start date")
+	}
+	if !validation.IsValidDate(m.PeriodEnd) {
+		return errors.New("StatementIssued must have a valid period end date")
+	}
+	if m.PeriodEnd < m.PeriodStart {
+		return errors.New("StatementIssued must not end before it starts")
+	}
+	if m.TotalCredits < 0 {
+		return errors.New("StatementIssued must not have negative total credits")
+	}
+	if m.TotalDebits < 0 {
+		return errors.New("StatementIssued must not have negative total debits")
+	}
+	if m.OpeningBalance+m.TotalCredits-m.TotalDebits != m.ClosingBalance {
+		return errors.New("StatementIssued must have a closing balance equal to its opening balance plus credits less debits")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *StatementIssued) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *StatementIssued) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -12,28 +12,49 @@ import (
 func init() {
 	dogma.RegisterEvent[*ThirdPartyAccountCredited]("a7f3c8e2-5d1b-4a9e-8c6f-3b2d0e7a1c5d")
 	dogma.RegisterEvent[*ThirdPartyAccountCreditFailed]("e9b4d7f0-2c6a-4e8b-9d3f-1a5c0b8e4d2a")
+	dogma.RegisterEvent[*ThirdPartyCreditAttemptFailed]("31c5a247-ab26-4b76-a3dc-cf89916b61f6")
 }
 
 // ThirdPartyAccountCredited is an event indicating that the credit to a
 // third-party bank account was completed successfully.
 type ThirdPartyAccountCredited struct {
-	TransactionID string
-	AccountID     string
-	Amount        int64
+	TransactionID   string
+	AccountID       string
+	TransactionType messages.TransactionType
+	Amount          int64
 }
 
 // ThirdPartyAccountCreditFailed is an event indicating that the credit to a
 // third-party bank account could not be completed.
 type ThirdPartyAccountCreditFailed struct {
-	TransactionID string
-	AccountID     string
-	Amount        int64
+	TransactionID   string
+	AccountID       string
+	TransactionType messages.TransactionType
+	Amount          int64
+}
+
+// ThirdPartyCreditAttemptFailed is an event indicating that an attempt to
+// credit a third-party bank account failed with an error that may not recur,
+// such as a timeout. The outcome of the attempt is unknown, so the credit is
+// retried.
+type ThirdPartyCreditAttemptFailed struct {
+	TransactionID   string
+	AccountID       string
+	TransactionType messages.TransactionType
+	Amount          int64
+	Attempt         int    // number of the attempt that failed, starting at 1
+	Error           string // description of the error, for operators
+
+	// Payee identifies the account by its BSB, account number and name, so
+	// that the credit is retried with the same details.
+	Payee *messages.ThirdPartyPayee
 }
 
 // MessageDescription returns a human-readable description of the message.
 func (m *ThirdPartyAccountCredited) MessageDescription() string {
 	return fmt.Sprintf(
-		"transfer %s: credited %s to third-party account %s",
+		"%s %s: credited %s to third-party account %s",
+		m.TransactionType,
 		m.TransactionID,
 		messages.FormatAmount(m.Amount),
 		m.AccountID,
@@ -43,10 +64,24 @@ func (m *ThirdPartyAccountCredited) MessageDescription() string {
 // MessageDescription returns a human-readable description of the message.
 func (m *ThirdPartyAccountCreditFailed) MessageDescription() string {
 	return fmt.Sprintf(
-		"transfer %s: failed to credit %s to third-party account %s",
+		"%s %s: failed to credit %s to third-party account %s",
+		m.TransactionType,
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ThirdPartyCreditAttemptFailed) MessageDescription() string {
+	return fmt.Sprintf(
+		"%s %s: attempt %d to credit %s to third-party account %s failed: %s",
+		m.TransactionType,
 		m.TransactionID,
+		m.Attempt,
 		messages.FormatAmount(m.Amount),
 		m.AccountID,
+		m.Error,
 	)
 }
 
@@ -74,6 +109,18 @@ func (m *ThirdPartyAccountCreditFailed) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
 
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ThirdPartyCreditAttemptFailed) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ThirdPartyCreditAttemptFailed) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *ThirdPartyAccountCredited) Validate(dogma.EventValidationScope) error {
 	if m.TransactionID == "" {
@@ -82,6 +129,9 @@ func (m *ThirdPartyAccountCredited) Validate(dogma.EventValidationScope) error {
 	if m.AccountID == "" {
 		return errors.New("ThirdPartyAccountCredited must not have an empty account ID")
 	}
+	if err := m.TransactionType.Validate(); err != nil {
+		return fmt.Errorf("ThirdPartyAccountCredited must have a valid transaction type: %w", err)
+	}
 	if m.Amount < 1 {
 		return errors.New("ThirdPartyAccountCredited must have a positive amount")
 	}
@@ -97,9 +147,42 @@ func (m *ThirdPartyAccountCreditFailed) Validate(dogma.EventValidationScope) err
 	if m.AccountID == "" {
 		return errors.New("ThirdPartyAccountCreditFailed must not have an empty account ID")
 	}
+	if err := m.TransactionType.Validate(); err != nil {
+		return fmt.Errorf("ThirdPartyAccountCreditFailed must have a valid transaction type: %w", err)
+	}
 	if m.Amount < 1 {
 		return errors.New("ThirdPartyAccountCreditFailed must have a positive amount")
 	}
 
 	return nil
 }
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ThirdPartyCreditAttemptFailed) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("ThirdPartyCreditAttemptFailed must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("ThirdPartyCreditAttemptFailed must not have an empty account ID")
+	}
+	if m.Payee == nil {
+		return errors.New("ThirdPartyCreditAttemptFailed must have a payee")
+	}
+	if err := m.Payee.Validate(); err != nil {
+		return fmt.Errorf("ThirdPartyCreditAttemptFailed must have a valid payee: %w", err)
+	}
+	if err := m.TransactionType.Validate(); err != nil {
+		return fmt.Errorf("ThirdPartyCreditAttemptFailed must have a valid transaction type: %w", err)
+	}
+	if m.Amount < 1 {
+		return errors.New("ThirdPartyCreditAttemptFailed must have a positive amount")
+	}
+	if m.Attempt < 1 {
+		return errors.New("ThirdPartyCreditAttemptFailed must have a positive attempt number")
+	}
+	if m.Error == "" {
+		return errors.New("ThirdPartyCreditAttemptFailed must not have an empty error")
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+)
+
+func init() {
+	dogma.RegisterEvent[*ThirdPartyPaymentReceived]("3c109b1f-3210-4c5c-9b8c-becd7d2b9637")
+	dogma.RegisterEvent[*ThirdPartyPaymentReturned]("35715fd2-674d-417a-b6c8-f90ea1618068")
+}
+
+// ThirdPartyPaymentReceived is an event indicating that a third-party bank has
+// sent a payment into an account held at this bank.
+type ThirdPartyPaymentReceived struct {
+	TransactionID           string
+	ThirdPartyTransactionID string // the third-party bank's own ID for the payment
+	AccountID               string
+	SenderAccountID         string // account at the third-party bank that sent the payment
+	Amount                  int64
+}
+
+// ThirdPartyPaymentReturned is an event indicating that a payment received from
+// a third-party bank has been returned to its sender because it could not be
+// credited to the account.
+type ThirdPartyPaymentReturned struct {
+	TransactionID   string
+	AccountID       string
+	SenderAccountID string
+	Amount          int64
+	Reason          messages.DebitFailureReason
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ThirdPartyPaymentReceived) MessageDescription() string {
+	return fmt.Sprintf(
+		"third-party payment %s: received %s from third-party account %s into account %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.SenderAccountID,
+		m.AccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *ThirdPartyPaymentReturned) MessageDescription() string {
+	return fmt.Sprintf(
+		"third-party payment %s: returned %s to third-party account %s: %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.SenderAccountID,
+		m.Reason,
+	)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ThirdPartyPaymentReceived) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ThirdPartyPaymentReceived) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *ThirdPartyPaymentReturned) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *ThirdPartyPaymentReturned) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ThirdPartyPaymentReceived) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("ThirdPartyPaymentReceived must not have an empty transaction ID")
+	}
+	if m.ThirdPartyTransactionID == "" {
+		return errors.New("ThirdPartyPaymentReceived must not have an empty third-party transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("ThirdPartyPaymentReceived must not have an empty account ID")
+	}
+	if m.SenderAccountID == "" {
+		return errors.New("ThirdPartyPaymentReceived must not have an empty sender account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("ThirdPartyPaymentReceived must have a positive amount")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *ThirdPartyPaymentReturned) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("ThirdPartyPaymentReturned must not have an empty transaction ID")
+	}
+	if m.AccountID == "" {
+		return errors.New("ThirdPartyPaymentReturned must not have an empty account ID")
+	}
+	if m.SenderAccountID == "" {
+		return errors.New("ThirdPartyPaymentReturned must not have an empty sender account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("ThirdPartyPaymentReturned must have a positive amount")
+	}
+	if err := m.Reason.Validate(); err != nil {
+		return fmt.Errorf("ThirdPartyPaymentReturned must have a valid reason: %w", err)
+	}
+
+	return nil
+}
@@ -15,6 +15,8 @@ func init() {
 	dogma.RegisterEvent[*TransferApproved]("bcc989cc-4ec7-4175-84dc-24908ac82676")
 	dogma.RegisterEvent[*TransferDeclined]("0e43679a-bf5b-4730-a4a0-543e17a67479")
 	dogma.RegisterEvent[*TransferFailed]("c6d8e9a1-2b4f-5e7c-8d0a-1f3e5c7b9d2e")
+	dogma.RegisterEvent[*TransferProceeded]("6d412b3a-c628-4e82-b4c6-1e4681c328a0")
+	dogma.RegisterEvent[*TransferCancelled]("54d8b28d-b606-40f3-bf72-24f76cacb310")
 }
 
 // TransferStarted is an event indicating that the process of transferring funds
@@ -26,6 +28,9 @@ type TransferStarted struct {
 	ToThirdPartyBank bool
 	Amount           int64
 	ScheduledTime    time.Time
+	ToPayee          *messages.ThirdPartyPayee
+	CustomerID       string
+	PayeeID          string
 }
 
 // TransferApproved is an event that indicates a requested transfer has been
@@ -56,6 +61,25 @@ type TransferFailed struct {
 	Amount        int64
 }
 
+// TransferProceeded is an event that indicates a transfer has reached its
+// scheduled time and may no longer be cancelled.
+type TransferProceeded struct {
+	TransactionID string
+	FromAccountID string
+	ToAccountID   string
+	Amount        int64
+	ScheduledTime time.Time
+}
+
+// TransferCancelled is an event that indicates a scheduled transfer was
+// cancelled before it proceeded.
+type TransferCancelled struct {
+	TransactionID string
+	FromAccountID string
+	ToAccountID   string
+	Amount        int64
+}
+
 // MessageDescription returns a human-readable description of the message.
 func (m *TransferStarted) MessageDescription() string {
 	return fmt.Sprintf(
@@ -107,6 +131,9 @@ func (m *TransferStarted) Validate(dogma.EventValidationScope) error {
 	if m.Amount < 1 {
 		return errors.New("TransferStarted must have a positive amount")
 	}
+	if (m.CustomerID == "") != (m.PayeeID == "") {
+		return errors.New("TransferStarted must have both a customer ID and payee ID, or neither")
+	}
 
 	return nil
 }
@@ -203,6 +230,28 @@ func (m *TransferFailed) MessageDescription() string {
 	)
 }
 
+// MessageDescription returns a human-readable description of the message.
+func (m *TransferProceeded) MessageDescription() string {
+	return fmt.Sprintf(
+		"transfer %s: proceeding with transfer of %s from account %s to account %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.FromAccountID,
+		m.ToAccountID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *TransferCancelled) MessageDescription() string {
+	return fmt.Sprintf(
+		"transfer %s: cancelled transfer of %s from account %s to account %s",
+		m.TransactionID,
+		messages.FormatAmount(m.Amount),
+		m.FromAccountID,
+		m.ToAccountID,
+	)
+}
+
 // Validate returns a non-nil error if the message is invalid.
 func (m *TransferFailed) Validate(dogma.EventValidationScope) error {
 	if m.TransactionID == "" {
@@ -224,6 +273,42 @@ func (m *TransferFailed) Validate(dogma.EventValidationScope) error {
 	return nil
 }
 
+// Validate returns a non-nil error if the message is invalid.
+func (m *TransferProceeded) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("TransferProceeded must not have an empty transaction ID")
+	}
+	if m.FromAccountID == "" {
+		return errors.New("TransferProceeded must not have an empty 'from' account ID")
+	}
+	if m.ToAccountID == "" {
+		return errors.New("TransferProceeded must not have an empty 'to' account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("TransferProceeded must have a positive amount")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *TransferCancelled) Validate(dogma.EventValidationScope) error {
+	if m.TransactionID == "" {
+		return errors.New("TransferCancelled must not have an empty transaction ID")
+	}
+	if m.FromAccountID == "" {
+		return errors.New("TransferCancelled must not have an empty 'from' account ID")
+	}
+	if m.ToAccountID == "" {
+		return errors.New("TransferCancelled must not have an empty 'to' account ID")
+	}
+	if m.Amount < 1 {
+		return errors.New("TransferCancelled must have a positive amount")
+	}
+
+	return nil
+}
+
 // MarshalBinary returns a binary representation of the message.
 // For simplicity in this example we use JSON.
 func (m *TransferFailed) MarshalBinary() ([]byte, error) {
@@ -235,3 +320,27 @@ func (m *TransferFailed) MarshalBinary() ([]byte, error) {
 func (m *TransferFailed) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, m)
 }
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *TransferProceeded) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *TransferProceeded) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *TransferCancelled) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *TransferCancelled) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+)
+
+func init() {
+	dogma.RegisterEvent[*UsernameReserved]("3e309577-a833-44a4-a733-0bdd9c662ae8")
+	dogma.RegisterEvent[*UsernameReservationDeclined]("37fbda67-46ac-45ca-9ef5-439593130a9d")
+}
+
+// UsernameReserved is an event indicating that a username has been reserved
+// for a customer.
+type UsernameReserved struct {
+	Username   string
+	CustomerID string
+}
+
+// UsernameReservationDeclined is an event indicating that a username could not
+// be reserved for a customer because it is already reserved for another.
+type UsernameReservationDeclined struct {
+	Username   string
+	CustomerID string
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *UsernameReserved) MessageDescription() string {
+	return fmt.Sprintf(
+		"reserved username %s for customer %s",
+		m.Username,
+		m.CustomerID,
+	)
+}
+
+// MessageDescription returns a human-readable description of the message.
+func (m *UsernameReservationDeclined) MessageDescription() string {
+	return fmt.Sprintf(
+		"declined to reserve username %s for customer %s: already taken",
+		m.Username,
+		m.CustomerID,
+	)
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *UsernameReserved) Validate(dogma.EventValidationScope) error {
+	if m.Username == "" {
+		return errors.New("UsernameReserved must not have an empty username")
+	}
+	if m.CustomerID == "" {
+		return errors.New("UsernameReserved must not have an empty customer ID")
+	}
+
+	return nil
+}
+
+// Validate returns a non-nil error if the message is invalid.
+func (m *UsernameReservationDeclined) Validate(dogma.EventValidationScope) error {
+	if m.Username == "" {
+		return errors.New("UsernameReservationDeclined must not have an empty username")
+	}
+	if m.CustomerID == "" {
+		return errors.New("UsernameReservationDeclined must not have an empty customer ID")
+	}
+
+	return nil
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *UsernameReserved) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *UsernameReserved) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// MarshalBinary returns a binary representation of the message.
+// For simplicity in this example we use JSON.
+func (m *UsernameReservationDeclined) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalBinary populates the message from its binary representation.
+// For simplicity in this example we use JSON.
+func (m *UsernameReservationDeclined) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, m)
+}
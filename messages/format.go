@@ -14,3 +14,9 @@ func FormatAmount(v int64) string {
 
 	return fmt.Sprintf(f, v/100, v%100)
 }
+
+// FormatInterestRate formats an annual interest rate in basis points as a
+// percentage.
+func FormatInterestRate(v int64) string {
+	return fmt.Sprintf("%d.%02d%% p.a.", v/100, v%100)
+}
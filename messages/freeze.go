@@ -0,0 +1,27 @@
+package messages
+
+import "fmt"
+
+// FreezeType defines the ways in which an account may be frozen.
+type FreezeType string
+
+const (
+	// DebitFreeze is a freeze that prevents debits from being applied to an
+	// account. Credits continue to be applied as normal.
+	DebitFreeze FreezeType = "debit"
+
+	// FullFreeze is a freeze that prevents debits from being applied to an
+	// account. Credits are queued, and applied when the account is unfrozen.
+	FullFreeze FreezeType = "full"
+)
+
+// Validate return an error if t is not a valid freeze type.
+func (t FreezeType) Validate() error {
+	switch t {
+	case DebitFreeze,
+		FullFreeze:
+		return nil
+	default:
+		return fmt.Errorf("invalid freeze type: %s", string(t))
+	}
+}
@@ -0,0 +1,163 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxPayeeAccountNameLength is the maximum length of a payee's account name.
+const maxPayeeAccountNameLength = 64
+
+// ThirdPartyPayee identifies an account held at a third-party bank.
+type ThirdPartyPayee struct {
+	// BSB is the code of the bank and branch at which the account is held, in
+	// NNN-NNN format.
+	BSB string
+
+	// AccountNumber is the account number within the branch. It has between 6
+	// and 9 digits, the last of which is a check digit.
+	AccountNumber string
+
+	// AccountName is the name of the account as given by the customer, which
+	// is confirmed with the third-party bank before money is sent.
+	AccountName string
+}
+
+// ParseThirdPartyPayee returns the payee with the given details, as entered by
+// a customer.
+//
+// Spaces and dashes are removed from the BSB and account number, and
+// surrounding whitespace from the account name. It returns an error if the
+// result is not a valid payee.
+func ParseThirdPartyPayee(bsb, accountNumber, accountName string) (ThirdPartyPayee, error) {
+	bsb = stripSeparators(bsb)
+	if len(bsb) == 6 {
+		bsb = bsb[:3] + "-" + bsb[3:]
+	}
+
+	p := ThirdPartyPayee{
+		BSB:           bsb,
+		AccountNumber: stripSeparators(accountNumber),
+		AccountName:   strings.Join(strings.Fields(accountName), " "),
+	}
+
+	return p, p.Validate()
+}
+
+// AccountID returns the ID by which the third-party bank identifies the
+// account, which is the digits of the BSB followed by the account number.
+func (p ThirdPartyPayee) AccountID() string {
+	return strings.ReplaceAll(p.BSB, "-", "") + p.AccountNumber
+}
+
+// String returns a human-readable representation of the payee.
+func (p ThirdPartyPayee) String() string {
+	return fmt.Sprintf("%s (BSB %s, account %s)", p.AccountName, p.BSB, p.AccountNumber)
+}
+
+// Validate returns an error if p is not a valid payee.
+//
+// The check digit is chosen so that the digits of the BSB and account number
+// together pass the Luhn algorithm. This catches most mistyped BSBs and account
+// numbers before any money is sent.
+func (p ThirdPartyPayee) Validate() error {
+	if len(p.BSB) != 7 || p.BSB[3] != '-' || !isDigits(p.BSB[:3]) || !isDigits(p.BSB[4:]) {
+		return errors.New("BSB must be six digits in NNN-NNN format")
+	}
+
+	if n := len(p.AccountNumber); n < 6 || n > 9 || !isDigits(p.AccountNumber) {
+		return errors.New("account number must be between 6 and 9 digits")
+	}
+
+	if !luhn(p.AccountID()) {
+		return errors.New("account number does not match the BSB")
+	}
+
+	if p.AccountName == "" {
+		return errors.New("account name must not be empty")
+	}
+
+	if len(p.AccountName) > maxPayeeAccountNameLength {
+		return fmt.Errorf("account name must not be longer than %d characters", maxPayeeAccountNameLength)
+	}
+
+	return nil
+}
+
+// PayeeCheckResult is the outcome of confirming the account name of a
+// [ThirdPartyPayee] with the third-party bank.
+type PayeeCheckResult string
+
+const (
+	// PayeeMatched means that the account name matches the name of the account
+	// held at the third-party bank.
+	PayeeMatched PayeeCheckResult = "matched"
+
+	// PayeeCloselyMatched means that the account name is similar to, but not
+	// the same as, the name of the account held at the third-party bank.
+	PayeeCloselyMatched PayeeCheckResult = "closely matched"
+
+	// PayeeNotMatched means that the account name does not match the name of
+	// the account, or that the third-party bank has no such account.
+	PayeeNotMatched PayeeCheckResult = "not matched"
+
+	// PayeeCheckUnavailable means that the account name could not be checked.
+	PayeeCheckUnavailable PayeeCheckResult = "unavailable"
+)
+
+// Validate return an error if r is not a valid result.
+func (r PayeeCheckResult) Validate() error {
+	switch r {
+	case PayeeMatched,
+		PayeeCloselyMatched,
+		PayeeNotMatched,
+		PayeeCheckUnavailable:
+		return nil
+	default:
+		return fmt.Errorf("invalid payee check result: %s", string(r))
+	}
+}
+
+// stripSeparators returns s with all spaces and dashes removed.
+func stripSeparators(s string) string {
+	return strings.Map(
+		func(r rune) rune {
+			if r == ' ' || r == '-' {
+				return -1
+			}
+			return r
+		},
+		s,
+	)
+}
+
+// isDigits returns true if s consists only of the digits 0-9.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// luhn returns true if the digits in s pass the Luhn algorithm.
+func luhn(s string) bool {
+	sum := 0
+	double := false
+
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,30 @@
+package messages
+
+import "fmt"
+
+// Frequency defines how often a standing order makes a payment.
+type Frequency string
+
+const (
+	// Weekly is a frequency of one payment every 7 days.
+	Weekly Frequency = "weekly"
+
+	// Fortnightly is a frequency of one payment every 14 days.
+	Fortnightly Frequency = "fortnightly"
+
+	// Monthly is a frequency of one payment on the same day of each month, or
+	// the last day of the month if it is shorter.
+	Monthly Frequency = "monthly"
+)
+
+// Validate return an error if f is not a valid frequency.
+func (f Frequency) Validate() error {
+	switch f {
+	case Weekly,
+		Fortnightly,
+		Monthly:
+		return nil
+	default:
+		return fmt.Errorf("invalid frequency: %s", string(f))
+	}
+}
@@ -17,6 +17,22 @@ const (
 
 	// Transfer is a transfer transaction type.
 	Transfer TransactionType = "transfer"
+
+	// AccountClosure is the transaction type used to sweep the remaining
+	// balance out of an account when it is closed.
+	AccountClosure TransactionType = "account closure"
+
+	// Interest is the transaction type used to pay the interest accrued on an
+	// account's balance.
+	Interest TransactionType = "interest"
+
+	// ThirdPartyPayment is the transaction type used to credit an account
+	// with a payment received from a third-party bank.
+	ThirdPartyPayment TransactionType = "third-party payment"
+
+	// CardPayment is the transaction type used for a card payment that is
+	// authorized as a hold on an account and captured later.
+	CardPayment TransactionType = "card payment"
 )
 
 // IsDebit returns true if the transaction type is a debit type.
@@ -26,6 +42,8 @@ func (t TransactionType) IsDebit() bool {
 		return true
 	case Transfer:
 		return true
+	case CardPayment:
+		return true
 	}
 
 	return false
@@ -36,7 +54,11 @@ func (t TransactionType) Validate() error {
 	switch t {
 	case Deposit,
 		Withdrawal,
-		Transfer:
+		Transfer,
+		AccountClosure,
+		Interest,
+		ThirdPartyPayment,
+		CardPayment:
 		return nil
 	default:
 		return fmt.Errorf("invalid transaction type: %s", string(t))
@@ -54,13 +76,45 @@ const (
 	// DailyDebitLimitExceeded means that the debit cannot be performed
 	// because it will exceed the account daily debit limit.
 	DailyDebitLimitExceeded DebitFailureReason = "daily debit limit exceeded"
+
+	// AccountClosed means that the debit (or credit) cannot be performed
+	// because the account has been closed.
+	AccountClosed DebitFailureReason = "account closed"
+
+	// AccountFrozen means that the debit cannot be performed because the
+	// account has been frozen.
+	AccountFrozen DebitFailureReason = "account frozen"
+
+	// WithdrawalLimitExceeded means that the debit cannot be performed because
+	// the account has reached its limit on the number of withdrawals that may
+	// be made each month.
+	WithdrawalLimitExceeded DebitFailureReason = "monthly withdrawal limit exceeded"
+
+	// TermDepositNotMatured means that the debit cannot be performed because
+	// the account is a term deposit that has not yet reached its maturity
+	// date.
+	TermDepositNotMatured DebitFailureReason = "term deposit has not matured"
+
+	// AccountNotFound means that the credit cannot be performed because the
+	// account has never been opened.
+	AccountNotFound DebitFailureReason = "account not found"
+
+	// PayeeCoolingOffLimitExceeded means that the debit cannot be performed
+	// because it would exceed the limit on transfers to a newly added payee.
+	PayeeCoolingOffLimitExceeded DebitFailureReason = "payee cooling-off limit exceeded"
 )
 
 // Validate return an error if r is not a valid reason.
 func (r DebitFailureReason) Validate() error {
 	switch r {
 	case InsufficientFunds,
-		DailyDebitLimitExceeded:
+		DailyDebitLimitExceeded,
+		AccountClosed,
+		AccountFrozen,
+		WithdrawalLimitExceeded,
+		TermDepositNotMatured,
+		AccountNotFound,
+		PayeeCoolingOffLimitExceeded:
 		return nil
 	default:
 		return fmt.Errorf("invalid debit failure reason: %s", string(r))
@@ -7,23 +7,42 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dogmatiq/example/messages"
 	"github.com/dogmatiq/example/messages/commands"
 	"github.com/dogmatiq/example/ui/templates"
 )
 
 // account is a summary of a bank account as displayed in the accounts list.
 type account struct {
-	ID      string
-	Name    string
-	Balance money
+	ID              string
+	CustomerID      string
+	Name            string
+	AccountType     string
+	MaturityDate    string
+	Balance         money
+	HeldAmount      money
+	DailyDebitLimit money
+	OverdraftLimit  money
+	InterestRate    interestRate
+	IsClosed        bool
+	FreezeType      string
+	FreezeReason    string
+}
+
+// AvailableFunds returns the total amount that may be debited from the account,
+// including any arranged overdraft, less any funds held for card payments.
+func (a account) AvailableFunds() money {
+	return a.Balance - a.HeldAmount + a.OverdraftLimit
 }
 
 // accountsFragment holds the data needed to render the accounts list table.
-// It is used both as a standalone HTMX response and composed into the full page.
+// It is used both as a server-sent event and composed into the full page.
 type accountsFragment struct {
-	CustomerID string
-	Accounts   []account
+	CustomerID     string
+	Accounts       []account
+	ClosedAccounts []account
 }
 
 // renderAccountsPage renders the full page showing a customer's accounts.
@@ -36,7 +55,7 @@ func (h *Handler) renderAccountsPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accounts, err := h.queryAccounts(r.Context(), customerID)
+	fragment, err := h.queryAccountsFragment(r.Context(), customerID)
 	if err != nil {
 		renderError(w, http.StatusNotFound)
 		return
@@ -50,11 +69,9 @@ func (h *Handler) renderAccountsPage(w http.ResponseWriter, r *http.Request) {
 			Title:        "Your Accounts",
 			CustomerID:   customerID,
 			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
 		},
-		AccountsFragment: accountsFragment{
-			CustomerID: customerID,
-			Accounts:   accounts,
-		},
+		AccountsFragment: fragment,
 	}
 
 	if err := templates.Get("accounts").ExecuteTemplate(w, "accounts.html", data); err != nil {
@@ -62,26 +79,18 @@ func (h *Handler) renderAccountsPage(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// renderAccountsFragment renders only the accounts table, without the
-// surrounding page layout. HTMX polls this endpoint to update the table
-// without a full page reload.
-func (h *Handler) renderAccountsFragment(w http.ResponseWriter, r *http.Request) {
-	customerID := r.PathValue("customerID")
-
-	accounts, err := h.queryAccounts(r.Context(), customerID)
+// queryAccountsFragment loads the data needed to render the accounts table.
+func (h *Handler) queryAccountsFragment(ctx context.Context, customerID string) (accountsFragment, error) {
+	accounts, closedAccounts, err := h.queryAccounts(ctx, customerID)
 	if err != nil {
-		renderError(w, http.StatusNotFound)
-		return
+		return accountsFragment{}, err
 	}
 
-	data := accountsFragment{
-		CustomerID: customerID,
-		Accounts:   accounts,
-	}
-
-	if err := templates.Get("accounts").ExecuteTemplate(w, "accounts-fragment", data); err != nil {
-		renderError(w, http.StatusInternalServerError, err.Error())
-	}
+	return accountsFragment{
+		CustomerID:     customerID,
+		Accounts:       accounts,
+		ClosedAccounts: closedAccounts,
+	}, nil
 }
 
 // renderOpenAccountPage renders the form for opening a new account.
@@ -106,6 +115,7 @@ func (h *Handler) renderOpenAccount(w http.ResponseWriter, r *http.Request, form
 			Title:        "Open a New Account",
 			CustomerID:   customerID,
 			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
 		},
 		Error: formError,
 	}
@@ -130,14 +140,22 @@ func (h *Handler) openAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accountID := generateAccountID()
+	accountType, maturityDate, formError := parseAccountType(r)
+	if formError != "" {
+		h.renderOpenAccount(w, r, formError)
+		return
+	}
+
+	accountID := idempotentAccountID(r)
 
 	err := h.CommandExecutor.ExecuteCommand(
 		r.Context(),
 		&commands.OpenAccount{
-			CustomerID:  customerID,
-			AccountID:   accountID,
-			AccountName: accountName,
+			CustomerID:   customerID,
+			AccountID:    accountID,
+			AccountName:  accountName,
+			AccountType:  accountType,
+			MaturityDate: maturityDate,
 		},
 	)
 	if err != nil {
@@ -148,120 +166,94 @@ func (h *Handler) openAccount(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts", customerID), http.StatusSeeOther)
 }
 
-// queryAccounts loads the accounts belonging to a specific customer.
-func (h *Handler) queryAccounts(ctx context.Context, customerID string) ([]account, error) {
+// queryAccounts loads the accounts belonging to a specific customer, separating
+// open accounts from those that have been closed.
+func (h *Handler) queryAccounts(ctx context.Context, customerID string) (open, closed []account, err error) {
 	rows, err := h.DB.QueryContext(
 		ctx,
 		`SELECT
 			id,
+			customer_id,
 			name,
-			balance
+			account_type,
+			maturity_date,
+			balance,
+			held_amount,
+			daily_debit_limit,
+			overdraft_limit,
+			interest_rate,
+			is_closed,
+			freeze_type,
+			freeze_reason
 		FROM accounts
 		WHERE customer_id = ?
 		ORDER BY name`,
 		customerID,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
-	var accounts []account
 	for rows.Next() {
 		var a account
 
 		if err := rows.Scan(
 			&a.ID,
+			&a.CustomerID,
 			&a.Name,
+			&a.AccountType,
+			&a.MaturityDate,
 			&a.Balance,
+			&a.HeldAmount,
+			&a.DailyDebitLimit,
+			&a.OverdraftLimit,
+			&a.InterestRate,
+			&a.IsClosed,
+			&a.FreezeType,
+			&a.FreezeReason,
 		); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		accounts = append(accounts, a)
+		if a.IsClosed {
+			closed = append(closed, a)
+		} else {
+			open = append(open, a)
+		}
 	}
 
-	return accounts, rows.Err()
+	return open, closed, rows.Err()
 }
 
-// accountGroup is a group of accounts belonging to a single customer, used to
-// populate optgroups in the transfer destination picker.
-type accountGroup struct {
-	CustomerID   string
-	CustomerName string
-	IsOwn        bool
-	Accounts     []account
+// parseAccountType parses the account type and maturity date submitted by the
+// signup and open account forms. It returns a non-empty form error if either is
+// invalid.
+func parseAccountType(r *http.Request) (t messages.AccountType, maturityDate, formError string) {
+	return checkAccountType(
+		messages.AccountType(r.FormValue("account_type")),
+		r.FormValue("maturity_date"),
+	)
 }
 
-// queryAllAccountsGrouped loads every account in the system grouped by
-// customer, excluding excludeAccountID. The group for currentCustomerID is
-// labelled "My Accounts" and appears first.
-func (h *Handler) queryAllAccountsGrouped(ctx context.Context, currentCustomerID, excludeAccountID string) ([]accountGroup, error) {
-	rows, err := h.DB.QueryContext(
-		ctx,
-		`SELECT
-			a.id,
-			a.name,
-			a.balance,
-			a.customer_id,
-			c.name
-		FROM accounts AS a
-		INNER JOIN customers AS c
-			ON c.id = a.customer_id
-		WHERE a.id != ?
-		ORDER BY
-			CASE WHEN a.customer_id = ? THEN 0 ELSE 1 END,
-			c.name,
-			a.name`,
-		excludeAccountID,
-		currentCustomerID,
-	)
-	if err != nil {
-		return nil, err
+// checkAccountType validates an account type and maturity date. It returns a
+// non-empty error message if either is invalid. The maturity date is discarded
+// for account types other than term deposits.
+func checkAccountType(t messages.AccountType, maturityDate string) (messages.AccountType, string, string) {
+	if err := t.Validate(); err != nil {
+		return "", "", "Invalid account type."
 	}
-	defer rows.Close()
-
-	var (
-		groups []accountGroup
-		g      *accountGroup
-	)
-
-	for rows.Next() {
-		var (
-			a            account
-			customerID   string
-			customerName string
-		)
 
-		if err := rows.Scan(
-			&a.ID,
-			&a.Name,
-			&a.Balance,
-			&customerID,
-			&customerName,
-		); err != nil {
-			return nil, err
-		}
-
-		if g == nil || g.CustomerID != customerID {
-			label := customerName
-			isOwn := customerID == currentCustomerID
-			if isOwn {
-				label = "My Accounts"
-			}
-
-			groups = append(groups, accountGroup{
-				CustomerID:   customerID,
-				CustomerName: label,
-				IsOwn:        isOwn,
-			})
-			g = &groups[len(groups)-1]
-		}
+	if t != messages.TermDeposit {
+		return t, "", ""
+	}
 
-		g.Accounts = append(g.Accounts, a)
+	d, err := time.Parse(time.DateOnly, maturityDate)
+	if err != nil || !d.After(time.Now()) {
+		return "", "", "Term deposits require a maturity date in the future."
 	}
 
-	return groups, rows.Err()
+	return t, maturityDate, ""
 }
 
 // generateAccountID produces a random 9-digit account number.
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/dogmatiq/example/messages"
+)
+
+// openAPI is the OpenAPI document that describes the JSON API.
+//
+//go:embed openapi.yaml
+var openAPI []byte
+
+// Error codes returned in the body of JSON API error responses. These values
+// are part of the API contract and must not change.
+const (
+	apiErrorNotFound             = "not_found"
+	apiErrorInvalidRequest       = "invalid_request"
+	apiErrorUnauthorized         = "unauthorized"
+	apiErrorForbidden            = "forbidden"
+	apiErrorUsernameTaken        = "username_taken"
+	apiErrorUnsupported          = "unsupported_media_type"
+	apiErrorTooLarge             = "request_too_large"
+	apiErrorIdempotencyKeyReused = "idempotency_key_reused"
+	apiErrorTransferFailed       = "transfer_failed"
+	apiErrorInternal             = "internal_error"
+)
+
+// apiErrorCodes maps each debit failure reason to its stable API error code.
+var apiErrorCodes = map[messages.DebitFailureReason]string{
+	messages.InsufficientFunds:            "insufficient_funds",
+	messages.DailyDebitLimitExceeded:      "daily_debit_limit_exceeded",
+	messages.AccountClosed:                "account_closed",
+	messages.AccountFrozen:                "account_frozen",
+	messages.WithdrawalLimitExceeded:      "withdrawal_limit_exceeded",
+	messages.TermDepositNotMatured:        "term_deposit_not_matured",
+	messages.PayeeCoolingOffLimitExceeded: "payee_cooling_off_limit_exceeded",
+}
+
+// apiError is the body of a JSON API error response.
+type apiError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// apiTransaction is the body of a JSON API response to a request that starts a
+// new transaction, such as a deposit, withdrawal or transfer.
+type apiTransaction struct {
+	TransactionID string `json:"transaction_id"`
+	Status        string `json:"status"`
+}
+
+// Transaction statuses returned by the JSON API.
+const (
+	// apiStatusApproved means the transaction has completed successfully.
+	apiStatusApproved = "approved"
+
+	// apiStatusPending means the transaction has been accepted, but is
+	// scheduled for a later time or has not yet been approved or declined.
+	apiStatusPending = "pending"
+)
+
+// writeJSON writes v as a JSON response with the given HTTP status code.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v) // nolint:errcheck
+}
+
+// writeAPIError writes a JSON error response.
+func writeAPIError(w http.ResponseWriter, code int, errorCode, message string) {
+	var body apiError
+	body.Error.Code = errorCode
+	body.Error.Message = message
+
+	writeJSON(w, code, body)
+}
+
+// writeAPIDecline writes a JSON error response indicating that a transaction
+// was declined for the given reason.
+func writeAPIDecline(w http.ResponseWriter, reason messages.DebitFailureReason) {
+	code, ok := apiErrorCodes[reason]
+	if !ok {
+		code = apiErrorInternal
+	}
+
+	writeAPIError(w, http.StatusUnprocessableEntity, code, string(reason))
+}
+
+// writeTransactionOutcome writes the JSON response to a request that started a
+// transaction, unless the transaction was declined or failed.
+func writeTransactionOutcome(w http.ResponseWriter, transactionID string, approved bool) {
+	if approved {
+		writeJSON(w, http.StatusCreated, apiTransaction{transactionID, apiStatusApproved})
+	} else {
+		writeJSON(w, http.StatusAccepted, apiTransaction{transactionID, apiStatusPending})
+	}
+}
+
+// decodeJSON decodes the JSON body of r into v. It writes an error response
+// and returns false if the body is not valid.
+//
+// The request must have a JSON content type. Browsers do not allow other
+// sites to send such requests, which protects the API from cross-site request
+// forgery when the session cookie is used for authentication.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if t, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); t != "application/json" {
+		writeAPIError(
+			w,
+			http.StatusUnsupportedMediaType,
+			apiErrorUnsupported,
+			"request body must be application/json",
+		)
+		return false
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); errors.As(err, new(*http.MaxBytesError)) {
+		writeAPIError(
+			w,
+			http.StatusRequestEntityTooLarge,
+			apiErrorTooLarge,
+			"request body is too large",
+		)
+		return false
+	} else if err != nil {
+		writeAPIError(
+			w,
+			http.StatusBadRequest,
+			apiErrorInvalidRequest,
+			fmt.Sprintf("request body is not valid: %s", err),
+		)
+		return false
+	}
+
+	return true
+}
+
+// serveOpenAPI serves the OpenAPI document that describes the JSON API.
+func (h *Handler) serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(openAPI) // nolint:errcheck
+}
+
+// apiNotFound writes a JSON error response for an unrecognized API route.
+func (h *Handler) apiNotFound(w http.ResponseWriter, _ *http.Request) {
+	writeAPIError(w, http.StatusNotFound, apiErrorNotFound, "no such API endpoint")
+}
@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+)
+
+// apiAccount is the JSON representation of an account. All amounts are in
+// cents.
+type apiAccount struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	AccountType     string `json:"account_type"`
+	MaturityDate    string `json:"maturity_date,omitempty"`
+	Balance         int64  `json:"balance"`
+	HeldAmount      int64  `json:"held_amount"`
+	AvailableFunds  int64  `json:"available_funds"`
+	DailyDebitLimit int64  `json:"daily_debit_limit"`
+	OverdraftLimit  int64  `json:"overdraft_limit"`
+	InterestRate    int64  `json:"interest_rate"`
+	IsClosed        bool   `json:"is_closed"`
+	FreezeType      string `json:"freeze_type,omitempty"`
+	FreezeReason    string `json:"freeze_reason,omitempty"`
+}
+
+// apiLedgerEntry is the JSON representation of an entry in an account's
+// ledger. All amounts are in cents.
+type apiLedgerEntry struct {
+	OccurredAt  time.Time `json:"occurred_at"`
+	Description string    `json:"description"`
+	Debit       int64     `json:"debit"`
+	Credit      int64     `json:"credit"`
+	Balance     int64     `json:"balance"`
+}
+
+// apiNewAccount is the body of a request to open a new account for an existing
+// customer.
+type apiNewAccount struct {
+	AccountName  string               `json:"account_name"`
+	AccountType  messages.AccountType `json:"account_type"`
+	MaturityDate string               `json:"maturity_date"`
+}
+
+// newAPIAccount returns the JSON representation of a.
+func newAPIAccount(a account) apiAccount {
+	return apiAccount{
+		ID:              a.ID,
+		Name:            a.Name,
+		AccountType:     a.AccountType,
+		MaturityDate:    a.MaturityDate,
+		Balance:         int64(a.Balance),
+		HeldAmount:      int64(a.HeldAmount),
+		AvailableFunds:  int64(a.AvailableFunds()),
+		DailyDebitLimit: int64(a.DailyDebitLimit),
+		OverdraftLimit:  int64(a.OverdraftLimit),
+		InterestRate:    int64(a.InterestRate),
+		IsClosed:        a.IsClosed,
+		FreezeType:      a.FreezeType,
+		FreezeReason:    a.FreezeReason,
+	}
+}
+
+// apiListAccounts responds with a list of a customer's accounts, including
+// those that have been closed.
+func (h *Handler) apiListAccounts(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+
+	if !h.apiCheckCustomer(w, r, customerID) {
+		return
+	}
+
+	open, closed, err := h.queryAccounts(r.Context(), customerID)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	result := []apiAccount{}
+	for _, a := range append(open, closed...) {
+		result = append(result, newAPIAccount(a))
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// apiGetAccount responds with a single account.
+func (h *Handler) apiGetAccount(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.apiQueryAccount(w, r, r.PathValue("customerID"), r.PathValue("accountID"))
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAPIAccount(a))
+}
+
+// apiOpenAccount opens a new account for an existing customer. The account ID
+// is derived from the request's idempotency key, if any, so a retried request
+// responds with the original account.
+func (h *Handler) apiOpenAccount(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+
+	if !h.apiCheckCustomer(w, r, customerID) {
+		return
+	}
+
+	var req apiNewAccount
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	accountName := strings.TrimSpace(req.AccountName)
+	if accountName == "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrorInvalidRequest, "account_name is required")
+		return
+	}
+
+	accountType, maturityDate, message := checkAccountType(req.AccountType, req.MaturityDate)
+	if message != "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrorInvalidRequest, message)
+		return
+	}
+
+	accountID := idempotentAccountID(r)
+
+	if err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.OpenAccount{
+			CustomerID:   customerID,
+			AccountID:    accountID,
+			AccountName:  accountName,
+			AccountType:  accountType,
+			MaturityDate: maturityDate,
+		},
+	); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	a, ok := h.apiQueryAccount(w, r, customerID, accountID)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newAPIAccount(a))
+}
+
+// apiListLedgerEntries responds with a page of an account's ledger entries,
+// most recent first, filtered by the request's query parameters.
+//
+// If there are more entries, the URL of the following page is given in a
+// "Link" header with the "next" relation.
+func (h *Handler) apiListLedgerEntries(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.apiQueryAccount(w, r, r.PathValue("customerID"), r.PathValue("accountID"))
+	if !ok {
+		return
+	}
+
+	filter, message := parseTransactionFilter(r.URL.Query())
+	if message != "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrorInvalidRequest, message)
+		return
+	}
+
+	transactions, next, err := h.queryTransactions(r.Context(), a.ID, filter)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	if next != "" {
+		filter.Before = next
+		w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, filter.Query()))
+	}
+
+	result := []apiLedgerEntry{}
+	for _, t := range transactions {
+		result = append(result, apiLedgerEntry{
+			OccurredAt:  t.OccurredAt,
+			Description: t.Description,
+			Debit:       int64(t.Debit),
+			Credit:      int64(t.Credit),
+			Balance:     int64(t.Balance),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// apiQueryAccount returns the details of an account owned by the given
+// customer. It writes an error response and returns false if there is no such
+// account.
+func (h *Handler) apiQueryAccount(
+	w http.ResponseWriter,
+	r *http.Request,
+	customerID, accountID string,
+) (account, bool) {
+	a, err := h.queryAccountDetails(r.Context(), accountID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && a.CustomerID != customerID) {
+		writeAPIError(w, http.StatusNotFound, apiErrorNotFound, "account not found")
+		return account{}, false
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return account{}, false
+	}
+
+	return a, true
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+)
+
+// apiCustomer is the JSON representation of a customer.
+type apiCustomer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// apiNewCustomer is the body of a request to acquire a new customer by opening
+// their first account.
+type apiNewCustomer struct {
+	CustomerName string               `json:"customer_name"`
+	Username     string               `json:"username"`
+	Password     string               `json:"password"`
+	AccountName  string               `json:"account_name"`
+	AccountType  messages.AccountType `json:"account_type"`
+	MaturityDate string               `json:"maturity_date"`
+}
+
+// apiNewCustomerResult is the body of the response to a request to acquire a
+// new customer.
+type apiNewCustomerResult struct {
+	CustomerID string `json:"customer_id"`
+	AccountID  string `json:"account_id"`
+}
+
+// apiGetCustomer responds with a single customer.
+func (h *Handler) apiGetCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+
+	name, err := h.queryCustomerName(r.Context(), customerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeAPIError(w, http.StatusNotFound, apiErrorNotFound, "customer not found")
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiCustomer{customerID, name})
+}
+
+// apiCreateCustomer acquires a new customer and opens their first account. It
+// is the API equivalent of the signup form.
+//
+// The customer and account IDs are derived from the request's idempotency key,
+// if any, so a retried request responds with the IDs of the original customer.
+// The credentials are not replaced by a retry, so the retry must have the same
+// username and password as the original request.
+func (h *Handler) apiCreateCustomer(w http.ResponseWriter, r *http.Request) {
+	var req apiNewCustomer
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	customerName := strings.TrimSpace(req.CustomerName)
+	accountName := strings.TrimSpace(req.AccountName)
+	username := normalizeUsername(req.Username)
+
+	if customerName == "" || accountName == "" || username == "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrorInvalidRequest, "customer_name, username and account_name are required")
+		return
+	}
+
+	if message := checkPasswordLength(req.Password); message != "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrorInvalidRequest, message)
+		return
+	}
+
+	accountType, maturityDate, message := checkAccountType(req.AccountType, req.MaturityDate)
+	if message != "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrorInvalidRequest, message)
+		return
+	}
+
+	result := apiNewCustomerResult{
+		CustomerID: idempotentID(r, "customer"),
+		AccountID:  idempotentAccountID(r),
+	}
+
+	if reserved, err := h.reserveUsername(r.Context(), username, result.CustomerID); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	} else if !reserved {
+		writeAPIError(w, http.StatusConflict, apiErrorUsernameTaken, "username is already taken")
+		return
+	}
+
+	if err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.OpenAccountForNewCustomer{
+			CustomerID:   result.CustomerID,
+			CustomerName: customerName,
+			AccountID:    result.AccountID,
+			AccountName:  accountName,
+			AccountType:  accountType,
+			MaturityDate: maturityDate,
+		},
+	); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	if err := h.setCredentials(r.Context(), result.CustomerID, username, req.Password); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	storedID, ok, err := h.checkCredentials(r.Context(), username, req.Password)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+	if !ok || storedID != result.CustomerID {
+		writeAPIError(w, http.StatusUnprocessableEntity, apiErrorIdempotencyKeyReused, "idempotency key has already been used with different credentials")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, result)
+}
+
+// apiCheckCustomer returns true if the given customer exists. Otherwise, it
+// writes an error response and returns false.
+func (h *Handler) apiCheckCustomer(w http.ResponseWriter, r *http.Request, customerID string) bool {
+	_, err := h.queryCustomerName(r.Context(), customerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeAPIError(w, http.StatusNotFound, apiErrorNotFound, "customer not found")
+		return false
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return false
+	}
+
+	return true
+}
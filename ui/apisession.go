@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"net/http"
+)
+
+// apiLogInRequest is the body of a request to log in to the JSON API.
+type apiLogInRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// apiSession is the body of the response to a successful login.
+type apiSession struct {
+	CustomerID string `json:"customer_id"`
+	Token      string `json:"token"`
+}
+
+// apiLogIn checks a customer's password and starts a new session.
+//
+// The session token is returned in the response body, for use as a bearer
+// token, and is also set as the session cookie.
+func (h *Handler) apiLogIn(w http.ResponseWriter, r *http.Request) {
+	var req apiLogInRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	customerID, ok, err := h.checkCredentials(r.Context(), normalizeUsername(req.Username), req.Password)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+	if !ok {
+		writeAPIError(w, http.StatusUnauthorized, apiErrorUnauthorized, "incorrect username or password")
+		return
+	}
+
+	token, err := h.startSession(w, r, customerID)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, apiSession{customerID, token})
+}
+
+// apiLogOut ends the current session.
+func (h *Handler) apiLogOut(w http.ResponseWriter, r *http.Request) {
+	if err := h.endSession(w, r); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+// apiDepositRequest is the body of a request to deposit funds into an account.
+type apiDepositRequest struct {
+	Amount int64 `json:"amount"`
+}
+
+// apiWithdrawalRequest is the body of a request to withdraw funds from an
+// account.
+type apiWithdrawalRequest struct {
+	Amount        int64      `json:"amount"`
+	ScheduledTime *time.Time `json:"scheduled_time"`
+}
+
+// apiTransferRequest is the body of a request to transfer funds from one
+// account to another. The destination is either another of the customer's own
+// accounts, or one of their payees.
+type apiTransferRequest struct {
+	ToAccountID   string     `json:"to_account_id"`
+	PayeeID       string     `json:"payee_id"`
+	Amount        int64      `json:"amount"`
+	ScheduledTime *time.Time `json:"scheduled_time"`
+}
+
+// apiDeposit deposits funds into an account.
+func (h *Handler) apiDeposit(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.apiQueryAccount(w, r, r.PathValue("customerID"), r.PathValue("accountID"))
+	if !ok {
+		return
+	}
+
+	var req apiDepositRequest
+	if !decodeJSON(w, r, &req) || !checkAPIAmount(w, req.Amount) {
+		return
+	}
+
+	var (
+		transactionID = idempotentID(r, "transaction")
+		approved      bool
+		declined      messages.DebitFailureReason
+	)
+
+	err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.Deposit{
+			TransactionID: transactionID,
+			AccountID:     a.ID,
+			Amount:        req.Amount,
+		},
+		dogma.WithEventObserver(func(context.Context, *events.DepositApproved) (bool, error) {
+			approved = true
+			return true, nil
+		}),
+		dogma.WithEventObserver(func(_ context.Context, e *events.DepositDeclined) (bool, error) {
+			declined = e.Reason
+			return true, nil
+		}),
+	)
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		o, err := h.queryOriginalOutcome(r.Context(), transactionID, transactionOutcome{
+			TransactionType: "deposit",
+			AccountID:       a.ID,
+			Amount:          req.Amount,
+		})
+		if !apiCheckOriginalOutcome(w, err) {
+			return
+		}
+
+		approved = o.Status == "approved"
+		declined = o.Reason
+	} else if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	if declined != "" {
+		writeAPIDecline(w, declined)
+		return
+	}
+
+	writeTransactionOutcome(w, transactionID, approved)
+}
+
+// apiWithdraw withdraws funds from an account.
+func (h *Handler) apiWithdraw(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.apiQueryAccount(w, r, r.PathValue("customerID"), r.PathValue("accountID"))
+	if !ok {
+		return
+	}
+
+	var req apiWithdrawalRequest
+	if !decodeJSON(w, r, &req) || !checkAPIAmount(w, req.Amount) {
+		return
+	}
+
+	var (
+		transactionID = idempotentID(r, "transaction")
+		approved      bool
+		declined      messages.DebitFailureReason
+	)
+
+	err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.Withdraw{
+			TransactionID: transactionID,
+			AccountID:     a.ID,
+			Amount:        req.Amount,
+			ScheduledTime: apiScheduledTime(req.ScheduledTime),
+		},
+		dogma.WithEventObserver(func(context.Context, *events.WithdrawalApproved) (bool, error) {
+			approved = true
+			return true, nil
+		}),
+		dogma.WithEventObserver(func(_ context.Context, e *events.WithdrawalDeclined) (bool, error) {
+			declined = e.Reason
+			return true, nil
+		}),
+	)
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		o, err := h.queryOriginalOutcome(r.Context(), transactionID, transactionOutcome{
+			TransactionType: "withdrawal",
+			AccountID:       a.ID,
+			Amount:          req.Amount,
+		})
+		if !apiCheckOriginalOutcome(w, err) {
+			return
+		}
+
+		approved = o.Status == "approved"
+		declined = o.Reason
+	} else if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	if declined != "" {
+		writeAPIDecline(w, declined)
+		return
+	}
+
+	writeTransactionOutcome(w, transactionID, approved)
+}
+
+// apiTransfer transfers funds from one account to another.
+func (h *Handler) apiTransfer(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.apiQueryAccount(w, r, r.PathValue("customerID"), r.PathValue("accountID"))
+	if !ok {
+		return
+	}
+
+	var req apiTransferRequest
+	if !decodeJSON(w, r, &req) || !checkAPIAmount(w, req.Amount) {
+		return
+	}
+
+	if (req.ToAccountID == "") == (req.PayeeID == "") {
+		writeAPIError(w, http.StatusBadRequest, apiErrorInvalidRequest, "exactly one of to_account_id and payee_id is required")
+		return
+	}
+
+	var (
+		to  destination
+		err error
+	)
+
+	if req.PayeeID != "" {
+		to, err = h.queryPayeeDestination(r.Context(), a.CustomerID, a.ID, req.PayeeID)
+	} else {
+		to, err = h.queryAccountDestination(r.Context(), a.CustomerID, a.ID, req.ToAccountID)
+	}
+	if errors.Is(err, errInvalidDestination) {
+		writeAPIError(w, http.StatusBadRequest, apiErrorInvalidRequest, "destination account not found")
+		return
+	} else if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	var (
+		transactionID = idempotentID(r, "transaction")
+		approved      bool
+		failed        bool
+		declined      messages.DebitFailureReason
+	)
+
+	c := &commands.Transfer{
+		TransactionID:    transactionID,
+		FromAccountID:    a.ID,
+		ToAccountID:      to.AccountID,
+		ToThirdPartyBank: to.ToThirdPartyBank,
+		ToPayee:          to.Payee,
+		Amount:           req.Amount,
+		ScheduledTime:    apiScheduledTime(req.ScheduledTime),
+	}
+
+	if to.PayeeID != "" {
+		c.CustomerID = a.CustomerID
+		c.PayeeID = to.PayeeID
+	}
+
+	err = h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		c,
+		dogma.WithEventObserver(func(context.Context, *events.TransferApproved) (bool, error) {
+			approved = true
+			return true, nil
+		}),
+		dogma.WithEventObserver(func(_ context.Context, e *events.TransferDeclined) (bool, error) {
+			declined = e.Reason
+			return true, nil
+		}),
+		dogma.WithEventObserver(func(context.Context, *events.TransferFailed) (bool, error) {
+			failed = true
+			return true, nil
+		}),
+	)
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		o, err := h.queryOriginalOutcome(r.Context(), transactionID, transactionOutcome{
+			TransactionType: "transfer",
+			AccountID:       a.ID,
+			ToAccountID:     to.AccountID,
+			Amount:          req.Amount,
+		})
+		if !apiCheckOriginalOutcome(w, err) {
+			return
+		}
+
+		approved = o.Status == "approved"
+		declined = o.Reason
+		failed = o.Status == "failed"
+	} else if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return
+	}
+
+	if declined != "" {
+		writeAPIDecline(w, declined)
+		return
+	}
+
+	if failed {
+		writeAPIError(w, http.StatusUnprocessableEntity, apiErrorTransferFailed, "transfer failed")
+		return
+	}
+
+	writeTransactionOutcome(w, transactionID, approved)
+}
+
+// apiCheckOriginalOutcome returns true if err, as returned by
+// queryOriginalOutcome, is nil. Otherwise, it writes an error response and
+// returns false.
+func apiCheckOriginalOutcome(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, errIdempotencyKeyReused) {
+		writeAPIError(w, http.StatusUnprocessableEntity, apiErrorIdempotencyKeyReused, err.Error())
+		return false
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+		return false
+	}
+
+	return true
+}
+
+// checkAPIAmount returns true if amount is a valid transaction amount.
+// Otherwise, it writes an error response and returns false.
+func checkAPIAmount(w http.ResponseWriter, amount int64) bool {
+	if amount > 0 {
+		return true
+	}
+
+	writeAPIError(w, http.StatusBadRequest, apiErrorInvalidRequest, "amount must be a positive number of cents")
+	return false
+}
+
+// apiScheduledTime returns the time at which a transaction should occur, given
+// the optional scheduled time supplied by the client.
+func apiScheduledTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Now()
+	}
+	return *t
+}
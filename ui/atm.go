@@ -10,7 +10,6 @@ import (
 	"github.com/dogmatiq/example/messages/commands"
 	"github.com/dogmatiq/example/messages/events"
 	"github.com/dogmatiq/example/ui/templates"
-	"github.com/google/uuid"
 )
 
 // renderATMPage renders the virtual ATM page with deposit and withdrawal forms.
@@ -28,7 +27,7 @@ func (h *Handler) renderATM(w http.ResponseWriter, r *http.Request, formError st
 		return
 	}
 
-	accountName, balance, err := h.queryAccountDetails(r.Context(), accountID)
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
 	if err != nil {
 		renderError(w, http.StatusNotFound)
 		return
@@ -39,16 +38,19 @@ func (h *Handler) renderATM(w http.ResponseWriter, r *http.Request, formError st
 		AccountID   string
 		AccountName string
 		Balance     money
+		IsFrozen    bool
 		Error       string
 	}{
 		pageData: pageData{
-			Title:        "Virtual ATM: " + accountName,
+			Title:        "Virtual ATM: " + acct.Name,
 			CustomerID:   customerID,
 			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
 		},
 		AccountID:   accountID,
-		AccountName: accountName,
-		Balance:     money(balance),
+		AccountName: acct.Name,
+		Balance:     acct.Balance,
+		IsFrozen:    acct.FreezeType != "",
 		Error:       formError,
 	}
 
@@ -72,22 +74,43 @@ func (h *Handler) deposit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var (
+		transactionID = idempotentID(r, "transaction")
+		formError     string
+	)
+
 	err = h.CommandExecutor.ExecuteCommand(
 		r.Context(),
 		&commands.Deposit{
-			TransactionID: uuid.New().String(),
+			TransactionID: transactionID,
 			AccountID:     accountID,
 			Amount:        int64(amount),
 		},
 		dogma.WithEventObserver(func(context.Context, *events.DepositApproved) (bool, error) {
 			return true, nil
 		}),
+		dogma.WithEventObserver(func(_ context.Context, e *events.DepositDeclined) (bool, error) {
+			formError = "Deposit declined — " + string(e.Reason) + "."
+			return true, nil
+		}),
 	)
-	if err != nil && !errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		formError, err = h.originalOutcomeError(r.Context(), transactionID, "Deposit", transactionOutcome{
+			TransactionType: "deposit",
+			AccountID:       accountID,
+			Amount:          int64(amount),
+		})
+	}
+	if err != nil {
 		renderError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if formError != "" {
+		h.renderATM(w, r, formError)
+		return
+	}
+
 	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts/%s/transactions", customerID, accountID), http.StatusSeeOther)
 }
 
@@ -104,12 +127,15 @@ func (h *Handler) withdraw(w http.ResponseWriter, r *http.Request) {
 
 	scheduledTime := parseSchedule(r.FormValue("schedule"))
 
-	var formError string
+	var (
+		transactionID = idempotentID(r, "transaction")
+		formError     string
+	)
 
 	err = h.CommandExecutor.ExecuteCommand(
 		r.Context(),
 		&commands.Withdraw{
-			TransactionID: uuid.New().String(),
+			TransactionID: transactionID,
 			AccountID:     accountID,
 			Amount:        int64(amount),
 			ScheduledTime: scheduledTime,
@@ -122,7 +148,14 @@ func (h *Handler) withdraw(w http.ResponseWriter, r *http.Request) {
 			return true, nil
 		}),
 	)
-	if err != nil && !errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		formError, err = h.originalOutcomeError(r.Context(), transactionID, "Withdrawal", transactionOutcome{
+			TransactionType: "withdrawal",
+			AccountID:       accountID,
+			Amount:          int64(amount),
+		})
+	}
+	if err != nil {
 		renderError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
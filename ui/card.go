@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/dogmatiq/example/ui/templates"
+)
+
+// renderCardPage renders the virtual card page, which simulates a merchant
+// authorizing a card payment from the account.
+func (h *Handler) renderCardPage(w http.ResponseWriter, r *http.Request) {
+	h.renderCard(w, r, "")
+}
+
+func (h *Handler) renderCard(w http.ResponseWriter, r *http.Request, formError string) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	customerName, err := h.queryCustomerName(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	data := struct {
+		pageData
+		AccountID   string
+		AccountName string
+		Balance     money
+		IsFrozen    bool
+		Error       string
+	}{
+		pageData: pageData{
+			Title:        "Virtual Card: " + acct.Name,
+			CustomerID:   customerID,
+			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
+		},
+		AccountID:   accountID,
+		AccountName: acct.Name,
+		Balance:     acct.Balance,
+		IsFrozen:    acct.FreezeType != "",
+		Error:       formError,
+	}
+
+	if formError != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	if err := templates.Get("card").ExecuteTemplate(w, "card.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// authorizeCardPayment processes a card payment form submission. It places a
+// hold on the funds, which the card network later captures or releases.
+func (h *Handler) authorizeCardPayment(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	merchant := strings.TrimSpace(r.FormValue("merchant"))
+	if merchant == "" {
+		h.renderCard(w, r, "A merchant is required.")
+		return
+	}
+
+	amount, err := parseMoney(r.FormValue("amount"))
+	if err != nil {
+		h.renderCard(w, r, "Invalid amount.")
+		return
+	}
+
+	var formError string
+
+	err = h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.AuthorizeDebit{
+			HoldID:        idempotentID(r, "hold"),
+			AccountID:     accountID,
+			Merchant:      merchant,
+			Amount:        int64(amount),
+			ScheduledTime: time.Now(),
+		},
+		dogma.WithEventObserver(func(context.Context, *events.DebitAuthorized) (bool, error) {
+			return true, nil
+		}),
+		dogma.WithEventObserver(func(_ context.Context, e *events.DebitAuthorizationDeclined) (bool, error) {
+			formError = "Card payment declined — " + string(e.Reason) + "."
+			return true, nil
+		}),
+	)
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		// The hold was authorized by an earlier submission of the same form,
+		// the outcome of which is shown on the transactions page.
+		err = nil
+	}
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if formError != "" {
+		h.renderCard(w, r, formError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts/%s/transactions", customerID, accountID), http.StatusSeeOther)
+}
+
+// captureHold processes a request from operations staff to debit the funds
+// reserved by a pending card payment, on behalf of the card network.
+func (h *Handler) captureHold(w http.ResponseWriter, r *http.Request, operator string) {
+	accountID := r.PathValue("accountID")
+
+	var declined string
+
+	err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.CaptureHold{
+			HoldID:    r.PathValue("holdID"),
+			AccountID: accountID,
+		},
+		dogma.WithEventObserver(func(context.Context, *events.HoldCaptured) (bool, error) {
+			return true, nil
+		}),
+		dogma.WithEventObserver(func(_ context.Context, e *events.HoldCaptureDeclined) (bool, error) {
+			declined = "Card payment declined — " + string(e.Reason) + "."
+			return true, nil
+		}),
+	)
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		h.renderOperatorAccount(w, r, operator, "The card payment is no longer pending.")
+		return
+	}
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if declined != "" {
+		h.renderOperatorAccount(w, r, operator, declined)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/ops/accounts/%s", accountID), http.StatusSeeOther)
+}
+
+// releaseHold processes a request from operations staff to cancel a pending
+// card payment on behalf of the card network, making the reserved funds
+// available again.
+func (h *Handler) releaseHold(w http.ResponseWriter, r *http.Request, operator string) {
+	accountID := r.PathValue("accountID")
+
+	err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.ReleaseHold{
+			HoldID:    r.PathValue("holdID"),
+			AccountID: accountID,
+		},
+		dogma.WithEventObserver(func(context.Context, *events.HoldReleased) (bool, error) {
+			return true, nil
+		}),
+	)
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		h.renderOperatorAccount(w, r, operator, "The card payment is no longer pending.")
+		return
+	}
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/ops/accounts/%s", accountID), http.StatusSeeOther)
+}
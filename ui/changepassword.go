@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/ui/templates"
+)
+
+// renderChangePasswordPage renders the form for a customer to change the
+// password they use to log in.
+func (h *Handler) renderChangePasswordPage(w http.ResponseWriter, r *http.Request) {
+	h.renderChangePassword(w, r, "")
+}
+
+func (h *Handler) renderChangePassword(w http.ResponseWriter, r *http.Request, formError string) {
+	customerID := r.PathValue("customerID")
+
+	customerName, err := h.queryCustomerName(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	data := struct {
+		pageData
+		Error string
+	}{
+		pageData: pageData{
+			Title:        "Change Password",
+			CustomerID:   customerID,
+			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
+		},
+		Error: formError,
+	}
+
+	if formError != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	if err := templates.Get("changepassword").ExecuteTemplate(w, "changepassword.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// changePassword handles the change password form submission. It checks the
+// customer's current password, dispatches a ChangeCustomerPassword command and
+// redirects to the accounts list.
+func (h *Handler) changePassword(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+	password := r.FormValue("new_password")
+
+	ok, err := h.checkCustomerPassword(r.Context(), customerID, r.FormValue("current_password"))
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		h.renderChangePassword(w, r, "Incorrect password.")
+		return
+	}
+
+	if formError := checkPasswordLength(password); formError != "" {
+		h.renderChangePassword(w, r, formError)
+		return
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.ChangeCustomerPassword{
+			CustomerID:   customerID,
+			PasswordHash: hash,
+		},
+	); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts", customerID), http.StatusSeeOther)
+}
+
+// checkCustomerPassword returns true if password is the correct password for
+// the given customer.
+func (h *Handler) checkCustomerPassword(
+	ctx context.Context,
+	customerID, password string,
+) (bool, error) {
+	var hash string
+
+	err := h.DB.QueryRowContext(
+		ctx,
+		`SELECT password_hash
+		FROM customer_credentials
+		WHERE customer_id = ?`,
+		customerID,
+	).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return checkPassword(hash, password), nil
+}
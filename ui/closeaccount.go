@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/dogmatiq/example/ui/templates"
+)
+
+// renderCloseAccountPage renders the form for closing an account.
+//
+// If the account is already closed but holds a balance that was returned to it
+// after the sweep made on closure failed, the form is used to nominate another
+// account for the balance instead.
+func (h *Handler) renderCloseAccountPage(w http.ResponseWriter, r *http.Request) {
+	h.renderCloseAccount(w, r, "")
+}
+
+func (h *Handler) renderCloseAccount(w http.ResponseWriter, r *http.Request, formError string) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	customerName, err := h.queryCustomerName(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
+	if err != nil || (acct.IsClosed && acct.Balance <= 0) {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	accounts, _, err := h.queryAccounts(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	var otherAccounts []account
+	for _, a := range accounts {
+		if a.ID != accountID {
+			otherAccounts = append(otherAccounts, a)
+		}
+	}
+
+	payees, err := h.queryPayees(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := struct {
+		pageData
+		AccountID     string
+		AccountName   string
+		Balance       money
+		IsClosed      bool
+		OtherAccounts []account
+		Payees        []payee
+		Error         string
+	}{
+		pageData: pageData{
+			Title:        "Close Account: " + acct.Name,
+			CustomerID:   customerID,
+			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
+		},
+		AccountID:     accountID,
+		AccountName:   acct.Name,
+		Balance:       acct.Balance,
+		IsClosed:      acct.IsClosed,
+		OtherAccounts: otherAccounts,
+		Payees:        payees,
+		Error:         formError,
+	}
+
+	if formError != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	if err := templates.Get("closeaccount").ExecuteTemplate(w, "closeaccount.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// closeAccount processes a close account form submission. Any remaining balance
+// is swept to either another of the customer's accounts, or to one of their
+// payees.
+func (h *Handler) closeAccount(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	var sweepTo destination
+
+	if v := r.FormValue("sweep_to"); v != "" {
+		var err error
+		sweepTo, err = h.queryDestination(r.Context(), customerID, accountID, v)
+		if errors.Is(err, errInvalidDestination) {
+			h.renderCloseAccount(w, r, "Invalid destination account.")
+			return
+		}
+		if err != nil {
+			renderError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	if acct.IsClosed {
+		h.sweepClosedAccount(w, r, sweepTo)
+		return
+	}
+
+	var formError string
+
+	err = h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.CloseAccount{
+			TransactionID:         idempotentID(r, "transaction"),
+			AccountID:             accountID,
+			SweepToAccountID:      sweepTo.AccountID,
+			SweepToThirdPartyBank: sweepTo.ToThirdPartyBank,
+			SweepToPayee:          sweepTo.Payee,
+			SweepToPayeeID:        sweepTo.PayeeID,
+		},
+		dogma.WithEventObserver(func(context.Context, *events.AccountClosed) (bool, error) {
+			return true, nil
+		}),
+		dogma.WithEventObserver(func(_ context.Context, e *events.AccountClosureDeclined) (bool, error) {
+			formError = "Account closure declined — " + string(e.Reason) + "."
+			return true, nil
+		}),
+	)
+	if err != nil && !errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if formError != "" {
+		h.renderCloseAccount(w, r, formError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts", customerID), http.StatusSeeOther)
+}
+
+// sweepClosedAccount moves the balance of a closed account, which was returned
+// to it after the sweep made on closure failed, to the nominated account.
+func (h *Handler) sweepClosedAccount(
+	w http.ResponseWriter,
+	r *http.Request,
+	sweepTo destination,
+) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	if sweepTo.AccountID == "" {
+		h.renderCloseAccount(w, r, "Choose an account to receive the returned balance.")
+		return
+	}
+
+	if err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.SweepClosedAccount{
+			TransactionID:         idempotentID(r, "transaction"),
+			AccountID:             accountID,
+			SweepToAccountID:      sweepTo.AccountID,
+			SweepToThirdPartyBank: sweepTo.ToThirdPartyBank,
+			SweepToPayee:          sweepTo.Payee,
+			SweepToPayeeID:        sweepTo.PayeeID,
+		},
+	); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts", customerID), http.StatusSeeOther)
+}
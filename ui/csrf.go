@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	// csrfCookie is the name of the cookie that identifies a browser that has
+	// not yet logged in, for the purpose of issuing CSRF tokens.
+	csrfCookie = "csrf"
+
+	// csrfField is the name of the form field that contains the CSRF token.
+	csrfField = "csrf_token"
+
+	// maxRequestBodySize is the maximum size, in bytes, of a request body.
+	maxRequestBodySize = 64 << 10
+)
+
+// csrfToken returns the CSRF token that must be submitted with any form
+// rendered in response to r.
+//
+// The token is derived from the session token, so it changes each time the
+// customer or operator logs in. Visitors that have not logged in are issued a random
+// cookie from which the token is derived instead, so that the login and signup
+// forms are also protected.
+func csrfToken(w http.ResponseWriter, r *http.Request) string {
+	seed := csrfSeed(r)
+
+	if seed == "" {
+		b := make([]byte, 32)
+		rand.Read(b) // nolint:errcheck // never returns an error
+		seed = base64.RawURLEncoding.EncodeToString(b)
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     csrfCookie,
+			Value:    seed,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	return deriveCSRFToken(seed)
+}
+
+// deriveCSRFToken returns the CSRF token derived from the given seed.
+func deriveCSRFToken(seed string) string {
+	sum := sha256.Sum256([]byte("csrf:" + seed))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// csrfSeed returns the secret from which the CSRF token for r is derived, or
+// an empty string if the browser has not yet been issued one.
+func csrfSeed(r *http.Request) string {
+	if c, err := r.Cookie(sessionCookie); err == nil && c.Value != "" {
+		return c.Value
+	}
+
+	if c, err := r.Cookie(operatorSessionCookie); err == nil && c.Value != "" {
+		return c.Value
+	}
+
+	if c, err := r.Cookie(csrfCookie); err == nil {
+		return c.Value
+	}
+
+	return ""
+}
+
+// protect wraps next such that request bodies are limited in size, and form
+// submissions are rejected unless they include the CSRF token issued to the
+// browser.
+//
+// JSON API requests do not carry a CSRF token. Instead, decodeJSON requires
+// them to have a JSON content type, which can not be sent cross-site without
+// the browser's permission.
+func protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+
+		if r.Method != http.MethodPost || strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			if errors.As(err, new(*http.MaxBytesError)) {
+				renderError(w, http.StatusRequestEntityTooLarge)
+			} else {
+				renderError(w, http.StatusBadRequest, err.Error())
+			}
+			return
+		}
+
+		seed := csrfSeed(r)
+		token := r.PostFormValue(csrfField)
+
+		if seed == "" || subtle.ConstantTimeCompare([]byte(token), []byte(deriveCSRFToken(seed))) != 1 {
+			renderError(w, http.StatusForbidden, "The form has expired. Please go back, reload the page and try again.")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
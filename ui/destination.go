@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/dogmatiq/example/messages"
+)
+
+// errInvalidDestination is returned by queryDestination if the destination is
+// not one of the customer's own accounts or payees.
+var errInvalidDestination = errors.New("invalid destination account")
+
+// destination is the account to which money is moved out of a customer's
+// account by a transfer, a standing order or the closure of the account.
+type destination struct {
+	AccountID        string
+	ToThirdPartyBank bool
+
+	// Payee identifies the third-party account by its BSB, account number and
+	// name. It is nil unless ToThirdPartyBank is true.
+	Payee *messages.ThirdPartyPayee
+
+	// PayeeID is the ID of the customer's payee, or an empty string if the
+	// destination is one of the customer's own accounts.
+	PayeeID string
+}
+
+// queryDestination returns the destination identified by the value of a
+// destination account field, which is "account:" followed by the ID of one of
+// the customer's own accounts, or "payee:" followed by the ID of one of their
+// payees.
+//
+// It returns errInvalidDestination if the value does not identify such an
+// account.
+func (h *Handler) queryDestination(
+	ctx context.Context,
+	customerID, fromAccountID, value string,
+) (destination, error) {
+	switch kind, id, _ := strings.Cut(value, ":"); kind {
+	case "account":
+		return h.queryAccountDestination(ctx, customerID, fromAccountID, id)
+	case "payee":
+		return h.queryPayeeDestination(ctx, customerID, fromAccountID, id)
+	default:
+		return destination{}, errInvalidDestination
+	}
+}
+
+// queryAccountDestination returns the destination for money moved to another
+// of the customer's own open accounts.
+//
+// It returns errInvalidDestination if the customer has no such account, or if
+// it is the account the money is moved from.
+func (h *Handler) queryAccountDestination(
+	ctx context.Context,
+	customerID, fromAccountID, accountID string,
+) (destination, error) {
+	acct, err := h.queryAccountDetails(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return destination{}, errInvalidDestination
+	}
+	if err != nil {
+		return destination{}, err
+	}
+
+	if acct.CustomerID != customerID || acct.IsClosed || acct.ID == fromAccountID {
+		return destination{}, errInvalidDestination
+	}
+
+	return destination{
+		AccountID: acct.ID,
+	}, nil
+}
+
+// queryPayeeDestination returns the destination for money moved to one of the
+// customer's payees.
+//
+// It returns errInvalidDestination if the customer has no such payee, or if
+// the payee's account is the account the money is moved from.
+func (h *Handler) queryPayeeDestination(
+	ctx context.Context,
+	customerID, fromAccountID, payeeID string,
+) (destination, error) {
+	p, err := h.queryPayee(ctx, customerID, payeeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return destination{}, errInvalidDestination
+	}
+	if err != nil {
+		return destination{}, err
+	}
+
+	if p.AccountID == fromAccountID {
+		return destination{}, errInvalidDestination
+	}
+
+	return destination{
+		AccountID:        p.AccountID,
+		ToThirdPartyBank: p.IsThirdParty,
+		Payee:            p.thirdPartyPayee(),
+		PayeeID:          p.ID,
+	}, nil
+}
@@ -0,0 +1,452 @@
+package ui
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/example/messages"
+)
+
+const (
+	// statementBankID identifies the bank in exported statements that
+	// require it.
+	statementBankID = "DOGMATIQ"
+
+	// statementCurrency is the ISO 4217 code of the currency that all
+	// amounts are held in.
+	statementCurrency = "AUD"
+)
+
+// statement describes an export of an account's transaction history.
+type statement struct {
+	Account        account
+	From, To       time.Time // first and last day included, inclusive
+	ClosingBalance money     // balance at the end of the last day
+}
+
+// statementEntry is a single ledger entry within a statement.
+type statementEntry struct {
+	transaction
+
+	// FITID uniquely identifies the entry within the account. It is
+	// derived from the ID of the transaction that produced the entry, and
+	// therefore does not change if the statement is exported again.
+	FITID string
+}
+
+// statementWriter writes a statement in a specific file format.
+type statementWriter interface {
+	Begin(s statement) error
+	Entry(e statementEntry) error
+	End(s statement) error
+}
+
+// exportFormat is a file format that a statement can be exported in.
+type exportFormat struct {
+	ContentType string
+	New         func(w io.Writer) statementWriter
+}
+
+// exportFormats is the set of supported export formats, keyed by file
+// extension.
+var exportFormats = map[string]exportFormat{
+	"csv": {"text/csv; charset=utf-8", newCSVStatementWriter},
+	"ofx": {"application/x-ofx", newOFXStatementWriter},
+	"qif": {"application/qif", newQIFStatementWriter},
+}
+
+// exportTransactions streams the ledger entries for an account within a date
+// range as a file that can be imported into accounting software.
+//
+// The format is chosen by the "format" path value, and the range by the
+// "from" and "to" query parameters, both of which are required.
+func (h *Handler) exportTransactions(w http.ResponseWriter, r *http.Request) {
+	accountID := r.PathValue("accountID")
+
+	format, ok := exportFormats[r.PathValue("format")]
+	if !ok {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	filter, filterError := parseTransactionFilter(r.URL.Query())
+	if filterError == "" && (filter.From == "" || filter.To == "") {
+		filterError = "Choose the first and last day of the statement."
+	}
+	if filterError != "" {
+		renderError(w, http.StatusBadRequest, filterError)
+		return
+	}
+
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	s := statement{Account: acct}
+	s.From, _ = time.ParseInLocation(time.DateOnly, filter.From, time.Local)
+	s.To, _ = time.ParseInLocation(time.DateOnly, filter.To, time.Local)
+
+	s.ClosingBalance, err = h.queryClosingBalance(r.Context(), accountID, filter.To)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType)
+	w.Header().Set(
+		"Content-Disposition",
+		fmt.Sprintf(
+			`attachment; filename="%s_%s_%s.%s"`,
+			accountID,
+			filter.From,
+			filter.To,
+			r.PathValue("format"),
+		),
+	)
+
+	// Once the response has begun there is no way to report an error to
+	// the browser, so the download is left incomplete instead.
+	sw := format.New(w)
+
+	if err := sw.Begin(s); err != nil {
+		return
+	}
+
+	if err := h.eachStatementEntry(
+		r.Context(),
+		accountID,
+		transactionFilter{From: filter.From, To: filter.To},
+		sw.Entry,
+	); err != nil {
+		return
+	}
+
+	sw.End(s) // nolint:errcheck
+}
+
+// eachStatementEntry calls fn for each ledger entry in an account that
+// matches f, in the order they occurred. Entries are read from the database
+// as they are needed, so that large histories are not held in memory.
+func (h *Handler) eachStatementEntry(
+	ctx context.Context,
+	accountID string,
+	f transactionFilter,
+	fn func(statementEntry) error,
+) error {
+	conditions, args := f.where()
+	conditions = append([]string{`account_id = ?`}, conditions...)
+	args = append([]any{accountID}, args...)
+
+	rows, err := h.DB.QueryContext(
+		ctx,
+		`SELECT
+			created_at,
+			transaction_id,
+			transaction_order,
+			description,
+			debit,
+			credit,
+			balance
+		FROM ledger
+		WHERE `+strings.Join(conditions, ` AND `)+`
+		ORDER BY
+			created_at,
+			transaction_id,
+			transaction_order`,
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			e     statementEntry
+			order int
+		)
+
+		if err := rows.Scan(
+			&e.OccurredAt,
+			&e.FITID,
+			&order,
+			&e.Description,
+			&e.Debit,
+			&e.Credit,
+			&e.Balance,
+		); err != nil {
+			return err
+		}
+
+		// A transaction may have more than one entry in the same account,
+		// such as a card payment and its refund, so the order of the entry
+		// within the transaction is included when it is not the first.
+		if order != 0 {
+			e.FITID += "-" + strconv.Itoa(order)
+		}
+
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// queryClosingBalance returns the balance of an account at the end of the
+// given day, formatted as YYYY-MM-DD.
+func (h *Handler) queryClosingBalance(
+	ctx context.Context,
+	accountID, day string,
+) (money, error) {
+	var balance money
+
+	err := h.DB.QueryRowContext(
+		ctx,
+		`SELECT balance
+		FROM ledger
+		WHERE account_id = ?
+			AND created_at < ?
+		ORDER BY
+			created_at DESC,
+			transaction_id DESC,
+			transaction_order DESC
+		LIMIT 1`,
+		accountID,
+		nextDay(day),
+	).Scan(&balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	return balance, err
+}
+
+// amount returns the signed amount of a ledger entry, which is negative for a
+// debit.
+func (e statementEntry) amount() money {
+	return e.Credit - e.Debit
+}
+
+// csvStatementWriter writes a statement as comma-separated values, with one
+// row per ledger entry.
+type csvStatementWriter struct {
+	w *csv.Writer
+}
+
+func newCSVStatementWriter(w io.Writer) statementWriter {
+	return &csvStatementWriter{csv.NewWriter(w)}
+}
+
+func (sw *csvStatementWriter) Begin(statement) error {
+	return sw.w.Write([]string{
+		"Date",
+		"Description",
+		"Debit",
+		"Credit",
+		"Balance",
+		"Transaction ID",
+	})
+}
+
+func (sw *csvStatementWriter) Entry(e statementEntry) error {
+	column := func(m money) string {
+		if m == 0 {
+			return ""
+		}
+		return m.Decimal()
+	}
+
+	return sw.w.Write([]string{
+		e.OccurredAt.Format(time.RFC3339),
+		e.Description,
+		column(e.Debit),
+		column(e.Credit),
+		e.Balance.Decimal(),
+		e.FITID,
+	})
+}
+
+func (sw *csvStatementWriter) End(statement) error {
+	sw.w.Flush()
+	return sw.w.Error()
+}
+
+// ofxStatementWriter writes a statement as an Open Financial Exchange 2.2
+// bank statement response.
+type ofxStatementWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newOFXStatementWriter(w io.Writer) statementWriter {
+	return &ofxStatementWriter{w, xml.NewEncoder(w)}
+}
+
+// ofxStatus is the STATUS aggregate of an OFX response that succeeded.
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+// ofxTransaction is the STMTTRN aggregate that describes a single ledger
+// entry.
+type ofxTransaction struct {
+	XMLName xml.Name `xml:"STMTTRN"`
+	Type    string   `xml:"TRNTYPE"`
+	Posted  string   `xml:"DTPOSTED"`
+	Amount  string   `xml:"TRNAMT"`
+	FITID   string   `xml:"FITID"`
+	Name    string   `xml:"NAME"`
+	Memo    string   `xml:"MEMO,omitempty"`
+}
+
+func (sw *ofxStatementWriter) Begin(s statement) error {
+	if _, err := io.WriteString(
+		sw.w,
+		`<?xml version="1.0" encoding="UTF-8" standalone="no"?>`+"\n"+
+			`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>`+"\n",
+	); err != nil {
+		return err
+	}
+
+	accountType := "CHECKING"
+	switch messages.AccountType(s.Account.AccountType) {
+	case messages.Savings:
+		accountType = "SAVINGS"
+	case messages.TermDeposit:
+		accountType = "CD"
+	}
+
+	sw.start("OFX")
+	sw.start("SIGNONMSGSRSV1")
+	sw.start("SONRS")
+	sw.element("STATUS", ofxStatus{0, "INFO"})
+	sw.element("DTSERVER", ofxDateTime(time.Now()))
+	sw.element("LANGUAGE", "ENG")
+	sw.end("SONRS")
+	sw.end("SIGNONMSGSRSV1")
+	sw.start("BANKMSGSRSV1")
+	sw.start("STMTTRNRS")
+	sw.element("TRNUID", "0")
+	sw.element("STATUS", ofxStatus{0, "INFO"})
+	sw.start("STMTRS")
+	sw.element("CURDEF", statementCurrency)
+	sw.start("BANKACCTFROM")
+	sw.element("BANKID", statementBankID)
+	sw.element("ACCTID", s.Account.ID)
+	sw.element("ACCTTYPE", accountType)
+	sw.end("BANKACCTFROM")
+	sw.start("BANKTRANLIST")
+	sw.element("DTSTART", s.From.Format("20060102"))
+	sw.element("DTEND", s.To.Format("20060102"))
+
+	return sw.enc.Flush()
+}
+
+func (sw *ofxStatementWriter) Entry(e statementEntry) error {
+	t := ofxTransaction{
+		Type:   "CREDIT",
+		Posted: ofxDateTime(e.OccurredAt),
+		Amount: e.amount().Decimal(),
+		FITID:  e.FITID,
+		Name:   e.Description,
+	}
+
+	if e.Debit != 0 {
+		t.Type = "DEBIT"
+	}
+
+	// NAME is limited to 32 characters, so longer descriptions are given
+	// in full in MEMO.
+	if r := []rune(e.Description); len(r) > 32 {
+		t.Name = string(r[:32])
+		t.Memo = e.Description
+	}
+
+	return sw.enc.Encode(t)
+}
+
+func (sw *ofxStatementWriter) End(s statement) error {
+	sw.end("BANKTRANLIST")
+	sw.start("LEDGERBAL")
+	sw.element("BALAMT", s.ClosingBalance.Decimal())
+	sw.element("DTASOF", ofxDateTime(s.To.AddDate(0, 0, 1).Add(-time.Second)))
+	sw.end("LEDGERBAL")
+	sw.end("STMTRS")
+	sw.end("STMTTRNRS")
+	sw.end("BANKMSGSRSV1")
+	sw.end("OFX")
+
+	return sw.enc.Close()
+}
+
+// start opens an OFX aggregate. Errors are reported by the next call to
+// Flush or Close.
+func (sw *ofxStatementWriter) start(name string) {
+	sw.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: name}}) // nolint:errcheck
+}
+
+// end closes an OFX aggregate.
+func (sw *ofxStatementWriter) end(name string) {
+	sw.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}) // nolint:errcheck
+}
+
+// element writes an OFX element with the given value.
+func (sw *ofxStatementWriter) element(name string, v any) {
+	sw.enc.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: name}}) // nolint:errcheck
+}
+
+// ofxDateTime formats t as an OFX date and time in UTC.
+func ofxDateTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+// qifStatementWriter writes a statement in the Quicken Interchange Format.
+type qifStatementWriter struct {
+	w io.Writer
+}
+
+func newQIFStatementWriter(w io.Writer) statementWriter {
+	return &qifStatementWriter{w}
+}
+
+func (sw *qifStatementWriter) Begin(statement) error {
+	_, err := io.WriteString(sw.w, "!Type:Bank\n")
+	return err
+}
+
+func (sw *qifStatementWriter) Entry(e statementEntry) error {
+	// QIF has no field for a unique identifier, so the FITID is given as
+	// the reference number, which importers use to detect duplicates.
+	_, err := fmt.Fprintf(
+		sw.w,
+		"D%s\nT%s\nP%s\nN%s\n^\n",
+		e.OccurredAt.Format("01/02/2006"),
+		e.amount().Decimal(),
+		qifLine(e.Description),
+		qifLine(e.FITID),
+	)
+	return err
+}
+
+func (sw *qifStatementWriter) End(statement) error {
+	return nil
+}
+
+// qifLine removes line breaks from s, which would otherwise begin a new QIF
+// field.
+func qifLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+)
+
+// freezeAccount processes a freeze form submission from operations staff.
+//
+// The operator recorded against the freeze is the operator that is logged in,
+// not a value supplied in the form.
+func (h *Handler) freezeAccount(w http.ResponseWriter, r *http.Request, operator string) {
+	accountID := r.PathValue("accountID")
+
+	freezeType := messages.FreezeType(r.FormValue("freeze_type"))
+	if err := freezeType.Validate(); err != nil {
+		h.renderOperatorAccount(w, r, operator, "Invalid freeze type.")
+		return
+	}
+
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	if reason == "" {
+		h.renderOperatorAccount(w, r, operator, "A reason is required.")
+		return
+	}
+
+	err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.FreezeAccount{
+			AccountID:  accountID,
+			FreezeType: freezeType,
+			Reason:     reason,
+			Operator:   operator,
+		},
+	)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/ops/accounts/%s", accountID), http.StatusSeeOther)
+}
+
+// unfreezeAccount processes an unfreeze form submission from operations staff.
+func (h *Handler) unfreezeAccount(w http.ResponseWriter, r *http.Request, operator string) {
+	accountID := r.PathValue("accountID")
+
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	if reason == "" {
+		h.renderOperatorAccount(w, r, operator, "A reason is required.")
+		return
+	}
+
+	err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.UnfreezeAccount{
+			AccountID: accountID,
+			Reason:    reason,
+			Operator:  operator,
+		},
+	)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/ops/accounts/%s", accountID), http.StatusSeeOther)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/ui/projections"
 	"github.com/dogmatiq/example/ui/templates"
 )
 
@@ -16,8 +17,23 @@ type Handler struct {
 	DB              *sql.DB
 	CommandExecutor dogma.CommandExecutor
 
-	once sync.Once
-	mux  http.ServeMux
+	// Feed, if non-nil, is used to push changes to the accounts and
+	// transactions tables to the browser as they occur.
+	Feed *projections.Feed
+
+	// Operators maps the lower-case username of each member of operations
+	// staff to a hash of their password, as produced by [HashPassword].
+	// Operators log in at /ops to freeze accounts, change their limits, set
+	// interest rates and settle pending card payments on behalf of the card
+	// network. If it is empty, no one can log in to the operations pages.
+	Operators map[string]string
+
+	once    sync.Once
+	mux     http.ServeMux
+	handler http.Handler
+
+	operatorSessionsM sync.Mutex
+	operatorSessions  map[string]operatorSession
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -25,24 +41,76 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.mux.HandleFunc("GET  /{$}", h.renderLoginPage)
 		h.mux.HandleFunc("GET  /signup", h.renderSignupPage)
 		h.mux.HandleFunc("POST /signup", h.openAccountForNewCustomer)
-		h.mux.HandleFunc("GET  /c/{customerID}/accounts", h.renderAccountsPage)
-		h.mux.HandleFunc("GET  /c/{customerID}/accounts/fragment", h.renderAccountsFragment)
-		h.mux.HandleFunc("GET  /c/{customerID}/accounts/new", h.renderOpenAccountPage)
-		h.mux.HandleFunc("POST /c/{customerID}/accounts", h.openAccount)
-		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/transactions", h.renderTransactionsPage)
-		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/transactions/fragment", h.renderTransactionsFragment)
-		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/atm", h.renderATMPage)
-		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/deposit", h.deposit)
-		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/withdraw", h.withdraw)
-		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/transfer", h.renderTransferPage)
-		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/transfer", h.transfer)
+		h.mux.HandleFunc("POST /login", h.logIn)
+		h.mux.HandleFunc("POST /logout", h.logOut)
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts", h.requireCustomer(h.renderAccountsPage))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/events", h.requireCustomer(h.streamAccounts))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/new", h.requireCustomer(h.renderOpenAccountPage))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts", h.requireCustomer(h.openAccount))
+		h.mux.HandleFunc("GET  /c/{customerID}/password", h.requireCustomer(h.renderChangePasswordPage))
+		h.mux.HandleFunc("POST /c/{customerID}/password", h.requireCustomer(h.changePassword))
+		h.mux.HandleFunc("GET  /c/{customerID}/payees", h.requireCustomer(h.renderPayeesPage))
+		h.mux.HandleFunc("GET  /c/{customerID}/payees/new", h.requireCustomer(h.renderNewPayeePage))
+		h.mux.HandleFunc("POST /c/{customerID}/payees", h.requireCustomer(h.addPayee))
+		h.mux.HandleFunc("POST /c/{customerID}/payees/{payeeID}/remove", h.requireCustomer(h.removePayee))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/transactions", h.requireCustomer(h.renderTransactionsPage))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/transactions/events", h.requireCustomer(h.streamTransactions))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/transactions/export/{format}", h.requireCustomer(h.exportTransactions))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/statements", h.requireCustomer(h.renderStatementsPage))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/statements/{statementID}", h.requireCustomer(h.renderStatementPage))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/statements/{statementID}/pdf", h.requireCustomer(h.downloadStatementPDF))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/atm", h.requireCustomer(h.renderATMPage))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/deposit", h.requireCustomer(h.deposit))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/withdraw", h.requireCustomer(h.withdraw))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/card", h.requireCustomer(h.renderCardPage))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/card", h.requireCustomer(h.authorizeCardPayment))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/transfer", h.requireCustomer(h.renderTransferPage))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/transfer", h.requireCustomer(h.transfer))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/transfers/{transactionID}/cancel", h.requireCustomer(h.cancelTransfer))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/close", h.requireCustomer(h.renderCloseAccountPage))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/close", h.requireCustomer(h.closeAccount))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/standing-orders", h.requireCustomer(h.renderStandingOrdersPage))
+		h.mux.HandleFunc("GET  /c/{customerID}/accounts/{accountID}/standing-orders/new", h.requireCustomer(h.renderNewStandingOrderPage))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/standing-orders", h.requireCustomer(h.createStandingOrder))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/standing-orders/{standingOrderID}/pause", h.requireCustomer(h.pauseStandingOrder))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/standing-orders/{standingOrderID}/resume", h.requireCustomer(h.resumeStandingOrder))
+		h.mux.HandleFunc("POST /c/{customerID}/accounts/{accountID}/standing-orders/{standingOrderID}/cancel", h.requireCustomer(h.cancelStandingOrder))
+
+		h.mux.HandleFunc("GET  /ops", h.renderOperatorLoginPage)
+		h.mux.HandleFunc("POST /ops/login", h.operatorLogIn)
+		h.mux.HandleFunc("POST /ops/logout", h.operatorLogOut)
+		h.mux.HandleFunc("GET  /ops/accounts", h.requireOperator(h.renderOperatorAccountsPage))
+		h.mux.HandleFunc("GET  /ops/accounts/{accountID}", h.requireOperator(h.renderOperatorAccountPage))
+		h.mux.HandleFunc("POST /ops/accounts/{accountID}/freeze", h.requireOperator(h.freezeAccount))
+		h.mux.HandleFunc("POST /ops/accounts/{accountID}/unfreeze", h.requireOperator(h.unfreezeAccount))
+		h.mux.HandleFunc("POST /ops/accounts/{accountID}/daily-debit-limit", h.requireOperator(h.changeDailyDebitLimit))
+		h.mux.HandleFunc("POST /ops/accounts/{accountID}/overdraft-limit", h.requireOperator(h.setOverdraftLimit))
+		h.mux.HandleFunc("POST /ops/accounts/{accountID}/interest-rate", h.requireOperator(h.setInterestRate))
+		h.mux.HandleFunc("POST /ops/accounts/{accountID}/holds/{holdID}/capture", h.requireOperator(h.captureHold))
+		h.mux.HandleFunc("POST /ops/accounts/{accountID}/holds/{holdID}/release", h.requireOperator(h.releaseHold))
+
+		h.mux.HandleFunc("GET  /api/v1/openapi.yaml", h.serveOpenAPI)
+		h.mux.HandleFunc("POST /api/v1/customers", h.apiCreateCustomer)
+		h.mux.HandleFunc("POST /api/v1/session", h.apiLogIn)
+		h.mux.HandleFunc("DELETE /api/v1/session", h.apiLogOut)
+		h.mux.HandleFunc("GET  /api/v1/customers/{customerID}", h.apiRequireCustomer(h.apiGetCustomer))
+		h.mux.HandleFunc("GET  /api/v1/customers/{customerID}/accounts", h.apiRequireCustomer(h.apiListAccounts))
+		h.mux.HandleFunc("POST /api/v1/customers/{customerID}/accounts", h.apiRequireCustomer(h.apiOpenAccount))
+		h.mux.HandleFunc("GET  /api/v1/customers/{customerID}/accounts/{accountID}", h.apiRequireCustomer(h.apiGetAccount))
+		h.mux.HandleFunc("GET  /api/v1/customers/{customerID}/accounts/{accountID}/ledger", h.apiRequireCustomer(h.apiListLedgerEntries))
+		h.mux.HandleFunc("POST /api/v1/customers/{customerID}/accounts/{accountID}/deposits", h.apiRequireCustomer(h.apiDeposit))
+		h.mux.HandleFunc("POST /api/v1/customers/{customerID}/accounts/{accountID}/withdrawals", h.apiRequireCustomer(h.apiWithdraw))
+		h.mux.HandleFunc("POST /api/v1/customers/{customerID}/accounts/{accountID}/transfers", h.apiRequireCustomer(h.apiTransfer))
+		h.mux.HandleFunc("/api/v1/", h.apiNotFound)
 
 		h.mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
 			renderError(w, http.StatusNotFound)
 		})
+
+		h.handler = protect(&h.mux)
 	})
 
-	h.mux.ServeHTTP(w, r)
+	h.handler.ServeHTTP(w, r)
 }
 
 // parseSchedule converts a schedule radio value into a time.
@@ -72,25 +140,47 @@ func (h *Handler) queryCustomerName(
 	return name, err
 }
 
-// queryAccountDetails returns the name and balance for a single account.
+// queryAccountDetails returns the details of a single account.
 func (h *Handler) queryAccountDetails(
 	ctx context.Context,
 	accountID string,
-) (name string, balance int64, err error) {
+) (a account, err error) {
 	err = h.DB.QueryRowContext(
 		ctx,
 		`SELECT
+			id,
+			customer_id,
 			name,
-			balance
+			account_type,
+			maturity_date,
+			balance,
+			held_amount,
+			daily_debit_limit,
+			overdraft_limit,
+			interest_rate,
+			is_closed,
+			freeze_type,
+			freeze_reason
 		FROM accounts
 		WHERE id = ?`,
 		accountID,
 	).Scan(
-		&name,
-		&balance,
+		&a.ID,
+		&a.CustomerID,
+		&a.Name,
+		&a.AccountType,
+		&a.MaturityDate,
+		&a.Balance,
+		&a.HeldAmount,
+		&a.DailyDebitLimit,
+		&a.OverdraftLimit,
+		&a.InterestRate,
+		&a.IsClosed,
+		&a.FreezeType,
+		&a.FreezeReason,
 	)
 
-	return name, balance, err
+	return a, err
 }
 
 // renderError writes a styled error page with the given HTTP status code and
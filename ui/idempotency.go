@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// idempotencyKeyField is the name of the form field that contains the
+	// idempotency key. Each rendering of a form contains a new key.
+	idempotencyKeyField = "idempotency_key"
+
+	// idempotencyKeyHeader is the name of the HTTP header that contains the
+	// idempotency key supplied by JSON API clients.
+	idempotencyKeyHeader = "Idempotency-Key"
+)
+
+// idempotencyNamespace is the namespace used to derive IDs from idempotency
+// keys.
+var idempotencyNamespace = uuid.MustParse("3a1b0c8e-7c52-4d4b-9a2e-6f0d1c9e5b47")
+
+// idempotencyKey returns the idempotency key supplied with a request, or an
+// empty string if there is none.
+func idempotencyKey(r *http.Request) string {
+	if key := r.Header.Get(idempotencyKeyHeader); key != "" {
+		return key
+	}
+	return r.PostFormValue(idempotencyKeyField)
+}
+
+// idempotentID returns the ID of the entity of the given kind that is created
+// by a request.
+//
+// The ID is derived from the request's idempotency key, so that repeating the
+// request produces the same ID, and the second command is ignored by the
+// domain. It is scoped to the customer, so that one customer can not guess the
+// IDs of another customer's entities. If the request has no key, a random ID
+// is returned.
+func idempotentID(r *http.Request, kind string) string {
+	key := idempotencyKey(r)
+	if key == "" {
+		return uuid.NewString()
+	}
+
+	return uuid.NewSHA1(
+		idempotencyNamespace,
+		[]byte(kind+"\x00"+r.PathValue("customerID")+"\x00"+key),
+	).String()
+}
+
+// idempotentAccountID returns the ID of the account that is opened by a
+// request, in the same format as generateAccountID.
+func idempotentAccountID(r *http.Request) string {
+	if idempotencyKey(r) == "" {
+		return generateAccountID()
+	}
+
+	sum := sha256.Sum256([]byte(idempotentID(r, "account")))
+	n := binary.BigEndian.Uint64(sum[:]) % 900_000_000
+
+	return strconv.FormatUint(n+100_000_000, 10)
+}
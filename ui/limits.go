@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/dogmatiq/example/messages/commands"
+)
+
+// changeDailyDebitLimit processes a daily debit limit form submission from
+// operations staff.
+func (h *Handler) changeDailyDebitLimit(w http.ResponseWriter, r *http.Request, operator string) {
+	accountID := r.PathValue("accountID")
+
+	limit, err := parseMoney(r.FormValue("daily_debit_limit"))
+	if err != nil {
+		h.renderOperatorAccount(w, r, operator, "Invalid daily debit limit.")
+		return
+	}
+
+	err = h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.ChangeDailyDebitLimit{
+			AccountID:       accountID,
+			DailyDebitLimit: int64(limit),
+		},
+	)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/ops/accounts/%s", accountID), http.StatusSeeOther)
+}
+
+// setOverdraftLimit processes an arranged overdraft form submission from
+// operations staff. A limit of zero removes the overdraft.
+func (h *Handler) setOverdraftLimit(w http.ResponseWriter, r *http.Request, operator string) {
+	accountID := r.PathValue("accountID")
+
+	limit, err := parseLimit(r.FormValue("overdraft_limit"))
+	if err != nil {
+		h.renderOperatorAccount(w, r, operator, "Invalid overdraft limit.")
+		return
+	}
+
+	err = h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.SetOverdraftLimit{
+			AccountID:      accountID,
+			OverdraftLimit: int64(limit),
+		},
+	)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/ops/accounts/%s", accountID), http.StatusSeeOther)
+}
+
+// setInterestRate processes an interest rate form submission from operations
+// staff. A rate of zero stops interest being paid.
+func (h *Handler) setInterestRate(w http.ResponseWriter, r *http.Request, operator string) {
+	accountID := r.PathValue("accountID")
+
+	rate, err := parseInterestRate(r.FormValue("interest_rate"))
+	if err != nil {
+		h.renderOperatorAccount(w, r, operator, "Invalid interest rate.")
+		return
+	}
+
+	err = h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.SetInterestRate{
+			AccountID:    accountID,
+			InterestRate: int64(rate),
+		},
+	)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/ops/accounts/%s", accountID), http.StatusSeeOther)
+}
@@ -2,54 +2,99 @@ package ui
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
 	"github.com/dogmatiq/example/ui/templates"
-	"github.com/google/uuid"
 )
 
-// customer is an existing bank customer shown on the login page.
-type customer struct {
-	ID   string
-	Name string
-}
-
-// renderLoginPage renders the login page, which lists existing customers and
-// provides a form to open a new account as a new customer.
+// renderLoginPage renders the login page, which provides a form for existing
+// customers to log in and a link for new customers to sign up.
+//
+// Customers that are already logged in are redirected to their accounts.
 func (h *Handler) renderLoginPage(w http.ResponseWriter, r *http.Request) {
-	customers, err := h.queryCustomers(r.Context())
-	if err != nil {
+	customerID, err := h.sessionCustomerID(r)
+	if err == nil {
+		http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts", customerID), http.StatusSeeOther)
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
 		renderError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	h.renderLogin(w, r, http.StatusOK, "", "")
+}
+
+func (h *Handler) renderLogin(w http.ResponseWriter, r *http.Request, code int, username, formError string) {
 	data := struct {
 		pageData
-		Customers []customer
+		Username string
+		Error    string
 	}{
-		pageData:  pageData{Title: "Welcome"},
-		Customers: customers,
+		pageData: pageData{Title: "Welcome", CSRFToken: csrfToken(w, r)},
+		Username: username,
+		Error:    formError,
 	}
 
+	w.WriteHeader(code)
+
 	if err := templates.Get("login").ExecuteTemplate(w, "login.html", data); err != nil {
 		renderError(w, http.StatusInternalServerError, err.Error())
 	}
 }
 
+// logIn handles the login form submission. It checks the customer's password,
+// starts a new session and redirects to the accounts list.
+func (h *Handler) logIn(w http.ResponseWriter, r *http.Request) {
+	username := normalizeUsername(r.FormValue("username"))
+
+	customerID, ok, err := h.checkCredentials(r.Context(), username, r.FormValue("password"))
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		h.renderLogin(w, r, http.StatusUnauthorized, username, "Incorrect username or password.")
+		return
+	}
+
+	if _, err := h.startSession(w, r, customerID); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts", customerID), http.StatusSeeOther)
+}
+
+// logOut ends the customer's session and redirects to the login page.
+func (h *Handler) logOut(w http.ResponseWriter, r *http.Request) {
+	if err := h.endSession(w, r); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
 // renderSignupPage renders the form for new customers to open their first account.
-func (h *Handler) renderSignupPage(w http.ResponseWriter, _ *http.Request) {
-	h.renderSignup(w, "")
+func (h *Handler) renderSignupPage(w http.ResponseWriter, r *http.Request) {
+	h.renderSignup(w, r, "")
 }
 
-func (h *Handler) renderSignup(w http.ResponseWriter, formError string) {
+func (h *Handler) renderSignup(w http.ResponseWriter, r *http.Request, formError string) {
 	data := struct {
 		pageData
 		Error string
 	}{
-		pageData: pageData{Title: "Sign Up"},
+		pageData: pageData{Title: "Sign Up", CSRFToken: csrfToken(w, r)},
 		Error:    formError,
 	}
 
@@ -64,18 +109,48 @@ func (h *Handler) renderSignup(w http.ResponseWriter, formError string) {
 
 // openAccountForNewCustomer handles the form submission to create a new
 // customer and open their first account. It dispatches an
-// OpenAccountForNewCustomer command and redirects to the accounts list.
+// OpenAccountForNewCustomer command, sets the customer's login credentials,
+// logs them in and redirects to the accounts list.
 func (h *Handler) openAccountForNewCustomer(w http.ResponseWriter, r *http.Request) {
 	customerName := strings.TrimSpace(r.FormValue("customer_name"))
 	accountName := strings.TrimSpace(r.FormValue("account_name"))
+	username := normalizeUsername(r.FormValue("username"))
+	password := r.FormValue("password")
 
 	if customerName == "" || accountName == "" {
-		h.renderSignup(w, "Name is required.")
+		h.renderSignup(w, r, "Name is required.")
+		return
+	}
+
+	if username == "" {
+		h.renderSignup(w, r, "Username is required.")
+		return
+	}
+
+	if formError := checkPasswordLength(password); formError != "" {
+		h.renderSignup(w, r, formError)
+		return
+	}
+
+	accountType, maturityDate, formError := parseAccountType(r)
+	if formError != "" {
+		h.renderSignup(w, r, formError)
 		return
 	}
 
-	customerID := uuid.NewString()
-	accountID := generateAccountID()
+	// The customer's ID is derived from the form's idempotency key, so if the
+	// form is submitted twice the username is already reserved for the very
+	// customer that the first submission created.
+	customerID := idempotentID(r, "customer")
+	accountID := idempotentAccountID(r)
+
+	if reserved, err := h.reserveUsername(r.Context(), username, customerID); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !reserved {
+		h.renderSignup(w, r, "That username is already taken.")
+		return
+	}
 
 	if err := h.CommandExecutor.ExecuteCommand(
 		r.Context(),
@@ -84,43 +159,130 @@ func (h *Handler) openAccountForNewCustomer(w http.ResponseWriter, r *http.Reque
 			CustomerName: customerName,
 			AccountID:    accountID,
 			AccountName:  accountName,
+			AccountType:  accountType,
+			MaturityDate: maturityDate,
 		},
 	); err != nil {
 		renderError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if err := h.setCredentials(r.Context(), customerID, username, password); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The credentials are not replaced if the form was submitted before, so
+	// the customer is only logged in if the credentials that were actually
+	// stored match those in this submission.
+	storedID, ok, err := h.checkCredentials(r.Context(), username, password)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok || storedID != customerID {
+		h.renderLogin(w, r, http.StatusUnauthorized, username, "Incorrect username or password.")
+		return
+	}
+
+	if _, err := h.startSession(w, r, customerID); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts", customerID), http.StatusSeeOther)
 }
 
-// queryCustomers loads all customers for display on the login page.
-func (h *Handler) queryCustomers(ctx context.Context) ([]customer, error) {
-	rows, err := h.DB.QueryContext(
+// setCredentials hashes a customer's password and dispatches a
+// SetCustomerCredentials command.
+func (h *Handler) setCredentials(ctx context.Context, customerID, username, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return h.CommandExecutor.ExecuteCommand(
+		ctx,
+		&commands.SetCustomerCredentials{
+			CustomerID:   customerID,
+			Username:     username,
+			PasswordHash: hash,
+		},
+	)
+}
+
+// checkCredentials returns the ID of the customer with the given username if
+// password is correct. ok is false if there is no such customer or the
+// password is incorrect.
+func (h *Handler) checkCredentials(
+	ctx context.Context,
+	username, password string,
+) (customerID string, ok bool, err error) {
+	var hash string
+
+	err = h.DB.QueryRowContext(
 		ctx,
 		`SELECT
-			id,
-			name
-		FROM customers
-		ORDER BY name`,
+			customer_id,
+			password_hash
+		FROM customer_credentials
+		WHERE username = ?`,
+		username,
+	).Scan(
+		&customerID,
+		&hash,
 	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
 	if err != nil {
-		return nil, err
+		return "", false, err
 	}
-	defer rows.Close()
 
-	var customers []customer
-	for rows.Next() {
-		var c customer
+	return customerID, checkPassword(hash, password), nil
+}
 
-		if err := rows.Scan(
-			&c.ID,
-			&c.Name,
-		); err != nil {
-			return nil, err
-		}
+// reserveUsername reserves a username for a customer. It returns false if the
+// username is already reserved for another customer.
+//
+// Reserving a username that is already reserved for the same customer
+// succeeds, so that a form or request that is submitted twice is not rejected.
+func (h *Handler) reserveUsername(ctx context.Context, username, customerID string) (bool, error) {
+	reserved := true
 
-		customers = append(customers, c)
+	err := h.CommandExecutor.ExecuteCommand(
+		ctx,
+		&commands.ReserveUsername{
+			Username:   username,
+			CustomerID: customerID,
+		},
+		dogma.WithEventObserver(func(context.Context, *events.UsernameReserved) (bool, error) {
+			return true, nil
+		}),
+		dogma.WithEventObserver(func(context.Context, *events.UsernameReservationDeclined) (bool, error) {
+			reserved = false
+			return true, nil
+		}),
+	)
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		// The username was reserved for this customer by an earlier request.
+		err = nil
 	}
 
-	return customers, rows.Err()
+	return reserved, err
+}
+
+// normalizeUsername returns the canonical form of a username, so that
+// usernames are not case-sensitive.
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// checkPasswordLength returns an error message if password is too short to be
+// used.
+func checkPasswordLength(password string) string {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return fmt.Sprintf("Password must be at least %d characters.", minPasswordLength)
+	}
+	return ""
 }
@@ -26,6 +26,33 @@ func (m money) String() string {
 	return p.Sprintf("%s%d.%02d", prefix, v/100, v%100)
 }
 
+// Decimal returns the amount without a currency symbol or digit grouping, in
+// the format accepted by parseMoney.
+func (m money) Decimal() string {
+	v := int64(m)
+	sign := ""
+
+	if v < 0 {
+		v = -v
+		sign = "-"
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// interestRate represents an annual interest rate in basis points.
+type interestRate int64
+
+func (r interestRate) String() string {
+	return fmt.Sprintf("%d.%02d%%", r/100, r%100)
+}
+
+// Decimal returns the rate as a percentage without a percent sign, in the
+// format accepted by parseInterestRate.
+func (r interestRate) Decimal() string {
+	return fmt.Sprintf("%d.%02d", r/100, r%100)
+}
+
 // parseMoney parses a string like "100.00" or "100" into cents.
 func parseMoney(s string) (money, error) {
 	s = strings.TrimSpace(s)
@@ -42,3 +69,39 @@ func parseMoney(s string) (money, error) {
 
 	return money(int64(math.Round(f * 100))), nil
 }
+
+// parseLimit parses a string like "100.00" into cents. Unlike parseMoney it
+// accepts zero, which removes the limit.
+func parseLimit(s string) (money, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "$")
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if f < 0 {
+		return 0, fmt.Errorf("limit must not be negative")
+	}
+
+	return money(int64(math.Round(f * 100))), nil
+}
+
+// parseInterestRate parses a percentage like "2.50" into basis points. Zero is
+// accepted, which stops interest being paid.
+func parseInterestRate(s string) (interestRate, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, "%")
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if f < 0 || f > 100 {
+		return 0, fmt.Errorf("interest rate must be between 0%% and 100%%")
+	}
+
+	return interestRate(int64(math.Round(f * 100))), nil
+}
@@ -0,0 +1,346 @@
+package ui
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/dogmatiq/example/ui/templates"
+)
+
+const (
+	// operatorSessionCookie is the name of the cookie that holds the session
+	// token of a member of operations staff.
+	operatorSessionCookie = "operator_session"
+
+	// operatorSessionLifetime is the length of time an operator's session
+	// remains valid after they log in.
+	operatorSessionLifetime = 8 * time.Hour
+)
+
+// operatorSession is a logged-in session of a member of operations staff.
+//
+// Operator sessions are held in memory, so operators must log in again
+// whenever the server is restarted.
+type operatorSession struct {
+	Operator  string
+	ExpiresAt time.Time
+}
+
+// operatorHandlerFunc is a handler for a page on the operations surface.
+// operator is the username of the member of operations staff that is logged
+// in.
+type operatorHandlerFunc func(w http.ResponseWriter, r *http.Request, operator string)
+
+// operatorAccount is a summary of a bank account as displayed to operations
+// staff.
+type operatorAccount struct {
+	account
+	CustomerName string
+}
+
+// renderOperatorLoginPage renders the login page for operations staff.
+//
+// Operators that are already logged in are redirected to the accounts list.
+func (h *Handler) renderOperatorLoginPage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.sessionOperator(r); ok {
+		http.Redirect(w, r, "/ops/accounts", http.StatusSeeOther)
+		return
+	}
+
+	h.renderOperatorLogin(w, r, http.StatusOK, "", "")
+}
+
+func (h *Handler) renderOperatorLogin(w http.ResponseWriter, r *http.Request, code int, username, formError string) {
+	data := struct {
+		pageData
+		Username string
+		Error    string
+	}{
+		pageData: pageData{Title: "Operations", CSRFToken: csrfToken(w, r)},
+		Username: username,
+		Error:    formError,
+	}
+
+	w.WriteHeader(code)
+
+	if err := templates.Get("opslogin").ExecuteTemplate(w, "opslogin.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// operatorLogIn handles the operations login form submission. It checks the
+// operator's password against [Handler.Operators] and starts a new operator
+// session.
+func (h *Handler) operatorLogIn(w http.ResponseWriter, r *http.Request) {
+	username := normalizeUsername(r.FormValue("username"))
+
+	hash, ok := h.Operators[username]
+	if !ok || !checkPassword(hash, r.FormValue("password")) {
+		h.renderOperatorLogin(w, r, http.StatusUnauthorized, username, "Incorrect username or password.")
+		return
+	}
+
+	if err := h.startOperatorSession(w, r, username); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, "/ops/accounts", http.StatusSeeOther)
+}
+
+// operatorLogOut ends the operator's session and redirects to the operations
+// login page.
+func (h *Handler) operatorLogOut(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(operatorSessionCookie); err == nil {
+		h.operatorSessionsM.Lock()
+		delete(h.operatorSessions, c.Value)
+		h.operatorSessionsM.Unlock()
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     operatorSessionCookie,
+		Path:     "/ops",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	http.Redirect(w, r, "/ops", http.StatusSeeOther)
+}
+
+// startOperatorSession creates a new session for a member of operations staff
+// and sets the operator session cookie on the response.
+//
+// The cookie is only sent with same-site requests for the operations pages,
+// so an operator's session can not be used by the customer UI, nor by forms
+// submitted from other sites.
+func (h *Handler) startOperatorSession(w http.ResponseWriter, r *http.Request, operator string) error {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(token)
+	now := time.Now()
+	expiresAt := now.Add(operatorSessionLifetime)
+
+	h.operatorSessionsM.Lock()
+	defer h.operatorSessionsM.Unlock()
+
+	for t, s := range h.operatorSessions {
+		if !s.ExpiresAt.After(now) {
+			delete(h.operatorSessions, t)
+		}
+	}
+
+	if h.operatorSessions == nil {
+		h.operatorSessions = map[string]operatorSession{}
+	}
+
+	h.operatorSessions[encoded] = operatorSession{
+		Operator:  operator,
+		ExpiresAt: expiresAt,
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     operatorSessionCookie,
+		Value:    encoded,
+		Path:     "/ops",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return nil
+}
+
+// sessionOperator returns the username of the operator that is logged in to
+// the session associated with the request. ok is false if there is no valid
+// session, or the operator has since been removed from [Handler.Operators].
+func (h *Handler) sessionOperator(r *http.Request) (operator string, ok bool) {
+	c, err := r.Cookie(operatorSessionCookie)
+	if err != nil || c.Value == "" {
+		return "", false
+	}
+
+	h.operatorSessionsM.Lock()
+	s, ok := h.operatorSessions[c.Value]
+	h.operatorSessionsM.Unlock()
+
+	if !ok || !s.ExpiresAt.After(time.Now()) {
+		return "", false
+	}
+
+	if _, ok := h.Operators[s.Operator]; !ok {
+		return "", false
+	}
+
+	return s.Operator, true
+}
+
+// requireOperator wraps an operations page handler such that it is only
+// invoked if the request belongs to an operator session.
+//
+// Visitors without an operator session are redirected to the operations login
+// page.
+func (h *Handler) requireOperator(fn operatorHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		operator, ok := h.sessionOperator(r)
+		if !ok {
+			http.Redirect(w, r, "/ops", http.StatusSeeOther)
+			return
+		}
+
+		fn(w, r, operator)
+	}
+}
+
+// renderOperatorAccountsPage renders the list of all accounts held with the
+// bank, for operations staff.
+func (h *Handler) renderOperatorAccountsPage(w http.ResponseWriter, r *http.Request, operator string) {
+	accounts, err := h.queryOperatorAccounts(r.Context())
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := struct {
+		pageData
+		Accounts []operatorAccount
+	}{
+		pageData: pageData{
+			Title:        "All Accounts",
+			OperatorName: operator,
+			CSRFToken:    csrfToken(w, r),
+		},
+		Accounts: accounts,
+	}
+
+	if err := templates.Get("opsaccounts").ExecuteTemplate(w, "opsaccounts.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// renderOperatorAccountPage renders the page used by operations staff to
+// manage a single account.
+func (h *Handler) renderOperatorAccountPage(w http.ResponseWriter, r *http.Request, operator string) {
+	h.renderOperatorAccount(w, r, operator, "")
+}
+
+func (h *Handler) renderOperatorAccount(w http.ResponseWriter, r *http.Request, operator, formError string) {
+	acct, err := h.queryAccountDetails(r.Context(), r.PathValue("accountID"))
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	customerName, err := h.queryAccountCustomerName(r.Context(), acct.ID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	holds, err := h.queryHolds(r.Context(), acct.ID)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := struct {
+		pageData
+		Account operatorAccount
+		Holds   []hold
+		Error   string
+	}{
+		pageData: pageData{
+			Title:        "Account: " + acct.Name,
+			OperatorName: operator,
+			CSRFToken:    csrfToken(w, r),
+		},
+		Account: operatorAccount{
+			account:      acct,
+			CustomerName: customerName,
+		},
+		Holds: holds,
+		Error: formError,
+	}
+
+	if formError != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	if err := templates.Get("opsaccount").ExecuteTemplate(w, "opsaccount.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// queryAccountCustomerName returns the name of the customer that holds an
+// account.
+func (h *Handler) queryAccountCustomerName(
+	ctx context.Context,
+	accountID string,
+) (name string, err error) {
+	err = h.DB.QueryRowContext(
+		ctx,
+		`SELECT c.name
+		FROM accounts AS a
+		INNER JOIN customers AS c
+			ON c.id = a.customer_id
+		WHERE a.id = ?`,
+		accountID,
+	).Scan(&name)
+	return name, err
+}
+
+// queryOperatorAccounts returns every account held with the bank, ordered by
+// customer name and then account name.
+func (h *Handler) queryOperatorAccounts(ctx context.Context) ([]operatorAccount, error) {
+	rows, err := h.DB.QueryContext(
+		ctx,
+		`SELECT
+			a.id,
+			a.name,
+			a.account_type,
+			a.balance,
+			a.is_closed,
+			a.freeze_type,
+			c.name
+		FROM accounts AS a
+		INNER JOIN customers AS c
+			ON c.id = a.customer_id
+		ORDER BY
+			c.name,
+			a.name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []operatorAccount
+
+	for rows.Next() {
+		var a operatorAccount
+
+		if err := rows.Scan(
+			&a.ID,
+			&a.Name,
+			&a.AccountType,
+			&a.Balance,
+			&a.IsClosed,
+			&a.FreezeType,
+			&a.CustomerName,
+		); err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, a)
+	}
+
+	return accounts, rows.Err()
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/example/messages"
+)
+
+// errIdempotencyKeyReused indicates that a request's idempotency key has
+// already been used to start a different transaction.
+var errIdempotencyKeyReused = errors.New("idempotency key has already been used for a different transaction")
+
+// transactionOutcome is the state of a transaction, as recorded by the
+// transactions projection.
+type transactionOutcome struct {
+	TransactionType string
+	AccountID       string
+	ToAccountID     string
+	Amount          int64
+	Status          string
+	Reason          messages.DebitFailureReason
+}
+
+// queryOriginalOutcome returns the outcome of a transaction that was started
+// by an earlier request with the same idempotency key.
+//
+// It is called when executing a command does not produce an approval or
+// decline, either because the transaction is scheduled for a later time, or
+// because TransactionHandler ignored the command as a retry of a transaction
+// that has already started. want describes the transaction that the request
+// attempted to start. Its status is "pending" if the transaction is not yet
+// known to the projection.
+//
+// It returns errIdempotencyKeyReused if the original transaction differs from
+// want.
+func (h *Handler) queryOriginalOutcome(
+	ctx context.Context,
+	transactionID string,
+	want transactionOutcome,
+) (transactionOutcome, error) {
+	var o transactionOutcome
+
+	err := h.DB.QueryRowContext(
+		ctx,
+		`SELECT
+			transaction_type,
+			account_id,
+			to_account_id,
+			amount,
+			status,
+			reason
+		FROM transactions
+		WHERE transaction_id = ?`,
+		transactionID,
+	).Scan(
+		&o.TransactionType,
+		&o.AccountID,
+		&o.ToAccountID,
+		&o.Amount,
+		&o.Status,
+		&o.Reason,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		want.Status = "pending"
+		return want, nil
+	}
+	if err != nil {
+		return transactionOutcome{}, err
+	}
+
+	if o.TransactionType != want.TransactionType ||
+		o.AccountID != want.AccountID ||
+		o.ToAccountID != want.ToAccountID ||
+		o.Amount != want.Amount {
+		return transactionOutcome{}, errIdempotencyKeyReused
+	}
+
+	return o, nil
+}
+
+// originalOutcomeError returns the message to display on a form if the
+// original transaction started by an earlier submission of the same form was
+// declined or failed. label is the type of transaction as shown to the
+// customer, such as "Deposit".
+//
+// It returns an empty string if the transaction was approved or is still
+// pending.
+func (h *Handler) originalOutcomeError(
+	ctx context.Context,
+	transactionID, label string,
+	want transactionOutcome,
+) (string, error) {
+	o, err := h.queryOriginalOutcome(ctx, transactionID, want)
+	if errors.Is(err, errIdempotencyKeyReused) {
+		return "This form has already been submitted with different details. Please reload the page and try again.", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch o.Status {
+	case "declined":
+		return fmt.Sprintf("%s declined — %s.", label, o.Reason), nil
+	case "failed":
+		return label + " failed.", nil
+	default:
+		return "", nil
+	}
+}
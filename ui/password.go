@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// passwordIterations is the number of PBKDF2 iterations used when hashing
+	// new passwords.
+	passwordIterations = 600_000
+
+	// passwordSaltLength is the length, in bytes, of the random salt used when
+	// hashing new passwords.
+	passwordSaltLength = 16
+
+	// passwordKeyLength is the length, in bytes, of the derived key.
+	passwordKeyLength = 32
+
+	// minPasswordLength is the minimum number of characters in a password.
+	minPasswordLength = 8
+)
+
+// HashPassword returns a salted hash of password, suitable for storage. It is
+// used to hash both customer passwords and the passwords of operations staff
+// supplied in [Handler.Operators].
+//
+// The hash is of the form "pbkdf2-sha256$<iterations>$<salt>$<key>", so that
+// the iteration count may be increased without invalidating existing hashes.
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, passwordKeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"pbkdf2-sha256$%d$%s$%s",
+		passwordIterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// checkPassword returns true if password matches a hash produced by
+// HashPassword.
+func checkPassword(hash, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+
+	want, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	got, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
@@ -0,0 +1,417 @@
+package ui
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/dogmatiq/example/ui/templates"
+	"github.com/google/uuid"
+)
+
+// payee is a saved payee as displayed in the payees list and the transfer
+// destination picker.
+type payee struct {
+	ID                    string
+	Name                  string
+	AccountID             string
+	IsThirdParty          bool
+	BSB                   string
+	AccountNumber         string
+	AccountName           string
+	CoolingOffUntil       time.Time
+	CoolingOffLimit       money
+	TotalDuringCoolingOff money
+}
+
+// Description returns a description of the payee's account.
+func (p payee) Description() string {
+	if p.IsThirdParty {
+		return fmt.Sprintf("BSB %s, account %s", p.BSB, p.AccountNumber)
+	}
+	return "account " + p.AccountID
+}
+
+// IsCoolingOff returns true if transfers to the payee are limited because it
+// was added recently.
+func (p payee) IsCoolingOff() bool {
+	return p.CoolingOffLimit > 0 && time.Now().Before(p.CoolingOffUntil)
+}
+
+// RemainingCoolingOffLimit returns the amount that may still be transferred to
+// the payee during its cooling-off period.
+func (p payee) RemainingCoolingOffLimit() money {
+	return max(p.CoolingOffLimit-p.TotalDuringCoolingOff, 0)
+}
+
+// thirdPartyPayee returns the details of a third-party payee's account, or nil
+// if the payee's account is held by this bank.
+func (p payee) thirdPartyPayee() *messages.ThirdPartyPayee {
+	if !p.IsThirdParty {
+		return nil
+	}
+
+	return &messages.ThirdPartyPayee{
+		BSB:           p.BSB,
+		AccountNumber: p.AccountNumber,
+		AccountName:   p.AccountName,
+	}
+}
+
+// payeeForm is the content of a submitted payee form, used to populate the form
+// when it is rendered again.
+type payeeForm struct {
+	Name          string
+	AccountID     string
+	BSB           string
+	AccountNumber string
+	AccountName   string
+
+	// PayeeWarning explains why the account name of a third-party payee could
+	// not be confirmed. The customer proceeds by submitting the form again.
+	PayeeWarning string
+
+	// ConfirmedPayee identifies the payee that the customer has been warned
+	// about, as returned by confirmedPayee.
+	ConfirmedPayee string
+}
+
+// renderPayeesPage renders the page listing a customer's payees.
+func (h *Handler) renderPayeesPage(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+
+	customerName, err := h.queryCustomerName(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	payees, err := h.queryPayees(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := struct {
+		pageData
+		Payees []payee
+	}{
+		pageData: pageData{
+			Title:        "Payees",
+			CustomerID:   customerID,
+			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
+		},
+		Payees: payees,
+	}
+
+	if err := templates.Get("payees").ExecuteTemplate(w, "payees.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// renderNewPayeePage renders the form used to add a payee.
+func (h *Handler) renderNewPayeePage(w http.ResponseWriter, r *http.Request) {
+	h.renderNewPayee(w, r, payeeForm{}, "")
+}
+
+func (h *Handler) renderNewPayee(w http.ResponseWriter, r *http.Request, form payeeForm, formError string) {
+	customerID := r.PathValue("customerID")
+
+	customerName, err := h.queryCustomerName(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	data := struct {
+		pageData
+		Form  payeeForm
+		Error string
+	}{
+		pageData: pageData{
+			Title:        "Add a Payee",
+			CustomerID:   customerID,
+			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
+		},
+		Form:  form,
+		Error: formError,
+	}
+
+	if formError != "" || form.PayeeWarning != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	if err := templates.Get("newpayee").ExecuteTemplate(w, "newpayee.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// addPayee processes a new payee form submission.
+func (h *Handler) addPayee(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+
+	form := payeeForm{
+		Name:           strings.Join(strings.Fields(r.FormValue("name")), " "),
+		AccountID:      strings.TrimSpace(r.FormValue("account_id")),
+		BSB:            strings.TrimSpace(r.FormValue("bsb")),
+		AccountNumber:  strings.TrimSpace(r.FormValue("account_number")),
+		AccountName:    strings.TrimSpace(r.FormValue("account_name")),
+		ConfirmedPayee: r.FormValue("confirmed_payee"),
+	}
+
+	c := &commands.AddPayee{
+		CustomerID: customerID,
+		PayeeID:    idempotentID(r, "payee"),
+		Name:       form.Name,
+		AccountID:  form.AccountID,
+	}
+
+	if form.BSB != "" || form.AccountNumber != "" || form.AccountName != "" {
+		if form.AccountID != "" {
+			h.renderNewPayee(w, r, form, "Enter either an account with this bank or a third-party account, not both.")
+			return
+		}
+
+		p, err := messages.ParseThirdPartyPayee(form.BSB, form.AccountNumber, form.AccountName)
+		if err != nil {
+			h.renderNewPayee(w, r, form, "Invalid third-party account — "+err.Error()+".")
+			return
+		}
+
+		// Confirm the account name with the third-party bank, unless the
+		// customer has already been warned about this payee and has chosen
+		// to proceed.
+		if form.ConfirmedPayee != confirmedPayee(p) {
+			warning, err := h.checkPayee(r.Context(), p)
+			if err != nil {
+				renderError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			if warning != "" {
+				form.PayeeWarning = warning
+				form.ConfirmedPayee = confirmedPayee(p)
+				h.renderNewPayee(w, r, form, "")
+				return
+			}
+		}
+
+		c.AccountID = p.AccountID()
+		c.ThirdPartyPayee = &p
+
+		if c.Name == "" {
+			c.Name = p.AccountName
+		}
+	} else if form.AccountID != "" {
+		acct, err := h.queryAccountDetails(r.Context(), form.AccountID)
+		if errors.Is(err, sql.ErrNoRows) || acct.IsClosed {
+			h.renderNewPayee(w, r, form, "There is no open account with that account number.")
+			return
+		}
+		if err != nil {
+			renderError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if acct.CustomerID == customerID {
+			h.renderNewPayee(w, r, form, "That is one of your own accounts, which you can transfer to without adding it as a payee.")
+			return
+		}
+
+		if c.Name == "" {
+			h.renderNewPayee(w, r, form, "Payee name is required.")
+			return
+		}
+	} else {
+		h.renderNewPayee(w, r, form, "Enter an account with this bank or a third-party account.")
+		return
+	}
+
+	var exists bool
+	if err := h.DB.QueryRowContext(
+		r.Context(),
+		`SELECT EXISTS (
+			SELECT 1
+			FROM payees
+			WHERE customer_id = ?
+				AND account_id = ?
+				AND to_third_party_bank = ?
+		)`,
+		customerID,
+		c.AccountID,
+		c.ThirdPartyPayee != nil,
+	).Scan(&exists); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if exists {
+		h.renderNewPayee(w, r, form, "That account is already one of your payees.")
+		return
+	}
+
+	if err := h.CommandExecutor.ExecuteCommand(r.Context(), c); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/c/%s/payees", customerID), http.StatusSeeOther)
+}
+
+// removePayee processes a request to remove a payee.
+func (h *Handler) removePayee(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+
+	if err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.RemovePayee{
+			CustomerID: customerID,
+			PayeeID:    r.PathValue("payeeID"),
+		},
+	); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/c/%s/payees", customerID), http.StatusSeeOther)
+}
+
+// checkPayee confirms the account name of a third-party payee with the
+// third-party bank. It returns a warning to show the customer if the name can
+// not be confirmed, or an empty string if it matches.
+func (h *Handler) checkPayee(ctx context.Context, p messages.ThirdPartyPayee) (string, error) {
+	var result *events.PayeeChecked
+
+	if err := h.CommandExecutor.ExecuteCommand(
+		ctx,
+		&commands.CheckPayee{
+			CheckID: uuid.NewString(),
+			Payee:   p,
+		},
+		dogma.WithEventObserver(func(_ context.Context, e *events.PayeeChecked) (bool, error) {
+			result = e
+			return true, nil
+		}),
+	); err != nil {
+		return "", err
+	}
+
+	switch result.Result {
+	case messages.PayeeMatched:
+		return "", nil
+	case messages.PayeeCloselyMatched:
+		return fmt.Sprintf(
+			"The account is held in the name of %s, which is not quite the name you entered. Check the details with the person you are paying.",
+			result.MatchedAccountName,
+		), nil
+	case messages.PayeeNotMatched:
+		return "The account name you entered does not match the account. If you continue, the money may go to someone you did not intend to pay and you may not get it back.", nil
+	default:
+		return "The account name could not be confirmed with the other bank. Check the details with the person you are paying.", nil
+	}
+}
+
+// confirmedPayee returns the value of the form field that records that the
+// customer has been warned about p.
+func confirmedPayee(p messages.ThirdPartyPayee) string {
+	return p.AccountID() + ":" + p.AccountName
+}
+
+// queryPayees loads the payees saved by a customer, ordered by name.
+func (h *Handler) queryPayees(ctx context.Context, customerID string) ([]payee, error) {
+	rows, err := h.DB.QueryContext(
+		ctx,
+		`SELECT
+			id,
+			name,
+			account_id,
+			to_third_party_bank,
+			bsb,
+			account_number,
+			account_name,
+			cooling_off_until,
+			cooling_off_limit,
+			total_during_cooling_off
+		FROM payees
+		WHERE customer_id = ?
+		ORDER BY name, added_at`,
+		customerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payees []payee
+
+	for rows.Next() {
+		p, err := scanPayee(rows)
+		if err != nil {
+			return nil, err
+		}
+		payees = append(payees, p)
+	}
+
+	return payees, rows.Err()
+}
+
+// queryPayee loads one of the payees saved by a customer. It returns
+// sql.ErrNoRows if the customer has no such payee.
+func (h *Handler) queryPayee(ctx context.Context, customerID, payeeID string) (payee, error) {
+	return scanPayee(
+		h.DB.QueryRowContext(
+			ctx,
+			`SELECT
+				id,
+				name,
+				account_id,
+				to_third_party_bank,
+				bsb,
+				account_number,
+				account_name,
+				cooling_off_until,
+				cooling_off_limit,
+				total_during_cooling_off
+			FROM payees
+			WHERE customer_id = ?
+				AND id = ?`,
+			customerID,
+			payeeID,
+		),
+	)
+}
+
+// scanPayee scans a row selected by queryPayees or queryPayee.
+func scanPayee(row interface{ Scan(...any) error }) (payee, error) {
+	var (
+		p               payee
+		coolingOffUntil sql.NullTime
+	)
+
+	err := row.Scan(
+		&p.ID,
+		&p.Name,
+		&p.AccountID,
+		&p.IsThirdParty,
+		&p.BSB,
+		&p.AccountNumber,
+		&p.AccountName,
+		&coolingOffUntil,
+		&p.CoolingOffLimit,
+		&p.TotalDuringCoolingOff,
+	)
+
+	p.CoolingOffUntil = coolingOffUntil.Time
+
+	return p, err
+}
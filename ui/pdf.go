@@ -0,0 +1,247 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// pdfFont identifies one of the standard PDF fonts, which every PDF reader
+// provides, so that no font data needs to be embedded in the document.
+type pdfFont int
+
+const (
+	pdfHelvetica pdfFont = iota
+	pdfHelveticaBold
+)
+
+// pdfFontNames is the base font name of each pdfFont, indexed by its value.
+var pdfFontNames = []string{
+	pdfHelvetica:     "Helvetica",
+	pdfHelveticaBold: "Helvetica-Bold",
+}
+
+// pdfFontWidths contains the advance width of the printable ASCII characters,
+// from space to tilde, in each font, in thousandths of the font size. They
+// are taken from the Adobe font metrics of the standard fonts.
+var pdfFontWidths = [][95]int{
+	pdfHelvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	pdfHelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+const (
+	// pdfPageWidth and pdfPageHeight are the dimensions of an A4 page, in
+	// points.
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+)
+
+// pdfDocument is a minimal writer for PDF documents consisting of text and
+// lines on A4 pages, sufficient for producing printable statements without
+// any external tools.
+//
+// Coordinates are in points, measured from the bottom-left corner of the
+// page. Text is limited to the characters of the Windows-1252 code page;
+// any others are replaced with a question mark.
+type pdfDocument struct {
+	pages   []*bytes.Buffer
+	current int
+}
+
+// NewPage begins a new page and makes it the target of subsequent drawing
+// operations.
+func (d *pdfDocument) NewPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.current = len(d.pages) - 1
+}
+
+// PageCount returns the number of pages in the document.
+func (d *pdfDocument) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage makes the page with the given zero-based index the target of
+// subsequent drawing operations.
+func (d *pdfDocument) SetPage(i int) {
+	d.current = i
+}
+
+// Text draws s with its baseline starting at (x, y).
+func (d *pdfDocument) Text(x, y float64, f pdfFont, size float64, s string) {
+	fmt.Fprintf(
+		d.pages[d.current],
+		"BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		f,
+		pdfNumber(size),
+		pdfNumber(x),
+		pdfNumber(y),
+		pdfEscape(pdfEncode(s)),
+	)
+}
+
+// TextRight draws s with its baseline ending at (x, y).
+func (d *pdfDocument) TextRight(x, y float64, f pdfFont, size float64, s string) {
+	d.Text(x-pdfTextWidth(f, size, s), y, f, size, s)
+}
+
+// Line draws a line from (x1, y1) to (x2, y2) with the given width.
+func (d *pdfDocument) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(
+		d.pages[d.current],
+		"%s w %s %s m %s %s l S\n",
+		pdfNumber(width),
+		pdfNumber(x1),
+		pdfNumber(y1),
+		pdfNumber(x2),
+		pdfNumber(y2),
+	)
+}
+
+// WriteTo writes the document to w.
+func (d *pdfDocument) WriteTo(w io.Writer) (int64, error) {
+	var (
+		buf     bytes.Buffer
+		offsets []int
+	)
+
+	object := func(format string, args ...any) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n", len(offsets))
+		fmt.Fprintf(&buf, format, args...)
+		buf.WriteString("\nendobj\n")
+	}
+
+	// Objects 1 to 4 are the catalog, the page tree and the fonts. They are
+	// followed by a page object and a content stream for each page.
+	const firstPage = 5
+
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPage+i*2))
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages))
+
+	for _, name := range pdfFontNames {
+		object("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name)
+	}
+
+	for i, p := range d.pages {
+		object(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F0 3 0 R /F1 4 0 R >> >> /Contents %d 0 R >>",
+			pdfNumber(pdfPageWidth),
+			pdfNumber(pdfPageHeight),
+			firstPage+i*2+1,
+		)
+		object("<< /Length %d >>\nstream\n%s\nendstream", p.Len(), p.Bytes())
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(
+		&buf,
+		"trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1,
+		xref,
+	)
+
+	return buf.WriteTo(w)
+}
+
+// pdfTextWidth returns the width of s when drawn in the given font and size.
+func pdfTextWidth(f pdfFont, size float64, s string) float64 {
+	width := 0
+	for _, c := range pdfEncode(s) {
+		if c >= ' ' && c <= '~' {
+			width += pdfFontWidths[f][c-' ']
+		} else {
+			width += 556
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// pdfTruncate shortens s with a trailing ellipsis so that it fits within the
+// given width.
+func pdfTruncate(f pdfFont, size, width float64, s string) string {
+	if pdfTextWidth(f, size, s) <= width {
+		return s
+	}
+
+	r := []rune(s)
+	for len(r) > 0 && pdfTextWidth(f, size, string(r)+"...") > width {
+		r = r[:len(r)-1]
+	}
+
+	return strings.TrimSpace(string(r)) + "..."
+}
+
+// pdfWinAnsi maps the characters that Windows-1252 places in the range 0x80
+// to 0x9f to their code.
+var pdfWinAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// pdfEncode converts s to the Windows-1252 encoding used by the fonts.
+func pdfEncode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			b = append(b, byte(r))
+		case pdfWinAnsi[r] != 0:
+			b = append(b, pdfWinAnsi[r])
+		default:
+			b = append(b, '?')
+		}
+	}
+	return b
+}
+
+// pdfEscape escapes b for use within a PDF literal string.
+func pdfEscape(b []byte) []byte {
+	var out []byte
+	for _, c := range b {
+		switch c {
+		case '\\', '(', ')':
+			out = append(out, '\\', c)
+		case '\r', '\n':
+			out = append(out, ' ')
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// pdfNumber formats v as a PDF real number, rounded to a hundredth of a
+// point.
+func pdfNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,93 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/dogmatiq/projectionkit/sqlprojection"
+)
+
+// CredentialsProjectionHandler maintains the credentials that customers use to
+// log in.
+//
+// The UI queries the customer_credentials table to authenticate customers on
+// the login page. Only password hashes are stored; the passwords themselves
+// never leave the UI.
+type CredentialsProjectionHandler struct {
+	sqlprojection.NoCompactBehavior
+}
+
+// Configure configs the engine for this projection.
+func (h *CredentialsProjectionHandler) Configure(c dogma.ProjectionConfigurer) {
+	c.Identity("credentials", "aaf451ae-4e10-4a78-b89a-a690825d0c50")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.CustomerCredentialsSet](),
+		dogma.HandlesEvent[*events.CustomerPasswordChanged](),
+	)
+}
+
+// HandleEvent inserts into or updates the "customer_credentials" table whenever
+// a customer's credentials are set or their password is changed.
+func (h *CredentialsProjectionHandler) HandleEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionEventScope,
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.CustomerCredentialsSet:
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO customer_credentials (
+				customer_id,
+				username,
+				password_hash
+			) VALUES (
+				?,
+				?,
+				?
+			) ON CONFLICT (customer_id) DO UPDATE SET
+				username = excluded.username,
+				password_hash = excluded.password_hash`,
+			x.CustomerID,
+			x.Username,
+			x.PasswordHash,
+		)
+		return err
+
+	case *events.CustomerPasswordChanged:
+		_, err := tx.ExecContext(
+			ctx,
+			`UPDATE customer_credentials SET
+				password_hash = ?
+			WHERE customer_id = ?`,
+			x.PasswordHash,
+			x.CustomerID,
+		)
+		return err
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// Reset clears all projection data.
+func (h *CredentialsProjectionHandler) Reset(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionResetScope,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM customer_credentials`,
+	)
+	return err
+}
+
+// tables returns the names of the tables that contain the projection's data.
+func (h *CredentialsProjectionHandler) tables() []string {
+	return []string{"customer_credentials"}
+}
@@ -0,0 +1,103 @@
+package projections_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/ui/projections"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_CredentialsProjectionHandler(t *testing.T) {
+	t.Run(
+		"when a customer's password is changed",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			Begin(t, &example.App{ReadDB: db}).
+				EnableHandlers("credentials").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccountForNewCustomer{
+							CustomerID:   "C001",
+							CustomerName: "Anna Smith",
+							AccountID:    "A001",
+							AccountName:  "Savings",
+							AccountType:  messages.Everyday,
+						},
+					),
+					ExecuteCommand(
+						&commands.SetCustomerCredentials{
+							CustomerID:   "C001",
+							Username:     "anna",
+							PasswordHash: "<hash 1>",
+						},
+					),
+					ExecuteCommand(
+						&commands.ChangeCustomerPassword{
+							CustomerID:   "C001",
+							PasswordHash: "<hash 2>",
+						},
+					),
+				)
+
+			rows, err := db.Query(
+				`SELECT
+					customer_id,
+					username,
+					password_hash
+				FROM customer_credentials`,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+
+			if !rows.Next() {
+				t.Fatal("expected a database row")
+			}
+
+			var (
+				customerID   string
+				username     string
+				passwordHash string
+			)
+
+			if err := rows.Scan(
+				&customerID,
+				&username,
+				&passwordHash,
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			if customerID != "C001" {
+				t.Fatalf(
+					`expected customer ID to be "C001", got "%s"`,
+					customerID,
+				)
+			}
+
+			if username != "anna" {
+				t.Fatalf(
+					`expected username to be "anna", got "%s"`,
+					username,
+				)
+			}
+
+			if passwordHash != "<hash 2>" {
+				t.Fatalf(
+					`expected password hash to be "<hash 2>", got "%s"`,
+					passwordHash,
+				)
+			}
+
+			if rows.Next() {
+				t.Fatal("expected no more rows")
+			}
+		},
+	)
+}
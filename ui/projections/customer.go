@@ -69,3 +69,8 @@ func (h *CustomerProjectionHandler) Reset(
 	)
 	return err
 }
+
+// tables returns the names of the tables that contain the projection's data.
+func (h *CustomerProjectionHandler) tables() []string {
+	return []string{"customers"}
+}
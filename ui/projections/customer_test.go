@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
 	"github.com/dogmatiq/example/messages/commands"
 	"github.com/dogmatiq/example/ui/projections"
 	. "github.com/dogmatiq/testkit"
@@ -25,6 +26,7 @@ func Test_CustomerProjectionHandler(t *testing.T) {
 							CustomerName: "Anna Smith",
 							AccountID:    "A001",
 							AccountName:  "Savings",
+							AccountType:  messages.Everyday,
 						},
 					),
 				)
@@ -5,7 +5,13 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/dogmatiq/projectionkit/sqlprojection"
 	_ "github.com/mattn/go-sqlite3" // install "sqlite3" driver
@@ -16,24 +22,31 @@ var (
 	// instance.
 	counter atomic.Int64
 
-	// schema contains the SQL schema files.
+	// migrations contains the SQL schema migrations, named
+	// "<version>_<name>.sql".
 	//
-	//go:embed *.sql
-	schema embed.FS
+	//go:embed migrations/*.sql
+	migrations embed.FS
 )
 
-// NewDB returns an in-memory SQLite database, with database tables necessary to
-// run the example application.
+// NewDB returns a SQLite database stored at the given path, with the database
+// tables necessary to run the example application. If path is empty, the
+// database is held in memory.
+//
+// Any schema migrations that have not yet been applied to the database are
+// applied before it is returned.
 //
 // It returns an error if the database is unable to be opened, or the schema is
-// unable to be created.
-func NewDB() (*sql.DB, error) {
+// unable to be migrated.
+func NewDB(path string) (*sql.DB, error) {
 	ctx := context.Background()
 
-	db, err := sql.Open(
-		"sqlite3",
-		fmt.Sprintf("file:db%d?mode=memory&cache=shared", counter.Add(1)),
-	)
+	dsn := fmt.Sprintf("file:db%d?mode=memory&cache=shared", counter.Add(1))
+	if path != "" {
+		dsn = fmt.Sprintf("file:%s?_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate", path)
+	}
+
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +56,7 @@ func NewDB() (*sql.DB, error) {
 		return nil, err
 	}
 
-	if err := CreateSchema(ctx, db); err != nil {
+	if err := Migrate(ctx, db); err != nil {
 		db.Close()
 		return nil, err
 	}
@@ -57,7 +70,7 @@ func NewDB() (*sql.DB, error) {
 // It panics if the database is unable to be opened, or the schema is unable to
 // be created.
 func MustNewDB() *sql.DB {
-	db, err := NewDB()
+	db, err := NewDB("")
 	if err != nil {
 		panic(err)
 	}
@@ -65,23 +78,153 @@ func MustNewDB() *sql.DB {
 	return db
 }
 
-// CreateSchema creates the schema elements required by the projection handlers.
-func CreateSchema(ctx context.Context, db *sql.DB) error {
-	entries, err := schema.ReadDir(".")
+// migration is a single schema migration.
+type migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// Migrate applies the schema migrations required by the projection handlers
+// that have not yet been applied to db, in order of their version.
+//
+// Each migration is applied in its own transaction, and recorded in the
+// schema_migrations table so that it is never applied again. The earliest
+// migrations use "CREATE TABLE IF NOT EXISTS" so that databases created before
+// migrations were introduced are adopted without error.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(
+		ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER   NOT NULL,
+			name       TEXT      NOT NULL,
+			applied_at TIMESTAMP NOT NULL,
+
+			PRIMARY KEY (version)
+		)`,
+	); err != nil {
+		return err
+	}
+
+	all, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedMigrations(ctx, db)
 	if err != nil {
 		return err
 	}
 
+	for _, m := range all {
+		if applied[m.Version] {
+			continue
+		}
+
+		if err := applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("unable to apply migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// loadMigrations returns the embedded schema migrations, ordered by version.
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []migration
+	versions := map[int]string{}
+
 	for _, e := range entries {
-		s, err := schema.ReadFile(e.Name())
+		file := e.Name()
+
+		prefix, name, ok := strings.Cut(strings.TrimSuffix(file, ".sql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s must be named <version>_<name>.sql", file)
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("migration %s must have a positive version number", file)
+		}
+
+		if other, ok := versions[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", other, file)
+		}
+		versions[version] = file
+
+		s, err := migrations.ReadFile(path.Join("migrations", file))
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		if _, err := db.ExecContext(ctx, string(s)); err != nil {
-			return err
+		result = append(result, migration{version, name, string(s)})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+// appliedMigrations returns the set of migration versions that have already
+// been applied to db.
+func appliedMigrations(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT version FROM schema_migrations`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
 		}
+		applied[version] = true
 	}
 
-	return nil
+	return applied, rows.Err()
+}
+
+// applyMigration applies a single migration to db and records that it has
+// been applied.
+func applyMigration(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO schema_migrations (
+			version,
+			name,
+			applied_at
+		) VALUES (
+			?, ?, ?
+		)`,
+		m.Version,
+		m.Name,
+		time.Now().UTC(),
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
@@ -0,0 +1,74 @@
+package projections_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dogmatiq/example/ui/projections"
+)
+
+func Test_NewDB(t *testing.T) {
+	t.Run(
+		"when a database is opened at a path",
+		func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "bank.db")
+
+			db, err := projections.NewDB(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want int
+			if err := db.QueryRow(
+				`SELECT COUNT(*) FROM schema_migrations`,
+			).Scan(&want); err != nil {
+				t.Fatal(err)
+			}
+
+			if want == 0 {
+				t.Fatal("expected migrations to be recorded")
+			}
+
+			if _, err := db.Exec(
+				`INSERT INTO customers (id, name) VALUES ('C001', 'Anna Smith')`,
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			db.Close()
+
+			t.Run(
+				"it retains existing data and does not reapply migrations when reopened",
+				func(t *testing.T) {
+					db, err := projections.NewDB(path)
+					if err != nil {
+						t.Fatal(err)
+					}
+					t.Cleanup(func() { db.Close() })
+
+					var got int
+					if err := db.QueryRow(
+						`SELECT COUNT(*) FROM schema_migrations`,
+					).Scan(&got); err != nil {
+						t.Fatal(err)
+					}
+
+					if got != want {
+						t.Fatalf("unexpected number of migrations: got %d, want %d", got, want)
+					}
+
+					var name string
+					if err := db.QueryRow(
+						`SELECT name FROM customers WHERE id = 'C001'`,
+					).Scan(&name); err != nil {
+						t.Fatal(err)
+					}
+
+					if name != "Anna Smith" {
+						t.Fatalf("unexpected customer name: got %q, want %q", name, "Anna Smith")
+					}
+				},
+			)
+		},
+	)
+}
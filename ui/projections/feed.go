@@ -0,0 +1,176 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/events"
+)
+
+// Feed notifies subscribers when a projection changes the data shown for an
+// account, so that the UI can push updates to the browser instead of polling.
+//
+// Subscribers are notified after the projection's changes are committed, so
+// that any query they make reflects the change. Notifications are coalesced;
+// a subscriber that is slow to respond receives a single notification for any
+// number of changes.
+type Feed struct {
+	DB *sql.DB
+
+	m    sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+}
+
+// SubscribeToAccount returns a channel that receives a value whenever the
+// data shown for the given account changes. The returned function must be
+// called to unsubscribe.
+func (f *Feed) SubscribeToAccount(accountID string) (<-chan struct{}, func()) {
+	return f.subscribe("account:" + accountID)
+}
+
+// SubscribeToCustomer returns a channel that receives a value whenever the
+// data shown for any of the given customer's accounts changes, including when
+// a new account is opened. The returned function must be called to
+// unsubscribe.
+func (f *Feed) SubscribeToCustomer(customerID string) (<-chan struct{}, func()) {
+	return f.subscribe("customer:" + customerID)
+}
+
+// Watch returns a projection handler that forwards to h, and notifies the
+// feed's subscribers of any change to an account once h has handled an event.
+func (f *Feed) Watch(h dogma.ProjectionMessageHandler) dogma.ProjectionMessageHandler {
+	return &watchedProjection{h, f}
+}
+
+func (f *Feed) subscribe(key string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.subs == nil {
+		f.subs = map[string]map[chan struct{}]struct{}{}
+	}
+
+	subs := f.subs[key]
+	if subs == nil {
+		subs = map[chan struct{}]struct{}{}
+		f.subs[key] = subs
+	}
+	subs[ch] = struct{}{}
+
+	return ch, func() {
+		f.m.Lock()
+		defer f.m.Unlock()
+
+		delete(subs, ch)
+		if len(subs) == 0 {
+			delete(f.subs, key)
+		}
+	}
+}
+
+// publish notifies the subscribers to the given account, and to the customer
+// that owns it.
+func (f *Feed) publish(ctx context.Context, accountID string) error {
+	var customerID string
+
+	err := f.DB.QueryRowContext(
+		ctx,
+		`SELECT customer_id
+		FROM accounts
+		WHERE id = ?`,
+		accountID,
+	).Scan(&customerID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	for _, key := range []string{"account:" + accountID, "customer:" + customerID} {
+		for ch := range f.subs[key] {
+			select {
+			case ch <- struct{}{}:
+			default:
+				// A notification is already pending.
+			}
+		}
+	}
+
+	return nil
+}
+
+// watchedProjection is a projection handler that notifies a feed after it
+// handles each event.
+type watchedProjection struct {
+	dogma.ProjectionMessageHandler
+
+	feed *Feed
+}
+
+func (h *watchedProjection) HandleEvent(
+	ctx context.Context,
+	s dogma.ProjectionEventScope,
+	e dogma.Event,
+) (uint64, error) {
+	cp, err := h.ProjectionMessageHandler.HandleEvent(ctx, s, e)
+	if err != nil {
+		return cp, err
+	}
+
+	if id := changedAccountID(e); id != "" {
+		// The event has already been applied, so failing to notify
+		// subscribers only delays the update until the next change.
+		h.feed.publish(ctx, id) // nolint:errcheck
+	}
+
+	return cp, nil
+}
+
+// changedAccountID returns the ID of the account whose data shown in the UI
+// is changed by e, or an empty string if there is none.
+func changedAccountID(e dogma.Event) string {
+	switch x := e.(type) {
+	case *events.AccountOpened:
+		return x.AccountID
+	case *events.AccountCredited:
+		return x.AccountID
+	case *events.AccountDebited:
+		return x.AccountID
+	case *events.DailyDebitLimitChanged:
+		return x.AccountID
+	case *events.OverdraftLimitChanged:
+		return x.AccountID
+	case *events.InterestRateChanged:
+		return x.AccountID
+	case *events.AccountClosed:
+		return x.AccountID
+	case *events.ClosedAccountSwept:
+		return x.AccountID
+	case *events.AccountFrozen:
+		return x.AccountID
+	case *events.AccountUnfrozen:
+		return x.AccountID
+	case *events.DebitAuthorized:
+		return x.AccountID
+	case *events.HoldCaptured:
+		return x.AccountID
+	case *events.HoldReleased:
+		return x.AccountID
+	case *events.HoldExpired:
+		return x.AccountID
+	case *events.TransferStarted:
+		return x.FromAccountID
+	case *events.TransferProceeded:
+		return x.FromAccountID
+	case *events.TransferCancelled:
+		return x.FromAccountID
+	default:
+		return ""
+	}
+}
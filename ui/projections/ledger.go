@@ -18,7 +18,9 @@ import (
 // the account balance.
 //
 // The UI queries the accounts table to list a customer's accounts and their
-// balances, and the ledger table to display transaction history.
+// balances, and the ledger table to display transaction history. Holds that
+// have been authorized but not yet captured are kept in the holds table, and
+// are only recorded in the ledger once they are captured.
 type LedgerProjectionHandler struct {
 	sqlprojection.NoCompactBehavior
 }
@@ -31,11 +33,32 @@ func (h *LedgerProjectionHandler) Configure(c dogma.ProjectionConfigurer) {
 		dogma.HandlesEvent[*events.AccountOpened](),
 		dogma.HandlesEvent[*events.AccountCredited](),
 		dogma.HandlesEvent[*events.AccountDebited](),
+		dogma.HandlesEvent[*events.DailyDebitLimitChanged](),
+		dogma.HandlesEvent[*events.OverdraftLimitChanged](),
+		dogma.HandlesEvent[*events.InterestRateChanged](),
+		dogma.HandlesEvent[*events.AccountClosed](),
+		dogma.HandlesEvent[*events.ClosedAccountSwept](),
+		dogma.HandlesEvent[*events.AccountFrozen](),
+		dogma.HandlesEvent[*events.AccountUnfrozen](),
+		dogma.HandlesEvent[*events.DebitAuthorized](),
+		dogma.HandlesEvent[*events.HoldCaptured](),
+		dogma.HandlesEvent[*events.HoldReleased](),
+		dogma.HandlesEvent[*events.HoldExpired](),
 	)
 }
 
 // HandleEvent inserts into the "ledger" table whenever an account is credited
-// or debited, and updates the "accounts" table to reflect the current balance.
+// or debited, and updates the "accounts" table to reflect the current balance,
+// daily debit limit, overdraft limit, interest rate and whether the account
+// is closed or frozen.
+//
+// When an account is closed its remaining balance is recorded as a debit, as it
+// is swept to another account. The same applies to a balance that is returned
+// to a closed account and then swept again.
+//
+// Authorized debits are inserted into the "holds" table, and removed when the
+// hold is captured, released or expired. A captured hold is recorded in the
+// ledger as a debit.
 func (h *LedgerProjectionHandler) HandleEvent(
 	ctx context.Context,
 	tx *sql.Tx,
@@ -49,6 +72,28 @@ func (h *LedgerProjectionHandler) HandleEvent(
 		return h.accountCredited(ctx, tx, s, x)
 	case *events.AccountDebited:
 		return h.accountDebited(ctx, tx, s, x)
+	case *events.DailyDebitLimitChanged:
+		return h.dailyDebitLimitChanged(ctx, tx, x)
+	case *events.OverdraftLimitChanged:
+		return h.overdraftLimitChanged(ctx, tx, x)
+	case *events.InterestRateChanged:
+		return h.interestRateChanged(ctx, tx, x)
+	case *events.AccountClosed:
+		return h.accountClosed(ctx, tx, s, x)
+	case *events.ClosedAccountSwept:
+		return h.sweepBalance(ctx, tx, s, x.AccountID, x.TransactionID, x.Balance)
+	case *events.AccountFrozen:
+		return h.accountFrozen(ctx, tx, x)
+	case *events.AccountUnfrozen:
+		return h.accountUnfrozen(ctx, tx, x)
+	case *events.DebitAuthorized:
+		return h.debitAuthorized(ctx, tx, s, x)
+	case *events.HoldCaptured:
+		return h.holdCaptured(ctx, tx, s, x)
+	case *events.HoldReleased:
+		return h.removeHold(ctx, tx, x.AccountID, x.HoldID, x.Amount)
+	case *events.HoldExpired:
+		return h.removeHold(ctx, tx, x.AccountID, x.HoldID, x.Amount)
 	default:
 		panic(dogma.UnexpectedMessage)
 	}
@@ -64,8 +109,14 @@ func (h *LedgerProjectionHandler) accountOpened(
 		`INSERT INTO accounts (
 			id,
 			name,
-			customer_id
+			customer_id,
+			account_type,
+			maturity_date,
+			daily_debit_limit
 		) VALUES (
+			?,
+			?,
+			?,
 			?,
 			?,
 			?
@@ -73,6 +124,91 @@ func (h *LedgerProjectionHandler) accountOpened(
 		x.AccountID,
 		x.AccountName,
 		x.CustomerID,
+		x.AccountType,
+		x.MaturityDate,
+		x.DailyDebitLimit,
+	)
+	return err
+}
+
+func (h *LedgerProjectionHandler) dailyDebitLimitChanged(
+	ctx context.Context,
+	tx *sql.Tx,
+	x *events.DailyDebitLimitChanged,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE accounts SET
+			daily_debit_limit = ?
+		WHERE id = ?`,
+		x.DailyDebitLimit,
+		x.AccountID,
+	)
+	return err
+}
+
+func (h *LedgerProjectionHandler) overdraftLimitChanged(
+	ctx context.Context,
+	tx *sql.Tx,
+	x *events.OverdraftLimitChanged,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE accounts SET
+			overdraft_limit = ?
+		WHERE id = ?`,
+		x.OverdraftLimit,
+		x.AccountID,
+	)
+	return err
+}
+
+func (h *LedgerProjectionHandler) interestRateChanged(
+	ctx context.Context,
+	tx *sql.Tx,
+	x *events.InterestRateChanged,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE accounts SET
+			interest_rate = ?
+		WHERE id = ?`,
+		x.InterestRate,
+		x.AccountID,
+	)
+	return err
+}
+
+func (h *LedgerProjectionHandler) accountFrozen(
+	ctx context.Context,
+	tx *sql.Tx,
+	x *events.AccountFrozen,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE accounts SET
+			freeze_type = ?,
+			freeze_reason = ?
+		WHERE id = ?`,
+		x.FreezeType,
+		x.Reason,
+		x.AccountID,
+	)
+	return err
+}
+
+func (h *LedgerProjectionHandler) accountUnfrozen(
+	ctx context.Context,
+	tx *sql.Tx,
+	x *events.AccountUnfrozen,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE accounts SET
+			freeze_type = '',
+			freeze_reason = ''
+		WHERE id = ?`,
+		x.AccountID,
 	)
 	return err
 }
@@ -193,6 +329,198 @@ func (h *LedgerProjectionHandler) accountDebited(
 	return err
 }
 
+func (h *LedgerProjectionHandler) accountClosed(
+	ctx context.Context,
+	tx *sql.Tx,
+	s dogma.ProjectionEventScope,
+	x *events.AccountClosed,
+) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE accounts SET
+			is_closed = TRUE
+		WHERE id = ?`,
+		x.AccountID,
+	); err != nil {
+		return err
+	}
+
+	if x.Balance == 0 {
+		return nil
+	}
+
+	return h.sweepBalance(ctx, tx, s, x.AccountID, x.TransactionID, x.Balance)
+}
+
+// sweepBalance records the removal of the balance of a closed account as a
+// debit in the ledger.
+func (h *LedgerProjectionHandler) sweepBalance(
+	ctx context.Context,
+	tx *sql.Tx,
+	s dogma.ProjectionEventScope,
+	accountID, transactionID string,
+	amount int64,
+) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE accounts SET
+			balance = 0
+		WHERE id = ?`,
+		accountID,
+	); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO ledger (
+			account_id,
+			transaction_id,
+			transaction_order,
+			description,
+			debit,
+			balance,
+			created_at
+		) VALUES (
+			?,
+			?,
+			(SELECT COUNT(*) FROM ledger WHERE transaction_id = ?),
+			?,
+			?,
+			0,
+			?
+		)`,
+		accountID,
+		transactionID,
+		transactionID,
+		debitDescription(messages.AccountClosure),
+		amount,
+		s.RecordedAt(),
+	)
+	return err
+}
+
+func (h *LedgerProjectionHandler) debitAuthorized(
+	ctx context.Context,
+	tx *sql.Tx,
+	s dogma.ProjectionEventScope,
+	x *events.DebitAuthorized,
+) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE accounts SET
+			held_amount = held_amount + ?
+		WHERE id = ?`,
+		x.Amount,
+		x.AccountID,
+	); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO holds (
+			account_id,
+			hold_id,
+			description,
+			amount,
+			created_at
+		) VALUES (
+			?, ?, ?, ?, ?
+		)`,
+		x.AccountID,
+		x.HoldID,
+		cardPaymentDescription(x.Merchant),
+		x.Amount,
+		s.RecordedAt(),
+	)
+	return err
+}
+
+func (h *LedgerProjectionHandler) holdCaptured(
+	ctx context.Context,
+	tx *sql.Tx,
+	s dogma.ProjectionEventScope,
+	x *events.HoldCaptured,
+) error {
+	if err := h.removeHold(ctx, tx, x.AccountID, x.HoldID, x.Amount); err != nil {
+		return err
+	}
+
+	var balance int64
+
+	if err := tx.QueryRowContext(
+		ctx,
+		`UPDATE accounts SET
+			balance = balance - ?
+		WHERE id = ?
+		RETURNING balance`,
+		x.Amount,
+		x.AccountID,
+	).Scan(&balance); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO ledger (
+			account_id,
+			transaction_id,
+			transaction_order,
+			description,
+			debit,
+			balance,
+			created_at
+		) VALUES (
+			?,
+			?,
+			(SELECT COUNT(*) FROM ledger WHERE transaction_id = ?),
+			?,
+			?,
+			?,
+			?
+		)`,
+		x.AccountID,
+		x.HoldID,
+		x.HoldID,
+		cardPaymentDescription(x.Merchant),
+		x.Amount,
+		balance,
+		s.RecordedAt(),
+	)
+	return err
+}
+
+// removeHold deletes a hold that is no longer outstanding and removes its
+// amount from the account's held amount.
+func (h *LedgerProjectionHandler) removeHold(
+	ctx context.Context,
+	tx *sql.Tx,
+	accountID, holdID string,
+	amount int64,
+) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM holds
+		WHERE account_id = ?
+		AND hold_id = ?`,
+		accountID,
+		holdID,
+	); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE accounts SET
+			held_amount = held_amount - ?
+		WHERE id = ?`,
+		amount,
+		accountID,
+	)
+	return err
+}
+
 // Reset clears all projection data.
 func (h *LedgerProjectionHandler) Reset(
 	ctx context.Context,
@@ -203,6 +531,10 @@ func (h *LedgerProjectionHandler) Reset(
 		return err
 	}
 
+	if _, err := tx.ExecContext(ctx, `DELETE FROM holds`); err != nil {
+		return err
+	}
+
 	if _, err := tx.ExecContext(ctx, `DELETE FROM accounts`); err != nil {
 		return err
 	}
@@ -210,6 +542,11 @@ func (h *LedgerProjectionHandler) Reset(
 	return nil
 }
 
+// tables returns the names of the tables that contain the projection's data.
+func (h *LedgerProjectionHandler) tables() []string {
+	return []string{"accounts", "ledger", "holds"}
+}
+
 // creditDescription returns the human-readable description for a credit entry
 // in the ledger.
 //
@@ -225,6 +562,12 @@ func creditDescription(t messages.TransactionType, isRefund bool) string {
 		return "Deposit"
 	case messages.Transfer:
 		return "Incoming transfer"
+	case messages.AccountClosure:
+		return "Transfer from closed account"
+	case messages.Interest:
+		return "Interest"
+	case messages.ThirdPartyPayment:
+		return "Payment from another bank"
 	default:
 		panic("unrecognized transaction type for credit: " + string(t))
 	}
@@ -238,7 +581,15 @@ func debitDescription(t messages.TransactionType) string {
 		return "Withdrawal"
 	case messages.Transfer:
 		return "Outgoing transfer"
+	case messages.AccountClosure:
+		return "Account closure"
 	default:
 		panic("unrecognized transaction type for debit: " + string(t))
 	}
 }
+
+// cardPaymentDescription returns the human-readable description for a card
+// payment made to merchant, whether it is held or has been captured.
+func cardPaymentDescription(merchant string) string {
+	return "Card payment to " + merchant
+}
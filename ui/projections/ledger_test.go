@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/domain"
+	"github.com/dogmatiq/example/messages"
 	"github.com/dogmatiq/example/messages/commands"
 	"github.com/dogmatiq/example/ui/projections"
 	. "github.com/dogmatiq/testkit"
@@ -26,26 +28,30 @@ func Test_LedgerProjectionHandler(t *testing.T) {
 							CustomerName: "Anna Smith",
 							AccountID:    "A001",
 							AccountName:  "Savings",
+							AccountType:  messages.Savings,
 						},
 					),
 				)
 
 			var (
-				name       string
-				customerID string
-				balance    int64
+				name        string
+				customerID  string
+				accountType string
+				balance     int64
 			)
 
 			if err := db.QueryRow(
 				`SELECT
 					name,
 					customer_id,
+					account_type,
 					balance
 				FROM accounts
 				WHERE id = "A001"`,
 			).Scan(
 				&name,
 				&customerID,
+				&accountType,
 				&balance,
 			); err != nil {
 				t.Fatal(err)
@@ -57,12 +63,266 @@ func Test_LedgerProjectionHandler(t *testing.T) {
 			if customerID != "C001" {
 				t.Fatalf(`expected customer ID to be "C001", got %q`, customerID)
 			}
+			if accountType != "everyday" {
+				t.Fatalf(`expected account type to be "everyday", got %q`, accountType)
+			}
 			if balance != 0 {
 				t.Fatalf(`expected balance to be 0, got %d`, balance)
 			}
 		},
 	)
 
+	t.Run(
+		"when the daily debit limit is changed",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			Begin(t, &example.App{ReadDB: db}).
+				EnableHandlers("ledger").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccountForNewCustomer{
+							CustomerID:   "C001",
+							CustomerName: "Anna Smith",
+							AccountID:    "A001",
+							AccountName:  "Savings",
+							AccountType:  messages.Savings,
+						},
+					),
+					ExecuteCommand(
+						&commands.ChangeDailyDebitLimit{
+							AccountID:       "A001",
+							DailyDebitLimit: 1000,
+						},
+					),
+				)
+
+			var limit int64
+
+			if err := db.QueryRow(
+				`SELECT daily_debit_limit
+				FROM accounts
+				WHERE id = "A001"`,
+			).Scan(&limit); err != nil {
+				t.Fatal(err)
+			}
+
+			if limit != 1000 {
+				t.Fatalf(`expected daily debit limit to be 1000, got %d`, limit)
+			}
+		},
+	)
+
+	t.Run(
+		"when an account is overdrawn",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			Begin(t, &example.App{ReadDB: db}).
+				EnableHandlers("ledger").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccountForNewCustomer{
+							CustomerID:   "C001",
+							CustomerName: "Anna Smith",
+							AccountID:    "A001",
+							AccountName:  "Savings",
+							AccountType:  messages.Savings,
+						},
+					),
+					ExecuteCommand(
+						&commands.SetOverdraftLimit{
+							AccountID:      "A001",
+							OverdraftLimit: 1000,
+						},
+					),
+					ExecuteCommand(
+						&commands.Withdraw{
+							TransactionID: "T001",
+							AccountID:     "A001",
+							Amount:        250,
+							ScheduledTime: time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC),
+						},
+					),
+				)
+
+			var (
+				accountBalance int64
+				overdraftLimit int64
+			)
+
+			if err := db.QueryRow(
+				`SELECT balance, overdraft_limit
+				FROM accounts
+				WHERE id = "A001"`,
+			).Scan(&accountBalance, &overdraftLimit); err != nil {
+				t.Fatal(err)
+			}
+
+			if accountBalance != -250 {
+				t.Fatalf(`expected account balance to be -250, got %d`, accountBalance)
+			}
+
+			if overdraftLimit != 1000 {
+				t.Fatalf(`expected overdraft limit to be 1000, got %d`, overdraftLimit)
+			}
+
+			var ledgerBalance int64
+
+			if err := db.QueryRow(
+				`SELECT balance
+				FROM ledger
+				WHERE account_id = "A001"
+					AND transaction_id = "T001"`,
+			).Scan(&ledgerBalance); err != nil {
+				t.Fatal(err)
+			}
+
+			if ledgerBalance != -250 {
+				t.Fatalf(`expected ledger balance to be -250, got %d`, ledgerBalance)
+			}
+		},
+	)
+
+	t.Run(
+		"when interest is paid",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			Begin(
+				t,
+				&example.App{ReadDB: db},
+				StartTimeAt(
+					time.Date(2001, time.January, 31, 11, 22, 33, 0, time.UTC),
+				),
+			).
+				EnableHandlers("ledger").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccountForNewCustomer{
+							CustomerID:   "C001",
+							CustomerName: "Anna Smith",
+							AccountID:    "A001",
+							AccountName:  "Savings",
+							AccountType:  messages.Savings,
+						},
+					),
+					ExecuteCommand(
+						&commands.Deposit{
+							TransactionID: "T001",
+							AccountID:     "A001",
+							Amount:        3650000,
+						},
+					),
+					ExecuteCommand(
+						&commands.SetInterestRate{
+							AccountID:    "A001",
+							InterestRate: 1000,
+						},
+					),
+					AdvanceTime(
+						ToTime(time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC)),
+					),
+				)
+
+			var (
+				description string
+				credit      int64
+			)
+
+			if err := db.QueryRow(
+				`SELECT description, credit
+				FROM ledger
+				WHERE account_id = "A001"
+					AND transaction_id = ?`,
+				domain.InterestTransactionID("A001:1", "2001-01"),
+			).Scan(&description, &credit); err != nil {
+				t.Fatal(err)
+			}
+
+			if description != "Interest" {
+				t.Fatalf(`expected description to be "Interest", got %q`, description)
+			}
+
+			if credit != 1000 {
+				t.Fatalf(`expected credit to be 1000, got %d`, credit)
+			}
+		},
+	)
+
+	t.Run(
+		"when an account is frozen and unfrozen",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			test := Begin(t, &example.App{ReadDB: db}).
+				EnableHandlers("ledger").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccountForNewCustomer{
+							CustomerID:   "C001",
+							CustomerName: "Anna Smith",
+							AccountID:    "A001",
+							AccountName:  "Savings",
+							AccountType:  messages.Savings,
+						},
+					),
+					ExecuteCommand(
+						&commands.FreezeAccount{
+							AccountID:  "A001",
+							FreezeType: messages.FullFreeze,
+							Reason:     "suspected fraud",
+							Operator:   "j.citizen",
+						},
+					),
+				)
+
+			var freezeType, freezeReason string
+
+			if err := db.QueryRow(
+				`SELECT freeze_type, freeze_reason
+				FROM accounts
+				WHERE id = "A001"`,
+			).Scan(&freezeType, &freezeReason); err != nil {
+				t.Fatal(err)
+			}
+
+			if freezeType != "full" {
+				t.Fatalf(`expected freeze type to be "full", got %q`, freezeType)
+			}
+
+			if freezeReason != "suspected fraud" {
+				t.Fatalf(`expected freeze reason to be "suspected fraud", got %q`, freezeReason)
+			}
+
+			test.Prepare(
+				ExecuteCommand(
+					&commands.UnfreezeAccount{
+						AccountID: "A001",
+						Reason:    "investigation complete",
+						Operator:  "j.citizen",
+					},
+				),
+			)
+
+			if err := db.QueryRow(
+				`SELECT freeze_type
+				FROM accounts
+				WHERE id = "A001"`,
+			).Scan(&freezeType); err != nil {
+				t.Fatal(err)
+			}
+
+			if freezeType != "" {
+				t.Fatalf(`expected account to be unfrozen, got freeze type %q`, freezeType)
+			}
+		},
+	)
+
 	t.Run(
 		"when an account is credited",
 		func(t *testing.T) {
@@ -78,6 +338,7 @@ func Test_LedgerProjectionHandler(t *testing.T) {
 							CustomerName: "Anna Smith",
 							AccountID:    "A001",
 							AccountName:  "Savings",
+							AccountType:  messages.Savings,
 						},
 					),
 					ExecuteCommand(
@@ -156,6 +417,7 @@ func Test_LedgerProjectionHandler(t *testing.T) {
 							CustomerName: "Anna Smith",
 							AccountID:    "A001",
 							AccountName:  "Savings",
+							AccountType:  messages.Savings,
 						},
 					),
 					ExecuteCommand(
@@ -243,6 +505,7 @@ func Test_LedgerProjectionHandler(t *testing.T) {
 							CustomerName: "Anna Smith",
 							AccountID:    "A001",
 							AccountName:  "Savings",
+							AccountType:  messages.Savings,
 						},
 					),
 					ExecuteCommand(
@@ -251,6 +514,7 @@ func Test_LedgerProjectionHandler(t *testing.T) {
 							CustomerName: "Bob Jones",
 							AccountID:    "A002",
 							AccountName:  "Checking",
+							AccountType:  messages.Everyday,
 						},
 					),
 					ExecuteCommand(
@@ -318,6 +582,7 @@ func Test_LedgerProjectionHandler(t *testing.T) {
 							CustomerName: "Anna Smith",
 							AccountID:    "A001",
 							AccountName:  "Savings",
+							AccountType:  messages.Savings,
 						},
 					),
 					ExecuteCommand(
@@ -326,6 +591,7 @@ func Test_LedgerProjectionHandler(t *testing.T) {
 							CustomerName: "Bob Jones",
 							AccountID:    "A002",
 							AccountName:  "Checking",
+							AccountType:  messages.Everyday,
 						},
 					),
 					ExecuteCommand(
@@ -363,4 +629,213 @@ func Test_LedgerProjectionHandler(t *testing.T) {
 			}
 		},
 	)
+
+	t.Run(
+		"when an account is closed",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			Begin(t, &example.App{ReadDB: db}).
+				EnableHandlers("ledger").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccountForNewCustomer{
+							CustomerID:   "C001",
+							CustomerName: "Anna Smith",
+							AccountID:    "A001",
+							AccountName:  "Savings",
+							AccountType:  messages.Savings,
+						},
+					),
+					ExecuteCommand(
+						&commands.OpenAccount{
+							CustomerID:  "C001",
+							AccountID:   "A002",
+							AccountName: "Checking",
+							AccountType: messages.Everyday,
+						},
+					),
+					ExecuteCommand(
+						&commands.Deposit{
+							TransactionID: "T001",
+							AccountID:     "A001",
+							Amount:        150,
+						},
+					),
+					ExecuteCommand(
+						&commands.CloseAccount{
+							TransactionID:    "T002",
+							AccountID:        "A001",
+							SweepToAccountID: "A002",
+						},
+					),
+				)
+
+			var (
+				isClosed       bool
+				accountBalance int64
+			)
+
+			if err := db.QueryRow(
+				`SELECT is_closed, balance
+				FROM accounts
+				WHERE id = "A001"`,
+			).Scan(&isClosed, &accountBalance); err != nil {
+				t.Fatal(err)
+			}
+
+			if !isClosed {
+				t.Fatal("expected account to be closed")
+			}
+
+			if accountBalance != 0 {
+				t.Fatalf(`expected account balance to be 0, got %d`, accountBalance)
+			}
+
+			var (
+				description string
+				debit       int64
+			)
+
+			if err := db.QueryRow(
+				`SELECT description, debit
+				FROM ledger
+				WHERE account_id = "A001"
+					AND transaction_id = "T002"`,
+			).Scan(&description, &debit); err != nil {
+				t.Fatal(err)
+			}
+
+			if description != "Account closure" {
+				t.Fatalf(`expected description to be "Account closure", got %q`, description)
+			}
+
+			if debit != 150 {
+				t.Fatalf(`expected debit to be 150, got %d`, debit)
+			}
+
+			if err := db.QueryRow(
+				`SELECT balance
+				FROM accounts
+				WHERE id = "A002"`,
+			).Scan(&accountBalance); err != nil {
+				t.Fatal(err)
+			}
+
+			if accountBalance != 150 {
+				t.Fatalf(`expected account balance to be 150, got %d`, accountBalance)
+			}
+		},
+	)
+
+	t.Run(
+		"when a hold is authorized and later captured",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			test := Begin(t, &example.App{ReadDB: db}).
+				EnableHandlers("ledger").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccountForNewCustomer{
+							CustomerID:   "C001",
+							CustomerName: "Anna Smith",
+							AccountID:    "A001",
+							AccountName:  "Savings",
+							AccountType:  messages.Savings,
+						},
+					),
+					ExecuteCommand(
+						&commands.Deposit{
+							TransactionID: "T001",
+							AccountID:     "A001",
+							Amount:        1000,
+						},
+					),
+					ExecuteCommand(
+						&commands.AuthorizeDebit{
+							HoldID:    "H001",
+							AccountID: "A001",
+							Merchant:  "Corner Cafe",
+							Amount:    450,
+						},
+					),
+				)
+
+			var (
+				description string
+				amount      int64
+				heldAmount  int64
+			)
+
+			if err := db.QueryRow(
+				`SELECT h.description, h.amount, a.held_amount
+				FROM holds AS h
+				INNER JOIN accounts AS a
+					ON a.id = h.account_id
+				WHERE h.hold_id = "H001"`,
+			).Scan(&description, &amount, &heldAmount); err != nil {
+				t.Fatal(err)
+			}
+
+			if description != "Card payment to Corner Cafe" {
+				t.Fatalf(`expected description to be "Card payment to Corner Cafe", got %q`, description)
+			}
+
+			if amount != 450 {
+				t.Fatalf(`expected hold amount to be 450, got %d`, amount)
+			}
+
+			if heldAmount != 450 {
+				t.Fatalf(`expected held amount to be 450, got %d`, heldAmount)
+			}
+
+			test.Prepare(
+				ExecuteCommand(
+					&commands.CaptureHold{
+						HoldID:    "H001",
+						AccountID: "A001",
+					},
+				),
+			)
+
+			var holds int
+
+			if err := db.QueryRow(
+				`SELECT COUNT(*)
+				FROM holds
+				WHERE account_id = "A001"`,
+			).Scan(&holds); err != nil {
+				t.Fatal(err)
+			}
+
+			if holds != 0 {
+				t.Fatalf(`expected no outstanding holds, got %d`, holds)
+			}
+
+			var (
+				debit   int64
+				balance int64
+			)
+
+			if err := db.QueryRow(
+				`SELECT debit, balance
+				FROM ledger
+				WHERE account_id = "A001"
+					AND transaction_id = "H001"`,
+			).Scan(&debit, &balance); err != nil {
+				t.Fatal(err)
+			}
+
+			if debit != 450 {
+				t.Fatalf(`expected debit to be 450, got %d`, debit)
+			}
+
+			if balance != 550 {
+				t.Fatalf(`expected balance to be 550, got %d`, balance)
+			}
+		},
+	)
 }
@@ -0,0 +1,55 @@
+-- accounts contains one row per bank account, with a running balance.
+--
+-- It is populated by the "ledger" projection, implemented by the
+-- LedgerProjectionHandler type in ledger.go.
+CREATE TABLE IF NOT EXISTS accounts (
+    id                TEXT    NOT NULL,            -- unique account identifier
+    name              TEXT    NOT NULL,            -- display name chosen by the customer
+    customer_id       TEXT    NOT NULL,            -- owner of the account
+    account_type      TEXT    NOT NULL,            -- type of account, such as "savings"
+    maturity_date     TEXT    NOT NULL DEFAULT '', -- date on which a term deposit matures, empty for other types
+    balance           INTEGER NOT NULL DEFAULT 0,  -- current balance, in cents, negative if overdrawn
+    held_amount       INTEGER NOT NULL DEFAULT 0,  -- total of holds not yet captured or released, in cents
+    daily_debit_limit INTEGER NOT NULL,            -- maximum total debits per day, in cents
+    overdraft_limit   INTEGER NOT NULL DEFAULT 0,  -- arranged overdraft limit, in cents
+    interest_rate     INTEGER NOT NULL DEFAULT 0,  -- annual interest rate, in basis points
+    is_closed         BOOLEAN NOT NULL DEFAULT 0,  -- true if the account has been closed
+    freeze_type       TEXT    NOT NULL DEFAULT '', -- type of freeze applied to the account, empty if not frozen
+    freeze_reason     TEXT    NOT NULL DEFAULT '', -- reason given by operations staff for the freeze
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_accounts_customer ON accounts (customer_id);
+
+-- ledger records each credit or debit against an account.
+--
+-- It is populated by the "ledger" projection, implemented by the
+-- LedgerProjectionHandler type in ledger.go.
+CREATE TABLE IF NOT EXISTS ledger (
+    account_id        TEXT      NOT NULL,            -- account this entry belongs to
+    transaction_id    TEXT      NOT NULL DEFAULT '', -- transaction that produced this entry
+    transaction_order INTEGER   NOT NULL DEFAULT 0,  -- order of this entry within its transaction
+    description       TEXT      NOT NULL,            -- human-readable description shown in the UI
+    debit             INTEGER   NOT NULL DEFAULT 0,  -- amount debited, in cents
+    credit            INTEGER   NOT NULL DEFAULT 0,  -- amount credited, in cents
+    balance           INTEGER   NOT NULL,            -- running account balance after this entry, in cents
+    created_at        TIMESTAMP NOT NULL,            -- time the originating event was recorded
+
+    PRIMARY KEY (account_id, transaction_id, transaction_order)
+);
+
+-- holds contains one row per hold that has been authorized against an account
+-- but not yet captured, released or expired.
+--
+-- It is populated by the "ledger" projection, implemented by the
+-- LedgerProjectionHandler type in ledger.go.
+CREATE TABLE IF NOT EXISTS holds (
+    account_id  TEXT      NOT NULL, -- account the funds are held in
+    hold_id     TEXT      NOT NULL, -- unique hold identifier
+    description TEXT      NOT NULL, -- human-readable description shown in the UI
+    amount      INTEGER   NOT NULL, -- amount held, in cents
+    created_at  TIMESTAMP NOT NULL, -- time the debit was authorized
+
+    PRIMARY KEY (account_id, hold_id)
+);
@@ -0,0 +1,39 @@
+-- standing_orders contains one row per standing order.
+--
+-- It is populated by the "standing-orders" projection, implemented by the
+-- StandingOrderProjectionHandler type in standingorder.go.
+CREATE TABLE IF NOT EXISTS standing_orders (
+    id                  TEXT    NOT NULL,            -- unique standing order identifier
+    from_account_id     TEXT    NOT NULL,            -- account from which payments are made
+    to_account_id       TEXT    NOT NULL,            -- account to which payments are made
+    to_third_party_bank BOOLEAN NOT NULL DEFAULT 0,  -- true if the "to" account is held by another bank
+    amount              INTEGER NOT NULL,            -- amount of each payment, in cents
+    frequency           TEXT    NOT NULL,            -- frequency of payments, such as "monthly"
+    start_date          TEXT    NOT NULL,            -- date on which the first payment falls due
+    end_date            TEXT    NOT NULL DEFAULT '', -- date after which no payments are made, empty if none
+    number_of_payments  INTEGER NOT NULL DEFAULT 0,  -- total number of payments, zero if unlimited
+    next_payment_date   TEXT    NOT NULL DEFAULT '', -- date on which the next payment falls due, empty if ended
+    status              TEXT    NOT NULL,            -- one of "active", "paused", "cancelled" or "completed"
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_standing_orders_from_account ON standing_orders (from_account_id);
+
+-- standing_order_payments records the outcome of each payment that has fallen
+-- due on a standing order.
+--
+-- It is populated by the "standing-orders" projection, implemented by the
+-- StandingOrderProjectionHandler type in standingorder.go.
+CREATE TABLE IF NOT EXISTS standing_order_payments (
+    standing_order_id TEXT    NOT NULL,            -- standing order this payment belongs to
+    payment_number    INTEGER NOT NULL,            -- position of this payment in the schedule, starting at 1
+    transaction_id    TEXT    NOT NULL DEFAULT '', -- transfer that made this payment, empty if skipped
+    date              TEXT    NOT NULL,            -- date on which the payment fell due
+    status            TEXT    NOT NULL,            -- one of "pending", "approved", "declined", "failed", "cancelled" or "skipped"
+    reason            TEXT    NOT NULL DEFAULT '', -- reason the payment was declined
+
+    PRIMARY KEY (standing_order_id, payment_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_standing_order_payments_transaction ON standing_order_payments (transaction_id);
@@ -0,0 +1,16 @@
+-- scheduled_transfers contains one row per transfer that has not yet reached
+-- its scheduled time.
+--
+-- It is populated by the "scheduled-transfers" projection, implemented by the
+-- ScheduledTransferProjectionHandler type in scheduledtransfer.go.
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    transaction_id  TEXT      NOT NULL, -- unique transaction identifier
+    from_account_id TEXT      NOT NULL, -- account from which funds are transferred
+    to_account_id   TEXT      NOT NULL, -- account to which funds are transferred
+    amount          INTEGER   NOT NULL, -- amount to transfer, in cents
+    scheduled_time  TIMESTAMP NOT NULL, -- time at which the transfer proceeds
+
+    PRIMARY KEY (transaction_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_from_account ON scheduled_transfers (from_account_id);
@@ -0,0 +1,17 @@
+-- customer_credentials contains the credentials each customer uses to log in.
+--
+-- It is populated by the "credentials" projection, implemented by the
+-- CredentialsProjectionHandler type in credentials.go.
+CREATE TABLE customer_credentials (
+    customer_id   TEXT NOT NULL, -- customer that the credentials belong to
+    username      TEXT NOT NULL, -- name the customer logs in with
+    password_hash TEXT NOT NULL, -- salted hash of the customer's password
+
+    PRIMARY KEY (customer_id)
+);
+
+-- Usernames are kept unique by the "username" aggregate, which reserves each
+-- one for a single customer before their credentials are set, rather than by
+-- a UNIQUE constraint, so that the projection never fails to apply an event
+-- that has already been recorded.
+CREATE INDEX idx_customer_credentials_username ON customer_credentials (username);
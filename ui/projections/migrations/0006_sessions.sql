@@ -0,0 +1,11 @@
+-- sessions contains the server-side state of each logged-in browser session.
+--
+-- It is not populated by a projection. The UI inserts a row when a customer
+-- logs in and deletes it when they log out.
+CREATE TABLE sessions (
+    id          TEXT      NOT NULL, -- SHA-256 hash of the session token stored in the cookie
+    customer_id TEXT      NOT NULL, -- customer that is logged in
+    expires_at  TIMESTAMP NOT NULL, -- time after which the session is no longer valid
+
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,18 @@
+-- transactions contains the outcome of each deposit, withdrawal and transfer.
+--
+-- It is populated by the "transactions" projection, implemented by the
+-- TransactionProjectionHandler type in transaction.go.
+--
+-- The UI uses it to answer a retried request with the outcome of the original
+-- transaction.
+CREATE TABLE transactions (
+    transaction_id   TEXT    NOT NULL,            -- unique transaction identifier
+    transaction_type TEXT    NOT NULL,            -- one of "deposit", "withdrawal" or "transfer"
+    account_id       TEXT    NOT NULL,            -- account credited or debited, or from which funds are transferred
+    to_account_id    TEXT    NOT NULL DEFAULT '', -- account to which funds are transferred, empty for other types
+    amount           INTEGER NOT NULL,            -- amount of the transaction, in cents
+    status           TEXT    NOT NULL,            -- one of "pending", "in progress", "approved", "declined", "failed" or "cancelled"
+    reason           TEXT    NOT NULL DEFAULT '', -- reason the transaction was declined
+
+    PRIMARY KEY (transaction_id)
+);
@@ -0,0 +1,26 @@
+-- idx_ledger_history supports paging through an account's transaction history,
+-- most recent first, using the position of the last entry on the previous page
+-- as a cursor.
+CREATE INDEX idx_ledger_history ON ledger (
+    account_id,
+    created_at,
+    transaction_id,
+    transaction_order
+);
+
+-- idx_ledger_credits and idx_ledger_debits support paging through a history
+-- that is filtered to only credits or only debits, without scanning the
+-- entries of the other type.
+CREATE INDEX idx_ledger_credits ON ledger (
+    account_id,
+    created_at,
+    transaction_id,
+    transaction_order
+) WHERE credit > 0;
+
+CREATE INDEX idx_ledger_debits ON ledger (
+    account_id,
+    created_at,
+    transaction_id,
+    transaction_order
+) WHERE debit > 0;
@@ -0,0 +1,19 @@
+-- statements contains one row per statement issued for an account.
+--
+-- It is populated by the "statements" projection, implemented by the
+-- StatementProjectionHandler type in statement.go.
+CREATE TABLE statements (
+    statement_id    TEXT      NOT NULL, -- unique statement identifier
+    account_id      TEXT      NOT NULL, -- account the statement was issued for
+    period_start    TEXT      NOT NULL, -- first day covered by the statement, as YYYY-MM-DD
+    period_end      TEXT      NOT NULL, -- last day covered by the statement, as YYYY-MM-DD
+    opening_balance INTEGER   NOT NULL, -- balance at the start of the period, in cents
+    total_credits   INTEGER   NOT NULL, -- total of all credits during the period, in cents
+    total_debits    INTEGER   NOT NULL, -- total of all debits during the period, in cents
+    closing_balance INTEGER   NOT NULL, -- balance at the end of the period, in cents
+    issued_at       TIMESTAMP NOT NULL, -- time the statement was issued
+
+    PRIMARY KEY (statement_id)
+);
+
+CREATE INDEX idx_statements_account ON statements (account_id, period_end);
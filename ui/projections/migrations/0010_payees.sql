@@ -0,0 +1,23 @@
+-- payees contains one row per payee that a customer has saved and not since
+-- removed.
+--
+-- It is populated by the "payees" projection, implemented by the
+-- PayeeProjectionHandler type in payee.go.
+CREATE TABLE payees (
+    id                       TEXT      NOT NULL,            -- unique payee identifier
+    customer_id              TEXT      NOT NULL,            -- customer that saved the payee
+    name                     TEXT      NOT NULL,            -- name by which the customer refers to the payee
+    account_id               TEXT      NOT NULL,            -- payee's account
+    to_third_party_bank      BOOLEAN   NOT NULL DEFAULT 0,  -- true if the account is held by another bank
+    bsb                      TEXT      NOT NULL DEFAULT '', -- BSB of a third-party account, in NNN-NNN format
+    account_number           TEXT      NOT NULL DEFAULT '', -- account number of a third-party account
+    account_name             TEXT      NOT NULL DEFAULT '', -- name of a third-party account
+    added_at                 TIMESTAMP NOT NULL,            -- time the payee was added
+    cooling_off_until        TIMESTAMP,                     -- time the cooling-off period ends, NULL if none
+    cooling_off_limit        INTEGER   NOT NULL DEFAULT 0,  -- limit on transfers during the cooling-off period, in cents
+    total_during_cooling_off INTEGER   NOT NULL DEFAULT 0,  -- total transferred during the cooling-off period, in cents
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_payees_customer ON payees (customer_id, name);
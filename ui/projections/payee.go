@@ -0,0 +1,142 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/dogmatiq/projectionkit/sqlprojection"
+)
+
+// PayeeProjectionHandler maintains the payees saved by each customer.
+//
+// The UI queries the payees table to list a customer's payees, and to offer
+// them as the destination of a transfer.
+type PayeeProjectionHandler struct {
+	sqlprojection.NoCompactBehavior
+}
+
+// Configure configs the engine for this projection.
+func (h *PayeeProjectionHandler) Configure(c dogma.ProjectionConfigurer) {
+	c.Identity("payees", "067646a9-4b25-4a4b-ac42-8efcfc13c587")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.PayeeAdded](),
+		dogma.HandlesEvent[*events.PayeeRemoved](),
+		dogma.HandlesEvent[*events.PayeeCoolingOffLimitConsumed](),
+	)
+}
+
+// HandleEvent inserts into the "payees" table whenever a payee is added, and
+// deletes from it whenever a payee is removed.
+//
+// The total transferred to a payee during its cooling-off period is updated as
+// each transfer is counted against the limit.
+func (h *PayeeProjectionHandler) HandleEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionEventScope,
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.PayeeAdded:
+		return h.payeeAdded(ctx, tx, x)
+
+	case *events.PayeeRemoved:
+		_, err := tx.ExecContext(
+			ctx,
+			`DELETE FROM payees
+			WHERE id = ?`,
+			x.PayeeID,
+		)
+		return err
+
+	case *events.PayeeCoolingOffLimitConsumed:
+		if x.CoolingOffLimit == 0 {
+			return nil
+		}
+
+		_, err := tx.ExecContext(
+			ctx,
+			`UPDATE payees SET
+				total_during_cooling_off = ?
+			WHERE id = ?`,
+			x.TotalDuringCoolingOff,
+			x.PayeeID,
+		)
+		return err
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+func (h *PayeeProjectionHandler) payeeAdded(
+	ctx context.Context,
+	tx *sql.Tx,
+	x *events.PayeeAdded,
+) error {
+	var bsb, accountNumber, accountName string
+	if p := x.ThirdPartyPayee; p != nil {
+		bsb = p.BSB
+		accountNumber = p.AccountNumber
+		accountName = p.AccountName
+	}
+
+	var coolingOffUntil sql.NullTime
+	if !x.CoolingOffUntil.IsZero() {
+		coolingOffUntil = sql.NullTime{Time: x.CoolingOffUntil, Valid: true}
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO payees (
+			id,
+			customer_id,
+			name,
+			account_id,
+			to_third_party_bank,
+			bsb,
+			account_number,
+			account_name,
+			added_at,
+			cooling_off_until,
+			cooling_off_limit,
+			total_during_cooling_off
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)`,
+		x.PayeeID,
+		x.CustomerID,
+		x.Name,
+		x.AccountID,
+		x.ThirdPartyPayee != nil,
+		bsb,
+		accountNumber,
+		accountName,
+		x.AddedAt,
+		coolingOffUntil,
+		x.CoolingOffLimit,
+		x.TotalDuringCoolingOff,
+	)
+	return err
+}
+
+// Reset clears all projection data.
+func (h *PayeeProjectionHandler) Reset(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionResetScope,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM payees`,
+	)
+	return err
+}
+
+// tables returns the names of the tables that contain the projection's data.
+func (h *PayeeProjectionHandler) tables() []string {
+	return []string{"payees"}
+}
@@ -0,0 +1,163 @@
+package projections_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/domain"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/ui/projections"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_PayeeProjectionHandler(t *testing.T) {
+	t.Run(
+		"when a transfer is made to a payee during its cooling-off period",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			addedAt := time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC)
+
+			Begin(
+				t,
+				&example.App{
+					PayeeAggregate: domain.PayeeHandler{
+						CoolingOffPeriod: 24 * time.Hour,
+						CoolingOffLimit:  1000,
+					},
+					ReadDB: db,
+				},
+				StartTimeAt(addedAt),
+			).
+				EnableHandlers("payees").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccount{
+							CustomerID:  "C001",
+							AccountID:   "A001",
+							AccountName: "Anna Smith",
+							AccountType: messages.Everyday,
+						},
+					),
+					ExecuteCommand(
+						&commands.OpenAccount{
+							CustomerID:  "C002",
+							AccountID:   "A002",
+							AccountName: "Bob Jones",
+							AccountType: messages.Everyday,
+						},
+					),
+					ExecuteCommand(
+						&commands.Deposit{
+							TransactionID: "D001",
+							AccountID:     "A001",
+							Amount:        500,
+						},
+					),
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+							Name:       "Bob",
+							AccountID:  "A002",
+						},
+					),
+					ExecuteCommand(
+						&commands.Transfer{
+							TransactionID: "T001",
+							FromAccountID: "A001",
+							ToAccountID:   "A002",
+							Amount:        300,
+							ScheduledTime: addedAt,
+							CustomerID:    "C001",
+							PayeeID:       "P001",
+						},
+					),
+				)
+
+			var (
+				customerID      string
+				name            string
+				coolingOffUntil time.Time
+				limit           int64
+				total           int64
+			)
+
+			if err := db.QueryRow(
+				`SELECT
+					customer_id,
+					name,
+					cooling_off_until,
+					cooling_off_limit,
+					total_during_cooling_off
+				FROM payees
+				WHERE id = "P001"`,
+			).Scan(
+				&customerID,
+				&name,
+				&coolingOffUntil,
+				&limit,
+				&total,
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			if customerID != "C001" {
+				t.Fatalf(`expected customer ID to be "C001", got %q`, customerID)
+			}
+			if name != "Bob" {
+				t.Fatalf(`expected name to be "Bob", got %q`, name)
+			}
+			if want := addedAt.Add(24 * time.Hour); !coolingOffUntil.Equal(want) {
+				t.Fatalf(`expected cooling-off period to end at %s, got %s`, want, coolingOffUntil)
+			}
+			if limit != 1000 {
+				t.Fatalf(`expected cooling-off limit to be 1000, got %d`, limit)
+			}
+			if total != 300 {
+				t.Fatalf(`expected total during cooling-off to be 300, got %d`, total)
+			}
+		},
+	)
+
+	t.Run(
+		"when a payee is removed",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			Begin(t, &example.App{ReadDB: db}).
+				EnableHandlers("payees").
+				Prepare(
+					ExecuteCommand(
+						&commands.AddPayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+							Name:       "Bob",
+							AccountID:  "A002",
+						},
+					),
+					ExecuteCommand(
+						&commands.RemovePayee{
+							CustomerID: "C001",
+							PayeeID:    "P001",
+						},
+					),
+				)
+
+			var n int
+			if err := db.QueryRow(
+				`SELECT COUNT(*) FROM payees`,
+			).Scan(&n); err != nil {
+				t.Fatal(err)
+			}
+
+			if n != 0 {
+				t.Fatalf("expected no payees, got %d", n)
+			}
+		},
+	)
+}
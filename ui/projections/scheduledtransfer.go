@@ -0,0 +1,106 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/dogmatiq/projectionkit/sqlprojection"
+)
+
+// ScheduledTransferProjectionHandler maintains a list of transfers that have
+// not yet reached their scheduled time.
+//
+// The UI queries the scheduled_transfers table to show pending transfers on
+// the transactions page, so that the customer may cancel them.
+type ScheduledTransferProjectionHandler struct {
+	sqlprojection.NoCompactBehavior
+}
+
+// Configure configs the engine for this projection.
+func (h *ScheduledTransferProjectionHandler) Configure(c dogma.ProjectionConfigurer) {
+	c.Identity("scheduled-transfers", "e4b7a2d9-1c83-4f5e-9a06-7d3c8b1f2e45")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.TransferStarted](),
+		dogma.HandlesEvent[*events.TransferProceeded](),
+		dogma.HandlesEvent[*events.TransferCancelled](),
+	)
+}
+
+// HandleEvent inserts into the "scheduled_transfers" table whenever a transfer
+// is started, and deletes from it once the transfer proceeds or is cancelled.
+func (h *ScheduledTransferProjectionHandler) HandleEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionEventScope,
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.TransferStarted:
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO scheduled_transfers (
+				transaction_id,
+				from_account_id,
+				to_account_id,
+				amount,
+				scheduled_time
+			) VALUES (
+				?,
+				?,
+				?,
+				?,
+				?
+			)`,
+			x.TransactionID,
+			x.FromAccountID,
+			x.ToAccountID,
+			x.Amount,
+			x.ScheduledTime,
+		)
+		return err
+
+	case *events.TransferProceeded:
+		return h.delete(ctx, tx, x.TransactionID)
+
+	case *events.TransferCancelled:
+		return h.delete(ctx, tx, x.TransactionID)
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+func (h *ScheduledTransferProjectionHandler) delete(
+	ctx context.Context,
+	tx *sql.Tx,
+	transactionID string,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM scheduled_transfers
+		WHERE transaction_id = ?`,
+		transactionID,
+	)
+	return err
+}
+
+// Reset clears all projection data.
+func (h *ScheduledTransferProjectionHandler) Reset(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionResetScope,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM scheduled_transfers`,
+	)
+	return err
+}
+
+// tables returns the names of the tables that contain the projection's data.
+func (h *ScheduledTransferProjectionHandler) tables() []string {
+	return []string{"scheduled_transfers"}
+}
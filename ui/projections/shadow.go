@@ -0,0 +1,93 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	"github.com/dogmatiq/projectionkit/sqlprojection"
+)
+
+// SwapProjection atomically replaces the data of the projection h in db with
+// the data in the SQLite database at shadowPath, along with the projection's
+// checkpoint offsets.
+//
+// The shadow database must have been created by [NewDB] so that its schema
+// matches that of db. Typically h has been rebuilt into the shadow database
+// while db continues to serve the UI. Any events that h handles in db while the
+// rebuild is in progress are handled again after the swap, as the checkpoint
+// offsets are replaced along with the data.
+func SwapProjection(
+	ctx context.Context,
+	db *sql.DB,
+	shadowPath string,
+	h sqlprojection.MessageHandler,
+) error {
+	t, ok := h.(interface{ tables() []string })
+	if !ok {
+		return fmt.Errorf("%T does not support being rebuilt into a shadow database", h)
+	}
+
+	key := runtimeconfig.
+		FromProjection(sqlprojection.New(db, sqlprojection.SQLiteDriver, h)).
+		Identity().
+		GetKey().
+		AsBytes()
+
+	// ATTACH and DETACH are not permitted within a transaction, so the shadow
+	// database is attached to a dedicated connection for the duration of the
+	// swap.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS shadow`, shadowPath); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), `DETACH DATABASE shadow`) // nolint:errcheck
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	for _, table := range t.tables() {
+		if _, err := tx.ExecContext(
+			ctx,
+			fmt.Sprintf(`DELETE FROM main.%s`, table),
+		); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			fmt.Sprintf(`INSERT INTO main.%s SELECT * FROM shadow.%s`, table, table),
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM main.projection_checkpoint WHERE handler = ?`,
+		key,
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO main.projection_checkpoint
+		SELECT * FROM shadow.projection_checkpoint
+		WHERE handler = ?`,
+		key,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
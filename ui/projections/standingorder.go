@@ -0,0 +1,259 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/dogmatiq/projectionkit/sqlprojection"
+)
+
+// StandingOrderProjectionHandler maintains a list of standing orders and the
+// outcome of each payment made on them.
+//
+// The UI queries the standing_orders table to list the standing orders that
+// make payments from an account, and the standing_order_payments table to show
+// whether recent payments succeeded.
+type StandingOrderProjectionHandler struct {
+	sqlprojection.NoCompactBehavior
+}
+
+// Configure configs the engine for this projection.
+func (h *StandingOrderProjectionHandler) Configure(c dogma.ProjectionConfigurer) {
+	c.Identity("standing-orders", "5f1c9e27-83ab-4d06-a7e4-2b6d90c8f315")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.StandingOrderCreated](),
+		dogma.HandlesEvent[*events.StandingOrderPaused](),
+		dogma.HandlesEvent[*events.StandingOrderResumed](),
+		dogma.HandlesEvent[*events.StandingOrderCancelled](),
+		dogma.HandlesEvent[*events.StandingOrderCompleted](),
+		dogma.HandlesEvent[*events.StandingOrderPaymentStarted](),
+		dogma.HandlesEvent[*events.StandingOrderPaymentSkipped](),
+		dogma.HandlesEvent[*events.TransferApproved](),
+		dogma.HandlesEvent[*events.TransferDeclined](),
+		dogma.HandlesEvent[*events.TransferFailed](),
+		dogma.HandlesEvent[*events.TransferCancelled](),
+	)
+}
+
+// HandleEvent inserts into the "standing_orders" table whenever a standing
+// order is created and updates its status as it is paused, resumed or ended.
+//
+// A row is inserted into the "standing_order_payments" table as each payment
+// falls due. The outcome of the transfer that makes the payment is recorded
+// against the row once it is known. Transfers that were not started by a
+// standing order have no effect.
+func (h *StandingOrderProjectionHandler) HandleEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionEventScope,
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.StandingOrderCreated:
+		return h.standingOrderCreated(ctx, tx, x)
+	case *events.StandingOrderPaused:
+		return h.updateStatus(ctx, tx, x.StandingOrderID, "paused")
+	case *events.StandingOrderResumed:
+		return h.updateStatus(ctx, tx, x.StandingOrderID, "active")
+	case *events.StandingOrderCancelled:
+		return h.standingOrderEnded(ctx, tx, x.StandingOrderID, "cancelled")
+	case *events.StandingOrderCompleted:
+		return h.standingOrderEnded(ctx, tx, x.StandingOrderID, "completed")
+	case *events.StandingOrderPaymentStarted:
+		return h.paymentFellDue(
+			ctx,
+			tx,
+			x.StandingOrderID,
+			x.PaymentNumber,
+			x.TransactionID,
+			x.Date,
+			x.NextPaymentDate,
+			"pending",
+		)
+	case *events.StandingOrderPaymentSkipped:
+		return h.paymentFellDue(
+			ctx,
+			tx,
+			x.StandingOrderID,
+			x.PaymentNumber,
+			"",
+			x.Date,
+			x.NextPaymentDate,
+			"skipped",
+		)
+	case *events.TransferApproved:
+		return h.updatePaymentStatus(ctx, tx, x.TransactionID, "approved", "")
+	case *events.TransferDeclined:
+		return h.updatePaymentStatus(ctx, tx, x.TransactionID, "declined", string(x.Reason))
+	case *events.TransferFailed:
+		return h.updatePaymentStatus(ctx, tx, x.TransactionID, "failed", "")
+	case *events.TransferCancelled:
+		return h.updatePaymentStatus(ctx, tx, x.TransactionID, "cancelled", "")
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+func (h *StandingOrderProjectionHandler) standingOrderCreated(
+	ctx context.Context,
+	tx *sql.Tx,
+	x *events.StandingOrderCreated,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO standing_orders (
+			id,
+			from_account_id,
+			to_account_id,
+			to_third_party_bank,
+			amount,
+			frequency,
+			start_date,
+			end_date,
+			number_of_payments,
+			next_payment_date,
+			status
+		) VALUES (
+			?,
+			?,
+			?,
+			?,
+			?,
+			?,
+			?,
+			?,
+			?,
+			?,
+			'active'
+		)`,
+		x.StandingOrderID,
+		x.FromAccountID,
+		x.ToAccountID,
+		x.ToThirdPartyBank,
+		x.Amount,
+		x.Frequency,
+		x.StartDate,
+		x.EndDate,
+		x.NumberOfPayments,
+		x.StartDate,
+	)
+	return err
+}
+
+func (h *StandingOrderProjectionHandler) updateStatus(
+	ctx context.Context,
+	tx *sql.Tx,
+	id, status string,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE standing_orders SET
+			status = ?
+		WHERE id = ?`,
+		status,
+		id,
+	)
+	return err
+}
+
+func (h *StandingOrderProjectionHandler) standingOrderEnded(
+	ctx context.Context,
+	tx *sql.Tx,
+	id, status string,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE standing_orders SET
+			status = ?,
+			next_payment_date = ''
+		WHERE id = ?`,
+		status,
+		id,
+	)
+	return err
+}
+
+func (h *StandingOrderProjectionHandler) paymentFellDue(
+	ctx context.Context,
+	tx *sql.Tx,
+	id string,
+	paymentNumber int,
+	transactionID, date, nextPaymentDate, status string,
+) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE standing_orders SET
+			next_payment_date = ?
+		WHERE id = ?`,
+		nextPaymentDate,
+		id,
+	); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO standing_order_payments (
+			standing_order_id,
+			payment_number,
+			transaction_id,
+			date,
+			status
+		) VALUES (
+			?,
+			?,
+			?,
+			?,
+			?
+		)`,
+		id,
+		paymentNumber,
+		transactionID,
+		date,
+		status,
+	)
+	return err
+}
+
+func (h *StandingOrderProjectionHandler) updatePaymentStatus(
+	ctx context.Context,
+	tx *sql.Tx,
+	transactionID, status, reason string,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE standing_order_payments SET
+			status = ?,
+			reason = ?
+		WHERE transaction_id = ?`,
+		status,
+		reason,
+		transactionID,
+	)
+	return err
+}
+
+// Reset clears all projection data.
+func (h *StandingOrderProjectionHandler) Reset(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionResetScope,
+) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM standing_order_payments`); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM standing_orders`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// tables returns the names of the tables that contain the projection's data.
+func (h *StandingOrderProjectionHandler) tables() []string {
+	return []string{"standing_orders", "standing_order_payments"}
+}
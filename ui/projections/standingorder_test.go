@@ -0,0 +1,101 @@
+package projections_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/ui/projections"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_StandingOrderProjectionHandler(t *testing.T) {
+	t.Run(
+		"when a standing order payment is declined",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			Begin(
+				t,
+				&example.App{ReadDB: db},
+				StartTimeAt(
+					time.Date(2001, time.February, 1, 11, 22, 33, 0, time.UTC),
+				),
+			).
+				EnableHandlers("standing-orders").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccount{
+							CustomerID:  "C001",
+							AccountID:   "A001",
+							AccountName: "Anna Smith",
+							AccountType: messages.Everyday,
+						},
+					),
+					ExecuteCommand(
+						&commands.OpenAccount{
+							CustomerID:  "C002",
+							AccountID:   "A002",
+							AccountName: "Bob Jones",
+							AccountType: messages.Everyday,
+						},
+					),
+					ExecuteCommand(
+						&commands.CreateStandingOrder{
+							StandingOrderID: "SO001",
+							FromAccountID:   "A001",
+							ToAccountID:     "A002",
+							Amount:          500,
+							Frequency:       messages.Weekly,
+							StartDate:       "2001-02-05",
+						},
+					),
+					AdvanceTime(
+						ToTime(time.Date(2001, time.February, 5, 0, 0, 0, 0, time.UTC)),
+					),
+				)
+
+			var (
+				status          string
+				reason          string
+				orderStatus     string
+				nextPaymentDate string
+			)
+
+			if err := db.QueryRow(
+				`SELECT
+					p.status,
+					p.reason,
+					o.status,
+					o.next_payment_date
+				FROM standing_order_payments AS p
+				INNER JOIN standing_orders AS o
+					ON o.id = p.standing_order_id
+				WHERE p.transaction_id = "SO001-1"`,
+			).Scan(
+				&status,
+				&reason,
+				&orderStatus,
+				&nextPaymentDate,
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			if status != "declined" {
+				t.Fatalf(`expected payment status to be "declined", got %q`, status)
+			}
+			if reason != "insufficient funds" {
+				t.Fatalf(`expected reason to be "insufficient funds", got %q`, reason)
+			}
+			if orderStatus != "active" {
+				t.Fatalf(`expected standing order status to be "active", got %q`, orderStatus)
+			}
+			if nextPaymentDate != "2001-02-12" {
+				t.Fatalf(`expected next payment date to be "2001-02-12", got %q`, nextPaymentDate)
+			}
+		},
+	)
+}
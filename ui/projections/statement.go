@@ -0,0 +1,85 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/dogmatiq/projectionkit/sqlprojection"
+)
+
+// StatementProjectionHandler maintains the statements issued for each
+// account.
+type StatementProjectionHandler struct {
+	sqlprojection.NoCompactBehavior
+}
+
+// Configure configs the engine for this projection.
+func (h *StatementProjectionHandler) Configure(c dogma.ProjectionConfigurer) {
+	c.Identity("statements", "80cc924c-e97c-43dd-81c5-8c4d657bb8a6")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.StatementIssued](),
+	)
+}
+
+// HandleEvent inserts into the "statements" table whenever a statement is
+// issued.
+func (h *StatementProjectionHandler) HandleEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	s dogma.ProjectionEventScope,
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.StatementIssued:
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO statements (
+				statement_id,
+				account_id,
+				period_start,
+				period_end,
+				opening_balance,
+				total_credits,
+				total_debits,
+				closing_balance,
+				issued_at
+			) VALUES (
+				?, ?, ?, ?, ?, ?, ?, ?, ?
+			)`,
+			x.StatementID,
+			x.AccountID,
+			x.PeriodStart,
+			x.PeriodEnd,
+			x.OpeningBalance,
+			x.TotalCredits,
+			x.TotalDebits,
+			x.ClosingBalance,
+			s.RecordedAt(),
+		)
+		return err
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+// Reset clears all projection data.
+func (h *StatementProjectionHandler) Reset(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionResetScope,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM statements`,
+	)
+	return err
+}
+
+// tables returns the names of the tables that contain the projection's data.
+func (h *StatementProjectionHandler) tables() []string {
+	return []string{"statements"}
+}
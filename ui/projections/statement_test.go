@@ -0,0 +1,103 @@
+package projections_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/ui/projections"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_StatementProjectionHandler(t *testing.T) {
+	t.Run(
+		"when a statement is issued",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			Begin(
+				t,
+				&example.App{ReadDB: db},
+				StartTimeAt(
+					time.Date(2001, time.January, 30, 11, 22, 33, 0, time.UTC),
+				),
+			).
+				EnableHandlers("statements").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccount{
+							CustomerID:  "C001",
+							AccountID:   "A001",
+							AccountName: "Anna Smith",
+							AccountType: messages.Everyday,
+						},
+					),
+					ExecuteCommand(
+						&commands.Deposit{
+							TransactionID: "D001",
+							AccountID:     "A001",
+							Amount:        10000,
+						},
+					),
+					AdvanceTime(
+						ToTime(time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC)),
+					),
+				)
+
+			var (
+				accountID      string
+				periodStart    string
+				periodEnd      string
+				openingBalance int64
+				totalCredits   int64
+				totalDebits    int64
+				closingBalance int64
+			)
+
+			if err := db.QueryRow(
+				`SELECT
+					account_id,
+					period_start,
+					period_end,
+					opening_balance,
+					total_credits,
+					total_debits,
+					closing_balance
+				FROM statements
+				WHERE statement_id = ?`,
+				"A001-statement-2001-01",
+			).Scan(
+				&accountID,
+				&periodStart,
+				&periodEnd,
+				&openingBalance,
+				&totalCredits,
+				&totalDebits,
+				&closingBalance,
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			if accountID != "A001" {
+				t.Fatalf(`expected account ID to be "A001", got %q`, accountID)
+			}
+
+			if periodStart != "2001-01-30" || periodEnd != "2001-01-31" {
+				t.Fatalf(`expected period to be 2001-01-30 to 2001-01-31, got %s to %s`, periodStart, periodEnd)
+			}
+
+			if openingBalance != 0 || totalCredits != 10000 || totalDebits != 0 || closingBalance != 10000 {
+				t.Fatalf(
+					`expected balances to be 0 + 10000 - 0 = 10000, got %d + %d - %d = %d`,
+					openingBalance,
+					totalCredits,
+					totalDebits,
+					closingBalance,
+				)
+			}
+		},
+	)
+}
@@ -0,0 +1,151 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/events"
+	"github.com/dogmatiq/projectionkit/sqlprojection"
+)
+
+// TransactionProjectionHandler maintains the outcome of each deposit,
+// withdrawal and transfer.
+//
+// The UI queries the transactions table when a request is retried with the
+// same idempotency key, so that it can respond with the outcome of the
+// original transaction.
+type TransactionProjectionHandler struct {
+	sqlprojection.NoCompactBehavior
+}
+
+// Configure configs the engine for this projection.
+func (h *TransactionProjectionHandler) Configure(c dogma.ProjectionConfigurer) {
+	c.Identity("transactions", "089e2a29-d9cd-405c-8d34-380f5bc3b537")
+
+	c.Routes(
+		dogma.HandlesEvent[*events.DepositStarted](),
+		dogma.HandlesEvent[*events.DepositApproved](),
+		dogma.HandlesEvent[*events.DepositDeclined](),
+		dogma.HandlesEvent[*events.WithdrawalStarted](),
+		dogma.HandlesEvent[*events.WithdrawalApproved](),
+		dogma.HandlesEvent[*events.WithdrawalDeclined](),
+		dogma.HandlesEvent[*events.TransferStarted](),
+		dogma.HandlesEvent[*events.TransferProceeded](),
+		dogma.HandlesEvent[*events.TransferCancelled](),
+		dogma.HandlesEvent[*events.TransferApproved](),
+		dogma.HandlesEvent[*events.TransferDeclined](),
+		dogma.HandlesEvent[*events.TransferFailed](),
+	)
+}
+
+// HandleEvent inserts into the "transactions" table whenever a transaction is
+// started, and updates its status as the transaction progresses.
+func (h *TransactionProjectionHandler) HandleEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionEventScope,
+	m dogma.Event,
+) error {
+	switch x := m.(type) {
+	case *events.DepositStarted:
+		return h.insert(ctx, tx, x.TransactionID, "deposit", x.AccountID, "", x.Amount)
+	case *events.DepositApproved:
+		return h.update(ctx, tx, x.TransactionID, "approved", "")
+	case *events.DepositDeclined:
+		return h.update(ctx, tx, x.TransactionID, "declined", x.Reason)
+
+	case *events.WithdrawalStarted:
+		return h.insert(ctx, tx, x.TransactionID, "withdrawal", x.AccountID, "", x.Amount)
+	case *events.WithdrawalApproved:
+		return h.update(ctx, tx, x.TransactionID, "approved", "")
+	case *events.WithdrawalDeclined:
+		return h.update(ctx, tx, x.TransactionID, "declined", x.Reason)
+
+	case *events.TransferStarted:
+		return h.insert(ctx, tx, x.TransactionID, "transfer", x.FromAccountID, x.ToAccountID, x.Amount)
+	case *events.TransferProceeded:
+		return h.update(ctx, tx, x.TransactionID, "in progress", "")
+	case *events.TransferCancelled:
+		return h.update(ctx, tx, x.TransactionID, "cancelled", "")
+	case *events.TransferApproved:
+		return h.update(ctx, tx, x.TransactionID, "approved", "")
+	case *events.TransferDeclined:
+		return h.update(ctx, tx, x.TransactionID, "declined", x.Reason)
+	case *events.TransferFailed:
+		return h.update(ctx, tx, x.TransactionID, "failed", "")
+
+	default:
+		panic(dogma.UnexpectedMessage)
+	}
+}
+
+func (h *TransactionProjectionHandler) insert(
+	ctx context.Context,
+	tx *sql.Tx,
+	transactionID, transactionType, accountID, toAccountID string,
+	amount int64,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO transactions (
+			transaction_id,
+			transaction_type,
+			account_id,
+			to_account_id,
+			amount,
+			status
+		) VALUES (
+			?,
+			?,
+			?,
+			?,
+			?,
+			'pending'
+		)`,
+		transactionID,
+		transactionType,
+		accountID,
+		toAccountID,
+		amount,
+	)
+	return err
+}
+
+func (h *TransactionProjectionHandler) update(
+	ctx context.Context,
+	tx *sql.Tx,
+	transactionID, status string,
+	reason messages.DebitFailureReason,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE transactions SET
+			status = ?,
+			reason = ?
+		WHERE transaction_id = ?`,
+		status,
+		reason,
+		transactionID,
+	)
+	return err
+}
+
+// Reset clears all projection data.
+func (h *TransactionProjectionHandler) Reset(
+	ctx context.Context,
+	tx *sql.Tx,
+	_ dogma.ProjectionResetScope,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM transactions`,
+	)
+	return err
+}
+
+// tables returns the names of the tables that contain the projection's data.
+func (h *TransactionProjectionHandler) tables() []string {
+	return []string{"transactions"}
+}
@@ -0,0 +1,112 @@
+package projections_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/example"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/ui/projections"
+	. "github.com/dogmatiq/testkit"
+)
+
+func Test_TransactionProjectionHandler(t *testing.T) {
+	t.Run(
+		"when transactions are approved and declined",
+		func(t *testing.T) {
+			db := projections.MustNewDB()
+			t.Cleanup(func() { db.Close() })
+
+			Begin(t, &example.App{ReadDB: db}).
+				EnableHandlers("transactions").
+				Prepare(
+					ExecuteCommand(
+						&commands.OpenAccountForNewCustomer{
+							CustomerID:   "C001",
+							CustomerName: "Anna Smith",
+							AccountID:    "A001",
+							AccountName:  "Savings",
+							AccountType:  messages.Everyday,
+						},
+					),
+					ExecuteCommand(
+						&commands.Deposit{
+							TransactionID: "T001",
+							AccountID:     "A001",
+							Amount:        100_00,
+						},
+					),
+					ExecuteCommand(
+						&commands.Withdraw{
+							TransactionID: "T002",
+							AccountID:     "A001",
+							Amount:        500_00,
+							ScheduledTime: time.Now(),
+						},
+					),
+				)
+
+			cases := []struct {
+				TransactionID   string
+				TransactionType string
+				Amount          int64
+				Status          string
+				Reason          string
+			}{
+				{"T001", "deposit", 100_00, "approved", ""},
+				{"T002", "withdrawal", 500_00, "declined", string(messages.InsufficientFunds)},
+			}
+
+			for _, c := range cases {
+				var (
+					transactionType string
+					accountID       string
+					amount          int64
+					status          string
+					reason          string
+				)
+
+				if err := db.QueryRow(
+					`SELECT
+						transaction_type,
+						account_id,
+						amount,
+						status,
+						reason
+					FROM transactions
+					WHERE transaction_id = ?`,
+					c.TransactionID,
+				).Scan(
+					&transactionType,
+					&accountID,
+					&amount,
+					&status,
+					&reason,
+				); err != nil {
+					t.Fatal(err)
+				}
+
+				if transactionType != c.TransactionType {
+					t.Fatalf(`expected %s to be a %q, got %q`, c.TransactionID, c.TransactionType, transactionType)
+				}
+
+				if accountID != "A001" {
+					t.Fatalf(`expected %s account ID to be "A001", got %q`, c.TransactionID, accountID)
+				}
+
+				if amount != c.Amount {
+					t.Fatalf(`expected %s amount to be %d, got %d`, c.TransactionID, c.Amount, amount)
+				}
+
+				if status != c.Status {
+					t.Fatalf(`expected %s status to be %q, got %q`, c.TransactionID, c.Status, status)
+				}
+
+				if reason != c.Reason {
+					t.Fatalf(`expected %s reason to be %q, got %q`, c.TransactionID, c.Reason, reason)
+				}
+			}
+		},
+	)
+}
@@ -0,0 +1,300 @@
+package ui
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// sessionCookie is the name of the cookie that holds the session token.
+	sessionCookie = "session"
+
+	// sessionLifetime is the length of time a session remains valid after the
+	// customer logs in.
+	sessionLifetime = 12 * time.Hour
+)
+
+var (
+	// errNotAuthorized indicates that the logged-in customer does not have
+	// access to the requested resource.
+	errNotAuthorized = errors.New("not authorized")
+
+	// errNotFound indicates that the requested resource does not exist.
+	errNotFound = errors.New("not found")
+)
+
+// startSession creates a new session for a customer and sets the session
+// cookie on the response. It returns the session token.
+func (h *Handler) startSession(w http.ResponseWriter, r *http.Request, customerID string) (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(sessionLifetime)
+
+	// Remove any sessions that have expired, so that the table does not grow
+	// without bound.
+	if _, err := h.DB.ExecContext(
+		r.Context(),
+		`DELETE FROM sessions
+		WHERE expires_at <= ?`,
+		now.UTC(),
+	); err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(token)
+
+	if _, err := h.DB.ExecContext(
+		r.Context(),
+		`INSERT INTO sessions (
+			id,
+			customer_id,
+			expires_at
+		) VALUES (?, ?, ?)`,
+		sessionID(encoded),
+		customerID,
+		expiresAt.UTC(),
+	); err != nil {
+		return "", err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    encoded,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return encoded, nil
+}
+
+// endSession deletes the session associated with the request, if any, and
+// clears the session cookie.
+func (h *Handler) endSession(w http.ResponseWriter, r *http.Request) error {
+	if token := sessionToken(r); token != "" {
+		if _, err := h.DB.ExecContext(
+			r.Context(),
+			`DELETE FROM sessions
+			WHERE id = ?`,
+			sessionID(token),
+		); err != nil {
+			return err
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+// sessionCustomerID returns the ID of the customer that is logged in to the
+// session associated with the request. It returns sql.ErrNoRows if there is
+// no valid session.
+func (h *Handler) sessionCustomerID(r *http.Request) (customerID string, err error) {
+	token := sessionToken(r)
+	if token == "" {
+		return "", sql.ErrNoRows
+	}
+
+	err = h.DB.QueryRowContext(
+		r.Context(),
+		`SELECT customer_id
+		FROM sessions
+		WHERE id = ?
+		AND expires_at > ?`,
+		sessionID(token),
+		time.Now().UTC(),
+	).Scan(&customerID)
+
+	return customerID, err
+}
+
+// sessionToken returns the session token supplied with the request, either as
+// a bearer token in the Authorization header or in the session cookie.
+func sessionToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		return c.Value
+	}
+
+	return ""
+}
+
+// sessionID returns the ID under which the session with the given token is
+// stored. Only a hash of the token is stored, so that the contents of the
+// database can not be used to hijack a session.
+func sessionID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// requireCustomer wraps a UI handler such that it is only invoked if the
+// request belongs to a session for the customer named in the URL.
+//
+// Visitors without a session are redirected to the login page.
+func (h *Handler) requireCustomer(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		customerID, err := h.sessionCustomerID(r)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			renderError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if err := h.authorize(r, customerID); errors.Is(err, errNotAuthorized) {
+			renderError(w, http.StatusForbidden)
+			return
+		} else if errors.Is(err, errNotFound) {
+			renderError(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			renderError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		fn(w, r)
+	}
+}
+
+// apiRequireCustomer wraps a JSON API handler such that it is only invoked if
+// the request belongs to a session for the customer named in the URL.
+func (h *Handler) apiRequireCustomer(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		customerID, err := h.sessionCustomerID(r)
+		if errors.Is(err, sql.ErrNoRows) {
+			writeAPIError(w, http.StatusUnauthorized, apiErrorUnauthorized, "authentication is required")
+			return
+		}
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+			return
+		}
+
+		if err := h.authorize(r, customerID); errors.Is(err, errNotAuthorized) {
+			writeAPIError(w, http.StatusForbidden, apiErrorForbidden, "access to this resource is not permitted")
+			return
+		} else if errors.Is(err, errNotFound) {
+			writeAPIError(w, http.StatusNotFound, apiErrorNotFound, "resource not found")
+			return
+		} else if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, err.Error())
+			return
+		}
+
+		fn(w, r)
+	}
+}
+
+// authorize returns errNotAuthorized if the customer, payee, account, standing
+// order or scheduled transfer named in the request URL does not belong to the
+// logged-in customer, or errNotFound if it does not exist.
+func (h *Handler) authorize(r *http.Request, customerID string) error {
+	if r.PathValue("customerID") != customerID {
+		return errNotAuthorized
+	}
+
+	if id := r.PathValue("payeeID"); id != "" {
+		if err := h.checkOwner(
+			r.Context(),
+			customerID,
+			`SELECT customer_id
+			FROM payees
+			WHERE id = ?`,
+			id,
+		); err != nil {
+			return err
+		}
+	}
+
+	accountID := r.PathValue("accountID")
+	if accountID == "" {
+		return nil
+	}
+
+	if err := h.checkOwner(
+		r.Context(),
+		customerID,
+		`SELECT customer_id
+		FROM accounts
+		WHERE id = ?`,
+		accountID,
+	); err != nil {
+		return err
+	}
+
+	if id := r.PathValue("standingOrderID"); id != "" {
+		if err := h.checkOwner(
+			r.Context(),
+			accountID,
+			`SELECT from_account_id
+			FROM standing_orders
+			WHERE id = ?`,
+			id,
+		); err != nil {
+			return err
+		}
+	}
+
+	if id := r.PathValue("transactionID"); id != "" {
+		if err := h.checkOwner(
+			r.Context(),
+			accountID,
+			`SELECT from_account_id
+			FROM scheduled_transfers
+			WHERE transaction_id = ?`,
+			id,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkOwner returns errNotAuthorized if query, which selects the owner of the
+// resource with the given ID, yields an owner other than want, or errNotFound
+// if it yields no owner at all.
+func (h *Handler) checkOwner(ctx context.Context, want, query, id string) error {
+	var owner string
+
+	err := h.DB.QueryRowContext(ctx, query, id).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if owner != want {
+		return errNotAuthorized
+	}
+
+	return nil
+}
@@ -0,0 +1,353 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/example/messages"
+	"github.com/dogmatiq/example/messages/commands"
+	"github.com/dogmatiq/example/ui/templates"
+)
+
+// standingOrder is a summary of a standing order as displayed in the standing
+// orders list.
+type standingOrder struct {
+	ID                string
+	ToAccountID       string
+	ToAccountName     string
+	Amount            money
+	Frequency         string
+	EndDate           string
+	NumberOfPayments  int
+	NextPaymentDate   string
+	Status            string
+	LastPaymentDate   string
+	LastPaymentStatus string
+	LastPaymentReason string
+}
+
+// IsEnded returns true if the standing order will make no further payments.
+func (o standingOrder) IsEnded() bool {
+	return o.Status == "cancelled" || o.Status == "completed"
+}
+
+// renderStandingOrdersPage renders the page listing the standing orders that
+// make payments from an account.
+func (h *Handler) renderStandingOrdersPage(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	customerName, err := h.queryCustomerName(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	orders, err := h.queryStandingOrders(r.Context(), accountID)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := struct {
+		pageData
+		AccountID      string
+		AccountName    string
+		Balance        money
+		IsClosed       bool
+		StandingOrders []standingOrder
+	}{
+		pageData: pageData{
+			Title:        "Standing Orders: " + acct.Name,
+			CustomerID:   customerID,
+			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
+		},
+		AccountID:      accountID,
+		AccountName:    acct.Name,
+		Balance:        acct.Balance,
+		IsClosed:       acct.IsClosed,
+		StandingOrders: orders,
+	}
+
+	if err := templates.Get("standingorders").ExecuteTemplate(w, "standingorders.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// renderNewStandingOrderPage renders the form used to create a standing order.
+func (h *Handler) renderNewStandingOrderPage(w http.ResponseWriter, r *http.Request) {
+	h.renderNewStandingOrder(w, r, "")
+}
+
+func (h *Handler) renderNewStandingOrder(w http.ResponseWriter, r *http.Request, formError string) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	customerName, err := h.queryCustomerName(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
+	if err != nil || acct.IsClosed {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	accounts, _, err := h.queryAccounts(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var ownAccounts []account
+	for _, a := range accounts {
+		if a.ID != accountID {
+			ownAccounts = append(ownAccounts, a)
+		}
+	}
+
+	payees, err := h.queryPayees(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := struct {
+		pageData
+		AccountID   string
+		AccountName string
+		Balance     money
+		OwnAccounts []account
+		Payees      []payee
+		Today       string
+		Error       string
+	}{
+		pageData: pageData{
+			Title:        "New Standing Order",
+			CustomerID:   customerID,
+			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
+		},
+		AccountID:   accountID,
+		AccountName: acct.Name,
+		Balance:     acct.Balance,
+		OwnAccounts: ownAccounts,
+		Payees:      payees,
+		Today:       time.Now().UTC().Format(time.DateOnly),
+		Error:       formError,
+	}
+
+	if formError != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	if err := templates.Get("newstandingorder").ExecuteTemplate(w, "newstandingorder.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// createStandingOrder processes a new standing order form submission.
+func (h *Handler) createStandingOrder(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	c := &commands.CreateStandingOrder{
+		StandingOrderID: idempotentID(r, "standing-order"),
+		FromAccountID:   accountID,
+	}
+
+	if r.FormValue("to") == "" {
+		h.renderNewStandingOrder(w, r, "Destination account is required.")
+		return
+	}
+
+	to, err := h.queryDestination(r.Context(), customerID, accountID, r.FormValue("to"))
+	if errors.Is(err, errInvalidDestination) {
+		h.renderNewStandingOrder(w, r, "Invalid destination account.")
+		return
+	}
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.ToAccountID = to.AccountID
+	c.ToThirdPartyBank = to.ToThirdPartyBank
+	c.ToPayee = to.Payee
+	if to.PayeeID != "" {
+		c.CustomerID = customerID
+		c.PayeeID = to.PayeeID
+	}
+
+	amount, err := parseMoney(r.FormValue("amount"))
+	if err != nil {
+		h.renderNewStandingOrder(w, r, "Invalid amount.")
+		return
+	}
+
+	frequency := messages.Frequency(r.FormValue("frequency"))
+	if err := frequency.Validate(); err != nil {
+		h.renderNewStandingOrder(w, r, "Invalid frequency.")
+		return
+	}
+
+	today := time.Now().UTC().Format(time.DateOnly)
+
+	startDate := r.FormValue("start_date")
+	if _, err := time.Parse(time.DateOnly, startDate); err != nil || startDate < today {
+		h.renderNewStandingOrder(w, r, "Start date must be today or later.")
+		return
+	}
+
+	endDate := r.FormValue("end_date")
+	if endDate != "" {
+		if _, err := time.Parse(time.DateOnly, endDate); err != nil || endDate < startDate {
+			h.renderNewStandingOrder(w, r, "End date must not be before the start date.")
+			return
+		}
+	}
+
+	var numberOfPayments int
+	if s := strings.TrimSpace(r.FormValue("number_of_payments")); s != "" {
+		numberOfPayments, err = strconv.Atoi(s)
+		if err != nil || numberOfPayments < 1 {
+			h.renderNewStandingOrder(w, r, "Invalid number of payments.")
+			return
+		}
+	}
+
+	c.Amount = int64(amount)
+	c.Frequency = frequency
+	c.StartDate = startDate
+	c.EndDate = endDate
+	c.NumberOfPayments = numberOfPayments
+
+	if err := h.CommandExecutor.ExecuteCommand(r.Context(), c); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts/%s/standing-orders", customerID, accountID), http.StatusSeeOther)
+}
+
+// pauseStandingOrder processes a request to pause a standing order.
+func (h *Handler) pauseStandingOrder(w http.ResponseWriter, r *http.Request) {
+	h.executeStandingOrderCommand(w, r, &commands.PauseStandingOrder{
+		StandingOrderID: r.PathValue("standingOrderID"),
+		FromAccountID:   r.PathValue("accountID"),
+	})
+}
+
+// resumeStandingOrder processes a request to resume a paused standing order.
+func (h *Handler) resumeStandingOrder(w http.ResponseWriter, r *http.Request) {
+	h.executeStandingOrderCommand(w, r, &commands.ResumeStandingOrder{
+		StandingOrderID: r.PathValue("standingOrderID"),
+		FromAccountID:   r.PathValue("accountID"),
+	})
+}
+
+// cancelStandingOrder processes a request to cancel a standing order.
+func (h *Handler) cancelStandingOrder(w http.ResponseWriter, r *http.Request) {
+	h.executeStandingOrderCommand(w, r, &commands.CancelStandingOrder{
+		StandingOrderID: r.PathValue("standingOrderID"),
+		FromAccountID:   r.PathValue("accountID"),
+	})
+}
+
+// executeStandingOrderCommand executes a command against a standing order and
+// redirects back to the standing orders page.
+func (h *Handler) executeStandingOrderCommand(w http.ResponseWriter, r *http.Request, c dogma.Command) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	if err := h.CommandExecutor.ExecuteCommand(r.Context(), c); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts/%s/standing-orders", customerID, accountID), http.StatusSeeOther)
+}
+
+// queryStandingOrders returns the standing orders that make payments from an
+// account, along with the outcome of the most recent payment on each. Active
+// and paused standing orders are listed before those that have ended.
+func (h *Handler) queryStandingOrders(ctx context.Context, accountID string) ([]standingOrder, error) {
+	rows, err := h.DB.QueryContext(
+		ctx,
+		`SELECT
+			o.id,
+			o.to_account_id,
+			COALESCE(a.name, ''),
+			o.amount,
+			o.frequency,
+			o.end_date,
+			o.number_of_payments,
+			o.next_payment_date,
+			o.status,
+			COALESCE(p.date, ''),
+			COALESCE(p.status, ''),
+			COALESCE(p.reason, '')
+		FROM standing_orders AS o
+		LEFT JOIN accounts AS a
+			ON a.id = o.to_account_id
+		LEFT JOIN standing_order_payments AS p
+			ON p.standing_order_id = o.id
+			AND p.payment_number = (
+				SELECT MAX(payment_number)
+				FROM standing_order_payments
+				WHERE standing_order_id = o.id
+			)
+		WHERE o.from_account_id = ?
+		ORDER BY
+			CASE WHEN o.status IN ('active', 'paused') THEN 0 ELSE 1 END,
+			o.start_date,
+			o.id`,
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []standingOrder
+
+	for rows.Next() {
+		var o standingOrder
+
+		if err := rows.Scan(
+			&o.ID,
+			&o.ToAccountID,
+			&o.ToAccountName,
+			&o.Amount,
+			&o.Frequency,
+			&o.EndDate,
+			&o.NumberOfPayments,
+			&o.NextPaymentDate,
+			&o.Status,
+			&o.LastPaymentDate,
+			&o.LastPaymentStatus,
+			&o.LastPaymentReason,
+		); err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, o)
+	}
+
+	return orders, rows.Err()
+}
@@ -0,0 +1,443 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dogmatiq/example/ui/templates"
+)
+
+// issuedStatement is a monthly statement that has been issued for an account.
+type issuedStatement struct {
+	ID             string
+	PeriodStart    string
+	PeriodEnd      string
+	OpeningBalance money
+	TotalCredits   money
+	TotalDebits    money
+	ClosingBalance money
+	IssuedAt       time.Time
+}
+
+// Period returns a human-readable description of the days covered by the
+// statement.
+func (s issuedStatement) Period() string {
+	start, _ := time.Parse(time.DateOnly, s.PeriodStart)
+	end, _ := time.Parse(time.DateOnly, s.PeriodEnd)
+	return start.Format("2 Jan 2006") + " to " + end.Format("2 Jan 2006")
+}
+
+// Month returns the name of the calendar month that the statement ends in.
+func (s issuedStatement) Month() string {
+	end, _ := time.Parse(time.DateOnly, s.PeriodEnd)
+	return end.Format("January 2006")
+}
+
+// renderStatementsPage renders the page listing the statements that have been
+// issued for an account, most recent first.
+func (h *Handler) renderStatementsPage(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	customerName, err := h.queryCustomerName(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	statements, err := h.queryStatements(r.Context(), accountID)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := struct {
+		pageData
+		AccountID   string
+		AccountName string
+		Balance     money
+		Statements  []issuedStatement
+	}{
+		pageData: pageData{
+			Title:        "Statements: " + acct.Name,
+			CustomerID:   customerID,
+			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
+		},
+		AccountID:   accountID,
+		AccountName: acct.Name,
+		Balance:     acct.Balance,
+		Statements:  statements,
+	}
+
+	if err := templates.Get("statements").ExecuteTemplate(w, "statements.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// renderStatementPage renders a single statement, including each of the ledger
+// entries within its period, in a form suitable for printing.
+func (h *Handler) renderStatementPage(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	customerName, err := h.queryCustomerName(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	s, err := h.queryStatement(r.Context(), accountID, r.PathValue("statementID"))
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	var entries []statementEntry
+	if err := h.eachStatementEntry(
+		r.Context(),
+		accountID,
+		transactionFilter{From: s.PeriodStart, To: s.PeriodEnd},
+		func(e statementEntry) error {
+			entries = append(entries, e)
+			return nil
+		},
+	); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := struct {
+		pageData
+		AccountID   string
+		AccountName string
+		AccountType string
+		Statement   issuedStatement
+		Entries     []statementEntry
+	}{
+		pageData: pageData{
+			Title:        "Statement: " + acct.Name + ", " + s.Month(),
+			CustomerID:   customerID,
+			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
+		},
+		AccountID:   accountID,
+		AccountName: acct.Name,
+		AccountType: acct.AccountType,
+		Statement:   s,
+		Entries:     entries,
+	}
+
+	if err := templates.Get("statement").ExecuteTemplate(w, "statement.html", data); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// downloadStatementPDF responds with a single statement as a PDF document.
+func (h *Handler) downloadStatementPDF(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	customerName, err := h.queryCustomerName(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	s, err := h.queryStatement(r.Context(), accountID, r.PathValue("statementID"))
+	if err != nil {
+		renderError(w, http.StatusNotFound)
+		return
+	}
+
+	sp := newStatementPDF(customerName, acct, s)
+
+	if err := h.eachStatementEntry(
+		r.Context(),
+		accountID,
+		transactionFilter{From: s.PeriodStart, To: s.PeriodEnd},
+		func(e statementEntry) error {
+			sp.Entry(e)
+			return nil
+		},
+	); err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set(
+		"Content-Disposition",
+		fmt.Sprintf(`attachment; filename="%s.pdf"`, s.ID),
+	)
+
+	sp.End().WriteTo(w) // nolint:errcheck
+}
+
+// queryStatements returns the statements issued for an account, most recent
+// first.
+func (h *Handler) queryStatements(
+	ctx context.Context,
+	accountID string,
+) ([]issuedStatement, error) {
+	rows, err := h.DB.QueryContext(
+		ctx,
+		`SELECT
+			statement_id,
+			period_start,
+			period_end,
+			opening_balance,
+			total_credits,
+			total_debits,
+			closing_balance,
+			issued_at
+		FROM statements
+		WHERE account_id = ?
+		ORDER BY period_end DESC`,
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statements []issuedStatement
+	for rows.Next() {
+		var s issuedStatement
+		if err := rows.Scan(
+			&s.ID,
+			&s.PeriodStart,
+			&s.PeriodEnd,
+			&s.OpeningBalance,
+			&s.TotalCredits,
+			&s.TotalDebits,
+			&s.ClosingBalance,
+			&s.IssuedAt,
+		); err != nil {
+			return nil, err
+		}
+		statements = append(statements, s)
+	}
+
+	return statements, rows.Err()
+}
+
+// queryStatement returns a single statement issued for an account.
+func (h *Handler) queryStatement(
+	ctx context.Context,
+	accountID, statementID string,
+) (s issuedStatement, err error) {
+	err = h.DB.QueryRowContext(
+		ctx,
+		`SELECT
+			statement_id,
+			period_start,
+			period_end,
+			opening_balance,
+			total_credits,
+			total_debits,
+			closing_balance,
+			issued_at
+		FROM statements
+		WHERE account_id = ?
+			AND statement_id = ?`,
+		accountID,
+		statementID,
+	).Scan(
+		&s.ID,
+		&s.PeriodStart,
+		&s.PeriodEnd,
+		&s.OpeningBalance,
+		&s.TotalCredits,
+		&s.TotalDebits,
+		&s.ClosingBalance,
+		&s.IssuedAt,
+	)
+
+	return s, err
+}
+
+// Layout of the statement PDF, in points.
+const (
+	statementPDFMargin    = 50.0
+	statementPDFRowHeight = 16.0
+	statementPDFFontSize  = 9.0
+)
+
+// statementPDF lays out a statement as a PDF document, starting a new page
+// whenever the table of ledger entries reaches the bottom of the current one.
+type statementPDF struct {
+	doc       pdfDocument
+	statement issuedStatement
+	accountID string
+	entries   int
+	y         float64
+}
+
+// statementPDFColumns are the positions of the columns of the ledger entries
+// table. Text is aligned to the left of its column, and amounts to the right.
+var statementPDFColumns = struct {
+	Date, Description, Debit, Credit, Balance float64
+}{
+	Date:        statementPDFMargin,
+	Description: statementPDFMargin + 60,
+	Debit:       pdfPageWidth - statementPDFMargin - 160,
+	Credit:      pdfPageWidth - statementPDFMargin - 80,
+	Balance:     pdfPageWidth - statementPDFMargin,
+}
+
+func newStatementPDF(customerName string, acct account, s issuedStatement) *statementPDF {
+	sp := &statementPDF{statement: s, accountID: acct.ID}
+	d := &sp.doc
+	d.NewPage()
+
+	left := statementPDFMargin
+	right := pdfPageWidth - statementPDFMargin
+	y := pdfPageHeight - statementPDFMargin - 18
+
+	d.Text(left, y, pdfHelveticaBold, 18, "Dogmatiq Bank")
+	d.TextRight(right, y, pdfHelveticaBold, 14, "Statement")
+	y -= 18
+	d.TextRight(right, y, pdfHelvetica, 10, s.Period())
+	y -= 30
+
+	d.Text(left, y, pdfHelveticaBold, 11, customerName)
+	y -= 15
+	d.Text(left, y, pdfHelvetica, 10, acct.Name+" ("+acct.AccountType+")")
+	y -= 13
+	d.Text(left, y, pdfHelvetica, 10, "Account "+acct.ID)
+	y -= 13
+	d.Text(left, y, pdfHelvetica, 10, "Issued "+s.IssuedAt.Format("2 Jan 2006"))
+	y -= 30
+
+	for _, row := range []struct {
+		Label  string
+		Amount money
+	}{
+		{"Opening balance", s.OpeningBalance},
+		{"Total credits", s.TotalCredits},
+		{"Total debits", s.TotalDebits},
+	} {
+		d.Text(left, y, pdfHelvetica, 10, row.Label)
+		d.TextRight(right, y, pdfHelvetica, 10, row.Amount.String())
+		y -= 15
+	}
+
+	d.Line(left, y+10, right, y+10, 0.5)
+	y -= 4
+	d.Text(left, y, pdfHelveticaBold, 10, "Closing balance")
+	d.TextRight(right, y, pdfHelveticaBold, 10, s.ClosingBalance.String())
+	y -= 36
+
+	sp.y = y
+	sp.tableHeader()
+
+	return sp
+}
+
+// tableHeader draws the column headings of the ledger entries table.
+func (sp *statementPDF) tableHeader() {
+	d := &sp.doc
+	c := statementPDFColumns
+	size := statementPDFFontSize
+
+	d.Text(c.Date, sp.y, pdfHelveticaBold, size, "DATE")
+	d.Text(c.Description, sp.y, pdfHelveticaBold, size, "DESCRIPTION")
+	d.TextRight(c.Debit, sp.y, pdfHelveticaBold, size, "DEBIT")
+	d.TextRight(c.Credit, sp.y, pdfHelveticaBold, size, "CREDIT")
+	d.TextRight(c.Balance, sp.y, pdfHelveticaBold, size, "BALANCE")
+	d.Line(statementPDFMargin, sp.y-5, c.Balance, sp.y-5, 0.75)
+
+	sp.y -= statementPDFRowHeight + 2
+}
+
+// Entry adds a row to the ledger entries table.
+func (sp *statementPDF) Entry(e statementEntry) {
+	if sp.y < statementPDFMargin+statementPDFRowHeight {
+		sp.doc.NewPage()
+		sp.y = pdfPageHeight - statementPDFMargin - 10
+		sp.tableHeader()
+	}
+
+	d := &sp.doc
+	c := statementPDFColumns
+	size := statementPDFFontSize
+
+	amount := func(x float64, m money) {
+		if m != 0 {
+			d.TextRight(x, sp.y, pdfHelvetica, size, m.String())
+		}
+	}
+
+	d.Text(c.Date, sp.y, pdfHelvetica, size, e.OccurredAt.Format("02 Jan"))
+	d.Text(
+		c.Description,
+		sp.y,
+		pdfHelvetica,
+		size,
+		pdfTruncate(pdfHelvetica, size, c.Debit-c.Description-70, e.Description),
+	)
+	amount(c.Debit, e.Debit)
+	amount(c.Credit, e.Credit)
+	amount(c.Balance, e.Balance)
+
+	sp.entries++
+	sp.y -= statementPDFRowHeight
+}
+
+// End completes the document, adding a footer to each page, and returns it.
+func (sp *statementPDF) End() *pdfDocument {
+	d := &sp.doc
+
+	if sp.entries == 0 {
+		d.Text(
+			statementPDFColumns.Description,
+			sp.y,
+			pdfHelvetica,
+			statementPDFFontSize,
+			"No transactions during this period.",
+		)
+	}
+
+	n := d.PageCount()
+
+	for i := range n {
+		d.SetPage(i)
+		d.Text(
+			statementPDFMargin,
+			statementPDFMargin/2,
+			pdfHelvetica,
+			8,
+			sp.accountID+" "+sp.statement.Month(),
+		)
+		d.TextRight(
+			pdfPageWidth-statementPDFMargin,
+			statementPDFMargin/2,
+			pdfHelvetica,
+			8,
+			fmt.Sprintf("Page %d of %d", i+1, n),
+		)
+	}
+
+	return d
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/example/ui/templates"
+)
+
+// keepAliveInterval is the interval at which a comment is sent on an idle
+// event stream, so that proxies do not close the connection.
+const keepAliveInterval = 15 * time.Second
+
+// streamAccounts pushes the accounts table to the browser as a server-sent
+// event whenever any of the customer's accounts changes.
+func (h *Handler) streamAccounts(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+
+	var updates <-chan struct{}
+	if h.Feed != nil {
+		ch, unsubscribe := h.Feed.SubscribeToCustomer(customerID)
+		defer unsubscribe()
+		updates = ch
+	}
+
+	h.stream(w, r, "accounts", updates, func(ctx context.Context, buf *bytes.Buffer) error {
+		data, err := h.queryAccountsFragment(ctx, customerID)
+		if err != nil {
+			return err
+		}
+		return templates.Get("accounts").ExecuteTemplate(buf, "accounts-fragment", data)
+	})
+}
+
+// streamTransactions pushes the page of the transactions table selected by the
+// request's query parameters to the browser as a server-sent event whenever
+// the account changes.
+func (h *Handler) streamTransactions(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+	token := csrfToken(w, r)
+
+	filter, filterError := parseTransactionFilter(r.URL.Query())
+	if filterError != "" {
+		renderError(w, http.StatusBadRequest, filterError)
+		return
+	}
+
+	var updates <-chan struct{}
+	if h.Feed != nil {
+		ch, unsubscribe := h.Feed.SubscribeToAccount(accountID)
+		defer unsubscribe()
+		updates = ch
+	}
+
+	h.stream(w, r, "transactions", updates, func(ctx context.Context, buf *bytes.Buffer) error {
+		data, err := h.queryTransactionsFragment(ctx, customerID, accountID, token, filter)
+		if err != nil {
+			return err
+		}
+		return templates.Get("transactions").ExecuteTemplate(buf, "transactions-fragment", data)
+	})
+}
+
+// stream writes server-sent events to w until the client disconnects.
+//
+// An event with the given name, containing the HTML produced by render, is
+// sent immediately, so that the browser catches up on any changes it missed
+// while disconnected, and again each time a value is received from updates.
+func (h *Handler) stream(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	updates <-chan struct{},
+	render func(context.Context, *bytes.Buffer) error,
+) {
+	ctx := r.Context()
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	var buf bytes.Buffer
+
+	for {
+		buf.Reset()
+		if err := render(ctx, &buf); err != nil {
+			return
+		}
+
+		fmt.Fprintf(w, "event: %s\n", name)
+		for line := range strings.Lines(buf.String()) {
+			fmt.Fprintf(w, "data: %s\n", strings.TrimRight(line, "\r\n"))
+		}
+		fmt.Fprint(w, "\n")
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+				if err := rc.Flush(); err != nil {
+					return
+				}
+			case <-updates:
+				break wait
+			}
+		}
+	}
+}
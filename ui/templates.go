@@ -5,4 +5,6 @@ type pageData struct {
 	Title        string
 	CustomerID   string
 	CustomerName string
+	OperatorName string
+	CSRFToken    string
 }
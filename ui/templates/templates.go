@@ -7,6 +7,8 @@ import (
 	"runtime/debug"
 	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 var (
@@ -20,6 +22,7 @@ var (
 		"time": func(t time.Time) string {
 			return t.Format("3:04 PM")
 		},
+		"idempotencyKey": uuid.NewString,
 		"commitHash": func() string {
 			if info, ok := debug.ReadBuildInfo(); ok {
 				for _, s := range info.Settings {
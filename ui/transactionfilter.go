@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"encoding/base64"
+	"html/template"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// transactionsPageSize is the maximum number of ledger entries shown on each
+// page of an account's transaction history.
+const transactionsPageSize = 50
+
+// transactionFilter restricts the ledger entries shown in an account's
+// transaction history.
+//
+// It is parsed from the query parameters of the transactions page, its event
+// stream and the JSON API, so that each page of a filtered history can be
+// linked to.
+type transactionFilter struct {
+	From      string // earliest date to include, as YYYY-MM-DD
+	To        string // latest date to include, as YYYY-MM-DD
+	Type      string // "credit", "debit" or empty for both
+	MinAmount money  // smallest amount to include, zero for no minimum
+	MaxAmount money  // largest amount to include, zero for no maximum
+	Search    string // text that must appear in the description
+	Before    string // cursor of the entry that precedes this page
+}
+
+// IsFiltered returns true if f restricts the history to matching entries.
+func (f transactionFilter) IsFiltered() bool {
+	return f.FirstPage() != transactionFilter{}
+}
+
+// IsFirstPage returns true if f selects the most recent entries.
+func (f transactionFilter) IsFirstPage() bool {
+	return f.Before == ""
+}
+
+// FirstPage returns a filter that selects the most recent entries that match
+// f.
+func (f transactionFilter) FirstPage() transactionFilter {
+	f.Before = ""
+	return f
+}
+
+// Query returns the URL query string that selects the same page of entries as
+// f.
+func (f transactionFilter) Query() template.URL {
+	q := url.Values{}
+
+	set := func(k, v string) {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+
+	set("from", f.From)
+	set("to", f.To)
+	set("type", f.Type)
+	if f.MinAmount != 0 {
+		set("min_amount", f.MinAmount.Decimal())
+	}
+	if f.MaxAmount != 0 {
+		set("max_amount", f.MaxAmount.Decimal())
+	}
+	set("q", f.Search)
+	set("before", f.Before)
+
+	return template.URL(q.Encode())
+}
+
+// parseTransactionFilter parses a transaction filter from URL query
+// parameters. It returns a non-empty error message if any of them are
+// invalid.
+func parseTransactionFilter(q url.Values) (transactionFilter, string) {
+	f := transactionFilter{
+		From:   strings.TrimSpace(q.Get("from")),
+		To:     strings.TrimSpace(q.Get("to")),
+		Type:   q.Get("type"),
+		Search: strings.TrimSpace(q.Get("q")),
+		Before: q.Get("before"),
+	}
+
+	for _, d := range []string{f.From, f.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, d); err != nil {
+			return transactionFilter{}, "Dates must be in the format YYYY-MM-DD."
+		}
+	}
+
+	if f.From != "" && f.To != "" && f.From > f.To {
+		return transactionFilter{}, "The start date must not be after the end date."
+	}
+
+	if f.Type != "" && f.Type != "credit" && f.Type != "debit" {
+		return transactionFilter{}, `The type must be either "credit" or "debit".`
+	}
+
+	for _, a := range []struct {
+		Value string
+		Into  *money
+	}{
+		{q.Get("min_amount"), &f.MinAmount},
+		{q.Get("max_amount"), &f.MaxAmount},
+	} {
+		if strings.TrimSpace(a.Value) == "" {
+			continue
+		}
+
+		m, err := parseMoney(a.Value)
+		if err != nil {
+			return transactionFilter{}, "Amounts must be positive numbers."
+		}
+		*a.Into = m
+	}
+
+	if f.MinAmount != 0 && f.MaxAmount != 0 && f.MinAmount > f.MaxAmount {
+		return transactionFilter{}, "The minimum amount must not be more than the maximum amount."
+	}
+
+	if f.Before != "" {
+		if _, ok := decodeLedgerCursor(f.Before); !ok {
+			return transactionFilter{}, "Invalid page cursor."
+		}
+	}
+
+	return f, ""
+}
+
+// where returns the SQL conditions and arguments that select the ledger
+// entries that match f. Each condition must be joined with "AND".
+func (f transactionFilter) where() (conditions []string, args []any) {
+	if f.From != "" {
+		conditions = append(conditions, `created_at >= ?`)
+		args = append(args, f.From)
+	}
+
+	if f.To != "" {
+		conditions = append(conditions, `created_at < ?`)
+		args = append(args, nextDay(f.To))
+	}
+
+	switch f.Type {
+	case "credit":
+		conditions = append(conditions, `credit > 0`)
+	case "debit":
+		conditions = append(conditions, `debit > 0`)
+	}
+
+	if f.MinAmount != 0 {
+		conditions = append(conditions, `debit + credit >= ?`)
+		args = append(args, int64(f.MinAmount))
+	}
+
+	if f.MaxAmount != 0 {
+		conditions = append(conditions, `debit + credit <= ?`)
+		args = append(args, int64(f.MaxAmount))
+	}
+
+	if f.Search != "" {
+		conditions = append(conditions, `description LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(f.Search)+"%")
+	}
+
+	if c, ok := decodeLedgerCursor(f.Before); ok {
+		conditions = append(conditions, `(created_at, transaction_id, transaction_order) < (?, ?, ?)`)
+		args = append(args, c.CreatedAt, c.TransactionID, c.TransactionOrder)
+	}
+
+	return conditions, args
+}
+
+// nextDay returns the day after the given day, both formatted as YYYY-MM-DD.
+//
+// Timestamps are stored as text that begins with the date, so any time on a
+// given day sorts before the following day.
+func nextDay(day string) string {
+	d, _ := time.Parse(time.DateOnly, day)
+	return d.AddDate(0, 0, 1).Format(time.DateOnly)
+}
+
+// ledgerCursor identifies a ledger entry by its position in an account's
+// transaction history.
+type ledgerCursor struct {
+	CreatedAt        string
+	TransactionID    string
+	TransactionOrder int
+}
+
+// encode returns the opaque representation of c used in URLs.
+func (c ledgerCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(c.CreatedAt + "\x00" + c.TransactionID + "\x00" + strconv.Itoa(c.TransactionOrder)),
+	)
+}
+
+// decodeLedgerCursor parses a cursor produced by ledgerCursor.encode.
+func decodeLedgerCursor(s string) (ledgerCursor, bool) {
+	if s == "" {
+		return ledgerCursor{}, false
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ledgerCursor{}, false
+	}
+
+	parts := strings.Split(string(data), "\x00")
+	if len(parts) != 3 {
+		return ledgerCursor{}, false
+	}
+
+	order, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return ledgerCursor{}, false
+	}
+
+	return ledgerCursor{parts[0], parts[1], order}, true
+}
+
+// escapeLike escapes the wildcard characters in s so that it matches itself
+// literally within a LIKE pattern that uses '\' as the escape character.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/dogmatiq/example/ui/templates"
@@ -17,12 +18,38 @@ type transaction struct {
 	Balance     money
 }
 
+// hold represents funds held in an account for a card payment that has been
+// authorized but not yet captured.
+type hold struct {
+	HoldID       string
+	AuthorizedAt time.Time
+	Description  string
+	Amount       money
+}
+
+// scheduledTransfer represents a transfer from an account that has not yet
+// reached its scheduled time.
+type scheduledTransfer struct {
+	TransactionID string
+	ToAccountID   string
+	Amount        money
+	ScheduledTime time.Time
+}
+
 // transactionsFragment holds the data needed to render the transactions table.
-// It is used both as a standalone HTMX response and composed into the full page.
+// It is used both as a server-sent event and composed into the full page.
 type transactionsFragment struct {
-	CustomerID   string
-	AccountID    string
-	Transactions []transaction
+	CustomerID         string
+	AccountID          string
+	CSRFToken          string
+	Filter             transactionFilter
+	ScheduledTransfers []scheduledTransfer
+	Holds              []hold
+	Transactions       []transaction
+
+	// NextPage selects the following page of the history, or is nil if there
+	// are no more entries.
+	NextPage *transactionFilter
 }
 
 // renderTransactionsPage renders the full page showing an account's transaction
@@ -37,13 +64,15 @@ func (h *Handler) renderTransactionsPage(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	accountName, balance, err := h.queryAccountDetails(r.Context(), accountID)
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
 	if err != nil {
 		renderError(w, http.StatusNotFound)
 		return
 	}
 
-	transactions, err := h.queryTransactions(r.Context(), accountID)
+	filter, filterError := parseTransactionFilter(r.URL.Query())
+
+	fragment, err := h.queryTransactionsFragment(r.Context(), customerID, accountID, csrfToken(w, r), filter)
 	if err != nil {
 		renderError(w, http.StatusNotFound)
 		return
@@ -53,22 +82,53 @@ func (h *Handler) renderTransactionsPage(w http.ResponseWriter, r *http.Request)
 		pageData
 		AccountID            string
 		AccountName          string
+		AccountType          string
+		MaturityDate         string
 		Balance              money
+		HeldAmount           money
+		DailyDebitLimit      money
+		OverdraftLimit       money
+		InterestRate         interestRate
+		IsClosed             bool
+		FreezeType           string
+		FreezeReason         string
+		FilterError          string
 		TransactionsFragment transactionsFragment
+		ExportFrom           string
+		ExportTo             string
 	}{
 		pageData: pageData{
-			Title:        "Transactions: " + accountName,
+			Title:        "Transactions: " + acct.Name,
 			CustomerID:   customerID,
 			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
 		},
-		AccountID:   accountID,
-		AccountName: accountName,
-		Balance:     money(balance),
-		TransactionsFragment: transactionsFragment{
-			CustomerID:   customerID,
-			AccountID:    accountID,
-			Transactions: transactions,
-		},
+		AccountID:            accountID,
+		AccountName:          acct.Name,
+		AccountType:          acct.AccountType,
+		MaturityDate:         acct.MaturityDate,
+		Balance:              acct.Balance,
+		HeldAmount:           acct.HeldAmount,
+		DailyDebitLimit:      acct.DailyDebitLimit,
+		OverdraftLimit:       acct.OverdraftLimit,
+		InterestRate:         acct.InterestRate,
+		IsClosed:             acct.IsClosed,
+		FreezeType:           acct.FreezeType,
+		FreezeReason:         acct.FreezeReason,
+		FilterError:          filterError,
+		TransactionsFragment: fragment,
+		ExportFrom:           filter.From,
+		ExportTo:             filter.To,
+	}
+
+	// Default to exporting the current month, unless the history is already
+	// filtered to a date range.
+	if data.ExportFrom == "" {
+		now := time.Now()
+		data.ExportFrom = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).Format(time.DateOnly)
+	}
+	if data.ExportTo == "" {
+		data.ExportTo = time.Now().Format(time.DateOnly)
 	}
 
 	if err := templates.Get("transactions").ExecuteTemplate(w, "transactions.html", data); err != nil {
@@ -76,67 +136,194 @@ func (h *Handler) renderTransactionsPage(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-// renderTransactionsFragment renders only the transactions table, without the
-// surrounding page layout. HTMX polls this endpoint to update the table
-// without a full page reload.
-func (h *Handler) renderTransactionsFragment(w http.ResponseWriter, r *http.Request) {
-	accountID := r.PathValue("accountID")
+// queryTransactionsFragment loads the data needed to render the page of the
+// transactions table selected by f.
+//
+// Scheduled transfers and holds are only included on the first page of the
+// unfiltered history, as they are not yet part of the ledger.
+func (h *Handler) queryTransactionsFragment(
+	ctx context.Context,
+	customerID, accountID, csrfToken string,
+	f transactionFilter,
+) (transactionsFragment, error) {
+	fragment := transactionsFragment{
+		CustomerID: customerID,
+		AccountID:  accountID,
+		CSRFToken:  csrfToken,
+		Filter:     f,
+	}
 
-	transactions, err := h.queryTransactions(r.Context(), accountID)
-	if err != nil {
-		renderError(w, http.StatusNotFound)
-		return
+	if f.IsFirstPage() && !f.IsFiltered() {
+		var err error
+
+		fragment.ScheduledTransfers, err = h.queryScheduledTransfers(ctx, accountID)
+		if err != nil {
+			return transactionsFragment{}, err
+		}
+
+		fragment.Holds, err = h.queryHolds(ctx, accountID)
+		if err != nil {
+			return transactionsFragment{}, err
+		}
 	}
 
-	data := transactionsFragment{
-		CustomerID:   r.PathValue("customerID"),
-		AccountID:    accountID,
-		Transactions: transactions,
+	transactions, next, err := h.queryTransactions(ctx, accountID, f)
+	if err != nil {
+		return transactionsFragment{}, err
 	}
 
-	if err := templates.Get("transactions").ExecuteTemplate(w, "transactions-fragment", data); err != nil {
-		renderError(w, http.StatusInternalServerError, err.Error())
+	fragment.Transactions = transactions
+
+	if next != "" {
+		f.Before = next
+		fragment.NextPage = &f
 	}
+
+	return fragment, nil
 }
 
-// queryTransactions loads the transaction history for an account.
-func (h *Handler) queryTransactions(ctx context.Context, accountID string) ([]transaction, error) {
+// queryTransactions loads a page of the transaction history for an account,
+// most recent first, containing only the entries that match f.
+//
+// next is the cursor of the entry that precedes the following page, or an
+// empty string if this is the last page.
+func (h *Handler) queryTransactions(
+	ctx context.Context,
+	accountID string,
+	f transactionFilter,
+) (transactions []transaction, next string, err error) {
+	conditions, args := f.where()
+	conditions = append([]string{`account_id = ?`}, conditions...)
+	args = append([]any{accountID}, args...)
+	args = append(args, transactionsPageSize+1)
+
 	rows, err := h.DB.QueryContext(
 		ctx,
 		`SELECT
+			CAST(created_at AS TEXT),
 			created_at,
+			transaction_id,
+			transaction_order,
 			description,
 			debit,
 			credit,
 			balance
 		FROM ledger
-		WHERE account_id = ?
+		WHERE `+strings.Join(conditions, ` AND `)+`
 		ORDER BY
 			created_at DESC,
-			transaction_order DESC`,
-		accountID,
+			transaction_id DESC,
+			transaction_order DESC
+		LIMIT ?`,
+		args...,
 	)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
-	var transactions []transaction
+	var cursor ledgerCursor
 	for rows.Next() {
+		if len(transactions) == transactionsPageSize {
+			next = cursor.encode()
+			break
+		}
+
 		var t transaction
 
 		if err := rows.Scan(
+			&cursor.CreatedAt,
 			&t.OccurredAt,
+			&cursor.TransactionID,
+			&cursor.TransactionOrder,
 			&t.Description,
 			&t.Debit,
 			&t.Credit,
 			&t.Balance,
 		); err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		transactions = append(transactions, t)
 	}
 
-	return transactions, rows.Err()
+	return transactions, next, rows.Err()
+}
+
+// queryScheduledTransfers loads the transfers from an account that have not yet
+// reached their scheduled time.
+func (h *Handler) queryScheduledTransfers(ctx context.Context, accountID string) ([]scheduledTransfer, error) {
+	rows, err := h.DB.QueryContext(
+		ctx,
+		`SELECT
+			transaction_id,
+			to_account_id,
+			amount,
+			scheduled_time
+		FROM scheduled_transfers
+		WHERE from_account_id = ?
+		ORDER BY scheduled_time`,
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []scheduledTransfer
+	for rows.Next() {
+		var t scheduledTransfer
+
+		if err := rows.Scan(
+			&t.TransactionID,
+			&t.ToAccountID,
+			&t.Amount,
+			&t.ScheduledTime,
+		); err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, t)
+	}
+
+	return transfers, rows.Err()
+}
+
+// queryHolds loads the holds on an account that have not yet been captured,
+// released or expired.
+func (h *Handler) queryHolds(ctx context.Context, accountID string) ([]hold, error) {
+	rows, err := h.DB.QueryContext(
+		ctx,
+		`SELECT
+			hold_id,
+			created_at,
+			description,
+			amount
+		FROM holds
+		WHERE account_id = ?
+		ORDER BY created_at DESC`,
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []hold
+	for rows.Next() {
+		var x hold
+
+		if err := rows.Scan(
+			&x.HoldID,
+			&x.AuthorizedAt,
+			&x.Description,
+			&x.Amount,
+		); err != nil {
+			return nil, err
+		}
+
+		holds = append(holds, x)
+	}
+
+	return holds, rows.Err()
 }
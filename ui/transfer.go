@@ -10,15 +10,24 @@ import (
 	"github.com/dogmatiq/example/messages/commands"
 	"github.com/dogmatiq/example/messages/events"
 	"github.com/dogmatiq/example/ui/templates"
-	"github.com/google/uuid"
 )
 
+// transferForm is the content of a submitted transfer form, used to populate
+// the form when it is rendered again.
+type transferForm struct {
+	// To identifies the destination of the transfer. It is "account:" followed
+	// by the ID of one of the customer's own accounts, or "payee:" followed
+	// by the ID of one of their payees.
+	To     string
+	Amount string
+}
+
 // renderTransferPage renders the transfer form.
 func (h *Handler) renderTransferPage(w http.ResponseWriter, r *http.Request) {
-	h.renderTransfer(w, r, "")
+	h.renderTransfer(w, r, transferForm{}, "")
 }
 
-func (h *Handler) renderTransfer(w http.ResponseWriter, r *http.Request, formError string) {
+func (h *Handler) renderTransfer(w http.ResponseWriter, r *http.Request, form transferForm, formError string) {
 	customerID := r.PathValue("customerID")
 	accountID := r.PathValue("accountID")
 
@@ -28,36 +37,56 @@ func (h *Handler) renderTransfer(w http.ResponseWriter, r *http.Request, formErr
 		return
 	}
 
-	accountName, balance, err := h.queryAccountDetails(r.Context(), accountID)
+	acct, err := h.queryAccountDetails(r.Context(), accountID)
 	if err != nil {
 		renderError(w, http.StatusNotFound)
 		return
 	}
 
-	accountGroups, err := h.queryAllAccountsGrouped(r.Context(), customerID, accountID)
+	accounts, _, err := h.queryAccounts(r.Context(), customerID)
 	if err != nil {
-		renderError(w, http.StatusNotFound)
+		renderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var ownAccounts []account
+	for _, a := range accounts {
+		if a.ID != accountID {
+			ownAccounts = append(ownAccounts, a)
+		}
+	}
+
+	payees, err := h.queryPayees(r.Context(), customerID)
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	data := struct {
 		pageData
-		AccountID     string
-		AccountName   string
-		Balance       money
-		AccountGroups []accountGroup
-		Error         string
+		AccountID   string
+		AccountName string
+		Balance     money
+		OwnAccounts []account
+		Payees      []payee
+		IsFrozen    bool
+		Form        transferForm
+		Error       string
 	}{
 		pageData: pageData{
 			Title:        "Transfer",
 			CustomerID:   customerID,
 			CustomerName: customerName,
+			CSRFToken:    csrfToken(w, r),
 		},
-		AccountID:     accountID,
-		AccountName:   accountName,
-		Balance:       money(balance),
-		AccountGroups: accountGroups,
-		Error:         formError,
+		AccountID:   accountID,
+		AccountName: acct.Name,
+		Balance:     acct.Balance,
+		OwnAccounts: ownAccounts,
+		Payees:      payees,
+		IsFrozen:    acct.FreezeType != "",
+		Form:        form,
+		Error:       formError,
 	}
 
 	if formError != "" {
@@ -74,31 +103,54 @@ func (h *Handler) transfer(w http.ResponseWriter, r *http.Request) {
 	customerID := r.PathValue("customerID")
 	accountID := r.PathValue("accountID")
 
-	amount, err := parseMoney(r.FormValue("amount"))
+	form := transferForm{
+		To:     r.FormValue("to"),
+		Amount: r.FormValue("amount"),
+	}
+
+	amount, err := parseMoney(form.Amount)
 	if err != nil {
-		h.renderTransfer(w, r, "Invalid amount.")
+		h.renderTransfer(w, r, form, "Invalid amount.")
+		return
+	}
+
+	if form.To == "" {
+		h.renderTransfer(w, r, form, "Destination account is required.")
 		return
 	}
 
-	toAccountID := r.FormValue("to_account_id")
-	if toAccountID == "" {
-		h.renderTransfer(w, r, "Destination account is required.")
+	to, err := h.queryDestination(r.Context(), customerID, accountID, form.To)
+	if errors.Is(err, errInvalidDestination) {
+		h.renderTransfer(w, r, form, "Invalid destination account.")
+		return
+	}
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	scheduledTime := parseSchedule(r.FormValue("schedule"))
+	transactionID := idempotentID(r, "transaction")
+
+	c := &commands.Transfer{
+		TransactionID:    transactionID,
+		FromAccountID:    accountID,
+		ToAccountID:      to.AccountID,
+		ToThirdPartyBank: to.ToThirdPartyBank,
+		ToPayee:          to.Payee,
+		Amount:           int64(amount),
+		ScheduledTime:    parseSchedule(r.FormValue("schedule")),
+	}
+
+	if to.PayeeID != "" {
+		c.CustomerID = customerID
+		c.PayeeID = to.PayeeID
+	}
 
 	var formError string
 
 	err = h.CommandExecutor.ExecuteCommand(
 		r.Context(),
-		&commands.Transfer{
-			TransactionID: uuid.New().String(),
-			FromAccountID: accountID,
-			ToAccountID:   toAccountID,
-			Amount:        int64(amount),
-			ScheduledTime: scheduledTime,
-		},
+		c,
 		dogma.WithEventObserver(func(context.Context, *events.TransferApproved) (bool, error) {
 			return true, nil
 		}),
@@ -111,13 +163,48 @@ func (h *Handler) transfer(w http.ResponseWriter, r *http.Request) {
 			return true, nil
 		}),
 	)
-	if err != nil && !errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		formError, err = h.originalOutcomeError(r.Context(), transactionID, "Transfer", transactionOutcome{
+			TransactionType: "transfer",
+			AccountID:       accountID,
+			ToAccountID:     c.ToAccountID,
+			Amount:          int64(amount),
+		})
+	}
+	if err != nil {
 		renderError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	if formError != "" {
-		h.renderTransfer(w, r, formError)
+		h.renderTransfer(w, r, form, formError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/c/%s/accounts/%s/transactions", customerID, accountID), http.StatusSeeOther)
+}
+
+// cancelTransfer processes a request to cancel a scheduled transfer.
+func (h *Handler) cancelTransfer(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("customerID")
+	accountID := r.PathValue("accountID")
+
+	err := h.CommandExecutor.ExecuteCommand(
+		r.Context(),
+		&commands.CancelTransfer{
+			TransactionID: r.PathValue("transactionID"),
+			FromAccountID: accountID,
+		},
+		dogma.WithEventObserver(func(context.Context, *events.TransferCancelled) (bool, error) {
+			return true, nil
+		}),
+	)
+	if errors.Is(err, dogma.ErrEventObserverNotSatisfied) {
+		renderError(w, http.StatusConflict, "The transfer has already proceeded and can no longer be cancelled.")
+		return
+	}
+	if err != nil {
+		renderError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 